@@ -25,23 +25,49 @@ package apt
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
 	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
 )
 
+// defaultListsDir is where apt-get update stores downloaded package indexes.
+const defaultListsDir = "/var/lib/apt/lists"
+
+// defaultDocDir holds the copyright file of every installed package.
+const defaultDocDir = "/usr/share/doc"
+
+// defaultOSReleasePath identifies the distribution and release.
+const defaultOSReleasePath = "/etc/os-release"
+
+// interruptRecoveryTimeout bounds the dpkg repair run after an interrupted operation.
+const interruptRecoveryTimeout = 10 * time.Minute
+
 // AptAdapter implements the PackageManager interface for APT.
 type AptAdapter struct {
 	executor     executor.Executor
 	aptGetPath   string
 	dpkgPath     string
 	aptCachePath string
+	aptMarkPath  string
+	listsDir     string
+	docDir       string
+	osRelease    string
+	httpClient   *http.Client
+	inventory    *adapters.Inventory
+	lockTimeout  time.Duration
 }
 
 // NewAptAdapter creates a new APT adapter.
@@ -60,9 +86,23 @@ func NewAptAdapter(exec executor.Executor, aptGetPath, dpkgPath, aptCachePath st
 		aptGetPath:   aptGetPath,
 		dpkgPath:     dpkgPath,
 		aptCachePath: aptCachePath,
+		aptMarkPath:  siblingTool(aptGetPath, "apt-mark"),
+		listsDir:     defaultListsDir,
+		docDir:       defaultDocDir,
+		osRelease:    defaultOSReleasePath,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
 	}
 }
 
+// siblingTool returns the path of tool in the directory of path, or tool alone
+// for a bare command name looked up on PATH.
+func siblingTool(path, tool string) string {
+	if !strings.ContainsRune(path, filepath.Separator) {
+		return tool
+	}
+	return filepath.Join(filepath.Dir(path), tool)
+}
+
 // GetManagerName returns the name of this package manager.
 func (a *AptAdapter) GetManagerName() string {
 	return "apt"
@@ -97,8 +137,41 @@ type AptPackageInfo struct {
 	Status            string   `json:"status"` // e.g., "install ok installed"
 }
 
+// SetInventory attaches a shared inventory. Once set, DetectInstalled and
+// ListInstalled are served from a single dpkg-query listing of every package.
+func (a *AptAdapter) SetInventory(inventory *adapters.Inventory) {
+	a.inventory = inventory
+}
+
 // DetectInstalled checks if a package is installed and returns its information.
-func (a *AptAdapter) DetectInstalled(ctx context.Context, name string) (*adapters.PackageInfo, error) {
+// With an inventory attached the answer comes from memory and AvailableVersions
+// is left empty; use Info when candidate versions are needed.
+func (a *AptAdapter) DetectInstalled(ctx context.Context, name string) (_ *adapters.PackageInfo, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "detect", name)
+	defer func() { telemetry.End(span, err) }()
+
+	if a.inventory == nil {
+		return a.queryPackage(ctx, name)
+	}
+
+	info, found, err := a.inventory.Lookup(ctx, name, a.listInstalled)
+	if err != nil {
+		// Fall back to a direct query if the bulk listing fails
+		return a.queryPackage(ctx, name)
+	}
+	if !found {
+		// The inventory holds every installed package, so a miss means not installed
+		return &adapters.PackageInfo{
+			Name:      name,
+			Installed: false,
+		}, nil
+	}
+
+	return &info, nil
+}
+
+// queryPackage looks up a single package with dpkg-query and apt-cache policy.
+func (a *AptAdapter) queryPackage(ctx context.Context, name string) (*adapters.PackageInfo, error) {
 	// Use dpkg-query to check installation status and version
 	args := []string{"--showformat", "${Package} ${Version} ${Status} ${Maintainer}", "--show", name}
 	result, err := a.executor.Run(ctx, a.dpkgPath, args, executor.ExecOpts{
@@ -162,7 +235,13 @@ func (a *AptAdapter) Install(ctx context.Context, name, version string) error {
 
 // InstallWithType installs a package. APT doesn't support types like cask/formula.
 // Implements idempotency by checking if the package is already installed before attempting installation.
-func (a *AptAdapter) InstallWithType(ctx context.Context, name, version string, packageType adapters.PackageType) error {
+// The package cache is not refreshed here; callers decide when UpdateCache runs.
+func (a *AptAdapter) InstallWithType(ctx context.Context, name, version string, packageType adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "install", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer a.inventory.Invalidate()
+
 	// IDEMPOTENCY CHECK: Check if package is already installed to avoid unnecessary operations
 	info, err := a.DetectInstalled(ctx, name)
 	if err == nil && info.Installed {
@@ -174,24 +253,47 @@ func (a *AptAdapter) InstallWithType(ctx context.Context, name, version string,
 		// Different version requested - continue with installation (may upgrade/downgrade)
 	}
 
-	// First, update cache to ensure latest package info
-	if err := a.UpdateCache(ctx); err != nil {
-		return fmt.Errorf("failed to update APT cache before install: %w", err)
-	}
-
-	args := []string{"install", "-y", "--no-install-recommends"}
+	args := append([]string{"install", "-y", "--no-install-recommends"}, statusFdArgs...)
 	if version != "" {
 		args = append(args, fmt.Sprintf("%s=%s", name, version))
 	} else {
 		args = append(args, name)
 	}
 
-	result, err := a.executor.Run(ctx, a.aptGetPath, args, executor.ExecOpts{
-		Timeout: 600 * time.Second, // 10 minutes for installation
-	})
+	result, err := a.runLocked(ctx, args, progressOpts(ctx, 600*time.Second)) // 10 minutes for installation
+	if err != nil || result.ExitCode != 0 {
+		return a.recoverIfInterrupted(ctx, commandError("install", name, result, err))
+	}
+
+	return nil
+}
+
+// InstallBatch installs several packages in a single apt-get transaction.
+func (a *AptAdapter) InstallBatch(ctx context.Context, packages []adapters.BatchPackage) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "install", adapters.BatchPackageNames(packages)...)
+	defer func() { telemetry.End(span, err) }()
+
+	if len(packages) == 0 {
+		return nil
+	}
+	defer a.inventory.Invalidate()
+
+	args := append([]string{"install", "-y", "--no-install-recommends"}, statusFdArgs...)
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		if pkg.Version != "" {
+			args = append(args, fmt.Sprintf("%s=%s", pkg.Name, pkg.Version))
+		} else {
+			args = append(args, pkg.Name)
+		}
+		names = append(names, pkg.Name)
+	}
+
+	// 10 minutes for the transaction plus a minute for every additional package
+	result, err := a.runLocked(ctx, args,
+		progressOpts(ctx, 600*time.Second+time.Duration(len(packages)-1)*time.Minute))
 	if err != nil || result.ExitCode != 0 {
-		return fmt.Errorf("failed to install %s: exit code %d, error: %w, stderr: %s",
-			name, result.ExitCode, err, result.Stderr)
+		return a.recoverIfInterrupted(ctx, commandError("install", strings.Join(names, ", "), result, err))
 	}
 
 	return nil
@@ -203,26 +305,106 @@ func (a *AptAdapter) Remove(ctx context.Context, name string) error {
 }
 
 // RemoveWithType removes a package. APT doesn't support types.
-func (a *AptAdapter) RemoveWithType(ctx context.Context, name string, packageType adapters.PackageType) error {
+func (a *AptAdapter) RemoveWithType(ctx context.Context, name string, packageType adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "remove", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer a.inventory.Invalidate()
+
 	args := []string{"remove", "-y", name}
 
-	result, err := a.executor.Run(ctx, a.aptGetPath, args, executor.ExecOpts{
+	result, err := a.runLocked(ctx, args, executor.ExecOpts{
 		Timeout: 300 * time.Second, // 5 minutes for removal
 	})
 	if err != nil || result.ExitCode != 0 {
 		// APT remove returns non-zero if package not installed, but we treat as no-op for idempotency
-		if strings.Contains(result.Stderr, "Package") && strings.Contains(result.Stderr, "is not installed") {
+		if !executor.IsInterrupted(err) &&
+			strings.Contains(result.Stderr, "Package") && strings.Contains(result.Stderr, "is not installed") {
 			return nil
 		}
-		return fmt.Errorf("failed to remove %s: exit code %d, error: %w, stderr: %s",
-			name, result.ExitCode, err, result.Stderr)
+		return a.recoverIfInterrupted(ctx, commandError("remove", name, result, err))
 	}
 
 	return nil
 }
 
+// RemoveBatch removes several packages in a single apt-get transaction.
+func (a *AptAdapter) RemoveBatch(ctx context.Context, packages []adapters.BatchPackage) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "remove", adapters.BatchPackageNames(packages)...)
+	defer func() { telemetry.End(span, err) }()
+
+	if len(packages) == 0 {
+		return nil
+	}
+	defer a.inventory.Invalidate()
+
+	args := []string{"remove", "-y"}
+	for _, pkg := range packages {
+		args = append(args, pkg.Name)
+	}
+
+	result, err := a.runLocked(ctx, args, executor.ExecOpts{
+		Timeout: 300*time.Second + time.Duration(len(packages)-1)*30*time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return a.recoverIfInterrupted(ctx, commandError("remove", strings.Join(args[2:], ", "), result, err))
+	}
+
+	return nil
+}
+
+// ListManuallyInstalled returns packages marked as manually installed by apt-mark.
+func (a *AptAdapter) ListManuallyInstalled(ctx context.Context) ([]string, error) {
+	result, err := a.executor.Run(ctx, a.aptMarkPath, []string{"showmanual"}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list manually installed packages: exit code %d, error: %w, stderr: %s",
+			result.ExitCode, err, result.Stderr)
+	}
+
+	return strings.Fields(result.Stdout), nil
+}
+
+// ListEssential returns packages marked Essential or of priority required or
+// important. Removing any of them can leave the system unbootable.
+func (a *AptAdapter) ListEssential(ctx context.Context) ([]string, error) {
+	args := []string{"--show", "--showformat", "${Package}\t${Essential}\t${Priority}\t${Status}\n"}
+	result, err := a.executor.Run(ctx, a.dpkgPath, args, executor.ExecOpts{
+		Timeout: 60 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("list essential packages of", "installed packages", result, err)
+	}
+
+	return parseDpkgEssential(result.Stdout), nil
+}
+
+// parseDpkgEssential parses "name\tessential\tpriority\tstatus" lines from
+// dpkg-query, keeping installed packages that are essential or of priority
+// required or important.
+func parseDpkgEssential(output string) []string {
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 4 || fields[0] == "" || !strings.HasSuffix(fields[3], " installed") {
+			continue
+		}
+		switch {
+		case fields[1] == "yes", fields[2] == "required", fields[2] == "important":
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
 // Pin pins or unpins a package.
-func (a *AptAdapter) Pin(ctx context.Context, name string, pin bool) error {
+func (a *AptAdapter) Pin(ctx context.Context, name string, pin bool) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "pin", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer a.inventory.Invalidate()
+
 	var args []string
 	if pin {
 		args = []string{"hold", name}
@@ -238,56 +420,361 @@ func (a *AptAdapter) Pin(ctx context.Context, name string, pin bool) error {
 		if !pin {
 			action = "unhold"
 		}
-		return fmt.Errorf("failed to %s %s: exit code %d, error: %w, stderr: %s",
-			action, name, result.ExitCode, err, result.Stderr)
+		return commandError(action, name, result, err)
 	}
 
 	return nil
 }
 
 // UpdateCache updates the APT package cache.
-func (a *AptAdapter) UpdateCache(ctx context.Context) error {
+func (a *AptAdapter) UpdateCache(ctx context.Context) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "update_cache")
+	defer func() { telemetry.End(span, err) }()
+
 	result, err := a.executor.Run(ctx, a.aptGetPath, []string{"update"}, executor.ExecOpts{
 		Timeout: 120 * time.Second, // 2 minutes for update
 	})
 	if err != nil || result.ExitCode != 0 {
-		return fmt.Errorf("failed to update APT cache: exit code %d, error: %w, stderr: %s",
-			result.ExitCode, err, result.Stderr)
+		return commandError("update", "APT cache", result, err)
 	}
 
 	return nil
 }
 
-// Search searches for packages matching a query.
+// CacheLastUpdated returns the modification time of the newest package index
+// under /var/lib/apt/lists, which apt-get update rewrites on every refresh.
+func (a *AptAdapter) CacheLastUpdated(_ context.Context) (time.Time, error) {
+	entries, err := os.ReadDir(a.listsDir)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read APT lists directory %s: %w", a.listsDir, err)
+	}
+
+	var newest time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == "lock" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+
+	if newest.IsZero() {
+		return time.Time{}, fmt.Errorf("no package indexes found in %s", filepath.Clean(a.listsDir))
+	}
+
+	return newest, nil
+}
+
+// Search searches package names and descriptions with 'apt-cache search',
+// which treats query as a regular expression.
 func (a *AptAdapter) Search(ctx context.Context, query string) ([]adapters.PackageInfo, error) {
-	result, err := a.executor.Run(ctx, a.aptGetPath, []string{"search", "--no-install-recommends", query}, executor.ExecOpts{
+	results, err := a.SearchCatalog(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]adapters.PackageInfo, 0, len(results))
+	for _, result := range results {
+		info := adapters.PackageInfo{
+			Name:      result.Name,
+			Installed: result.Installed,
+			Type:      result.Type,
+		}
+		if result.Version != "" {
+			info.AvailableVersions = []string{result.Version}
+		}
+		packages = append(packages, info)
+	}
+	return packages, nil
+}
+
+// Info retrieves detailed information about a package, including candidate
+// versions. It always queries the system and never uses the inventory.
+func (a *AptAdapter) Info(ctx context.Context, name string) (*adapters.PackageInfo, error) {
+	return a.queryPackage(ctx, name)
+}
+
+// Candidates reports the candidate version apt would install and every version
+// in the package's version table, as shown by 'apt-cache policy'.
+func (a *AptAdapter) Candidates(ctx context.Context, name string) (_ *adapters.VersionCandidates, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "candidates", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := a.executor.Run(ctx, a.aptCachePath, []string{"policy", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query candidates for", name, result, err)
+	}
+
+	candidates := parsePolicy(result.Stdout)
+	if candidates.Candidate == "" {
+		return nil, fmt.Errorf("package %s has no installation candidate: %w", name, adapters.ErrNotFound)
+	}
+	return candidates, nil
+}
+
+// parsePolicy parses 'apt-cache policy' output for a single package. Versions
+// in the version table are listed in apt's order of preference; the installed
+// one is marked with '***'.
+func parsePolicy(output string) *adapters.VersionCandidates {
+	candidates := &adapters.VersionCandidates{}
+	inTable := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Candidate:"):
+			candidate := strings.TrimSpace(strings.TrimPrefix(trimmed, "Candidate:"))
+			if candidate != "(none)" {
+				candidates.Candidate = candidate
+			}
+		case strings.HasPrefix(trimmed, "Version table:"):
+			inTable = true
+		case inTable:
+			fields := strings.Fields(strings.TrimPrefix(trimmed, "***"))
+			// Version lines are "<version> <priority>"; source lines are a priority
+			// followed by an archive URL or /var/lib/dpkg/status
+			if len(fields) == 2 && isNumeric(fields[1]) {
+				candidates.Available = append(candidates.Available, fields[0])
+			}
+		}
+	}
+	return candidates
+}
+
+// isNumeric reports whether s is a non-empty run of digits, optionally negative.
+func isNumeric(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Upgrade upgrades an installed package to its candidate version. Packages
+// that are not installed are left alone.
+func (a *AptAdapter) Upgrade(ctx context.Context, name string, _ adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "upgrade", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer a.inventory.Invalidate()
+
+	args := append([]string{"install", "-y", "--only-upgrade", "--no-install-recommends"}, statusFdArgs...)
+	args = append(args, name)
+	result, err := a.runLocked(ctx, args, progressOpts(ctx, 600*time.Second))
+	if err != nil || result.ExitCode != 0 {
+		return a.recoverIfInterrupted(ctx, commandError("upgrade", name, result, err))
+	}
+
+	return nil
+}
+
+// SimulateInstall previews an install with 'apt-get -s', which needs no
+// privileges and does not change the system.
+func (a *AptAdapter) SimulateInstall(ctx context.Context, name, version string) (_ *adapters.Simulation, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "simulate_install", name)
+	defer func() { telemetry.End(span, err) }()
+
+	target := name
+	if version != "" {
+		target = fmt.Sprintf("%s=%s", name, version)
+	}
+	return a.simulate(ctx, "install", target, []string{"-s", "install", "-y", "--no-install-recommends", target})
+}
+
+// SimulateRemove previews a removal with 'apt-get -s'.
+func (a *AptAdapter) SimulateRemove(ctx context.Context, name string) (_ *adapters.Simulation, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "simulate_remove", name)
+	defer func() { telemetry.End(span, err) }()
+
+	return a.simulate(ctx, "remove", name, []string{"-s", "remove", "-y", name})
+}
+
+func (a *AptAdapter) simulate(ctx context.Context, operation, target string, args []string) (*adapters.Simulation, error) {
+	result, err := a.executor.Run(ctx, a.aptGetPath, args, executor.ExecOpts{
+		Timeout: 60 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("simulate "+operation+" of", target, result, err)
+	}
+	return parseSimulation(result.Stdout), nil
+}
+
+var (
+	// simulatedInstallPattern matches "Inst name [old] (new ...)" and "Conf name (new ...)"
+	simulatedInstallPattern = regexp.MustCompile(`^(?:Inst|Conf) (\S+)(?: \[[^\]]*\])?(?: \((\S+))?`)
+	// simulatedRemovalPattern matches "Remv name [old]" and "Purg name [old]"
+	simulatedRemovalPattern = regexp.MustCompile(`^(?:Remv|Purg) (\S+)(?: \[([^\]]*)\])?`)
+)
+
+// parseSimulation extracts package changes from 'apt-get -s' output. Every
+// installed package appears on both an Inst and a Conf line; it is reported once.
+func parseSimulation(output string) *adapters.Simulation {
+	simulation := &adapters.Simulation{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		if m := simulatedInstallPattern.FindStringSubmatch(line); m != nil {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				simulation.Installs = append(simulation.Installs, adapters.SimulatedPackage{Name: m[1], Version: m[2]})
+			}
+			continue
+		}
+		if m := simulatedRemovalPattern.FindStringSubmatch(line); m != nil {
+			simulation.Removals = append(simulation.Removals, adapters.SimulatedPackage{Name: m[1], Version: m[2]})
+		}
+	}
+	return simulation
+}
+
+// ListInstalled returns every package dpkg reports as installed.
+func (a *AptAdapter) ListInstalled(ctx context.Context) ([]adapters.PackageInfo, error) {
+	if a.inventory != nil {
+		return a.inventory.Packages(ctx, a.listInstalled)
+	}
+	return a.listInstalled(ctx)
+}
+
+// installedListFormat lists every package with its status, the virtual packages
+// it provides and its relationship fields, in dependencyFields order.
+const installedListFormat = "${Package}\t${Version}\t${Status}\t${Provides}\t" +
+	"${Pre-Depends}\t${Depends}\t${Recommends}\t${Suggests}\n"
+
+// listInstalled runs a single dpkg-query covering every package on the system.
+func (a *AptAdapter) listInstalled(ctx context.Context) ([]adapters.PackageInfo, error) {
+	args := []string{"--show", "--showformat", installedListFormat}
+	result, err := a.executor.Run(ctx, a.dpkgPath, args, executor.ExecOpts{
 		Timeout: 60 * time.Second,
 	})
 	if err != nil || result.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to search packages: exit code %d, error: %w", result.ExitCode, err)
+		return nil, fmt.Errorf("failed to list installed packages: exit code %d, error: %w, stderr: %s",
+			result.ExitCode, err, result.Stderr)
 	}
 
+	return parseDpkgInstalledList(result.Stdout), nil
+}
+
+// parseDpkgInstalledList parses installedListFormat lines from dpkg-query,
+// keeping only packages whose status is fully installed. Packages marked
+// "hold" are reported as pinned. Lines may stop after the status.
+func parseDpkgInstalledList(output string) []adapters.PackageInfo {
 	var packages []adapters.PackageInfo
-	lines := strings.Split(result.Stdout, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "Listing...") {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) < 3 || fields[0] == "" {
 			continue
 		}
-		// Parse package names from search output (first column)
-		parts := strings.Fields(line)
-		if len(parts) > 0 {
-			packages = append(packages, adapters.PackageInfo{
-				Name:      parts[0],
-				Installed: false, // Search doesn't show install status
-			})
+		if !strings.HasSuffix(fields[2], " installed") {
+			continue
+		}
+		info := adapters.PackageInfo{
+			Name:      fields[0],
+			Version:   fields[1],
+			Installed: true,
+			Pinned:    strings.HasPrefix(fields[2], "hold "),
+			Type:      adapters.PackageTypeFormula,
+		}
+		if len(fields) > 3 {
+			info.Provides = providedNames(fields[3])
 		}
+		if len(fields) >= 4+len(dependencyFields) {
+			control := make(map[string]string, len(dependencyFields))
+			for i, relation := range dependencyFields {
+				control[relation.field] = fields[4+i]
+			}
+			info.Dependencies = parseDependencies(control)
+		}
+		packages = append(packages, info)
 	}
+	return packages
+}
 
-	return packages, nil
+// providedNames returns the virtual package names in a Provides field.
+func providedNames(provides string) []string {
+	var names []string
+	for _, virtual := range strings.Split(provides, ",") {
+		if name := dependencyName(virtual); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
-// Info retrieves detailed information about a package.
-func (a *AptAdapter) Info(ctx context.Context, name string) (*adapters.PackageInfo, error) {
-	return a.DetectInstalled(ctx, name)
+// Recover repairs an interrupted dpkg run by configuring unpacked packages and
+// letting apt fix any broken dependencies left behind.
+func (a *AptAdapter) Recover(ctx context.Context) error {
+	defer a.inventory.Invalidate()
+
+	result, err := a.executor.Run(ctx, "dpkg", []string{"--configure", "-a"}, executor.ExecOpts{
+		Timeout: 300 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return commandError("run", "dpkg --configure -a", result, err)
+	}
+
+	result, err = a.runLocked(ctx, []string{"-f", "install", "-y"}, executor.ExecOpts{
+		Timeout: 300 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return commandError("run", "apt-get -f install", result, err)
+	}
+
+	return nil
+}
+
+// recoverIfInterrupted repairs the dpkg database when an apt-get run was cut
+// short by cancellation or a timeout, since dpkg otherwise stays half-configured
+// and blocks every later operation. It runs detached from ctx, which is already done.
+// A successful repair is recorded on err so diagnostics can report it.
+func (a *AptAdapter) recoverIfInterrupted(ctx context.Context, err error) error {
+	if !executor.IsInterrupted(err) {
+		return err
+	}
+
+	recoverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptRecoveryTimeout)
+	defer cancel()
+
+	tflog.Warn(ctx, "apt-get was interrupted, running dpkg recovery", map[string]interface{}{
+		"error": err.Error(),
+	})
+	if recoverErr := a.Recover(recoverCtx); recoverErr != nil {
+		tflog.Warn(ctx, "dpkg recovery after interruption failed", map[string]interface{}{
+			"error": recoverErr.Error(),
+		})
+		return err
+	}
+
+	var opErr *adapters.OperationError
+	if errors.As(err, &opErr) {
+		opErr.Recovery = "The package database was repaired with `dpkg --configure -a`."
+	}
+	return err
+}
+
+// SetLockTimeout sets how long apt-get waits for the dpkg lock held by another
+// process before failing. Zero leaves APT's default of failing immediately.
+func (a *AptAdapter) SetLockTimeout(timeout time.Duration) {
+	a.lockTimeout = timeout
+}
+
+// runLocked runs an apt-get command that takes the dpkg lock, waiting up to the
+// configured lock timeout for it. The wait is added to the command's timeout so
+// a busy lock does not eat into the time the operation itself needs.
+func (a *AptAdapter) runLocked(ctx context.Context, args []string, opts executor.ExecOpts) (executor.ExecResult, error) {
+	if a.lockTimeout > 0 {
+		lockArg := fmt.Sprintf("DPkg::Lock::Timeout=%d", int(a.lockTimeout/time.Second))
+		args = append([]string{"-o", lockArg}, args...)
+		opts.Timeout += a.lockTimeout
+	}
+	return a.executor.Run(ctx, a.aptGetPath, args, opts)
 }
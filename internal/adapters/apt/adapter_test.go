@@ -1,15 +1,24 @@
 package apt
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 	"github.com/jamesainslie/terraform-provider-package/internal/executor"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockExecutor for testing
@@ -73,7 +82,7 @@ func TestAptAdapter_DetectInstalled_Simple(t *testing.T) {
 	exec.AssertExpectations(t)
 }
 
-func TestAptAdapter_Install_UpdateCache(t *testing.T) {
+func TestAptAdapter_Install_DoesNotUpdateCache(t *testing.T) {
 	exec := &MockExecutor{}
 	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
 
@@ -84,11 +93,8 @@ func TestAptAdapter_Install_UpdateCache(t *testing.T) {
 		Return(executor.ExecResult{ExitCode: 1, Stderr: "not found"}, fmt.Errorf("not found")).
 		Once()
 
-	// Test that install calls update first
-	exec.On("Run", mock.Anything, "apt-get", []string{"update"}, mock.Anything).
-		Return(executor.ExecResult{ExitCode: 0}, nil).
-		Once()
-	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "testpkg"}, mock.Anything).
+	// Cache refreshes are driven by the provider, so install must not run apt-get update itself
+	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "testpkg"}, mock.Anything).
 		Return(executor.ExecResult{ExitCode: 0}, nil).
 		Once()
 
@@ -96,6 +102,7 @@ func TestAptAdapter_Install_UpdateCache(t *testing.T) {
 	assert.NoError(t, err)
 
 	exec.AssertExpectations(t)
+	exec.AssertNotCalled(t, "Run", mock.Anything, "apt-get", []string{"update"}, mock.Anything)
 }
 
 func TestAptAdapter_Install_WithVersion(t *testing.T) {
@@ -110,10 +117,7 @@ func TestAptAdapter_Install_WithVersion(t *testing.T) {
 		Once()
 
 	// Test install with version
-	exec.On("Run", mock.Anything, "apt-get", []string{"update"}, mock.Anything).
-		Return(executor.ExecResult{ExitCode: 0}, nil).
-		Once()
-	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "testpkg=1.0"}, mock.Anything).
+	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "testpkg=1.0"}, mock.Anything).
 		Return(executor.ExecResult{ExitCode: 0}, nil).
 		Once()
 
@@ -190,24 +194,86 @@ func TestAptAdapter_UpdateCache(t *testing.T) {
 	exec.AssertExpectations(t)
 }
 
+const searchRecords = `Package: curl
+Version: 7.81.0-1ubuntu1.16
+Priority: optional
+Section: web
+Description-en: command line tool for transferring data with URL syntax
+ curl is a command line tool for transferring data with URL syntax.
+
+Package: curl
+Version: 7.81.0-1ubuntu1
+Section: web
+Description-en: command line tool for transferring data with URL syntax
+
+Package: libcurl4
+Version: 7.81.0-1ubuntu1.16
+Section: libs
+Description: easy-to-use client-side URL transfer library (OpenSSL flavour)
+
+`
+
+func TestParseSearchRecords(t *testing.T) {
+	assert.Equal(t, []adapters.SearchResult{
+		{Name: "curl", Version: "7.81.0-1ubuntu1.16", Section: "web",
+			Description: "command line tool for transferring data with URL syntax", Type: adapters.PackageTypeFormula},
+		{Name: "libcurl4", Version: "7.81.0-1ubuntu1.16", Section: "libs",
+			Description: "easy-to-use client-side URL transfer library (OpenSSL flavour)", Type: adapters.PackageTypeFormula},
+	}, parseSearchRecords(searchRecords))
+	assert.Empty(t, parseSearchRecords(""))
+}
+
 func TestAptAdapter_Search(t *testing.T) {
 	exec := &MockExecutor{}
 	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
 
-	searchOutput := `testpkg/testing - Test package
-anotherpkg - Another package
-`
-	exec.On("Run", mock.Anything, "apt-get", []string{"search", "--no-install-recommends", "test"}, mock.Anything).
-		Return(executor.ExecResult{ExitCode: 0, Stdout: searchOutput}, nil).
-		Once()
+	exec.On("Run", mock.Anything, "apt-cache", []string{"search", "--full", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: searchRecords}, nil).Once()
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--show", "--showformat", installedListFormat},
+		mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "libcurl4\t7.81.0-1ubuntu1.16\tinstall ok installed\n"}, nil).Once()
 
-	results, err := adapter.Search(context.Background(), "test")
+	results, err := adapter.Search(context.Background(), "curl")
 	assert.NoError(t, err)
 	assert.Len(t, results, 2)
-	assert.Equal(t, "testpkg/testing", results[0].Name)
-	assert.Equal(t, "anotherpkg", results[1].Name)
-	assert.False(t, results[0].Installed) // Search doesn't set installed status
+	assert.Equal(t, "curl", results[0].Name)
+	assert.False(t, results[0].Installed)
+	assert.Equal(t, []string{"7.81.0-1ubuntu1.16"}, results[0].AvailableVersions)
+	assert.Equal(t, "libcurl4", results[1].Name)
+	assert.True(t, results[1].Installed)
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_SearchCatalog_NoMatches(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-cache", []string{"search", "--full", "^nothing$"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).Once()
 
+	results, err := adapter.SearchCatalog(context.Background(), "^nothing$", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_SearchCatalog_Keep(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-cache", []string{"search", "--full", "."}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: searchRecords}, nil).Once()
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--show", "--showformat", installedListFormat},
+		mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "libcurl4\t7.81.0-1ubuntu1.16\tinstall ok installed\n"}, nil).Once()
+
+	results, err := adapter.SearchCatalog(context.Background(), ".",
+		func(name string) bool { return strings.HasPrefix(name, "lib") })
+	assert.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "libcurl4", results[0].Name)
+	assert.True(t, results[0].Installed)
 	exec.AssertExpectations(t)
 }
 
@@ -245,7 +311,7 @@ func TestAptAdapter_Install_Idempotency_AlreadyInstalled(t *testing.T) {
 
 	// UpdateCache and Install should NOT be called since package is already installed
 	exec.AssertNotCalled(t, "Run", mock.Anything, "apt-get", []string{"update"}, mock.Anything)
-	exec.AssertNotCalled(t, "Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "testpkg"}, mock.Anything)
+	exec.AssertNotCalled(t, "Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "testpkg"}, mock.Anything)
 
 	// This should return without error and without calling install
 	err := adapter.InstallWithType(context.Background(), "testpkg", "", adapters.PackageTypeAuto)
@@ -270,11 +336,8 @@ func TestAptAdapter_Install_Idempotency_VersionMismatch(t *testing.T) {
 		Return(executor.ExecResult{ExitCode: 0, Stdout: "Candidate: 2.0\nVersion table: *** 2.0"}, nil).
 		Once()
 
-	// UpdateCache and Install SHOULD be called since version differs
-	exec.On("Run", mock.Anything, "apt-get", []string{"update"}, mock.Anything).
-		Return(executor.ExecResult{ExitCode: 0}, nil).
-		Once()
-	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "testpkg=2.0"}, mock.Anything).
+	// Install SHOULD be called since version differs
+	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "testpkg=2.0"}, mock.Anything).
 		Return(executor.ExecResult{ExitCode: 0}, nil).
 		Once()
 
@@ -284,3 +347,929 @@ func TestAptAdapter_Install_Idempotency_VersionMismatch(t *testing.T) {
 
 	exec.AssertExpectations(t)
 }
+
+func TestAptAdapter_ListInstalled(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	listOutput := "curl\t7.81.0-1ubuntu1.15\tinstall ok installed\n" +
+		"oldpkg\t1.2-3\tdeinstall ok config-files\n" +
+		"jq\t1.6-2.1ubuntu3\tinstall ok installed\n" +
+		"nginx\t1.18.0-6ubuntu14.3\thold ok installed\n"
+	exec.On("Run", mock.Anything, "dpkg-query",
+		[]string{"--show", "--showformat", installedListFormat}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: listOutput}, nil).
+		Once()
+
+	packages, err := adapter.ListInstalled(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, packages, 3)
+	assert.Equal(t, "curl", packages[0].Name)
+	assert.Equal(t, "7.81.0-1ubuntu1.15", packages[0].Version)
+	assert.False(t, packages[0].Pinned)
+	assert.Equal(t, "jq", packages[1].Name)
+	assert.True(t, packages[2].Pinned)
+	assert.True(t, packages[1].Installed)
+
+	exec.AssertExpectations(t)
+}
+
+func TestParseDpkgEssential(t *testing.T) {
+	output := "bash\tyes\trequired\tinstall ok installed\n" +
+		"openssh-server\tno\tstandard\tinstall ok installed\n" +
+		"apt\t\timportant\tinstall ok installed\n" +
+		"jq\t\toptional\tinstall ok installed\n" +
+		"old-libc\tyes\trequired\tdeinstall ok config-files\n"
+	assert.Equal(t, []string{"bash", "apt"}, parseDpkgEssential(output))
+}
+
+func TestAptAdapter_Recover(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "dpkg", []string{"--configure", "-a"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).
+		Once()
+	exec.On("Run", mock.Anything, "apt-get", []string{"-f", "install", "-y"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).
+		Once()
+
+	err := adapter.Recover(context.Background())
+	assert.NoError(t, err)
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_Recover_ConfigureFails(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "dpkg", []string{"--configure", "-a"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 2, Stderr: "dpkg: error"}, nil).
+		Once()
+
+	err := adapter.Recover(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "dpkg --configure -a")
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_CacheLastUpdated(t *testing.T) {
+	adapter := NewAptAdapter(&MockExecutor{}, "apt-get", "dpkg-query", "apt-cache")
+	adapter.listsDir = t.TempDir()
+
+	_, err := adapter.CacheLastUpdated(context.Background())
+	assert.Error(t, err, "Empty lists directory means the cache has never been updated")
+
+	older := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	newer := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	for name, mtime := range map[string]time.Time{
+		"archive.ubuntu.com_ubuntu_dists_jammy_InRelease":           older,
+		"security.ubuntu.com_ubuntu_dists_jammy-security_InRelease": newer,
+		"lock": time.Now(),
+	} {
+		path := filepath.Join(adapter.listsDir, name)
+		assert.NoError(t, os.WriteFile(path, []byte("x"), 0o600))
+		assert.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	lastUpdated, err := adapter.CacheLastUpdated(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, newer.Equal(lastUpdated), "expected %v, got %v", newer, lastUpdated)
+}
+
+func TestAptAdapter_InstallBatch(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-get",
+		[]string{"install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "curl", "jq=1.6-2", "git"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).
+		Once()
+
+	err := adapter.InstallBatch(context.Background(), []adapters.BatchPackage{
+		{Name: "curl"},
+		{Name: "jq", Version: "1.6-2"},
+		{Name: "git"},
+	})
+	assert.NoError(t, err)
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_DetectInstalled_ServedFromInventory(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+	adapter.SetInventory(adapters.NewInventory())
+
+	listArgs := []string{"--show", "--showformat", installedListFormat}
+	listOutput := "curl\t7.81.0-1ubuntu1.15\tinstall ok installed\n" +
+		"jq\t1.6-2.1ubuntu3\tinstall ok installed\n"
+	// A single listing answers every lookup until the inventory is invalidated
+	exec.On("Run", mock.Anything, "dpkg-query", listArgs, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: listOutput}, nil).
+		Once()
+
+	curl, err := adapter.DetectInstalled(context.Background(), "curl")
+	assert.NoError(t, err)
+	assert.True(t, curl.Installed)
+	assert.Equal(t, "7.81.0-1ubuntu1.15", curl.Version)
+
+	jq, err := adapter.DetectInstalled(context.Background(), "jq")
+	assert.NoError(t, err)
+	assert.True(t, jq.Installed)
+
+	missing, err := adapter.DetectInstalled(context.Background(), "htop")
+	assert.NoError(t, err)
+	assert.False(t, missing.Installed)
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_Install_InvalidatesInventory(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+	adapter.SetInventory(adapters.NewInventory())
+
+	listArgs := []string{"--show", "--showformat", installedListFormat}
+	exec.On("Run", mock.Anything, "dpkg-query", listArgs, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "curl\t7.81.0\tinstall ok installed\n"}, nil).
+		Once()
+	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "jq"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).
+		Once()
+	exec.On("Run", mock.Anything, "dpkg-query", listArgs, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "curl\t7.81.0\tinstall ok installed\njq\t1.6\tinstall ok installed\n"}, nil).
+		Once()
+
+	err := adapter.Install(context.Background(), "jq", "")
+	assert.NoError(t, err)
+
+	info, err := adapter.DetectInstalled(context.Background(), "jq")
+	assert.NoError(t, err)
+	assert.True(t, info.Installed)
+	assert.Equal(t, "1.6", info.Version)
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_Install_InterruptedRunsRecovery(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	interrupted := &executor.InterruptedError{Command: "apt-get", Cause: context.Canceled}
+	exec.On("Run", mock.Anything, "dpkg-query", mock.AnythingOfType("[]string"), mock.Anything).
+		Return(executor.ExecResult{ExitCode: 1}, fmt.Errorf("not found")).
+		Once()
+	exec.On("Run", mock.Anything, "apt-get", []string{"install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: -1}, interrupted).
+		Once()
+	exec.On("Run", mock.Anything, "dpkg", []string{"--configure", "-a"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).
+		Once()
+	exec.On("Run", mock.Anything, "apt-get", []string{"-f", "install", "-y"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).
+		Once()
+
+	err := adapter.Install(context.Background(), "curl", "")
+	assert.Error(t, err)
+	assert.True(t, executor.IsInterrupted(err), "interruption should stay visible to callers")
+	var opErr *adapters.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Contains(t, opErr.Recovery, "dpkg --configure -a")
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_Install_InterruptedRecoveryFails(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	interrupted := &executor.InterruptedError{Command: "apt-get", Cause: context.Canceled}
+	exec.On("Run", mock.Anything, "dpkg-query", mock.AnythingOfType("[]string"), mock.Anything).
+		Return(executor.ExecResult{ExitCode: 1}, fmt.Errorf("not found")).
+		Once()
+	exec.On("Run", mock.Anything, "apt-get", mock.Anything, mock.Anything).
+		Return(executor.ExecResult{ExitCode: -1}, interrupted).
+		Once()
+	exec.On("Run", mock.Anything, "dpkg", []string{"--configure", "-a"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 2, Stderr: "dpkg: error"}, nil).
+		Once()
+
+	err := adapter.Install(context.Background(), "curl", "")
+	var opErr *adapters.OperationError
+	require.ErrorAs(t, err, &opErr)
+	assert.Empty(t, opErr.Recovery, "a failed repair must not be reported as done")
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_Install_LockTimeout(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+	adapter.SetLockTimeout(2 * time.Minute)
+
+	exec.On("Run", mock.Anything, "dpkg-query", mock.AnythingOfType("[]string"), mock.Anything).
+		Return(executor.ExecResult{ExitCode: 1}, fmt.Errorf("not found")).
+		Once()
+	exec.On("Run", mock.Anything, "apt-get",
+		[]string{"-o", "DPkg::Lock::Timeout=120", "install", "-y", "--no-install-recommends", "-o", "APT::Status-Fd=3", "curl"},
+		mock.MatchedBy(func(opts executor.ExecOpts) bool { return opts.Timeout == 12*time.Minute })).
+		Return(executor.ExecResult{ExitCode: 0}, nil).
+		Once()
+
+	require.NoError(t, adapter.Install(context.Background(), "curl", ""))
+
+	exec.AssertExpectations(t)
+}
+
+const curlPolicy = `curl:
+  Installed: 7.81.0-1ubuntu1.15
+  Candidate: 7.81.0-1ubuntu1.16
+  Version table:
+     7.81.0-1ubuntu1.16 500
+        500 http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages
+ *** 7.81.0-1ubuntu1.15 100
+        100 /var/lib/dpkg/status
+     7.81.0-1 500
+        500 http://archive.ubuntu.com/ubuntu jammy/main amd64 Packages
+`
+
+func TestParsePolicy(t *testing.T) {
+	candidates := parsePolicy(curlPolicy)
+
+	assert.Equal(t, "7.81.0-1ubuntu1.16", candidates.Candidate)
+	assert.Equal(t, []string{"7.81.0-1ubuntu1.16", "7.81.0-1ubuntu1.15", "7.81.0-1"}, candidates.Available)
+}
+
+func TestAptAdapter_Candidates(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-cache", []string{"policy", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: curlPolicy}, nil).Once()
+	exec.On("Run", mock.Anything, "apt-cache", []string{"policy", "nosuchpkg"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).Once()
+	exec.On("Run", mock.Anything, "apt-cache", []string{"policy", "virtual"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "virtual:\n  Installed: (none)\n  Candidate: (none)\n  Version table:\n"}, nil).Once()
+
+	candidates, err := adapter.Candidates(context.Background(), "curl")
+	assert.NoError(t, err)
+	assert.Equal(t, "7.81.0-1ubuntu1.16", candidates.Candidate)
+
+	_, err = adapter.Candidates(context.Background(), "nosuchpkg")
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+
+	_, err = adapter.Candidates(context.Background(), "virtual")
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_Upgrade(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-get",
+		[]string{"install", "-y", "--only-upgrade", "--no-install-recommends", "-o", "APT::Status-Fd=3", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).Once()
+
+	assert.NoError(t, adapter.Upgrade(context.Background(), "curl", adapters.PackageTypeAuto))
+	exec.AssertExpectations(t)
+}
+
+const nginxSimulation = `NOTE: This is only a simulation!
+      apt-get needs root privileges for real execution.
+Reading package lists...
+The following packages will be REMOVED:
+  apache2
+The following NEW packages will be installed:
+  libnginx-mod-http-geoip2 nginx nginx-common
+Remv apache2 [2.4.52-1ubuntu4.7]
+Inst nginx-common (1.18.0-6ubuntu14.4 Ubuntu:22.04/jammy-updates [all])
+Inst libnginx-mod-http-geoip2 (1.18.0-6ubuntu14.4 Ubuntu:22.04/jammy-updates [amd64])
+Inst libssl3 [3.0.2-0ubuntu1.10] (3.0.2-0ubuntu1.15 Ubuntu:22.04/jammy-updates [amd64])
+Inst nginx (1.18.0-6ubuntu14.4 Ubuntu:22.04/jammy-updates [amd64])
+Conf nginx-common (1.18.0-6ubuntu14.4 Ubuntu:22.04/jammy-updates [all])
+Conf libnginx-mod-http-geoip2 (1.18.0-6ubuntu14.4 Ubuntu:22.04/jammy-updates [amd64])
+Conf libssl3 (3.0.2-0ubuntu1.15 Ubuntu:22.04/jammy-updates [amd64])
+Conf nginx (1.18.0-6ubuntu14.4 Ubuntu:22.04/jammy-updates [amd64])
+`
+
+func TestParseSimulation(t *testing.T) {
+	simulation := parseSimulation(nginxSimulation)
+
+	assert.Equal(t, []adapters.SimulatedPackage{
+		{Name: "nginx-common", Version: "1.18.0-6ubuntu14.4"},
+		{Name: "libnginx-mod-http-geoip2", Version: "1.18.0-6ubuntu14.4"},
+		{Name: "libssl3", Version: "3.0.2-0ubuntu1.15"},
+		{Name: "nginx", Version: "1.18.0-6ubuntu14.4"},
+	}, simulation.Installs)
+	assert.Equal(t, []adapters.SimulatedPackage{{Name: "apache2", Version: "2.4.52-1ubuntu4.7"}}, simulation.Removals)
+
+	simulation = parseSimulation("Purg curl [7.81.0-1ubuntu1.16]\nRemv libcurl4 [7.81.0-1ubuntu1.16]\n")
+	assert.Empty(t, simulation.Installs)
+	assert.Equal(t, []adapters.SimulatedPackage{
+		{Name: "curl", Version: "7.81.0-1ubuntu1.16"},
+		{Name: "libcurl4", Version: "7.81.0-1ubuntu1.16"},
+	}, simulation.Removals)
+
+	simulation = parseSimulation("curl is already the newest version (7.81.0-1ubuntu1.16).\n")
+	assert.Empty(t, simulation.Installs)
+	assert.Empty(t, simulation.Removals)
+}
+
+func TestAptAdapter_Simulate(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-get",
+		[]string{"-s", "install", "-y", "--no-install-recommends", "nginx=1.18.0-6ubuntu14.4"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: nginxSimulation}, nil).Once()
+	exec.On("Run", mock.Anything, "apt-get", []string{"-s", "remove", "-y", "nginx"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "Remv nginx [1.18.0-6ubuntu14.4]\n"}, nil).Once()
+	exec.On("Run", mock.Anything, "apt-get", []string{"-s", "install", "-y", "--no-install-recommends", "nosuchpkg"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 100, Stderr: "E: Unable to locate package nosuchpkg"}, nil).Once()
+
+	simulation, err := adapter.SimulateInstall(context.Background(), "nginx", "1.18.0-6ubuntu14.4")
+	assert.NoError(t, err)
+	assert.Len(t, simulation.Installs, 4)
+	assert.Len(t, simulation.Removals, 1)
+
+	simulation, err = adapter.SimulateRemove(context.Background(), "nginx")
+	assert.NoError(t, err)
+	assert.Equal(t, []adapters.SimulatedPackage{{Name: "nginx", Version: "1.18.0-6ubuntu14.4"}}, simulation.Removals)
+
+	_, err = adapter.SimulateInstall(context.Background(), "nosuchpkg", "")
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+
+	exec.AssertExpectations(t)
+}
+
+func TestParseVerify(t *testing.T) {
+	output := "??5??????   /usr/bin/curl\n" +
+		"missing     /usr/share/doc/curl/copyright\n" +
+		"??5?????? c /etc/curlrc\n" +
+		"?????????   /usr/lib/x86_64-linux-gnu/libcurl.so.4\n"
+
+	assert.Equal(t, []string{
+		"/usr/bin/curl has been modified",
+		"/usr/share/doc/curl/copyright is missing",
+		"/usr/lib/x86_64-linux-gnu/libcurl.so.4 differs from the package (?????????)",
+	}, parseVerify(output))
+	assert.Empty(t, parseVerify(""))
+}
+
+func TestUnsatisfiedDependencies(t *testing.T) {
+	installed := parseDpkgInstalledList("libc6\t2.35\tinstall ok installed\t\n" +
+		"postfix\t3.6.4\tinstall ok installed\tmail-transport-agent, default-mta (= 3.6.4)\n" +
+		"libssl3\t3.0.2\tdeinstall ok config-files\t\n")
+	dependencies := parseDependencies(map[string]string{
+		"Depends": "libc6 (>= 2.34), libssl3:amd64 (>= 3.0.0), default-mta | mail-transport-agent, " +
+			"libzstd1 | libzstd",
+		"Recommends": "ca-certificates",
+	})
+
+	assert.Equal(t, []string{"libssl3", "libzstd1 | libzstd"},
+		unsatisfiedDependencies(dependencies, providedPackages(installed)))
+}
+
+func TestAptAdapter_MissingDependencies(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+	adapter.SetInventory(adapters.NewInventory())
+
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--show", "--showformat", installedListFormat}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "curl\t7.81.0\tinstall ok installed\t\t\t" +
+			"libc6 (>= 2.34), libcurl4 (= 7.81.0-1ubuntu1.16)\t\t\n" +
+			"wget\t1.21.2\tinstall ok installed\t\t\tlibc6, libssl3\t\t\n" +
+			"libc6\t2.35\tinstall ok installed\t\t\t\t\t\n"}, nil).Once()
+
+	missing, err := adapter.MissingDependencies(context.Background(), "curl")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"libcurl4"}, missing)
+
+	// Further packages are checked against the same listing
+	missing, err = adapter.MissingDependencies(context.Background(), "wget")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"libssl3"}, missing)
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_Reinstall(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-get",
+		[]string{"install", "-y", "--reinstall", "--no-install-recommends", "-o", "APT::Status-Fd=3", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0}, nil).Once()
+
+	assert.NoError(t, adapter.Reinstall(context.Background(), "curl", adapters.PackageTypeAuto))
+	exec.AssertExpectations(t)
+}
+
+func TestParseDpkgMetadata(t *testing.T) {
+	metadata := parseDpkgMetadata("command line tool for transferring data with URL syntax\thttps://curl.se/\t454\n")
+	assert.Equal(t, &adapters.PackageMetadata{
+		Description:   "command line tool for transferring data with URL syntax",
+		Homepage:      "https://curl.se/",
+		InstalledSize: 454 * 1024,
+	}, metadata)
+
+	assert.Equal(t, &adapters.PackageMetadata{}, parseDpkgMetadata(""))
+}
+
+func TestParseCopyrightLicenses(t *testing.T) {
+	copyright := `Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/
+Upstream-Name: curl
+
+Files: *
+Copyright: 1996-2022, Daniel Stenberg <daniel@haxx.se>
+License: curl
+
+Files: lib/krb5.c
+License: BSD-3-Clause
+
+Files: debian/*
+License: curl
+`
+	assert.Equal(t, "curl, BSD-3-Clause", parseCopyrightLicenses(copyright))
+	assert.Equal(t, "", parseCopyrightLicenses("This package was debianized by someone.\n"))
+}
+
+func TestParseRecursiveDepends(t *testing.T) {
+	output := `curl
+  Depends: libc6
+  Depends: libcurl4
+ |Depends: <libssl>
+  Depends: zlib1g
+libc6
+  Depends: libgcc-s1
+libcurl4
+  Depends: libc6
+<libssl>
+libgcc-s1
+zlib1g
+`
+	assert.Equal(t, []string{"libc6", "libcurl4", "zlib1g", "libgcc-s1"}, parseRecursiveDepends(output, "curl"))
+}
+
+func TestAptAdapter_Files(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--listfiles", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "/.\n/usr\n/usr/bin\n/usr/bin/curl\n" +
+			"diverted by dash to: /usr/share/man/man1/sh.distrib.1.gz\n"}, nil).Once()
+
+	files, err := adapter.Files(context.Background(), "curl")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/usr", "/usr/bin", "/usr/bin/curl"}, files)
+	exec.AssertExpectations(t)
+}
+
+func TestParseDpkgSearch(t *testing.T) {
+	output := "diversion by dash from: /bin/sh\ndiversion by dash to: /bin/sh.distrib\n" +
+		"libc6:amd64, libc6:i386, libc-bin: /usr/lib\ncoreutils: /usr/lib/coreutils\n"
+	assert.Equal(t, []string{"libc6", "libc-bin"}, parseDpkgSearch(output, "/usr/lib"))
+	assert.Empty(t, parseDpkgSearch("diversion by dash from: /bin/sh\n", "/bin/sh"))
+}
+
+func TestUsrMergeAlias(t *testing.T) {
+	assert.Equal(t, "/bin/ls", usrMergeAlias("/usr/bin/ls"))
+	assert.Equal(t, "/usr/sbin/nginx", usrMergeAlias("/sbin/nginx"))
+	assert.Equal(t, "/usr/lib64/ld.so", usrMergeAlias("/lib64/ld.so"))
+	assert.Empty(t, usrMergeAlias("/usr/share/doc/curl"))
+	assert.Empty(t, usrMergeAlias("/binary/file"))
+}
+
+func TestAptAdapter_FileOwner(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+	notFound := executor.ExecResult{ExitCode: 1, Stderr: "dpkg-query: no path found matching pattern /x\n"}
+
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--search", "/nonexistent/usr/bin/curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "curl: /nonexistent/usr/bin/curl\n"}, nil).Once()
+	owner, err := adapter.FileOwner(context.Background(), "/nonexistent/usr/bin/curl")
+	assert.NoError(t, err)
+	assert.Equal(t, &adapters.FileOwnership{Packages: []string{"curl"}, Path: "/nonexistent/usr/bin/curl"}, owner)
+
+	// Merged-/usr paths fall back to the root-level path the package ships.
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--search", "/usr/sbin/no-such-tool"}, mock.Anything).
+		Return(notFound, nil).Once()
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--search", "/sbin/no-such-tool"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "tool:amd64: /sbin/no-such-tool\n"}, nil).Once()
+	owner, err = adapter.FileOwner(context.Background(), "/usr/sbin/no-such-tool")
+	assert.NoError(t, err)
+	assert.Equal(t, &adapters.FileOwnership{Packages: []string{"tool"}, Path: "/sbin/no-such-tool"}, owner)
+
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--search", "/opt/unowned"}, mock.Anything).
+		Return(notFound, nil).Once()
+	_, err = adapter.FileOwner(context.Background(), "/opt/unowned")
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--search", "/opt/locked"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 2, Stderr: "dpkg-query: error: failed to open package info file"}, nil).Once()
+	_, err = adapter.FileOwner(context.Background(), "/opt/locked")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, adapters.ErrNotFound)
+	exec.AssertExpectations(t)
+}
+
+const upgradablePolicies = `curl:
+  Installed: 7.81.0-1ubuntu1.15
+  Candidate: 7.81.0-1ubuntu1.16
+  Version table:
+     7.81.0-1ubuntu1.16 500
+        500 http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages
+        500 http://security.ubuntu.com/ubuntu jammy-security/main amd64 Packages
+ *** 7.81.0-1ubuntu1.15 100
+        100 /var/lib/dpkg/status
+     7.81.0-1 500
+        500 http://archive.ubuntu.com/ubuntu jammy/main amd64 Packages
+jq:
+  Installed: 1.6-2.1ubuntu3
+  Candidate: 1.6-2.1ubuntu3
+  Version table:
+ *** 1.6-2.1ubuntu3 500
+        500 http://archive.ubuntu.com/ubuntu jammy/universe amd64 Packages
+        100 /var/lib/dpkg/status
+nginx:
+  Installed: 1.18.0-6ubuntu14.3
+  Candidate: 1.18.0-6ubuntu14.4
+  Version table:
+     1.18.0-6ubuntu14.4 500
+        500 http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages
+ *** 1.18.0-6ubuntu14.3 100
+        100 /var/lib/dpkg/status
+openssl:
+  Installed: 1.1.1n-0+deb10u5
+  Candidate: 1.1.1n-0+deb10u6
+  Version table:
+     1.1.1n-0+deb10u6 500
+        500 http://security.debian.org/debian-security buster/updates/main amd64 Packages
+ *** 1.1.1n-0+deb10u5 100
+        100 /var/lib/dpkg/status
+`
+
+func TestParseUpgradablePolicies(t *testing.T) {
+	assert.Equal(t, []adapters.AvailableUpdate{
+		{Name: "curl", CurrentVersion: "7.81.0-1ubuntu1.15", LatestVersion: "7.81.0-1ubuntu1.16",
+			Security: true, Origin: "jammy-updates,jammy-security"},
+		{Name: "nginx", CurrentVersion: "1.18.0-6ubuntu14.3", LatestVersion: "1.18.0-6ubuntu14.4",
+			Origin: "jammy-updates"},
+		{Name: "openssl", CurrentVersion: "1.1.1n-0+deb10u5", LatestVersion: "1.1.1n-0+deb10u6",
+			Security: true, Origin: "buster/updates"},
+	}, parseUpgradablePolicies(upgradablePolicies))
+	assert.Empty(t, parseUpgradablePolicies(""))
+}
+
+func TestAptAdapter_ListUpdates(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "/usr/bin/apt-get", "/usr/bin/dpkg-query", "/usr/bin/apt-cache")
+
+	exec.On("Run", mock.Anything, "/usr/bin/dpkg-query", []string{"--show", "--showformat", installedListFormat}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "curl\t7.81.0-1ubuntu1.15\tinstall ok installed\n" +
+			"jq\t1.6-2.1ubuntu3\tinstall ok installed\n" +
+			"nginx\t1.18.0-6ubuntu14.3\thold ok installed\n" +
+			"openssl\t1.1.1n-0+deb10u5\tinstall ok installed\n"}, nil).Once()
+	exec.On("Run", mock.Anything, "/usr/bin/apt-cache", []string{"policy", "curl", "jq", "nginx", "openssl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: upgradablePolicies}, nil).Once()
+
+	updates, err := adapter.ListUpdates(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, updates, 3)
+	assert.False(t, updates[0].Pinned)
+	assert.True(t, updates[1].Pinned)
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_ListManuallyInstalled(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "/usr/bin/apt-get", "", "")
+
+	exec.On("Run", mock.Anything, "/usr/bin/apt-mark", []string{"showmanual"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "curl\nnginx\n"}, nil).Once()
+
+	manual, err := adapter.ListManuallyInstalled(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"curl", "nginx"}, manual)
+	exec.AssertExpectations(t)
+}
+
+const curlShow = `Package: curl
+Version: 7.81.0-1ubuntu1.16
+Priority: optional
+Pre-Depends: libc6 (>= 2.34)
+Depends: libcurl4 (= 7.81.0-1ubuntu1.16), zlib1g (>= 1:1.1.4)
+Recommends: ca-certificates
+Suggests: curl-doc | wget
+Description: command line tool for transferring data with URL syntax
+ This is a command line tool and library for transferring data with URLs.
+`
+
+func TestParseControl(t *testing.T) {
+	control := parseControl(curlShow + "\nPackage: ignored\n")
+	assert.Equal(t, "curl", control["Package"])
+	assert.Equal(t, "libc6 (>= 2.34)", control["Pre-Depends"])
+	assert.Equal(t, "command line tool for transferring data with URL syntax\n"+
+		"This is a command line tool and library for transferring data with URLs.", control["Description"])
+}
+
+func TestParseDependencies(t *testing.T) {
+	assert.Equal(t, []adapters.Dependency{
+		{Name: "libc6", Constraint: ">= 2.34", Type: adapters.DependencyRuntime, Relation: "Pre-Depends"},
+		{Name: "libcurl4", Constraint: "= 7.81.0-1ubuntu1.16", Type: adapters.DependencyRuntime, Relation: "Depends"},
+		{Name: "zlib1g", Constraint: ">= 1:1.1.4", Type: adapters.DependencyRuntime, Relation: "Depends"},
+		{Name: "ca-certificates", Type: adapters.DependencyOptional, Relation: "Recommends"},
+		{Name: "curl-doc", Type: adapters.DependencyOptional, Relation: "Suggests", Alternatives: []string{"wget"}},
+	}, parseDependencies(parseControl(curlShow)))
+	assert.Empty(t, parseDependencies(map[string]string{}))
+}
+
+func TestAptAdapter_Dependencies(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-cache", []string{"show", "--no-all-versions", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: curlShow}, nil).Once()
+	exec.On("Run", mock.Anything, "apt-cache", []string{"show", "--no-all-versions", "missing"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 100, Stderr: "E: No packages found"}, nil).Once()
+
+	dependencies, err := adapter.Dependencies(context.Background(), "curl")
+	assert.NoError(t, err)
+	assert.Len(t, dependencies, 5)
+
+	_, err = adapter.Dependencies(context.Background(), "missing")
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+	exec.AssertExpectations(t)
+}
+
+const reverseDependencyList = "curl\t7.81.0\tinstall ok installed\tcurl-provider\t\tlibc6 (>= 2.34), libcurl4 (= 7.81.0)\t\t\n" +
+	"apt-transport-https\t2.4.11\tinstall ok installed\t\t\tapt (>= 1.5)\t\t\n" +
+	"devscripts\t2.22.1\tinstall ok installed\t\t\tdpkg-dev\tcurl | wget\t\n" +
+	"git\t2.34.1\tinstall ok installed\t\t\tlibc6\t\tcurl-provider (>= 1)\n" +
+	"oldpkg\t1.0\tdeinstall ok config-files\t\t\tcurl\t\t\n"
+
+func TestReverseDependencies(t *testing.T) {
+	installed := parseDpkgInstalledList(reverseDependencyList)
+	assert.Equal(t, []adapters.Dependency{
+		{Name: "devscripts", Type: adapters.DependencyOptional, Relation: "Recommends", Alternatives: []string{"wget"}},
+		{Name: "git", Constraint: ">= 1", Type: adapters.DependencyOptional, Relation: "Suggests"},
+	}, reverseDependencies(installed, "curl"))
+	assert.Equal(t, []adapters.Dependency{}, reverseDependencies(installed, "jq"))
+}
+
+func TestAptAdapter_ReverseDependencies(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+	adapter.SetInventory(adapters.NewInventory())
+
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--show", "--showformat", installedListFormat}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: reverseDependencyList}, nil).Once()
+
+	dependents, err := adapter.ReverseDependencies(context.Background(), "libc6")
+	assert.NoError(t, err)
+	assert.Len(t, dependents, 2)
+	assert.Equal(t, "curl", dependents[0].Name)
+	assert.Equal(t, "Depends", dependents[0].Relation)
+	assert.Equal(t, ">= 2.34", dependents[0].Constraint)
+
+	// Walking on to the dependents reuses the same listing
+	dependents, err = adapter.ReverseDependencies(context.Background(), "curl")
+	assert.NoError(t, err)
+	assert.Len(t, dependents, 2)
+	exec.AssertExpectations(t)
+}
+
+const curlMadison = `      curl | 7.81.0-1ubuntu1.16 | http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages
+      curl | 7.81.0-1ubuntu1.16 | http://security.ubuntu.com/ubuntu jammy-security/main amd64 Packages
+      curl | 7.81.0-1ubuntu1 | http://archive.ubuntu.com/ubuntu jammy/main amd64 Packages
+      curl | 7.81.0-1ubuntu1 | http://archive.ubuntu.com/ubuntu jammy/main Sources
+  libcurl4 | 7.81.0-1ubuntu1 | http://archive.ubuntu.com/ubuntu jammy/main amd64 Packages
+`
+
+func TestParseMadison(t *testing.T) {
+	assert.Equal(t, []adapters.VersionRelease{
+		{Version: "7.81.0-1ubuntu1.16", Origin: "http://archive.ubuntu.com/ubuntu", Suite: "jammy-updates/main"},
+		{Version: "7.81.0-1ubuntu1.16", Origin: "http://security.ubuntu.com/ubuntu", Suite: "jammy-security/main"},
+		{Version: "7.81.0-1ubuntu1", Origin: "http://archive.ubuntu.com/ubuntu", Suite: "jammy/main"},
+	}, parseMadison(curlMadison, "curl"))
+	assert.Equal(t, []adapters.VersionRelease{}, parseMadison("N: Unable to locate package jq\n", "jq"))
+}
+
+func TestAptAdapter_VersionHistory(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+
+	exec.On("Run", mock.Anything, "apt-cache", []string{"madison", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: curlMadison}, nil).Once()
+
+	releases, err := adapter.VersionHistory(context.Background(), "curl")
+	assert.NoError(t, err)
+	assert.Len(t, releases, 3)
+	exec.AssertExpectations(t)
+}
+
+func TestAptAdapter_SnapshotHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mr/binary/curl/":
+			_, _ = w.Write([]byte(`{"_comment": "foo", "binary": "curl", "result": [
+				{"binary_version": "8.5.0-2", "name": "curl", "source": "curl", "version": "8.5.0-2"},
+				{"binary_version": "7.88.1-10", "name": "curl", "source": "curl", "version": "7.88.1-10"},
+				{"binary_version": "7.88.1-10", "name": "curl", "source": "curl", "version": "7.88.1-10"}
+			]}`))
+		case "/mr/binary/broken/":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	adapter := NewAptAdapter(&MockExecutor{}, "apt-get", "dpkg-query", "apt-cache")
+
+	releases, err := adapter.SnapshotHistory(context.Background(), server.URL+"/", "curl")
+	assert.NoError(t, err)
+	assert.Equal(t, []adapters.VersionRelease{
+		{Version: "8.5.0-2", Origin: server.URL},
+		{Version: "7.88.1-10", Origin: server.URL},
+	}, releases)
+
+	releases, err = adapter.SnapshotHistory(context.Background(), server.URL, "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, releases)
+
+	_, err = adapter.SnapshotHistory(context.Background(), server.URL, "broken")
+	assert.Error(t, err)
+}
+
+func TestAptAdapter_RepositoryPackages(t *testing.T) {
+	adapter := NewAptAdapter(&MockExecutor{}, "apt-get", "dpkg-query", "apt-cache")
+	adapter.listsDir = t.TempDir()
+
+	main := "Package: nodejs\nVersion: 20.11.0-1nodesource1\nDescription: JavaScript runtime\n" +
+		" Node.js is a runtime.\n\nPackage: corepack\nVersion: 0.24.0\nDescription: Package manager manager\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(adapter.listsDir,
+		"deb.nodesource.com_node%5f20.x_dists_nodistro_main_binary-amd64_Packages"), []byte(main), 0o644))
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := gz.Write([]byte("Package: nodejs\nVersion: 20.0.0\n\nPackage: npm\nVersion: 10.2.4\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+	assert.NoError(t, os.WriteFile(filepath.Join(adapter.listsDir,
+		"deb.nodesource.com_node%5f20.x_dists_nodistro_main_binary-arm64_Packages.gz"), compressed.Bytes(), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(adapter.listsDir,
+		"deb.nodesource.com_node%5f20.x_dists_nodistro_InRelease"), []byte("ignored"), 0o644))
+
+	packages, err := adapter.RepositoryPackages(context.Background(), "https://deb.nodesource.com/node_20.x/", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []adapters.RepositoryPackage{
+		{Name: "corepack", Version: "0.24.0", Description: "Package manager manager"},
+		{Name: "nodejs", Version: "20.11.0-1nodesource1", Description: "JavaScript runtime"},
+		{Name: "npm", Version: "10.2.4"},
+	}, packages)
+
+	packages, err = adapter.RepositoryPackages(context.Background(), "deb.nodesource.com/node_20.x", 1)
+	assert.NoError(t, err)
+	assert.Len(t, packages, 1)
+
+	_, err = adapter.RepositoryPackages(context.Background(), "deb.nodesource.com/node_18.x", 100)
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+}
+
+func TestParseAptVersion(t *testing.T) {
+	assert.Equal(t, "2.4.11", parseAptVersion("apt 2.4.11 (amd64)\nSupported modules:\n"))
+	assert.Equal(t, "unexpected", parseAptVersion("unexpected\n"))
+}
+
+func TestParseOSRelease(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		wantErr  bool
+	}{
+		{"debian", "PRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nID=debian\nVERSION_ID=\"12\"\n", "Debian:12", false},
+		{"ubuntu", "NAME=\"Ubuntu\"\nID=ubuntu\nID_LIKE=debian\nVERSION_ID=\"22.04\"\n", "Ubuntu:22.04", false},
+		{"debian testing", "ID=debian\n", "Debian", false},
+		{"derivative", "ID=linuxmint\nID_LIKE=\"ubuntu debian\"\nVERSION_ID=\"21.3\"\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ecosystem, err := parseOSRelease(tt.content)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ecosystem)
+		})
+	}
+}
+
+func TestAptAdapter_Ecosystem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "os-release")
+	assert.NoError(t, os.WriteFile(path, []byte("ID=debian\nVERSION_ID=\"12\"\n"), 0o600))
+	adapter := NewAptAdapter(&MockExecutor{}, "", "", "")
+	adapter.osRelease = path
+
+	ecosystem, err := adapter.Ecosystem(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Debian:12", ecosystem)
+
+	adapter.osRelease = filepath.Join(t.TempDir(), "missing")
+	_, err = adapter.Ecosystem(context.Background())
+	assert.Error(t, err)
+}
+
+func TestParseDpkgSources(t *testing.T) {
+	output := "curl\tcurl\t7.88.1-10+deb12u5\tinstall ok installed\n" +
+		"libssl3\topenssl\t3.0.11-1~deb12u2\thold ok installed\n" +
+		"removed\tremoved\t1.0\tdeinstall ok config-files\n"
+
+	assert.Equal(t, map[string]adapters.SourcePackage{
+		"curl":    {Name: "curl", Version: "7.88.1-10+deb12u5"},
+		"libssl3": {Name: "openssl", Version: "3.0.11-1~deb12u2"},
+	}, parseDpkgSources(output))
+}
+
+func TestParsePolicyOrigins(t *testing.T) {
+	output := `curl:
+  Installed: 7.81.0-1ubuntu1.15
+  Candidate: 7.81.0-1ubuntu1.16
+  Version table:
+     7.81.0-1ubuntu1.16 500
+        500 http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages
+ *** 7.81.0-1ubuntu1.15 500
+        500 http://security.ubuntu.com/ubuntu jammy-security/main amd64 Packages
+        500 http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages
+        100 /var/lib/dpkg/status
+libc6:i386:
+  Installed: 2.35-0ubuntu3.6
+  Candidate: 2.35-0ubuntu3.6
+  Version table:
+ *** 2.35-0ubuntu3.6 500
+        500 http://archive.ubuntu.com/ubuntu jammy-updates/main i386 Packages
+        100 /var/lib/dpkg/status
+local-tool:
+  Installed: 1.0
+  Candidate: 1.0
+  Version table:
+ *** 1.0 100
+        100 /var/lib/dpkg/status
+`
+
+	assert.Equal(t, map[string]string{
+		"curl":       "http://security.ubuntu.com/ubuntu jammy-security/main",
+		"libc6:i386": "http://archive.ubuntu.com/ubuntu jammy-updates/main",
+	}, parsePolicyOrigins(output))
+}
+
+func TestAptAdapter_ListComponents(t *testing.T) {
+	exec := &MockExecutor{}
+	adapter := NewAptAdapter(exec, "apt-get", "dpkg-query", "apt-cache")
+	adapter.docDir = t.TempDir()
+	adapter.osRelease = filepath.Join(t.TempDir(), "os-release")
+	assert.NoError(t, os.WriteFile(adapter.osRelease, []byte("ID=debian\nVERSION_ID=\"12\"\n"), 0o600))
+	assert.NoError(t, os.MkdirAll(filepath.Join(adapter.docDir, "curl"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(adapter.docDir, "curl", "copyright"),
+		[]byte("Files: *\nLicense: curl\n"), 0o600))
+
+	exec.On("Run", mock.Anything, "dpkg-query", []string{"--show", "--showformat", componentFormat}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "" +
+			"curl\tcurl\t7.88.1-10+deb12u5\tamd64\tAlessandro Ghedini <ghedo@debian.org>\thttps://curl.se/\tcommand line tool for transferring data with URL syntax\tinstall ok installed\n" +
+			"base-files\tbase-files\t12.4+deb12u5\tamd64\tSantiago Vila <sanvila@debian.org>\t\tDebian base system miscellaneous files\tinstall ok installed\n" +
+			"removed\tremoved\t1.0\tall\tNobody\t\tgone\tdeinstall ok config-files\n"}, nil)
+	exec.On("Run", mock.Anything, "apt-cache", []string{"policy", "base-files", "curl"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "curl:\n  Installed: 7.88.1-10+deb12u5\n  Version table:\n" +
+			" *** 7.88.1-10+deb12u5 500\n        500 http://deb.debian.org/debian bookworm/main amd64 Packages\n"}, nil)
+
+	components, err := adapter.ListComponents(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []adapters.Component{
+		{
+			Name:         "base-files",
+			Version:      "12.4+deb12u5",
+			Architecture: "amd64",
+			Purl:         "pkg:deb/debian/base-files@12.4%2Bdeb12u5?arch=amd64&distro=debian-12",
+			Supplier:     "Santiago Vila <sanvila@debian.org>",
+			Description:  "Debian base system miscellaneous files",
+		},
+		{
+			Name:             "curl",
+			Version:          "7.88.1-10+deb12u5",
+			Architecture:     "amd64",
+			Purl:             "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12",
+			License:          "curl",
+			Supplier:         "Alessandro Ghedini <ghedo@debian.org>",
+			SourceRepository: "http://deb.debian.org/debian bookworm/main",
+			Homepage:         "https://curl.se/",
+			Description:      "command line tool for transferring data with URL syntax",
+		},
+	}, components)
+}
@@ -0,0 +1,158 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// VerifyIntegrity checks the installed files of name against the checksums
+// recorded by dpkg. Locally modified configuration files are not reported.
+func (a *AptAdapter) VerifyIntegrity(ctx context.Context, name string) (_ []string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "verify", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := a.executor.Run(ctx, "dpkg", []string{"--verify", name}, executor.ExecOpts{
+		Timeout: 5 * time.Minute,
+	})
+	// dpkg exits non-zero when it finds problems, so only fail without a report
+	if err != nil || (result.ExitCode != 0 && strings.TrimSpace(result.Stdout) == "") {
+		return nil, commandError("verify", name, result, err)
+	}
+
+	return parseVerify(result.Stdout), nil
+}
+
+// parseVerify parses 'dpkg --verify' output, which uses the rpm -V format:
+// "??5??????   /usr/bin/curl", "missing     /usr/bin/curl", or with a "c"
+// marker before the path for configuration files.
+func parseVerify(output string) []string {
+	var issues []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if len(fields) == 3 && fields[1] == "c" {
+			continue
+		}
+		file := fields[len(fields)-1]
+		switch {
+		case fields[0] == "missing":
+			issues = append(issues, file+" is missing")
+		case len(fields[0]) > 2 && fields[0][2] == '5':
+			issues = append(issues, file+" has been modified")
+		default:
+			issues = append(issues, file+" differs from the package ("+fields[0]+")")
+		}
+	}
+	return issues
+}
+
+// MissingDependencies returns the Depends and Pre-Depends of name that no
+// installed package satisfies. Version constraints are not checked. The answer
+// comes from the installed package listing, which is shared through the
+// inventory when one is attached.
+func (a *AptAdapter) MissingDependencies(ctx context.Context, name string) (_ []string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "missing_dependencies", name)
+	defer func() { telemetry.End(span, err) }()
+
+	installed, err := a.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range installed {
+		if pkg.Name == name {
+			return unsatisfiedDependencies(pkg.Dependencies, providedPackages(installed)), nil
+		}
+	}
+	// A package that is not installed has nothing to satisfy
+	return nil, nil
+}
+
+// dependencyQualifier matches version constraints and architecture qualifiers
+// such as " (>= 2.34)" and ":any".
+var dependencyQualifier = regexp.MustCompile(`\s*\([^)]*\)|:\S+`)
+
+// dependencyName strips version constraints and architecture qualifiers.
+func dependencyName(dependency string) string {
+	return strings.TrimSpace(dependencyQualifier.ReplaceAllString(dependency, ""))
+}
+
+// providedPackages returns the names of installed packages and of the
+// virtual packages they provide.
+func providedPackages(installed []adapters.PackageInfo) map[string]bool {
+	provided := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		provided[pkg.Name] = true
+		for _, virtual := range pkg.Provides {
+			provided[virtual] = true
+		}
+	}
+	return provided
+}
+
+// unsatisfiedDependencies returns the Pre-Depends and Depends groups, such as
+// "default-mta | mail-transport-agent", with no provided alternative.
+func unsatisfiedDependencies(dependencies []adapters.Dependency, provided map[string]bool) []string {
+	var missing []string
+	for _, dependency := range dependencies {
+		if dependency.Relation != "Pre-Depends" && dependency.Relation != "Depends" {
+			continue
+		}
+		alternatives := append([]string{dependency.Name}, dependency.Alternatives...)
+		satisfied := false
+		for _, name := range alternatives {
+			satisfied = satisfied || provided[name]
+		}
+		if !satisfied {
+			missing = append(missing, strings.Join(alternatives, " | "))
+		}
+	}
+	return missing
+}
+
+// Reinstall reinstalls the installed version of name.
+func (a *AptAdapter) Reinstall(ctx context.Context, name string, _ adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "reinstall", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer a.inventory.Invalidate()
+
+	args := append([]string{"install", "-y", "--reinstall", "--no-install-recommends"}, statusFdArgs...)
+	args = append(args, name)
+	result, err := a.runLocked(ctx, args, progressOpts(ctx, 600*time.Second))
+	if err != nil || result.ExitCode != 0 {
+		return a.recoverIfInterrupted(ctx, commandError("reinstall", name, result, err))
+	}
+
+	return nil
+}
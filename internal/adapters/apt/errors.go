@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// aptErrorPatterns recognize apt-get and dpkg failure messages. Permission
+// errors come before lock errors because apt reports a lock it cannot open
+// for lack of privileges as both.
+var aptErrorPatterns = []adapters.OutputPattern{
+	{Kind: adapters.ErrPermissionDenied, Substrings: []string{
+		"are you root?",
+		"(13: Permission denied)",
+		"Permission denied",
+		"This command has to be run with superuser privileges",
+	}},
+	{Kind: adapters.ErrLockHeld, Substrings: []string{
+		"Could not get lock",
+		"Unable to acquire the dpkg frontend lock",
+		"Unable to lock the administration directory",
+		"is another process using it?",
+	}},
+	{Kind: adapters.ErrVersionUnavailable, Substrings: []string{
+		"' was not found",
+	}},
+	{Kind: adapters.ErrNotFound, Substrings: []string{
+		"Unable to locate package",
+		"has no installation candidate",
+		"No packages found",
+		"is not installed, so not removed",
+		"no path found matching pattern",
+	}},
+	{Kind: adapters.ErrDependencyConflict, Substrings: []string{
+		"Unmet dependencies",
+		"you have held broken packages",
+		"but it is not going to be installed",
+		"Conflicts:",
+		"Breaks:",
+		"dependency problems",
+	}},
+	{Kind: adapters.ErrNetwork, Substrings: []string{
+		"Temporary failure resolving",
+		"Could not resolve",
+		"Failed to fetch",
+		"Could not connect to",
+		"Connection timed out",
+		"Connection failed",
+		"Network is unreachable",
+		"Hash Sum mismatch",
+	}},
+}
+
+// classifyAptOutput maps apt-get output to an adapter error kind.
+func classifyAptOutput(stdout, stderr string) error {
+	return adapters.MatchOutput(aptErrorPatterns, stderr+"\n"+stdout)
+}
+
+// commandError builds a typed error for a failed apt command.
+func commandError(operation, target string, result executor.ExecResult, err error) error {
+	return adapters.NewOperationError("apt", operation, target, result, err, classifyAptOutput)
+}
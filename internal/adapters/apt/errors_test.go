@@ -0,0 +1,37 @@
+package apt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestClassifyAptOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   error
+	}{
+		{"lock", "E: Could not get lock /var/lib/dpkg/lock-frontend. It is held by process 1234 (unattended-upgr)",
+			adapters.ErrLockHeld},
+		{"not root", "E: Could not open lock file /var/lib/dpkg/lock-frontend - open (13: Permission denied)\n" +
+			"E: Unable to acquire the dpkg frontend lock (/var/lib/dpkg/lock-frontend), are you root?",
+			adapters.ErrPermissionDenied},
+		{"unknown package", "E: Unable to locate package nosuchpkg", adapters.ErrNotFound},
+		{"no candidate", "E: Package 'python' has no installation candidate", adapters.ErrNotFound},
+		{"version", "E: Version '9.9' for 'curl' was not found", adapters.ErrVersionUnavailable},
+		{"held broken", "E: Unable to correct problems, you have held broken packages.",
+			adapters.ErrDependencyConflict},
+		{"network", "W: Failed to fetch http://archive.ubuntu.com/ubuntu/dists/jammy/InRelease  " +
+			"Temporary failure resolving 'archive.ubuntu.com'", adapters.ErrNetwork},
+		{"unrecognized", "E: Sub-process /usr/bin/dpkg returned an error code (1)", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyAptOutput("", tt.stderr))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// statusFdArgs asks apt-get to write machine-readable progress to the status
+// pipe the executor opens as file descriptor 3.
+var statusFdArgs = []string{"-o", "APT::Status-Fd=3"}
+
+// maxCapturedOutput bounds the output kept from long-running apt-get commands.
+const maxCapturedOutput = 1 << 20
+
+// progressOpts returns ExecOpts that stream apt-get's status-fd progress as
+// ProgressEvents while the command runs.
+func progressOpts(ctx context.Context, timeout time.Duration) executor.ExecOpts {
+	return executor.ExecOpts{
+		Timeout:        timeout,
+		StatusFd:       true,
+		MaxOutputBytes: maxCapturedOutput,
+		OnOutput: func(line executor.OutputLine) {
+			if line.Stream != executor.StreamStatus {
+				return
+			}
+			if event, ok := parseAptStatusLine(line.Text); ok {
+				adapters.ReportProgress(ctx, event)
+			}
+		},
+	}
+}
+
+// parseAptStatusLine parses an APT::Status-Fd line such as
+// "pmstatus:curl:42.8571:Unpacking curl (amd64)" or
+// "dlstatus:1:9.53:Retrieving file 1 of 2".
+func parseAptStatusLine(line string) (adapters.ProgressEvent, bool) {
+	parts := strings.SplitN(strings.TrimSpace(line), ":", 4)
+	if len(parts) < 4 {
+		return adapters.ProgressEvent{}, false
+	}
+
+	event := adapters.ProgressEvent{
+		Manager: "apt",
+		Package: parts[1],
+		Percent: -1,
+		Message: parts[3],
+	}
+	if percent, err := strconv.ParseFloat(parts[2], 64); err == nil {
+		event.Percent = percent
+	}
+
+	switch parts[0] {
+	case "dlstatus":
+		// The second field is a download counter, not a package name
+		event.Phase = adapters.ProgressPhaseDownload
+		event.Package = ""
+	case "pmstatus":
+		event.Phase = aptPhaseFromMessage(event.Message)
+	case "pmerror":
+		event.Phase = adapters.ProgressPhaseError
+	default:
+		return adapters.ProgressEvent{}, false
+	}
+
+	return event, true
+}
+
+// aptPhaseFromMessage maps dpkg action descriptions to progress phases.
+func aptPhaseFromMessage(message string) string {
+	switch {
+	case strings.HasPrefix(message, "Unpacking"), strings.HasPrefix(message, "Preparing"):
+		return adapters.ProgressPhaseUnpack
+	case strings.HasPrefix(message, "Configuring"), strings.HasPrefix(message, "Setting up"):
+		return adapters.ProgressPhaseConfigure
+	case strings.HasPrefix(message, "Removing"), strings.HasPrefix(message, "Removed"):
+		return adapters.ProgressPhaseRemove
+	case strings.HasPrefix(message, "Installed"):
+		return adapters.ProgressPhaseComplete
+	default:
+		return adapters.ProgressPhaseInstall
+	}
+}
@@ -0,0 +1,41 @@
+package apt
+
+import (
+	"testing"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAptStatusLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		ok      bool
+		phase   string
+		pkg     string
+		percent float64
+	}{
+		{"dlstatus:1:9.5346:Retrieving file 1 of 2", true, adapters.ProgressPhaseDownload, "", 9.5346},
+		{"pmstatus:curl:42.8571:Unpacking curl (amd64)", true, adapters.ProgressPhaseUnpack, "curl", 42.8571},
+		{"pmstatus:curl:71.4286:Configuring curl (amd64)", true, adapters.ProgressPhaseConfigure, "curl", 71.4286},
+		{"pmstatus:curl:100:Installed curl (amd64)", true, adapters.ProgressPhaseComplete, "curl", 100},
+		{"pmerror:broken:50:subprocess installed post-installation script returned error exit status 1", true,
+			adapters.ProgressPhaseError, "broken", 50},
+		{"pmconffile:/etc/foo:50:'/etc/foo' '/etc/foo.dpkg-new' 1 1", false, "", "", 0},
+		{"Reading package lists...", false, "", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			event, ok := parseAptStatusLine(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			if !tt.ok {
+				return
+			}
+			assert.Equal(t, "apt", event.Manager)
+			assert.Equal(t, tt.phase, event.Phase)
+			assert.Equal(t, tt.pkg, event.Package)
+			assert.InDelta(t, tt.percent, event.Percent, 0.0001)
+		})
+	}
+}
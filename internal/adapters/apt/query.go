@@ -0,0 +1,553 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// dependencyFields maps the control file relationship fields read by
+// Dependencies to the dependency type they declare. Recommends count as
+// optional because the provider installs with --no-install-recommends.
+var dependencyFields = []struct {
+	field   string
+	depType adapters.DependencyType
+}{
+	{"Pre-Depends", adapters.DependencyRuntime},
+	{"Depends", adapters.DependencyRuntime},
+	{"Recommends", adapters.DependencyOptional},
+	{"Suggests", adapters.DependencyOptional},
+}
+
+// Dependencies returns the relationships declared by the candidate version of
+// name, as shown by 'apt-cache show'. Debian packages have no build
+// dependencies. For a group of alternatives such as "a | b", the first is
+// the dependency apt installs and the rest are reported as its alternatives.
+func (a *AptAdapter) Dependencies(ctx context.Context, name string) (_ []adapters.Dependency, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "dependencies", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := a.executor.Run(ctx, a.aptCachePath, []string{"show", "--no-all-versions", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query dependencies of", name, result, err)
+	}
+
+	return parseDependencies(parseControl(result.Stdout)), nil
+}
+
+// parseControl parses the first stanza of a Debian control file into its
+// fields. Continuation lines are joined to their field with newlines.
+func parseControl(output string) map[string]string {
+	fields := map[string]string{}
+	last := ""
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(fields) > 0 {
+				return fields
+			}
+		case line[0] == ' ' || line[0] == '\t':
+			if last != "" {
+				fields[last] += "\n" + strings.TrimSpace(line)
+			}
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			last = key
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+// dependencyConstraint matches the version constraint of a relationship, such
+// as "(>= 2.34)".
+var dependencyConstraint = regexp.MustCompile(`\(\s*([<>=]+)\s*([^)\s]+)\s*\)`)
+
+// relationAlternative is one alternative of a relationship group, such as
+// "libcurl4 (>= 7.81)" in "libcurl4 (>= 7.81) | libcurl3".
+type relationAlternative struct {
+	name       string
+	constraint string
+}
+
+// parseRelationGroups splits a relationship field into its comma-separated
+// groups of "|"-separated alternatives.
+func parseRelationGroups(value string) [][]relationAlternative {
+	var groups [][]relationAlternative
+	for _, group := range strings.Split(value, ",") {
+		var alternatives []relationAlternative
+		for _, alternative := range strings.Split(group, "|") {
+			name := dependencyName(alternative)
+			if name == "" {
+				continue
+			}
+
+			constraint := ""
+			if match := dependencyConstraint.FindStringSubmatch(alternative); match != nil {
+				constraint = match[1] + " " + match[2]
+			}
+			alternatives = append(alternatives, relationAlternative{name: name, constraint: constraint})
+		}
+		if len(alternatives) > 0 {
+			groups = append(groups, alternatives)
+		}
+	}
+	return groups
+}
+
+// otherAlternatives returns the names in group other than the one at index.
+func otherAlternatives(group []relationAlternative, index int) []string {
+	var names []string
+	for i, alternative := range group {
+		if i != index {
+			names = append(names, alternative.name)
+		}
+	}
+	return names
+}
+
+// parseDependencies converts control file relationship fields to dependencies.
+func parseDependencies(control map[string]string) []adapters.Dependency {
+	dependencies := []adapters.Dependency{}
+	for _, relation := range dependencyFields {
+		for _, group := range parseRelationGroups(control[relation.field]) {
+			dependencies = append(dependencies, adapters.Dependency{
+				Name:         group[0].name,
+				Constraint:   group[0].constraint,
+				Type:         relation.depType,
+				Relation:     relation.field,
+				Alternatives: otherAlternatives(group, 0),
+			})
+		}
+	}
+	return dependencies
+}
+
+// ReverseDependencies returns the installed packages with a relationship on
+// name, or on a virtual package name provides. The relationships come from
+// the installed package listing, so with an inventory attached a recursive
+// walk reads the dpkg database once rather than once per package.
+func (a *AptAdapter) ReverseDependencies(ctx context.Context, name string) (_ []adapters.Dependency, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "reverse_dependencies", name)
+	defer func() { telemetry.End(span, err) }()
+
+	installed, err := a.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return reverseDependencies(installed, name), nil
+}
+
+// reverseDependencies finds the installed packages whose relationships can be
+// satisfied by name. The version constraint is only known when name is the
+// first alternative of a relationship.
+func reverseDependencies(installed []adapters.PackageInfo, name string) []adapters.Dependency {
+	targets := map[string]bool{name: true}
+	for _, pkg := range installed {
+		if pkg.Name == name {
+			for _, virtual := range pkg.Provides {
+				targets[virtual] = true
+			}
+		}
+	}
+
+	dependents := []adapters.Dependency{}
+	for _, pkg := range installed {
+		if pkg.Name == name {
+			continue
+		}
+		for _, dependency := range pkg.Dependencies {
+			alternatives := append([]string{dependency.Name}, dependency.Alternatives...)
+			for j, alternative := range alternatives {
+				if !targets[alternative] {
+					continue
+				}
+				dependent := adapters.Dependency{
+					Name:     pkg.Name,
+					Type:     dependency.Type,
+					Relation: dependency.Relation,
+				}
+				if j == 0 {
+					dependent.Constraint = dependency.Constraint
+				}
+				for k, other := range alternatives {
+					if k != j {
+						dependent.Alternatives = append(dependent.Alternatives, other)
+					}
+				}
+				dependents = append(dependents, dependent)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// VersionHistory returns every version of name available from the configured
+// sources, with the source and suite publishing it, as shown by
+// 'apt-cache madison'.
+func (a *AptAdapter) VersionHistory(ctx context.Context, name string) (_ []adapters.VersionRelease, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "version_history", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := a.executor.Run(ctx, a.aptCachePath, []string{"madison", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query versions of", name, result, err)
+	}
+
+	return parseMadison(result.Stdout, name), nil
+}
+
+// parseMadison parses 'apt-cache madison' lines such as
+// "curl | 7.81.0-1ubuntu1.16 | http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages",
+// skipping source package entries and other packages matched by name.
+func parseMadison(output, name string) []adapters.VersionRelease {
+	releases := []adapters.VersionRelease{}
+	for _, line := range strings.Split(output, "\n") {
+		columns := strings.Split(line, "|")
+		if len(columns) != 3 || strings.TrimSpace(columns[0]) != name {
+			continue
+		}
+
+		source := strings.Fields(columns[2])
+		if len(source) == 0 || source[len(source)-1] == "Sources" {
+			continue
+		}
+		release := adapters.VersionRelease{
+			Version: strings.TrimSpace(columns[1]),
+			Origin:  source[0],
+		}
+		if len(source) > 1 {
+			release.Suite = source[1]
+		}
+		releases = append(releases, release)
+	}
+	return releases
+}
+
+// snapshotBinary is the response of the snapshot.debian.org machine-readable
+// interface for /mr/binary/<name>/.
+type snapshotBinary struct {
+	Result []struct {
+		BinaryVersion string `json:"binary_version"`
+		Source        string `json:"source"`
+	} `json:"result"`
+}
+
+// SnapshotHistory lists the versions of the binary package name in a
+// snapshot.debian.org-style archive, using its machine-readable interface.
+func (a *AptAdapter) SnapshotHistory(ctx context.Context, baseURL, name string) (_ []adapters.VersionRelease, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "snapshot_history", name)
+	defer func() { telemetry.End(span, err) }()
+
+	endpoint := strings.TrimRight(baseURL, "/") + "/mr/binary/" + url.PathEscape(name) + "/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot archive URL %s: %w", baseURL, err)
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshot archive %s: %w: %w", baseURL, err, adapters.ErrNetwork)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return []adapters.VersionRelease{}, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("snapshot archive %s returned %s for %s", baseURL, resp.Status, name)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot archive response: %w", err)
+	}
+	return parseSnapshotBinary(body, strings.TrimRight(baseURL, "/"))
+}
+
+// parseSnapshotBinary converts a /mr/binary/<name>/ response to releases from
+// origin, keeping the archive's newest-first order.
+func parseSnapshotBinary(body []byte, origin string) ([]adapters.VersionRelease, error) {
+	var binary snapshotBinary
+	if err := json.Unmarshal(body, &binary); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot archive response: %w", err)
+	}
+
+	releases := []adapters.VersionRelease{}
+	seen := map[string]bool{}
+	for _, result := range binary.Result {
+		if result.BinaryVersion == "" || seen[result.BinaryVersion] {
+			continue
+		}
+		seen[result.BinaryVersion] = true
+		releases = append(releases, adapters.VersionRelease{Version: result.BinaryVersion, Origin: origin})
+	}
+	return releases, nil
+}
+
+// SearchCatalog searches the names and descriptions of the packages in the
+// downloaded indexes with 'apt-cache search --full', reporting each package's
+// candidate version and whether it is installed.
+func (a *AptAdapter) SearchCatalog(
+	ctx context.Context, pattern string, keep func(name string) bool) (_ []adapters.SearchResult, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "search", pattern)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := a.executor.Run(ctx, a.aptCachePath, []string{"search", "--full", pattern}, executor.ExecOpts{
+		Timeout: 60 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("search packages matching", pattern, result, err)
+	}
+
+	results := adapters.KeepSearchResults(parseSearchRecords(result.Stdout), keep)
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	installed, err := a.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	installedNames := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		installedNames[pkg.Name] = true
+	}
+	for i := range results {
+		results[i].Installed = installedNames[results[i].Name]
+	}
+	return results, nil
+}
+
+// parseSearchRecords parses the package records printed by 'apt-cache search
+// --full', keeping the first record of each package and the first line of its
+// description.
+func parseSearchRecords(output string) []adapters.SearchResult {
+	results := []adapters.SearchResult{}
+	seen := map[string]bool{}
+	for _, stanza := range strings.Split(output, "\n\n") {
+		fields := parseControl(stanza)
+		name := fields["Package"]
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		description := fields["Description"]
+		if description == "" {
+			description = fields["Description-en"]
+		}
+		description, _, _ = strings.Cut(description, "\n")
+		results = append(results, adapters.SearchResult{
+			Name:        name,
+			Description: description,
+			Version:     fields["Version"],
+			Section:     fields["Section"],
+			Type:        adapters.PackageTypeFormula,
+		})
+	}
+	return results
+}
+
+// RepositoryPackages lists the packages in the downloaded indexes of a
+// source. repository is the source URI, optionally followed by the suite and
+// component path, e.g. "deb.nodesource.com/node_20.x" or
+// "archive.ubuntu.com/ubuntu/dists/jammy/universe". When several indexes list
+// a package, the first one in file name order wins.
+func (a *AptAdapter) RepositoryPackages(ctx context.Context, repository string, limit int) (_ []adapters.RepositoryPackage, err error) {
+	_, span := adapters.StartSpan(ctx, "apt", "repository_packages", repository)
+	defer func() { telemetry.End(span, err) }()
+
+	indexes, err := a.repositoryIndexes(repository)
+	if err != nil {
+		return nil, err
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no package indexes for repository %s in %s, run 'apt-get update' after adding it: %w",
+			repository, filepath.Clean(a.listsDir), adapters.ErrNotFound)
+	}
+
+	packages := map[string]adapters.RepositoryPackage{}
+	for _, index := range indexes {
+		if err := readPackagesIndex(index, packages); err != nil {
+			return nil, err
+		}
+	}
+
+	return sortedRepositoryPackages(packages, limit), nil
+}
+
+// repositoryIndexes returns the Packages indexes downloaded from repository.
+// apt names them after the source URL, with "/" replaced by "_" and "_"
+// escaped as "%5f".
+func (a *AptAdapter) repositoryIndexes(repository string) ([]string, error) {
+	prefix := repository
+	if _, rest, ok := strings.Cut(prefix, "://"); ok {
+		prefix = rest
+	}
+	prefix = strings.Trim(prefix, "/")
+	prefix = strings.ReplaceAll(prefix, "_", "%5f")
+	prefix = strings.ReplaceAll(prefix, "/", "_") + "_"
+
+	entries, err := os.ReadDir(a.listsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read APT lists directory %s: %w", a.listsDir, err)
+	}
+
+	var indexes []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if strings.HasSuffix(name, "_Packages") || strings.HasSuffix(name, "_Packages.gz") {
+			indexes = append(indexes, filepath.Join(a.listsDir, name))
+		}
+	}
+	return indexes, nil
+}
+
+// readPackagesIndex adds the packages of a plain or gzip-compressed Packages
+// index to packages, keeping entries already present.
+func readPackagesIndex(path string, packages map[string]adapters.RepositoryPackage) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read package index %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to decompress package index %s: %w", path, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var pkg adapters.RepositoryPackage
+	flush := func() {
+		if _, seen := packages[pkg.Name]; pkg.Name != "" && !seen {
+			packages[pkg.Name] = pkg
+		}
+		pkg = adapters.RepositoryPackage{}
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || line[0] == ' ' {
+			continue
+		}
+		switch key {
+		case "Package":
+			pkg.Name = strings.TrimSpace(value)
+		case "Version":
+			pkg.Version = strings.TrimSpace(value)
+		case "Description":
+			pkg.Description = strings.TrimSpace(value)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read package index %s: %w", path, err)
+	}
+	return nil
+}
+
+// sortedRepositoryPackages returns up to limit packages sorted by name.
+func sortedRepositoryPackages(packages map[string]adapters.RepositoryPackage, limit int) []adapters.RepositoryPackage {
+	sorted := make([]adapters.RepositoryPackage, 0, len(packages))
+	for _, pkg := range packages {
+		sorted = append(sorted, pkg)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	if limit >= 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// Describe reports the apt version from 'apt-get --version'.
+func (a *AptAdapter) Describe(ctx context.Context) (*adapters.ManagerDetails, error) {
+	result, err := a.executor.Run(ctx, a.aptGetPath, []string{"--version"}, executor.ExecOpts{
+		Timeout: 10 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query version of", a.aptGetPath, result, err)
+	}
+
+	path := a.aptGetPath
+	if resolved, err := exec.LookPath(path); err == nil {
+		path = resolved
+	}
+	return &adapters.ManagerDetails{
+		Version: parseAptVersion(result.Stdout),
+		Path:    path,
+	}, nil
+}
+
+// parseAptVersion extracts the version from "apt 2.4.11 (amd64)".
+func parseAptVersion(output string) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) >= 2 && fields[0] == "apt" {
+		return fields[1]
+	}
+	return strings.TrimSpace(firstLine)
+}
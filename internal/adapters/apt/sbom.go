@@ -0,0 +1,179 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/platform"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// componentFormat is the dpkg-query format read by ListComponents. The
+// binary:Package field qualifies foreign-architecture packages, e.g.
+// "libc6:i386", which is how apt-cache policy names them.
+const componentFormat = "${Package}\t${binary:Package}\t${Version}\t${Architecture}\t${Maintainer}\t" +
+	"${Homepage}\t${binary:Summary}\t${Status}\n"
+
+// dpkgComponent is an installed package read from dpkg-query.
+type dpkgComponent struct {
+	adapters.Component
+	// qualifiedName is the name apt-cache policy reports the package under
+	qualifiedName string
+}
+
+// ListComponents describes every installed package from the dpkg database,
+// the packages' machine-readable copyright files and 'apt-cache policy',
+// which names the archive the installed version was downloaded from.
+func (a *AptAdapter) ListComponents(ctx context.Context) (_ []adapters.Component, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "list_components")
+	defer func() { telemetry.End(span, err) }()
+
+	args := []string{"--show", "--showformat", componentFormat}
+	result, err := a.executor.Run(ctx, a.dpkgPath, args, executor.ExecOpts{Timeout: 60 * time.Second})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("list", "installed packages", result, err)
+	}
+	installed := parseDpkgComponents(result.Stdout)
+	if len(installed) == 0 {
+		return []adapters.Component{}, nil
+	}
+
+	names := make([]string, 0, len(installed))
+	for _, pkg := range installed {
+		names = append(names, pkg.qualifiedName)
+	}
+	policy, err := a.executor.Run(ctx, a.aptCachePath, append([]string{"policy"}, names...), executor.ExecOpts{
+		Timeout: 2 * time.Minute,
+	})
+	if err != nil || policy.ExitCode != 0 {
+		return nil, commandError("query origins of", "installed packages", policy, err)
+	}
+	origins := parsePolicyOrigins(policy.Stdout)
+
+	release, err := os.ReadFile(a.osRelease)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	distribution := platform.ParseOSRelease(string(release))
+	namespace := distribution["ID"]
+	if namespace == "" {
+		namespace = "debian"
+	}
+	distro := ""
+	if distribution["VERSION_ID"] != "" {
+		distro = namespace + "-" + distribution["VERSION_ID"]
+	}
+
+	components := make([]adapters.Component, 0, len(installed))
+	for _, pkg := range installed {
+		component := pkg.Component
+		copyright, err := os.ReadFile(filepath.Join(a.docDir, component.Name, "copyright"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		component.License = parseCopyrightLicenses(string(copyright))
+		component.SourceRepository = origins[pkg.qualifiedName]
+		if component.SourceRepository == "" {
+			// apt-cache policy drops the native architecture from its headings
+			component.SourceRepository = origins[component.Name]
+		}
+		component.Purl = adapters.PackageURL("deb", namespace, component.Name, component.Version, map[string]string{
+			"arch":   component.Architecture,
+			"distro": distro,
+		})
+		components = append(components, component)
+	}
+	return components, nil
+}
+
+// parseDpkgComponents parses componentFormat lines from dpkg-query, keeping
+// only installed packages, sorted by name and architecture.
+func parseDpkgComponents(output string) []dpkgComponent {
+	var components []dpkgComponent
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 8 || fields[0] == "" || !strings.HasSuffix(fields[7], " installed") {
+			continue
+		}
+		components = append(components, dpkgComponent{
+			Component: adapters.Component{
+				Name:         fields[0],
+				Version:      fields[2],
+				Architecture: fields[3],
+				Supplier:     strings.TrimSpace(fields[4]),
+				Homepage:     strings.TrimSpace(fields[5]),
+				Description:  strings.TrimSpace(fields[6]),
+			},
+			qualifiedName: fields[1],
+		})
+	}
+	sort.SliceStable(components, func(i, j int) bool {
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].Architecture < components[j].Architecture
+	})
+	return components
+}
+
+// parsePolicyOrigins parses 'apt-cache policy' output for several packages,
+// returning for each the first archive publishing its installed ('***')
+// version as "<uri> <suite>/<component>". Packages whose installed version is
+// only recorded in /var/lib/dpkg/status, such as locally built packages or
+// versions removed from the archive, have no origin.
+func parsePolicyOrigins(output string) map[string]string {
+	origins := map[string]string{}
+	current := ""
+	installed := false
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			current = strings.TrimSuffix(strings.TrimSpace(line), ":")
+			installed = false
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		fields := strings.Fields(strings.TrimPrefix(trimmed, "***"))
+		switch {
+		case len(fields) == 2 && isNumeric(fields[1]):
+			// A version line: "<version> <priority>", marked '***' when installed
+			installed = strings.HasPrefix(trimmed, "***")
+		case installed && len(fields) >= 3 && isNumeric(fields[0]) && origins[current] == "":
+			origins[current] = fields[1] + " " + fields[2]
+		}
+	}
+	return origins
+}
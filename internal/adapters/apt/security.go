@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/platform"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// osvEcosystems maps os-release IDs to OSV ecosystem names.
+var osvEcosystems = map[string]string{
+	"debian": "Debian",
+	"ubuntu": "Ubuntu",
+}
+
+// Ecosystem returns the OSV ecosystem of the running distribution, read from
+// /etc/os-release, e.g. "Debian:12" or "Ubuntu:22.04".
+func (a *AptAdapter) Ecosystem(_ context.Context) (string, error) {
+	data, err := os.ReadFile(a.osRelease)
+	if err != nil {
+		return "", fmt.Errorf("failed to identify the distribution: %w", err)
+	}
+	return parseOSRelease(string(data))
+}
+
+// parseOSRelease maps the ID and VERSION_ID of an os-release file to an OSV
+// ecosystem. Derivatives such as Linux Mint are not matched through ID_LIKE,
+// since their release numbers are their own.
+func parseOSRelease(content string) (string, error) {
+	fields := platform.ParseOSRelease(content)
+	ecosystem, ok := osvEcosystems[fields["ID"]]
+	if !ok {
+		return "", fmt.Errorf("no vulnerability ecosystem for distribution %q", fields["ID"])
+	}
+	if fields["VERSION_ID"] == "" {
+		// testing and unstable have no release number
+		return ecosystem, nil
+	}
+	return ecosystem + ":" + fields["VERSION_ID"], nil
+}
+
+// ListSources maps each installed package to its source package, which
+// Debian and Ubuntu security advisories are published against.
+func (a *AptAdapter) ListSources(ctx context.Context) (_ map[string]adapters.SourcePackage, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "list_sources")
+	defer func() { telemetry.End(span, err) }()
+
+	args := []string{"--show", "--showformat", "${Package}\t${source:Package}\t${source:Version}\t${Status}\n"}
+	result, err := a.executor.Run(ctx, a.dpkgPath, args, executor.ExecOpts{Timeout: 60 * time.Second})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("list source packages of", "installed packages", result, err)
+	}
+	return parseDpkgSources(result.Stdout), nil
+}
+
+// parseDpkgSources parses "name\tsource\tsource-version\tstatus" lines from
+// dpkg-query, keeping only installed packages.
+func parseDpkgSources(output string) map[string]adapters.SourcePackage {
+	sources := map[string]adapters.SourcePackage{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 4 || fields[0] == "" || !strings.HasSuffix(fields[3], " installed") {
+			continue
+		}
+		sources[fields[0]] = adapters.SourcePackage{Name: fields[1], Version: fields[2]}
+	}
+	return sources
+}
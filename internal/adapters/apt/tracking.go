@@ -0,0 +1,269 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// Metadata describes an installed package from the dpkg database and the
+// package's machine-readable copyright file.
+func (a *AptAdapter) Metadata(ctx context.Context, name string) (_ *adapters.PackageMetadata, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "metadata", name)
+	defer func() { telemetry.End(span, err) }()
+
+	args := []string{"--show", "--showformat", "${binary:Summary}\t${Homepage}\t${Installed-Size}", name}
+	result, err := a.executor.Run(ctx, a.dpkgPath, args, executor.ExecOpts{Timeout: 30 * time.Second})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("read metadata of", name, result, err)
+	}
+	metadata := parseDpkgMetadata(result.Stdout)
+
+	copyright, err := os.ReadFile(filepath.Join(a.docDir, name, "copyright"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	metadata.License = parseCopyrightLicenses(string(copyright))
+
+	return metadata, nil
+}
+
+// parseDpkgMetadata parses "summary\thomepage\tinstalled-size" from dpkg-query,
+// where the installed size is in KiB.
+func parseDpkgMetadata(output string) *adapters.PackageMetadata {
+	fields := strings.Split(strings.TrimRight(output, "\n"), "\t")
+	for len(fields) < 3 {
+		fields = append(fields, "")
+	}
+	metadata := &adapters.PackageMetadata{
+		Description: strings.TrimSpace(fields[0]),
+		Homepage:    strings.TrimSpace(fields[1]),
+	}
+	if size, err := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64); err == nil {
+		metadata.InstalledSize = size * 1024
+	}
+	return metadata
+}
+
+// parseCopyrightLicenses returns the distinct licenses named by the License
+// fields of a machine-readable (DEP-5) copyright file, or "" if it has none.
+func parseCopyrightLicenses(copyright string) string {
+	var licenses []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(copyright, "\n") {
+		license, found := strings.CutPrefix(line, "License:")
+		license = strings.TrimSpace(license)
+		if !found || license == "" || seen[license] {
+			continue
+		}
+		seen[license] = true
+		licenses = append(licenses, license)
+	}
+	return strings.Join(licenses, ", ")
+}
+
+// DependencyTree returns the installed packages name depends on, directly or
+// through other packages, following only Depends and PreDepends.
+func (a *AptAdapter) DependencyTree(ctx context.Context, name string) (_ map[string]string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "dependency_tree", name)
+	defer func() { telemetry.End(span, err) }()
+
+	args := []string{"depends", "--recurse", "--installed", "--no-recommends", "--no-suggests",
+		"--no-conflicts", "--no-breaks", "--no-replaces", "--no-enhances", name}
+	result, err := a.executor.Run(ctx, a.aptCachePath, args, executor.ExecOpts{Timeout: 60 * time.Second})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("read dependencies of", name, result, err)
+	}
+
+	installed, err := a.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string, len(installed))
+	for _, pkg := range installed {
+		versions[pkg.Name] = pkg.Version
+	}
+
+	tree := map[string]string{}
+	for _, dependency := range parseRecursiveDepends(result.Stdout, name) {
+		tree[dependency] = versions[dependency]
+	}
+	return tree, nil
+}
+
+// parseRecursiveDepends returns the packages other than root named in
+// 'apt-cache depends --recurse' output, skipping <virtual> packages.
+func parseRecursiveDepends(output, root string) []string {
+	var dependencies []string
+	seen := map[string]bool{root: true}
+	add := func(name string) {
+		if name == "" || strings.HasPrefix(name, "<") || seen[name] {
+			return
+		}
+		seen[name] = true
+		dependencies = append(dependencies, name)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimLeft(line, " |")
+		if trimmed == "" {
+			continue
+		}
+		if _, target, found := strings.Cut(trimmed, ": "); found {
+			add(strings.TrimSpace(target))
+			continue
+		}
+		if trimmed == line {
+			// An unindented line heads the dependencies of a package in the tree
+			add(strings.TrimSpace(line))
+		}
+	}
+	return dependencies
+}
+
+// Files returns the paths dpkg recorded for name, including directories.
+func (a *AptAdapter) Files(ctx context.Context, name string) (_ []string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "files", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := a.executor.Run(ctx, a.dpkgPath, []string{"--listfiles", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("list files of", name, result, err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(result.Stdout, "\n") {
+		// Skips "/." and diversion notes such as "diverted by dash to: /usr/share/man/..."
+		if strings.HasPrefix(line, "/") && line != "/." {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// usrMergedDirs are the root-level directories that merged-/usr systems link
+// into /usr.
+var usrMergedDirs = []string{"bin", "sbin", "lib", "lib32", "lib64", "libx32"}
+
+// FileOwner finds the package owning path with 'dpkg-query --search'. A path
+// dpkg does not know is retried through its symbolic links and under its
+// merged-/usr alias, since packages record the path they ship, e.g. /bin/ls
+// on systems where it is reached as /usr/bin/ls.
+func (a *AptAdapter) FileOwner(ctx context.Context, path string) (_ *adapters.FileOwnership, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "file_owner", path)
+	defer func() { telemetry.End(span, err) }()
+
+	for _, candidate := range ownerCandidates(path) {
+		result, runErr := a.executor.Run(ctx, a.dpkgPath, []string{"--search", candidate}, executor.ExecOpts{
+			Timeout: 30 * time.Second,
+		})
+		if runErr == nil && result.ExitCode == 0 {
+			if packages := parseDpkgSearch(result.Stdout, candidate); len(packages) > 0 {
+				return &adapters.FileOwnership{Packages: packages, Path: candidate}, nil
+			}
+			continue
+		}
+		if cmdErr := commandError("find the package owning", candidate, result, runErr); !errors.Is(cmdErr, adapters.ErrNotFound) {
+			return nil, cmdErr
+		}
+	}
+	return nil, fmt.Errorf("no installed package owns %s: %w", path, adapters.ErrNotFound)
+}
+
+// ownerCandidates returns path followed by its symlink-resolved form and the
+// merged-/usr aliases of both, without duplicates.
+func ownerCandidates(path string) []string {
+	path = filepath.Clean(path)
+	paths := []string{path}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved != path {
+		paths = append(paths, resolved)
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(candidate string) {
+		if !seen[candidate] {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+	for _, p := range paths {
+		add(p)
+		if alias := usrMergeAlias(p); alias != "" {
+			add(alias)
+		}
+	}
+	return candidates
+}
+
+// usrMergeAlias maps /usr/bin/x to /bin/x and back, returning "" for paths
+// outside the merged directories.
+func usrMergeAlias(path string) string {
+	for _, dir := range usrMergedDirs {
+		switch {
+		case strings.HasPrefix(path, "/usr/"+dir+"/"):
+			return strings.TrimPrefix(path, "/usr")
+		case strings.HasPrefix(path, "/"+dir+"/"):
+			return "/usr" + path
+		}
+	}
+	return ""
+}
+
+// parseDpkgSearch returns the packages 'dpkg-query --search' reports for
+// path, from lines such as "libc6:amd64, libc6:i386: /lib/x86_64-linux-gnu".
+// Architecture qualifiers are dropped and diversion notes skipped.
+func parseDpkgSearch(output, path string) []string {
+	var packages []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "diversion by ") {
+			continue
+		}
+		owners, file, ok := strings.Cut(line, ": /")
+		if !ok || "/"+strings.TrimSpace(file) != path {
+			continue
+		}
+		for _, owner := range strings.Split(owners, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(owner), ":")
+			if name != "" && !seen[name] {
+				seen[name] = true
+				packages = append(packages, name)
+			}
+		}
+	}
+	return packages
+}
@@ -0,0 +1,138 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package apt
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// ListUpdates lists installed packages whose candidate version differs from
+// the installed one, from a single 'apt-cache policy' query of every installed
+// package. Unlike 'apt-get -s upgrade' this includes held packages, which are
+// reported as pinned.
+func (a *AptAdapter) ListUpdates(ctx context.Context) (_ []adapters.AvailableUpdate, err error) {
+	ctx, span := adapters.StartSpan(ctx, "apt", "list_updates")
+	defer func() { telemetry.End(span, err) }()
+
+	installed, err := a.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(installed) == 0 {
+		return nil, nil
+	}
+
+	args := make([]string, 0, len(installed)+1)
+	args = append(args, "policy")
+	held := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		args = append(args, pkg.Name)
+		held[pkg.Name] = pkg.Pinned
+	}
+	result, err := a.executor.Run(ctx, a.aptCachePath, args, executor.ExecOpts{
+		Timeout: 120 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query candidate versions of", "installed packages", result, err)
+	}
+
+	updates := parseUpgradablePolicies(result.Stdout)
+	for i := range updates {
+		updates[i].Pinned = held[updates[i].Name]
+	}
+	return updates, nil
+}
+
+// parseUpgradablePolicies parses 'apt-cache policy' output for several
+// packages and returns those whose candidate differs from the installed
+// version. Each package's stanza looks like
+//
+//	curl:
+//	  Installed: 7.81.0-1ubuntu1.15
+//	  Candidate: 7.81.0-1ubuntu1.16
+//	  Version table:
+//	     7.81.0-1ubuntu1.16 500
+//	        500 http://archive.ubuntu.com/ubuntu jammy-updates/main amd64 Packages
+//	        500 http://security.ubuntu.com/ubuntu jammy-security/main amd64 Packages
+//	 *** 7.81.0-1ubuntu1.15 100
+//	        100 /var/lib/dpkg/status
+//
+// The origin lists the suites publishing the candidate. An update is a
+// security update when any of them is a security pocket: "-security", or
+// "/updates" on Debian releases before bullseye.
+func parseUpgradablePolicies(output string) []adapters.AvailableUpdate {
+	var updates []adapters.AvailableUpdate
+	var update *adapters.AvailableUpdate
+	var suites []string
+	tableVersion := ""
+
+	finish := func() {
+		if update != nil && update.LatestVersion != "" && update.LatestVersion != update.CurrentVersion {
+			update.Origin = strings.Join(suites, ",")
+			for _, suite := range suites {
+				if strings.HasSuffix(suite, "-security") || strings.HasSuffix(suite, "/updates") {
+					update.Security = true
+				}
+			}
+			updates = append(updates, *update)
+		}
+		update, suites, tableVersion = nil, nil, ""
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case line[0] != ' ' && strings.HasSuffix(trimmed, ":"):
+			finish()
+			update = &adapters.AvailableUpdate{Name: strings.TrimSuffix(trimmed, ":")}
+		case update == nil:
+		case strings.HasPrefix(trimmed, "Installed:"):
+			update.CurrentVersion = strings.TrimSpace(strings.TrimPrefix(trimmed, "Installed:"))
+		case strings.HasPrefix(trimmed, "Candidate:"):
+			if candidate := strings.TrimSpace(strings.TrimPrefix(trimmed, "Candidate:")); candidate != "(none)" {
+				update.LatestVersion = candidate
+			}
+		case strings.HasPrefix(trimmed, "Version table:"):
+		default:
+			fields := strings.Fields(strings.TrimPrefix(trimmed, "***"))
+			switch {
+			case len(fields) == 2 && isNumeric(fields[1]):
+				tableVersion = fields[0]
+			case len(fields) >= 3 && tableVersion == update.LatestVersion && strings.Contains(fields[1], "://"):
+				// Source lines are "<priority> <uri> <suite>/<component> <arch> Packages"
+				if slash := strings.LastIndex(fields[2], "/"); slash > 0 {
+					suites = append(suites, fields[2][:slash])
+				}
+			}
+		}
+	}
+	finish()
+	return updates
+}
@@ -37,21 +37,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+
 	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
 )
 
+// noAutoUpdateEnv stops brew from running its own implicit 'brew update' so that
+// cache refreshes are governed solely by the provider's update_cache policy.
+var noAutoUpdateEnv = []string{"HOMEBREW_NO_AUTO_UPDATE=1"}
+
 // BrewAdapter implements the PackageManager interface for Homebrew.
 type BrewAdapter struct {
-	executor executor.Executor
-	brewPath string
+	executor  executor.Executor
+	brewPath  string
+	inventory *adapters.Inventory
 }
 
 // NewBrewAdapter creates a new Homebrew adapter.
@@ -95,6 +104,7 @@ type brewInfo struct {
 	Name       string             `json:"name"`
 	FullName   string             `json:"full_name"`
 	Versions   versions           `json:"versions"`
+	Revision   int                `json:"revision"`
 	Installed  []installedVersion `json:"installed"`
 	LinkedKeg  string             `json:"linked_keg,omitempty"`
 	Pinned     bool               `json:"pinned"`
@@ -127,10 +137,35 @@ type dependency struct {
 	Version  string `json:"version"`
 }
 
+// SetInventory attaches a shared inventory. Once set, DetectInstalled and
+// ListInstalled are served from a single 'brew info --json=v2 --installed'.
+func (b *BrewAdapter) SetInventory(inventory *adapters.Inventory) {
+	b.inventory = inventory
+}
+
 // DetectInstalled checks if a package is installed and returns its information.
 // This method implements Homebrew's dual-type detection logic since packages can be
 // either formulae (command-line tools) or casks (GUI applications).
-func (b *BrewAdapter) DetectInstalled(ctx context.Context, name string) (*adapters.PackageInfo, error) {
+//
+// With an inventory attached, installed packages are answered from memory. Names
+// missing from the inventory still go through 'brew info', since they may be
+// aliases (e.g. "python") or packages that are not installed at all.
+func (b *BrewAdapter) DetectInstalled(ctx context.Context, name string) (_ *adapters.PackageInfo, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "detect", name)
+	defer func() { telemetry.End(span, err) }()
+
+	if b.inventory != nil {
+		info, found, err := b.inventory.Lookup(ctx, name, b.listInstalled)
+		if err == nil && found {
+			return &info, nil
+		}
+	}
+
+	return b.queryPackage(ctx, name)
+}
+
+// queryPackage looks up a single package with 'brew info', trying formula then cask.
+func (b *BrewAdapter) queryPackage(ctx context.Context, name string) (*adapters.PackageInfo, error) {
 	// First try as formula, then as cask
 	// Note: This will produce expected "stderr" messages when the wrong type is tried
 	// (e.g., "Error: Cask 'jq' is unavailable" when jq is actually a formula)
@@ -257,7 +292,12 @@ func (b *BrewAdapter) Install(ctx context.Context, name, version string) error {
 
 // InstallWithType installs a package with explicit type and optional version specification.
 // Implements idempotency by checking if the package is already installed before attempting installation.
-func (b *BrewAdapter) InstallWithType(ctx context.Context, name, version string, packageType adapters.PackageType) error {
+func (b *BrewAdapter) InstallWithType(ctx context.Context, name, version string, packageType adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "install", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
 	// DEBUG: Log installation request
 	tflog.Debug(ctx, "BrewAdapter.InstallWithType starting",
 		map[string]interface{}{
@@ -268,7 +308,6 @@ func (b *BrewAdapter) InstallWithType(ctx context.Context, name, version string,
 		})
 
 	var isCask bool
-	var err error
 
 	switch packageType {
 	case adapters.PackageTypeCask:
@@ -382,9 +421,7 @@ func (b *BrewAdapter) InstallWithType(ctx context.Context, name, version string,
 		"timeout":      "300s",
 	})
 
-	result, err := b.executor.Run(ctx, b.brewPath, args, executor.ExecOpts{
-		Timeout: 300 * time.Second, // 5 minutes for package installation
-	})
+	result, err := b.executor.Run(ctx, b.brewPath, args, progressOpts(ctx, 300*time.Second)) // 5 minutes for package installation
 
 	// DEBUG: Log execution results
 	tflog.Debug(ctx, "Brew install command completed", map[string]interface{}{
@@ -415,8 +452,7 @@ func (b *BrewAdapter) InstallWithType(ctx context.Context, name, version string,
 			"stderr":       result.Stderr,
 		})
 
-		return fmt.Errorf("failed to install %s: exit code %d, error: %w, stderr: %s",
-			packageName, result.ExitCode, err, result.Stderr)
+		return commandError("install", packageName, result, err)
 	}
 
 	tflog.Debug(ctx, "BrewAdapter.InstallWithType completed successfully", map[string]interface{}{
@@ -427,6 +463,68 @@ func (b *BrewAdapter) InstallWithType(ctx context.Context, name, version string,
 	return nil
 }
 
+// InstallBatch installs several packages with one 'brew install' per package type.
+// Packages with PackageTypeAuto are passed without --cask, which lets brew
+// resolve formulae and casks itself.
+func (b *BrewAdapter) InstallBatch(ctx context.Context, packages []adapters.BatchPackage) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "install", adapters.BatchPackageNames(packages)...)
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
+	var formulae, casks []string
+	for _, pkg := range packages {
+		switch pkg.Type {
+		case adapters.PackageTypeCask:
+			casks = append(casks, pkg.Name)
+		case adapters.PackageTypeFormula, adapters.PackageTypeAuto, "":
+			name := pkg.Name
+			if pkg.Version != "" {
+				name = fmt.Sprintf("%s@%s", pkg.Name, pkg.Version)
+			}
+			formulae = append(formulae, name)
+		default:
+			return fmt.Errorf("unsupported package type: %s", pkg.Type)
+		}
+	}
+
+	if len(formulae) > 0 {
+		if err := b.runBatchInstall(ctx, formulae, false); err != nil {
+			return err
+		}
+	}
+	if len(casks) > 0 {
+		if err := b.runBatchInstall(ctx, casks, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runBatchInstall runs a single 'brew install' for the given package names.
+func (b *BrewAdapter) runBatchInstall(ctx context.Context, names []string, isCask bool) error {
+	args := []string{"install"}
+	if isCask {
+		args = append(args, "--cask")
+	}
+	args = append(args, names...)
+
+	tflog.Debug(ctx, "Executing batched brew install", map[string]interface{}{
+		"packages": names,
+		"is_cask":  isCask,
+	})
+
+	// 5 minutes for the first package plus 2 minutes for every additional one
+	result, err := b.executor.Run(ctx, b.brewPath, args,
+		progressOpts(ctx, 300*time.Second+time.Duration(len(names)-1)*2*time.Minute))
+	if err != nil || result.ExitCode != 0 {
+		return commandError("install", strings.Join(names, ", "), result, err)
+	}
+
+	return nil
+}
+
 // Remove uninstalls a package.
 func (b *BrewAdapter) Remove(ctx context.Context, name string) error {
 	// Use auto-detection for backward compatibility
@@ -434,9 +532,13 @@ func (b *BrewAdapter) Remove(ctx context.Context, name string) error {
 }
 
 // RemoveWithType uninstalls a package with explicit type.
-func (b *BrewAdapter) RemoveWithType(ctx context.Context, name string, packageType adapters.PackageType) error {
+func (b *BrewAdapter) RemoveWithType(ctx context.Context, name string, packageType adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "remove", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
 	var isCask bool
-	var err error
 
 	switch packageType {
 	case adapters.PackageTypeCask:
@@ -465,15 +567,85 @@ func (b *BrewAdapter) RemoveWithType(ctx context.Context, name string, packageTy
 	})
 
 	if err != nil || result.ExitCode != 0 {
-		return fmt.Errorf("failed to uninstall %s: exit code %d, error: %w, stderr: %s",
-			name, result.ExitCode, err, result.Stderr)
+		return commandError("uninstall", name, result, err)
 	}
 
 	return nil
 }
 
+// RemoveBatch uninstalls several packages with one 'brew uninstall' per package type.
+func (b *BrewAdapter) RemoveBatch(ctx context.Context, packages []adapters.BatchPackage) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "remove", adapters.BatchPackageNames(packages)...)
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
+	var formulae, casks []string
+	for _, pkg := range packages {
+		if pkg.Type == adapters.PackageTypeCask {
+			casks = append(casks, pkg.Name)
+		} else {
+			formulae = append(formulae, pkg.Name)
+		}
+	}
+
+	for _, group := range []struct {
+		names  []string
+		isCask bool
+	}{{formulae, false}, {casks, true}} {
+		if len(group.names) == 0 {
+			continue
+		}
+
+		args := []string{"uninstall"}
+		if group.isCask {
+			args = append(args, "--cask")
+		}
+		args = append(args, group.names...)
+
+		result, err := b.executor.Run(ctx, b.brewPath, args, executor.ExecOpts{
+			Timeout: 120*time.Second + time.Duration(len(group.names)-1)*30*time.Second,
+		})
+		if err != nil || result.ExitCode != 0 {
+			return commandError("uninstall", strings.Join(group.names, ", "), result, err)
+		}
+	}
+
+	return nil
+}
+
+// ListManuallyInstalled returns formulae installed on request plus all casks,
+// which Homebrew only ever installs on request.
+func (b *BrewAdapter) ListManuallyInstalled(ctx context.Context) ([]string, error) {
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"leaves", "--installed-on-request"}, executor.ExecOpts{
+		Timeout: 60 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list leaves: exit code %d, error: %w, stderr: %s",
+			result.ExitCode, err, result.Stderr)
+	}
+	names := strings.Fields(result.Stdout)
+
+	installed, err := b.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, pkg := range installed {
+		if pkg.Type == adapters.PackageTypeCask {
+			names = append(names, pkg.Name)
+		}
+	}
+
+	return names, nil
+}
+
 // Pin pins or unpins a package at its current version.
-func (b *BrewAdapter) Pin(ctx context.Context, name string, pin bool) error {
+func (b *BrewAdapter) Pin(ctx context.Context, name string, pin bool) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "pin", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
 	// Casks don't support pinning
 	isCask, err := b.isCask(ctx, name)
 	if err == nil && isCask {
@@ -499,27 +671,60 @@ func (b *BrewAdapter) Pin(ctx context.Context, name string, pin bool) error {
 		if !pin {
 			action = "unpin"
 		}
-		return fmt.Errorf("failed to %s %s: exit code %d, error: %w, stderr: %s",
-			action, name, result.ExitCode, err, result.Stderr)
+		return commandError(action, name, result, err)
 	}
 
 	return nil
 }
 
-// UpdateCache updates Homebrew's package cache.
-func (b *BrewAdapter) UpdateCache(ctx context.Context) error {
+// UpdateCache updates Homebrew's package cache. The inventory is invalidated
+// because the outdated status it records depends on the cached metadata.
+func (b *BrewAdapter) UpdateCache(ctx context.Context) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "update_cache")
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
 	result, err := b.executor.Run(ctx, b.brewPath, []string{"update"}, executor.ExecOpts{
 		Timeout: 120 * time.Second, // 2 minutes for update
 	})
 
 	if err != nil || result.ExitCode != 0 {
-		return fmt.Errorf("failed to update brew cache: exit code %d, error: %w, stderr: %s",
-			result.ExitCode, err, result.Stderr)
+		return commandError("update", "brew cache", result, err)
 	}
 
 	return nil
 }
 
+// CacheLastUpdated returns when Homebrew last refreshed its package metadata.
+// The API index under 'brew --cache' is preferred; tap-based installs fall back
+// to the FETCH_HEAD of the Homebrew repository.
+func (b *BrewAdapter) CacheLastUpdated(ctx context.Context) (time.Time, error) {
+	candidates := make([]string, 0, 2)
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"--cache"}, executor.ExecOpts{
+		Timeout: 10 * time.Second,
+	})
+	if err == nil && result.ExitCode == 0 {
+		candidates = append(candidates, filepath.Join(strings.TrimSpace(result.Stdout), "api", "formula.jws.json"))
+	}
+
+	result, err = b.executor.Run(ctx, b.brewPath, []string{"--repository"}, executor.ExecOpts{
+		Timeout: 10 * time.Second,
+	})
+	if err == nil && result.ExitCode == 0 {
+		candidates = append(candidates, filepath.Join(strings.TrimSpace(result.Stdout), ".git", "FETCH_HEAD"))
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil {
+			return info.ModTime(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("could not determine when brew was last updated")
+}
+
 // Search searches for packages matching a query.
 func (b *BrewAdapter) Search(ctx context.Context, query string) ([]adapters.PackageInfo, error) {
 	// Search formulas
@@ -586,9 +791,174 @@ func (b *BrewAdapter) searchType(ctx context.Context, query string, isCask bool)
 	return packages, nil
 }
 
-// Info retrieves detailed information about a package.
+// Info retrieves detailed information about a package. It always queries
+// Homebrew directly and never uses the inventory.
 func (b *BrewAdapter) Info(ctx context.Context, name string) (*adapters.PackageInfo, error) {
-	return b.DetectInstalled(ctx, name)
+	return b.queryPackage(ctx, name)
+}
+
+// ListInstalled returns every installed formula and cask.
+func (b *BrewAdapter) ListInstalled(ctx context.Context) ([]adapters.PackageInfo, error) {
+	if b.inventory != nil {
+		return b.inventory.Packages(ctx, b.listInstalled)
+	}
+	return b.listInstalled(ctx)
+}
+
+// brewInstalledResponse is the subset of 'brew info --json=v2 --installed' used
+// to build the inventory.
+type brewInstalledResponse struct {
+	Formulae []brewInfo `json:"formulae"`
+	Casks    []struct {
+		Token     string `json:"token"`
+		Tap       string `json:"tap"`
+		Version   string `json:"version"`
+		Installed string `json:"installed"`
+		Outdated  bool   `json:"outdated"`
+	} `json:"casks"`
+}
+
+// listInstalled describes every installed formula and cask with one brew invocation.
+func (b *BrewAdapter) listInstalled(ctx context.Context) ([]adapters.PackageInfo, error) {
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"info", "--json=v2", "--installed"}, executor.ExecOpts{
+		Timeout: 120 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list installed packages: exit code %d, error: %w, stderr: %s",
+			result.ExitCode, err, result.Stderr)
+	}
+
+	return parseBrewInstalled(result.Stdout)
+}
+
+// parseBrewInstalled converts 'brew info --json=v2 --installed' output into package information.
+func parseBrewInstalled(output string) ([]adapters.PackageInfo, error) {
+	var response brewInstalledResponse
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info v2 JSON: %w", err)
+	}
+
+	packages := make([]adapters.PackageInfo, 0, len(response.Formulae)+len(response.Casks))
+	for _, formula := range response.Formulae {
+		if len(formula.Installed) == 0 {
+			continue
+		}
+
+		// The linked keg is the active version; otherwise use the newest installed keg
+		version := formula.LinkedKeg
+		if version == "" {
+			version = formula.Installed[len(formula.Installed)-1].Version
+		}
+
+		pkg := adapters.PackageInfo{
+			Name:       formula.Name,
+			Version:    version,
+			Installed:  true,
+			Pinned:     formula.Pinned,
+			Repository: formula.Tap,
+			Type:       adapters.PackageTypeFormula,
+			Outdated:   formula.Outdated,
+		}
+		if formula.Versions.Stable != "" {
+			pkg.AvailableVersions = []string{formula.Versions.Stable}
+		}
+		packages = append(packages, pkg)
+	}
+
+	for _, cask := range response.Casks {
+		if cask.Installed == "" {
+			continue
+		}
+
+		pkg := adapters.PackageInfo{
+			Name:       cask.Token,
+			Version:    cask.Installed,
+			Installed:  true,
+			Repository: cask.Tap,
+			Type:       adapters.PackageTypeCask,
+			Outdated:   cask.Outdated,
+		}
+		if cask.Version != "" {
+			pkg.AvailableVersions = []string{cask.Version}
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// Candidates reports the version 'brew install' would install. Homebrew only
+// offers the current stable version of a formula or cask, so it is the only
+// available version.
+func (b *BrewAdapter) Candidates(ctx context.Context, name string) (_ *adapters.VersionCandidates, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "candidates", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"info", "--json=v2", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query candidates for", name, result, err)
+	}
+
+	candidate, err := parseBrewCandidate(result.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	if candidate == "" {
+		return nil, fmt.Errorf("package %s %w", name, adapters.ErrNotFound)
+	}
+	return &adapters.VersionCandidates{Candidate: candidate, Available: []string{candidate}}, nil
+}
+
+// parseBrewCandidate extracts the installable version from 'brew info --json=v2'
+// output. Formula revisions are appended the way Homebrew names installed kegs
+// (e.g. "1.7.1_1"), so the candidate compares equal to an up-to-date install.
+func parseBrewCandidate(output string) (string, error) {
+	var response brewInstalledResponse
+	if err := json.Unmarshal([]byte(output), &response); err != nil {
+		return "", fmt.Errorf("failed to parse brew info v2 JSON: %w", err)
+	}
+
+	if len(response.Formulae) > 0 {
+		formula := response.Formulae[0]
+		if formula.Versions.Stable == "" || formula.Revision == 0 {
+			return formula.Versions.Stable, nil
+		}
+		return fmt.Sprintf("%s_%d", formula.Versions.Stable, formula.Revision), nil
+	}
+	if len(response.Casks) > 0 {
+		return response.Casks[0].Version, nil
+	}
+	return "", nil
+}
+
+// Upgrade upgrades an installed formula or cask to its current stable version.
+func (b *BrewAdapter) Upgrade(ctx context.Context, name string, packageType adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "upgrade", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
+	isCask := packageType == adapters.PackageTypeCask
+	if packageType == adapters.PackageTypeAuto {
+		if isCask, err = b.isCask(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"upgrade"}
+	if isCask {
+		args = append(args, "--cask")
+	}
+	args = append(args, name)
+
+	result, err := b.executor.Run(ctx, b.brewPath, args, progressOpts(ctx, 300*time.Second))
+	if err != nil || result.ExitCode != 0 {
+		return commandError("upgrade", name, result, err)
+	}
+
+	return nil
 }
 
 // isCask determines if a package is a cask or formula.
@@ -618,7 +988,7 @@ func (b *BrewAdapter) isCask(ctx context.Context, name string) (bool, error) {
 		return false, nil
 	}
 
-	return false, fmt.Errorf("package %s not found", name)
+	return false, fmt.Errorf("package %s %w", name, adapters.ErrNotFound)
 }
 
 // getStringValue safely extracts a string value from a map[string]interface{}
@@ -0,0 +1,360 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBrewInstalled(t *testing.T) {
+	output := `{
+  "formulae": [
+    {
+      "name": "jq",
+      "full_name": "jq",
+      "tap": "homebrew/core",
+      "versions": {"stable": "1.7.1", "head": "HEAD"},
+      "installed": [{"version": "1.6"}, {"version": "1.7"}],
+      "linked_keg": "1.7",
+      "pinned": true,
+      "outdated": true
+    },
+    {
+      "name": "terraform",
+      "full_name": "hashicorp/tap/terraform",
+      "tap": "hashicorp/tap",
+      "versions": {"stable": "1.9.0"},
+      "installed": [{"version": "1.9.0"}],
+      "pinned": false,
+      "outdated": false
+    }
+  ],
+  "casks": [
+    {"token": "firefox", "tap": "homebrew/cask", "version": "128.0", "installed": "127.0", "outdated": true},
+    {"token": "not-installed", "tap": "homebrew/cask", "version": "1.0", "installed": null}
+  ]
+}`
+
+	packages, err := parseBrewInstalled(output)
+	require.NoError(t, err)
+	require.Len(t, packages, 3)
+
+	assert.Equal(t, "jq", packages[0].Name)
+	assert.Equal(t, "1.7", packages[0].Version, "linked keg should win over other installed kegs")
+	assert.True(t, packages[0].Pinned)
+	assert.True(t, packages[0].Outdated)
+	assert.Equal(t, []string{"1.7.1"}, packages[0].AvailableVersions)
+	assert.Equal(t, adapters.PackageTypeFormula, packages[0].Type)
+
+	assert.Equal(t, "terraform", packages[1].Name)
+	assert.Equal(t, "hashicorp/tap", packages[1].Repository)
+
+	assert.Equal(t, "firefox", packages[2].Name)
+	assert.Equal(t, "127.0", packages[2].Version)
+	assert.Equal(t, adapters.PackageTypeCask, packages[2].Type)
+	assert.True(t, packages[2].Outdated)
+}
+
+func TestParseBrewInstalled_InvalidJSON(t *testing.T) {
+	_, err := parseBrewInstalled("not json")
+	assert.Error(t, err)
+}
+
+func TestParseBrewCandidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"formula", `{"formulae": [{"name": "jq", "versions": {"stable": "1.7.1"}, "revision": 0}], "casks": []}`, "1.7.1"},
+		{"formula revision", `{"formulae": [{"name": "git", "versions": {"stable": "2.44.0"}, "revision": 1}], "casks": []}`,
+			"2.44.0_1"},
+		{"cask", `{"formulae": [], "casks": [{"token": "firefox", "version": "125.0.1"}]}`, "125.0.1"},
+		{"none", `{"formulae": [], "casks": []}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBrewCandidate(tt.output)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMissing(t *testing.T) {
+	assert.Equal(t, []string{"oniguruma", "gettext"}, parseMissing("jq: oniguruma gettext\n"))
+	assert.Empty(t, parseMissing(""))
+}
+
+func TestVerifyKeg(t *testing.T) {
+	cellar := t.TempDir()
+	keg := filepath.Join(cellar, "1.7.1")
+	require.NoError(t, os.Mkdir(keg, 0o755))
+
+	issues, err := verifyKeg(keg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(keg, installReceipt) + " is missing"}, issues)
+
+	require.NoError(t, os.WriteFile(filepath.Join(keg, installReceipt), []byte("{}"), 0o644))
+	issues, err = verifyKeg(keg)
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	issues, err = verifyKeg(filepath.Join(cellar, "1.6"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(cellar, "1.6") + " is missing"}, issues)
+}
+
+func TestParseBrewMetadata(t *testing.T) {
+	output := `{"formulae": [{"name": "jq", "desc": "Lightweight and flexible command-line JSON processor",
+"homepage": "https://jqlang.github.io/jq/", "license": "MIT",
+"installed": [{"version": "1.7"}, {"version": "1.7.1"}]}], "casks": []}`
+
+	metadata, version, err := parseBrewMetadata(output)
+	require.NoError(t, err)
+	assert.Equal(t, "1.7.1", version)
+	assert.Equal(t, &adapters.PackageMetadata{
+		Description: "Lightweight and flexible command-line JSON processor",
+		Homepage:    "https://jqlang.github.io/jq/",
+		License:     "MIT",
+	}, metadata)
+
+	metadata, version, err = parseBrewMetadata(`{"formulae": [], "casks": [{"desc": "Web browser", "homepage": "https://www.mozilla.org/firefox/"}]}`)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+	assert.Equal(t, "Web browser", metadata.Description)
+
+	_, _, err = parseBrewMetadata(`{"formulae": [], "casks": []}`)
+	assert.Error(t, err)
+}
+
+func TestDirSize(t *testing.T) {
+	keg := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(keg, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(keg, "bin", "jq"), make([]byte, 300), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(keg, installReceipt), make([]byte, 12), 0o644))
+
+	size, err := dirSize(keg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(312), size)
+}
+
+func TestParseDepsTree(t *testing.T) {
+	output := "curl\n├── brotli\n├── openssl@3\n│   └── ca-certificates\n└── zstd\n    └── ca-certificates\n"
+	assert.Equal(t, []string{"brotli", "openssl@3", "ca-certificates", "zstd"}, parseDepsTree(output))
+}
+
+func TestParseBrewList(t *testing.T) {
+	output := "/opt/homebrew/Cellar/jq/1.7.1/bin/jq\n/opt/homebrew/Cellar/jq/1.7.1/share/doc/jq/ (3 files)\n" +
+		"==> App\n/Applications/Firefox.app (89 files, 370.2MB)\n"
+	assert.Equal(t, []string{
+		"/opt/homebrew/Cellar/jq/1.7.1/bin/jq",
+		"/opt/homebrew/Cellar/jq/1.7.1/share/doc/jq/",
+		"/Applications/Firefox.app",
+	}, parseBrewList(output))
+}
+
+func TestKegOwner(t *testing.T) {
+	assert.Equal(t, "jq", kegOwner("/opt/homebrew/Cellar", "/opt/homebrew/Cellar/jq/1.7.1/bin/jq"))
+	assert.Equal(t, "firefox", kegOwner("/opt/homebrew/Caskroom", "/opt/homebrew/Caskroom/firefox/128.0"))
+	assert.Empty(t, kegOwner("/opt/homebrew/Cellar", "/opt/homebrew/Cellar"))
+	assert.Empty(t, kegOwner("/opt/homebrew/Cellar", "/usr/bin/jq"))
+	assert.Empty(t, kegOwner("/opt/homebrew/Cellar", "/opt/homebrew/CellarX/jq"))
+}
+
+func TestParseBrewSearch(t *testing.T) {
+	assert.Equal(t, []adapters.SearchResult{
+		{Name: "jq", Type: adapters.PackageTypeFormula},
+		{Name: "jql", Type: adapters.PackageTypeFormula},
+		{Name: "hashicorp/tap/terraform", Type: adapters.PackageTypeFormula},
+	}, parseBrewSearch("==> Formulae\njq    jql\nhashicorp/tap/terraform\n", adapters.PackageTypeFormula))
+}
+
+func TestDescribeSearchResults(t *testing.T) {
+	info, err := parseQueryInfo(`{
+  "formulae": [
+    {"name": "jq", "full_name": "jq", "tap": "homebrew/core", "desc": "Lightweight JSON processor",
+     "versions": {"stable": "1.7.1"}, "installed": [{"version": "1.7.1"}]},
+    {"name": "terraform", "full_name": "hashicorp/tap/terraform", "tap": "hashicorp/tap",
+     "desc": "Terraform", "versions": {"stable": "1.9.0"}, "installed": []}
+  ],
+  "casks": [
+    {"token": "jq-gui", "full_token": "jq-gui", "tap": "homebrew/cask", "desc": "GUI for jq",
+     "version": "2.0", "installed": null}
+  ]
+}`, "jq")
+	require.NoError(t, err)
+
+	results := []adapters.SearchResult{
+		{Name: "jq", Type: adapters.PackageTypeFormula},
+		{Name: "hashicorp/tap/terraform", Type: adapters.PackageTypeFormula},
+		{Name: "jq-gui", Type: adapters.PackageTypeCask},
+		{Name: "unknown", Type: adapters.PackageTypeCask},
+	}
+	describeSearchResults(results, info)
+	assert.Equal(t, []adapters.SearchResult{
+		{Name: "jq", Description: "Lightweight JSON processor", Version: "1.7.1", Repository: "homebrew/core",
+			Installed: true, Type: adapters.PackageTypeFormula},
+		{Name: "hashicorp/tap/terraform", Description: "Terraform", Version: "1.9.0", Repository: "hashicorp/tap",
+			Type: adapters.PackageTypeFormula},
+		{Name: "jq-gui", Description: "GUI for jq", Version: "2.0", Repository: "homebrew/cask",
+			Type: adapters.PackageTypeCask},
+		{Name: "unknown", Type: adapters.PackageTypeCask},
+	}, results)
+}
+
+func TestOutdatedPackages(t *testing.T) {
+	installed := []adapters.PackageInfo{
+		{Name: "jq", Version: "1.6", AvailableVersions: []string{"1.7.1"}, Outdated: true, Pinned: true,
+			Repository: "homebrew/core"},
+		{Name: "terraform", Version: "1.6.0", AvailableVersions: []string{"1.6.0"}},
+	}
+
+	assert.Equal(t, []adapters.AvailableUpdate{
+		{Name: "jq", CurrentVersion: "1.6", LatestVersion: "1.7.1", Pinned: true, Origin: "homebrew/core"},
+	}, outdatedPackages(installed))
+}
+
+const queryInfoJSON = `{
+  "formulae": [{
+    "name": "wget", "full_name": "wget", "desc": "Internet file retriever",
+    "versions": {"stable": "1.24.5"},
+    "dependencies": ["libidn2", "openssl@3"],
+    "build_dependencies": ["pkgconf"],
+    "optional_dependencies": ["gpgme"],
+    "recommended_dependencies": ["gettext"]
+  }],
+  "casks": [{
+    "token": "font-fira-code", "full_token": "homebrew/cask-fonts/font-fira-code",
+    "desc": "Monospaced font with programming ligatures", "version": "6.2",
+    "depends_on": {"formula": ["fontconfig"]}
+  }]
+}`
+
+func TestBrewDependencies(t *testing.T) {
+	info, err := parseQueryInfo(queryInfoJSON, "wget")
+	require.NoError(t, err)
+	assert.Equal(t, []adapters.Dependency{
+		{Name: "libidn2", Type: adapters.DependencyRuntime, Relation: "depends_on"},
+		{Name: "openssl@3", Type: adapters.DependencyRuntime, Relation: "depends_on"},
+		{Name: "gettext", Type: adapters.DependencyRuntime, Relation: "depends_on :recommended"},
+		{Name: "pkgconf", Type: adapters.DependencyBuild, Relation: "depends_on :build"},
+		{Name: "gpgme", Type: adapters.DependencyOptional, Relation: "depends_on :optional"},
+	}, brewDependencies(info))
+
+	info.Formulae = nil
+	assert.Equal(t, []adapters.Dependency{
+		{Name: "fontconfig", Type: adapters.DependencyRuntime, Relation: "depends_on"},
+	}, brewDependencies(info))
+
+	_, err = parseQueryInfo("not json", "wget")
+	assert.Error(t, err)
+}
+
+func TestParseUses(t *testing.T) {
+	assert.Equal(t, []adapters.Dependency{
+		{Name: "curl", Type: adapters.DependencyRuntime, Relation: "depends_on"},
+		{Name: "git", Type: adapters.DependencyRuntime, Relation: "depends_on"},
+		{Name: "wget", Type: adapters.DependencyRuntime, Relation: "depends_on"},
+	}, parseUses("curl\ngit\nwget\n"))
+	assert.Equal(t, []adapters.Dependency{}, parseUses(""))
+}
+
+func TestBrewVersionHistory(t *testing.T) {
+	info, err := parseQueryInfo(`{"formulae": [{"name": "python", "tap": "homebrew/core",
+		"versions": {"stable": "3.12.2"}}]}`, "python")
+	require.NoError(t, err)
+
+	search := "==> Formulae\npython@3.10   python@3.11   python@3.12\nboost-python3\n"
+	assert.Equal(t, []adapters.VersionRelease{
+		{Version: "3.12.2", Origin: "homebrew/core"},
+		{Version: "3.10", Origin: "homebrew/core"},
+		{Version: "3.11", Origin: "homebrew/core"},
+		{Version: "3.12", Origin: "homebrew/core"},
+	}, brewVersionHistory("python", info, search))
+	assert.Equal(t, []adapters.VersionRelease{}, brewVersionHistory("python", &brewQueryInfo{}, ""))
+}
+
+func TestParseTapPackages(t *testing.T) {
+	output := `[{"name": "hashicorp/tap", "installed": true,
+		"formula_names": ["hashicorp/tap/terraform", "hashicorp/tap/consul"],
+		"cask_tokens": ["hashicorp/tap/hashicorp-vagrant"]}]`
+	names, err := parseTapPackages(output, "hashicorp/tap")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hashicorp/tap/consul", "hashicorp/tap/hashicorp-vagrant", "hashicorp/tap/terraform"}, names)
+
+	_, err = parseTapPackages(`[{"name": "hashicorp/tap", "installed": false}]`, "hashicorp/tap")
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+}
+
+func TestRepositoryPackages(t *testing.T) {
+	info, err := parseQueryInfo(queryInfoJSON, "homebrew/cask-fonts")
+	require.NoError(t, err)
+	assert.Equal(t, []adapters.RepositoryPackage{
+		{Name: "font-fira-code", Version: "6.2", Description: "Monospaced font with programming ligatures"},
+		{Name: "wget", Version: "1.24.5", Description: "Internet file retriever"},
+	}, repositoryPackages(info))
+}
+
+func TestParseBrewVersion(t *testing.T) {
+	assert.Equal(t, "4.2.10", parseBrewVersion("Homebrew 4.2.10\nHomebrew/homebrew-core (git revision 1a2b)\n"))
+	assert.Equal(t, "unexpected", parseBrewVersion("unexpected"))
+}
+
+func TestParseBrewComponents(t *testing.T) {
+	output := `{
+  "formulae": [
+    {"name": "jq", "tap": "homebrew/core", "desc": "Lightweight and flexible command-line JSON processor",
+     "homepage": "https://jqlang.github.io/jq/", "license": "MIT", "linked_keg": "1.7.1",
+     "installed": [{"version": "1.7"}, {"version": "1.7.1"}]},
+    {"name": "tool", "tap": "acme/tools", "license": null, "installed": [{"version": "2.0_1"}]},
+    {"name": "uninstalled", "tap": "homebrew/core", "installed": []}
+  ],
+  "casks": [
+    {"token": "firefox", "tap": "homebrew/cask", "desc": "Web browser", "homepage": "https://www.mozilla.org/firefox/",
+     "installed": "125.0.1"},
+    {"token": "missing", "tap": "homebrew/cask", "installed": null}
+  ]
+}`
+
+	components, err := parseBrewComponents(output, "arm64")
+	assert.NoError(t, err)
+	assert.Equal(t, []adapters.Component{
+		{
+			Name:             "firefox",
+			Version:          "125.0.1",
+			Purl:             "pkg:brew/firefox@125.0.1?type=cask",
+			Supplier:         "Homebrew",
+			SourceRepository: "homebrew/cask",
+			Homepage:         "https://www.mozilla.org/firefox/",
+			Description:      "Web browser",
+		},
+		{
+			Name:             "jq",
+			Version:          "1.7.1",
+			Architecture:     "arm64",
+			Purl:             "pkg:brew/jq@1.7.1",
+			License:          "MIT",
+			Supplier:         "Homebrew",
+			SourceRepository: "homebrew/core",
+			Homepage:         "https://jqlang.github.io/jq/",
+			Description:      "Lightweight and flexible command-line JSON processor",
+		},
+		{
+			Name:             "tool",
+			Version:          "2.0_1",
+			Architecture:     "arm64",
+			Purl:             "pkg:brew/tool@2.0_1?tap=acme%2Ftools",
+			Supplier:         "acme",
+			SourceRepository: "acme/tools",
+		},
+	}, components)
+
+	_, err = parseBrewComponents("not json", "arm64")
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package brew
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// installReceipt is written into every formula keg by 'brew install'.
+const installReceipt = "INSTALL_RECEIPT.json"
+
+// VerifyIntegrity checks that the keg of an installed formula still holds its
+// install receipt. Casks carry no receipt and are not checked.
+func (b *BrewAdapter) VerifyIntegrity(ctx context.Context, name string) (_ []string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "verify", name)
+	defer func() { telemetry.End(span, err) }()
+
+	info, err := b.DetectInstalled(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Installed || info.Type == adapters.PackageTypeCask {
+		return nil, nil
+	}
+
+	keg, err := b.kegPath(ctx, name, info.Version)
+	if err != nil {
+		return nil, err
+	}
+	return verifyKeg(keg)
+}
+
+// kegPath returns the keg directory of version of the formula name.
+func (b *BrewAdapter) kegPath(ctx context.Context, name, version string) (string, error) {
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"--cellar", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return "", commandError("locate the keg of", name, result, err)
+	}
+	return filepath.Join(strings.TrimSpace(result.Stdout), version), nil
+}
+
+// verifyKeg reports a keg directory that is missing or has no install receipt.
+func verifyKeg(keg string) ([]string, error) {
+	if _, err := os.Stat(keg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{keg + " is missing"}, nil
+		}
+		return nil, err
+	}
+	receipt := filepath.Join(keg, installReceipt)
+	if _, err := os.Stat(receipt); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{receipt + " is missing"}, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// MissingDependencies returns the dependencies of name that are not installed,
+// as reported by 'brew missing'.
+func (b *BrewAdapter) MissingDependencies(ctx context.Context, name string) (_ []string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "missing_dependencies", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"missing", name}, executor.ExecOpts{
+		Timeout: 60 * time.Second,
+	})
+	// brew missing exits non-zero when it finds missing dependencies
+	if err != nil || (result.ExitCode != 0 && strings.TrimSpace(result.Stdout) == "") {
+		return nil, commandError("check dependencies of", name, result, err)
+	}
+
+	return parseMissing(result.Stdout), nil
+}
+
+// parseMissing parses "formula: dependency dependency" lines.
+func parseMissing(output string) []string {
+	var missing []string
+	for _, line := range strings.Split(output, "\n") {
+		_, dependencies, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		missing = append(missing, strings.Fields(dependencies)...)
+	}
+	return missing
+}
+
+// Reinstall reinstalls the installed version of name.
+func (b *BrewAdapter) Reinstall(ctx context.Context, name string, packageType adapters.PackageType) (err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "reinstall", name)
+	defer func() { telemetry.End(span, err) }()
+
+	defer b.inventory.Invalidate()
+
+	isCask := packageType == adapters.PackageTypeCask
+	if packageType == adapters.PackageTypeAuto {
+		if isCask, err = b.isCask(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"reinstall"}
+	if isCask {
+		args = append(args, "--cask")
+	}
+	args = append(args, name)
+
+	result, err := b.executor.Run(ctx, b.brewPath, args, progressOpts(ctx, 300*time.Second))
+	if err != nil || result.ExitCode != 0 {
+		return commandError("reinstall", name, result, err)
+	}
+
+	return nil
+}
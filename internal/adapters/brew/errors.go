@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package brew
+
+import (
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// brewErrorPatterns recognize Homebrew failure messages.
+var brewErrorPatterns = []adapters.OutputPattern{
+	{Kind: adapters.ErrLockHeld, Substrings: []string{
+		"Another active Homebrew",
+		"has already locked",
+		"is already locked",
+	}},
+	{Kind: adapters.ErrPermissionDenied, Substrings: []string{
+		"Permission denied",
+		"is not writable",
+		"Running Homebrew as root is extremely dangerous",
+		"Operation not permitted",
+	}},
+	{Kind: adapters.ErrNotFound, Substrings: []string{
+		"No available formula",
+		"No available cask",
+		"No available tap",
+		"No formulae or casks found",
+		"No formulae found",
+		"No casks found",
+		"No cask with this name exists",
+		"No such keg",
+		"is not installed",
+		"Invalid tap name",
+		"Repository not found",
+	}},
+	{Kind: adapters.ErrDependencyConflict, Substrings: []string{
+		"because conflicting formulae are installed",
+		"conflicts with",
+		"Refusing to uninstall",
+		"because it is required by",
+	}},
+	{Kind: adapters.ErrNetwork, Substrings: []string{
+		"Failed to download",
+		"Could not resolve host",
+		"Failed to connect",
+		"Connection timed out",
+		"SSL certificate problem",
+		"Download failed",
+		"Network is unreachable",
+	}},
+}
+
+// classifyBrewOutput maps brew output to an adapter error kind.
+func classifyBrewOutput(stdout, stderr string) error {
+	return adapters.MatchOutput(brewErrorPatterns, stderr+"\n"+stdout)
+}
+
+// commandError builds a typed error for a failed brew command.
+func commandError(operation, target string, result executor.ExecResult, err error) error {
+	return adapters.NewOperationError("brew", operation, target, result, err, classifyBrewOutput)
+}
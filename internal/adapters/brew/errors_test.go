@@ -0,0 +1,34 @@
+package brew
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestClassifyBrewOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		want   error
+	}{
+		{"lock", "Error: Another active Homebrew update process is already in progress.", adapters.ErrLockHeld},
+		{"formula", "Error: No available formula with the name \"nosuchformula\".", adapters.ErrNotFound},
+		{"cask", "Error: Cask 'nosuchcask' is unavailable: No Cask with this name exists.", adapters.ErrNotFound},
+		{"tap", "Error: Invalid tap name 'nope'", adapters.ErrNotFound},
+		{"permission", "Error: /opt/homebrew/Cellar is not writable.", adapters.ErrPermissionDenied},
+		{"conflict", "Error: Refusing to uninstall /opt/homebrew/Cellar/openssl@3\nbecause it is required by curl",
+			adapters.ErrDependencyConflict},
+		{"network", "curl: (6) Could not resolve host: ghcr.io\nError: Failed to download resource \"jq\"",
+			adapters.ErrNetwork},
+		{"unrecognized", "Error: An exception occurred within a child process", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyBrewOutput("", tt.stderr))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package brew
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// maxCapturedOutput bounds the output kept from long-running brew commands.
+const maxCapturedOutput = 1 << 20
+
+// progressOpts returns ExecOpts that stream brew's phase headers ("==> Pouring ...")
+// as ProgressEvents while the command runs.
+func progressOpts(ctx context.Context, timeout time.Duration) executor.ExecOpts {
+	return executor.ExecOpts{
+		Timeout:        timeout,
+		Env:            noAutoUpdateEnv,
+		MaxOutputBytes: maxCapturedOutput,
+		OnOutput: func(line executor.OutputLine) {
+			if event, ok := parseBrewProgressLine(line.Text); ok {
+				adapters.ReportProgress(ctx, event)
+			}
+		},
+	}
+}
+
+// brewPhases maps the verb of a brew "==>" header to a progress phase.
+var brewPhases = map[string]string{
+	"Fetching":     adapters.ProgressPhaseDownload,
+	"Downloading":  adapters.ProgressPhaseDownload,
+	"Pouring":      adapters.ProgressPhaseUnpack,
+	"Installing":   adapters.ProgressPhaseInstall,
+	"Upgrading":    adapters.ProgressPhaseInstall,
+	"Linking":      adapters.ProgressPhaseConfigure,
+	"Moving":       adapters.ProgressPhaseConfigure,
+	"Uninstalling": adapters.ProgressPhaseRemove,
+}
+
+// parseBrewProgressLine parses brew output lines such as
+// "==> Pouring llvm--17.0.6.arm64_sonoma.bottle.tar.gz" or the
+// "🍺  /opt/homebrew/Cellar/jq/1.7.1: 19 files, 1.3MB" completion summary.
+// Brew prints no percentages when its output is not a terminal.
+func parseBrewProgressLine(line string) (adapters.ProgressEvent, bool) {
+	line = strings.TrimSpace(line)
+
+	if rest, ok := strings.CutPrefix(line, "🍺"); ok {
+		rest = strings.TrimSpace(rest)
+		event := adapters.ProgressEvent{
+			Manager: "brew",
+			Phase:   adapters.ProgressPhaseComplete,
+			Percent: 100,
+			Message: rest,
+		}
+		// The Cellar path ends in <name>/<version>
+		if path, _, found := strings.Cut(rest, ":"); found {
+			segments := strings.Split(path, "/")
+			if len(segments) >= 2 {
+				event.Package = segments[len(segments)-2]
+			}
+		}
+		return event, true
+	}
+
+	rest, ok := strings.CutPrefix(line, "==> ")
+	if !ok {
+		return adapters.ProgressEvent{}, false
+	}
+
+	verb, subject, _ := strings.Cut(rest, " ")
+	phase, known := brewPhases[verb]
+	if !known {
+		return adapters.ProgressEvent{}, false
+	}
+
+	event := adapters.ProgressEvent{
+		Manager: "brew",
+		Phase:   phase,
+		Percent: -1,
+		Message: rest,
+	}
+	// Only these headers name the package; others name a URL, bottle file or keg path
+	switch verb {
+	case "Fetching", "Installing", "Upgrading":
+		if fields := strings.Fields(subject); len(fields) > 0 {
+			event.Package = fields[0]
+		}
+	}
+
+	return event, true
+}
@@ -0,0 +1,39 @@
+package brew
+
+import (
+	"testing"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBrewProgressLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		ok    bool
+		phase string
+		pkg   string
+	}{
+		{"==> Fetching llvm", true, adapters.ProgressPhaseDownload, "llvm"},
+		{"==> Downloading https://ghcr.io/v2/homebrew/core/llvm/blobs/sha256:abc", true, adapters.ProgressPhaseDownload, ""},
+		{"==> Pouring llvm--17.0.6.arm64_sonoma.bottle.tar.gz", true, adapters.ProgressPhaseUnpack, ""},
+		{"==> Installing llvm dependency: z3", true, adapters.ProgressPhaseInstall, "llvm"},
+		{"🍺  /opt/homebrew/Cellar/jq/1.7.1: 19 files, 1.3MB", true, adapters.ProgressPhaseComplete, "jq"},
+		{"==> Caveats", false, "", ""},
+		{"######################################################################## 100.0%", false, "", ""},
+		{"==> Installing", true, adapters.ProgressPhaseInstall, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			event, ok := parseBrewProgressLine(tt.line)
+			assert.Equal(t, tt.ok, ok)
+			if !tt.ok {
+				return
+			}
+			assert.Equal(t, "brew", event.Manager)
+			assert.Equal(t, tt.phase, event.Phase)
+			assert.Equal(t, tt.pkg, event.Package)
+		})
+	}
+}
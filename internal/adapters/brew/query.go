@@ -0,0 +1,434 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package brew
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// brewQueryInfo is the part of 'brew info --json=v2' read by the queries in
+// this file.
+type brewQueryInfo struct {
+	Formulae []struct {
+		Name                    string   `json:"name"`
+		FullName                string   `json:"full_name"`
+		Tap                     string   `json:"tap"`
+		Desc                    string   `json:"desc"`
+		Versions                versions `json:"versions"`
+		Dependencies            []string `json:"dependencies"`
+		BuildDependencies       []string `json:"build_dependencies"`
+		OptionalDependencies    []string `json:"optional_dependencies"`
+		RecommendedDependencies []string `json:"recommended_dependencies"`
+		Installed               []struct {
+			Version string `json:"version"`
+		} `json:"installed"`
+	} `json:"formulae"`
+	Casks []struct {
+		Token     string  `json:"token"`
+		FullToken string  `json:"full_token"`
+		Tap       string  `json:"tap"`
+		Desc      string  `json:"desc"`
+		Version   string  `json:"version"`
+		Installed *string `json:"installed"`
+		DependsOn struct {
+			Formula []string `json:"formula"`
+			Cask    []string `json:"cask"`
+		} `json:"depends_on"`
+	} `json:"casks"`
+}
+
+// queryInfo runs 'brew info --json=v2' for names.
+func (b *BrewAdapter) queryInfo(ctx context.Context, operation, target string, names ...string) (*brewQueryInfo, error) {
+	args := append([]string{"info", "--json=v2"}, names...)
+	result, err := b.executor.Run(ctx, b.brewPath, args, executor.ExecOpts{
+		Timeout: 2 * time.Minute,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError(operation, target, result, err)
+	}
+
+	return parseQueryInfo(result.Stdout, target)
+}
+
+// parseQueryInfo parses 'brew info --json=v2' output.
+func parseQueryInfo(output, target string) (*brewQueryInfo, error) {
+	var info brewQueryInfo
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info JSON for %s: %w", target, err)
+	}
+	return &info, nil
+}
+
+// Dependencies returns the declared dependencies of a formula, or the formulae
+// and casks a cask depends on. Recommended dependencies are installed by
+// default, so they are reported as runtime dependencies. Relations follow the
+// formula DSL, e.g. "depends_on :build".
+func (b *BrewAdapter) Dependencies(ctx context.Context, name string) (_ []adapters.Dependency, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "dependencies", name)
+	defer func() { telemetry.End(span, err) }()
+
+	info, err := b.queryInfo(ctx, "query dependencies of", name, name)
+	if err != nil {
+		return nil, err
+	}
+	return brewDependencies(info), nil
+}
+
+// brewDependsOn is the relation of a runtime dependency.
+const brewDependsOn = "depends_on"
+
+// brewDependencies converts the first formula or cask in info to dependencies.
+func brewDependencies(info *brewQueryInfo) []adapters.Dependency {
+	dependencies := []adapters.Dependency{}
+	add := func(names []string, depType adapters.DependencyType, relation string) {
+		for _, name := range names {
+			dependencies = append(dependencies, adapters.Dependency{Name: name, Type: depType, Relation: relation})
+		}
+	}
+
+	switch {
+	case len(info.Formulae) > 0:
+		formula := info.Formulae[0]
+		add(formula.Dependencies, adapters.DependencyRuntime, brewDependsOn)
+		add(formula.RecommendedDependencies, adapters.DependencyRuntime, brewDependsOn+" :recommended")
+		add(formula.BuildDependencies, adapters.DependencyBuild, brewDependsOn+" :build")
+		add(formula.OptionalDependencies, adapters.DependencyOptional, brewDependsOn+" :optional")
+	case len(info.Casks) > 0:
+		cask := info.Casks[0]
+		add(cask.DependsOn.Formula, adapters.DependencyRuntime, brewDependsOn)
+		add(cask.DependsOn.Cask, adapters.DependencyRuntime, brewDependsOn)
+	}
+	return dependencies
+}
+
+// ReverseDependencies returns the installed formulae and casks that use name
+// at runtime, as listed by 'brew uses --installed'.
+func (b *BrewAdapter) ReverseDependencies(ctx context.Context, name string) (_ []adapters.Dependency, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "reverse_dependencies", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"uses", "--installed", name}, executor.ExecOpts{
+		Timeout: 2 * time.Minute,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query reverse dependencies of", name, result, err)
+	}
+
+	return parseUses(result.Stdout), nil
+}
+
+// parseUses parses 'brew uses' output, one package per line or column.
+func parseUses(output string) []adapters.Dependency {
+	dependents := []adapters.Dependency{}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "==>") {
+			continue
+		}
+		for _, name := range strings.Fields(line) {
+			dependents = append(dependents, adapters.Dependency{
+				Name:     name,
+				Type:     adapters.DependencyRuntime,
+				Relation: brewDependsOn,
+			})
+		}
+	}
+	return dependents
+}
+
+// VersionHistory returns the stable version of name followed by the versions
+// of its versioned formulae, e.g. "3.11" for python@3.11. Homebrew only
+// publishes the current version of each formula.
+func (b *BrewAdapter) VersionHistory(ctx context.Context, name string) (_ []adapters.VersionRelease, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "version_history", name)
+	defer func() { telemetry.End(span, err) }()
+
+	info, err := b.queryInfo(ctx, "query versions of", name, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// brew search exits non-zero when nothing matches, so only the output counts
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"search", name + "@"}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil {
+		return nil, commandError("search versions of", name, result, err)
+	}
+
+	return brewVersionHistory(name, info, result.Stdout), nil
+}
+
+// brewVersionHistory combines the stable version from info with the versioned
+// formulae in 'brew search name@' output. Versioned formulae are reported
+// from the tap of name, where Homebrew keeps them.
+func brewVersionHistory(name string, info *brewQueryInfo, searchOutput string) []adapters.VersionRelease {
+	releases := []adapters.VersionRelease{}
+	seen := map[string]bool{}
+	tap := ""
+	add := func(version string) {
+		if version != "" && !seen[version] {
+			seen[version] = true
+			releases = append(releases, adapters.VersionRelease{Version: version, Origin: tap})
+		}
+	}
+
+	switch {
+	case len(info.Formulae) > 0:
+		tap = info.Formulae[0].Tap
+		add(info.Formulae[0].Versions.Stable)
+	case len(info.Casks) > 0:
+		tap = info.Casks[0].Tap
+		add(info.Casks[0].Version)
+	}
+
+	for _, line := range strings.Split(searchOutput, "\n") {
+		if strings.Contains(line, "==>") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if version, ok := strings.CutPrefix(field, name+"@"); ok {
+				add(version)
+			}
+		}
+	}
+	return releases
+}
+
+// brewTapInfo is the part of 'brew tap-info --json' read by RepositoryPackages.
+type brewTapInfo struct {
+	Name         string   `json:"name"`
+	Installed    bool     `json:"installed"`
+	FormulaNames []string `json:"formula_names"`
+	CaskTokens   []string `json:"cask_tokens"`
+}
+
+// RepositoryPackages lists the formulae and casks of a tap, e.g.
+// "homebrew/cask-fonts", with their descriptions and stable versions.
+func (b *BrewAdapter) RepositoryPackages(ctx context.Context, repository string, limit int) (_ []adapters.RepositoryPackage, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "repository_packages", repository)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"tap-info", "--json", repository}, executor.ExecOpts{
+		Timeout: 60 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query tap", repository, result, err)
+	}
+
+	names, err := parseTapPackages(result.Stdout, repository)
+	if err != nil {
+		return nil, err
+	}
+	if limit >= 0 && len(names) > limit {
+		names = names[:limit]
+	}
+	if len(names) == 0 {
+		return []adapters.RepositoryPackage{}, nil
+	}
+
+	info, err := b.queryInfo(ctx, "describe packages of", repository, names...)
+	if err != nil {
+		return nil, err
+	}
+	return repositoryPackages(info), nil
+}
+
+// parseTapPackages returns the sorted full names of the formulae and casks in
+// 'brew tap-info --json' output.
+func parseTapPackages(output, repository string) ([]string, error) {
+	var taps []brewTapInfo
+	if err := json.Unmarshal([]byte(output), &taps); err != nil {
+		return nil, fmt.Errorf("failed to parse brew tap-info JSON for %s: %w", repository, err)
+	}
+	if len(taps) == 0 || !taps[0].Installed {
+		return nil, fmt.Errorf("tap %s is not tapped: %w", repository, adapters.ErrNotFound)
+	}
+
+	names := append(append([]string{}, taps[0].FormulaNames...), taps[0].CaskTokens...)
+	sort.Strings(names)
+	return names, nil
+}
+
+// repositoryPackages converts the formulae and casks in info to repository
+// packages named without their tap prefix, sorted by name.
+func repositoryPackages(info *brewQueryInfo) []adapters.RepositoryPackage {
+	packages := make([]adapters.RepositoryPackage, 0, len(info.Formulae)+len(info.Casks))
+	for _, formula := range info.Formulae {
+		packages = append(packages, adapters.RepositoryPackage{
+			Name:        formula.Name,
+			Version:     formula.Versions.Stable,
+			Description: formula.Desc,
+		})
+	}
+	for _, cask := range info.Casks {
+		packages = append(packages, adapters.RepositoryPackage{
+			Name:        cask.Token,
+			Version:     cask.Version,
+			Description: cask.Desc,
+		})
+	}
+
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages
+}
+
+// SearchCatalog searches formula and cask names with 'brew search /pattern/'
+// and describes the matches with 'brew info', which reports their stable
+// version, tap and whether they are installed.
+func (b *BrewAdapter) SearchCatalog(
+	ctx context.Context, pattern string, keep func(name string) bool) (_ []adapters.SearchResult, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "search", pattern)
+	defer func() { telemetry.End(span, err) }()
+
+	results := []adapters.SearchResult{}
+	for _, packageType := range []adapters.PackageType{adapters.PackageTypeFormula, adapters.PackageTypeCask} {
+		args := []string{"search", "--" + string(packageType), "/" + pattern + "/"}
+		result, runErr := b.executor.Run(ctx, b.brewPath, args, executor.ExecOpts{
+			Timeout: 60 * time.Second,
+		})
+		if runErr != nil || result.ExitCode != 0 {
+			// brew search fails when nothing matches
+			if cmdErr := commandError("search packages matching", pattern, result, runErr); !errors.Is(cmdErr, adapters.ErrNotFound) {
+				return nil, cmdErr
+			}
+			continue
+		}
+		results = append(results, parseBrewSearch(result.Stdout, packageType)...)
+	}
+	results = adapters.KeepSearchResults(results, keep)
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	info, err := b.queryInfo(ctx, "describe packages matching", pattern, names...)
+	if err != nil {
+		return nil, err
+	}
+	describeSearchResults(results, info)
+	return results, nil
+}
+
+// parseBrewSearch parses the names listed by 'brew search', which may be
+// printed in columns under "==>" headers.
+func parseBrewSearch(output string, packageType adapters.PackageType) []adapters.SearchResult {
+	var results []adapters.SearchResult
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "==>") {
+			continue
+		}
+		for _, name := range strings.Fields(line) {
+			results = append(results, adapters.SearchResult{Name: name, Type: packageType})
+		}
+	}
+	return results
+}
+
+// describeSearchResults fills in the description, stable version, tap and
+// installed status of results from 'brew info' output. Results are matched by
+// name or, for packages from third-party taps, by their full name.
+func describeSearchResults(results []adapters.SearchResult, info *brewQueryInfo) {
+	formulae := map[string]adapters.SearchResult{}
+	for _, formula := range info.Formulae {
+		described := adapters.SearchResult{
+			Description: formula.Desc,
+			Version:     formula.Versions.Stable,
+			Repository:  formula.Tap,
+			Installed:   len(formula.Installed) > 0,
+		}
+		formulae[formula.Name] = described
+		formulae[formula.FullName] = described
+	}
+	casks := map[string]adapters.SearchResult{}
+	for _, cask := range info.Casks {
+		described := adapters.SearchResult{
+			Description: cask.Desc,
+			Version:     cask.Version,
+			Repository:  cask.Tap,
+			Installed:   cask.Installed != nil,
+		}
+		casks[cask.Token] = described
+		casks[cask.FullToken] = described
+	}
+
+	for i, result := range results {
+		described, ok := formulae[result.Name]
+		if result.Type == adapters.PackageTypeCask {
+			described, ok = casks[result.Name]
+		}
+		if !ok {
+			continue
+		}
+		described.Name = result.Name
+		described.Type = result.Type
+		results[i] = described
+	}
+}
+
+// Describe reports the Homebrew version from 'brew --version'.
+func (b *BrewAdapter) Describe(ctx context.Context) (*adapters.ManagerDetails, error) {
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"--version"}, executor.ExecOpts{
+		Timeout: 10 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("query version of", b.brewPath, result, err)
+	}
+
+	path := b.brewPath
+	if resolved, err := exec.LookPath(path); err == nil {
+		path = resolved
+	}
+	return &adapters.ManagerDetails{
+		Version: parseBrewVersion(result.Stdout),
+		Path:    path,
+	}, nil
+}
+
+// parseBrewVersion extracts the version from "Homebrew 4.2.0".
+func parseBrewVersion(output string) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	firstLine = strings.TrimSpace(firstLine)
+	if version, ok := strings.CutPrefix(firstLine, "Homebrew "); ok {
+		return version
+	}
+	return firstLine
+}
+
+// Ecosystem returns "Homebrew"; formulae are not tied to an OS release.
+func (b *BrewAdapter) Ecosystem(_ context.Context) (string, error) {
+	return "Homebrew", nil
+}
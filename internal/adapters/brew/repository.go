@@ -61,19 +61,26 @@ func (b *BrewRepositoryManager) AddRepository(ctx context.Context, name, uri, gp
 		return fmt.Errorf("homebrew taps do not support GPG keys")
 	}
 
-	// Use the URI as the tap name
+	// Use the URI as the tap name, unless it is a remote URL for the tap named by name
 	tapName := uri
 	if tapName == "" {
 		tapName = name
 	}
-
-	result, err := b.executor.Run(ctx, b.brewPath, []string{"tap", tapName}, executor.ExecOpts{
+	args := []string{"tap", tapName}
+	opts := executor.ExecOpts{
 		Timeout: 120 * time.Second, // 2 minutes for tap addition
-	})
+	}
+	if isTapRemoteURL(uri) {
+		// Private tap URLs often embed access tokens, so keep them out of the logs
+		tapName = name
+		args = []string{"tap", name, uri}
+		opts.SensitiveArgs = []int{2}
+	}
+
+	result, err := b.executor.Run(ctx, b.brewPath, args, opts)
 
 	if err != nil || result.ExitCode != 0 {
-		return fmt.Errorf("failed to add tap %s: exit code %d, error: %w, stderr: %s",
-			tapName, result.ExitCode, err, result.Stderr)
+		return commandError("add tap", tapName, result, err)
 	}
 
 	return nil
@@ -96,8 +103,7 @@ func (b *BrewRepositoryManager) RemoveRepository(ctx context.Context, name strin
 		}
 
 		if err != nil || result.ExitCode != 0 {
-			return fmt.Errorf("failed to remove tap %s: exit code %d, error: %w, stderr: %s",
-				name, result.ExitCode, err, result.Stderr)
+			return commandError("remove tap", name, result, err)
 		}
 	}
 
@@ -150,3 +156,9 @@ func (b *BrewRepositoryManager) IsTapInstalled(ctx context.Context, tapName stri
 
 	return false, nil
 }
+
+// isTapRemoteURL reports whether uri is a git remote rather than a tap name
+// such as "homebrew/cask-fonts".
+func isTapRemoteURL(uri string) bool {
+	return strings.Contains(uri, "://") || strings.HasPrefix(uri, "git@")
+}
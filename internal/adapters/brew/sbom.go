@@ -0,0 +1,153 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package brew
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// brewComponents is the part of 'brew info --json=v2 --installed' read by
+// ListComponents.
+type brewComponents struct {
+	Formulae []struct {
+		Name      string             `json:"name"`
+		Tap       string             `json:"tap"`
+		Desc      string             `json:"desc"`
+		Homepage  string             `json:"homepage"`
+		License   string             `json:"license"`
+		LinkedKeg string             `json:"linked_keg"`
+		Installed []installedVersion `json:"installed"`
+	} `json:"formulae"`
+	Casks []struct {
+		Token     string `json:"token"`
+		Tap       string `json:"tap"`
+		Desc      string `json:"desc"`
+		Homepage  string `json:"homepage"`
+		Installed string `json:"installed"`
+	} `json:"casks"`
+}
+
+// brewArchitectures maps Go architectures to the names Homebrew bottles use.
+var brewArchitectures = map[string]string{
+	"amd64": "x86_64",
+	"arm64": "arm64",
+}
+
+// ListComponents describes every installed formula and cask from
+// 'brew info --json=v2 --installed'. Formulae declare SPDX licenses; casks
+// declare none. Formulae are reported for the host architecture, since
+// bottles are built per architecture.
+func (b *BrewAdapter) ListComponents(ctx context.Context) (_ []adapters.Component, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "list_components")
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"info", "--json=v2", "--installed"}, executor.ExecOpts{
+		Timeout: 120 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("list", "installed packages", result, err)
+	}
+	return parseBrewComponents(result.Stdout, brewArchitectures[runtime.GOARCH])
+}
+
+// parseBrewComponents converts 'brew info --json=v2 --installed' output into
+// components sorted by name.
+func parseBrewComponents(output, architecture string) ([]adapters.Component, error) {
+	var info brewComponents
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info v2 JSON: %w", err)
+	}
+
+	components := make([]adapters.Component, 0, len(info.Formulae)+len(info.Casks))
+	for _, formula := range info.Formulae {
+		if len(formula.Installed) == 0 {
+			continue
+		}
+		version := formula.LinkedKeg
+		if version == "" {
+			version = formula.Installed[len(formula.Installed)-1].Version
+		}
+		components = append(components, adapters.Component{
+			Name:             formula.Name,
+			Version:          version,
+			Architecture:     architecture,
+			Purl:             brewPackageURL(formula.Name, version, formula.Tap, false),
+			License:          formula.License,
+			Supplier:         tapSupplier(formula.Tap),
+			SourceRepository: formula.Tap,
+			Homepage:         formula.Homepage,
+			Description:      formula.Desc,
+		})
+	}
+	for _, cask := range info.Casks {
+		if cask.Installed == "" {
+			continue
+		}
+		components = append(components, adapters.Component{
+			Name:             cask.Token,
+			Version:          cask.Installed,
+			Purl:             brewPackageURL(cask.Token, cask.Installed, cask.Tap, true),
+			Supplier:         tapSupplier(cask.Tap),
+			SourceRepository: cask.Tap,
+			Homepage:         cask.Homepage,
+			Description:      cask.Desc,
+		})
+	}
+
+	sort.SliceStable(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+	return components, nil
+}
+
+// brewPackageURL builds a "pkg:brew" package URL. Packages from taps other
+// than the official homebrew/core and homebrew/cask taps are qualified with
+// their tap, and casks with "type=cask".
+func brewPackageURL(name, version, tap string, cask bool) string {
+	qualifiers := map[string]string{}
+	if tap != "" && tap != "homebrew/core" && tap != "homebrew/cask" {
+		qualifiers["tap"] = tap
+	}
+	if cask {
+		qualifiers["type"] = "cask"
+	}
+	return adapters.PackageURL("brew", "", name, version, qualifiers)
+}
+
+// tapSupplier names the organization distributing a tap: "Homebrew" for the
+// official homebrew/* taps, otherwise the tap's owner.
+func tapSupplier(tap string) string {
+	owner, _, _ := strings.Cut(tap, "/")
+	if owner == "homebrew" {
+		return "Homebrew"
+	}
+	return owner
+}
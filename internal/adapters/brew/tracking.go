@@ -0,0 +1,248 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package brew
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// brewMetadata is the part of 'brew info --json=v2' read by Metadata.
+type brewMetadata struct {
+	Formulae []struct {
+		Desc      string             `json:"desc"`
+		Homepage  string             `json:"homepage"`
+		License   string             `json:"license"`
+		Installed []installedVersion `json:"installed"`
+	} `json:"formulae"`
+	Casks []struct {
+		Desc     string `json:"desc"`
+		Homepage string `json:"homepage"`
+	} `json:"casks"`
+}
+
+// Metadata describes a formula or cask from 'brew info'. The installed size is
+// measured from the keg, so it is only reported for formulae.
+func (b *BrewAdapter) Metadata(ctx context.Context, name string) (_ *adapters.PackageMetadata, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "metadata", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"info", "--json=v2", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("read metadata of", name, result, err)
+	}
+
+	metadata, version, err := parseBrewMetadata(result.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		keg, err := b.kegPath(ctx, name, version)
+		if err != nil {
+			return nil, err
+		}
+		if metadata.InstalledSize, err = dirSize(keg); err != nil {
+			return nil, err
+		}
+	}
+	return metadata, nil
+}
+
+// parseBrewMetadata returns the metadata in 'brew info --json=v2' output, and
+// the installed version of a formula.
+func parseBrewMetadata(output string) (*adapters.PackageMetadata, string, error) {
+	var info brewMetadata
+	if err := json.Unmarshal([]byte(output), &info); err != nil {
+		return nil, "", fmt.Errorf("failed to parse brew info JSON: %w", err)
+	}
+
+	switch {
+	case len(info.Formulae) > 0:
+		formula := info.Formulae[0]
+		version := ""
+		if len(formula.Installed) > 0 {
+			version = formula.Installed[len(formula.Installed)-1].Version
+		}
+		return &adapters.PackageMetadata{
+			Description: formula.Desc,
+			Homepage:    formula.Homepage,
+			License:     formula.License,
+		}, version, nil
+	case len(info.Casks) > 0:
+		return &adapters.PackageMetadata{Description: info.Casks[0].Desc, Homepage: info.Casks[0].Homepage}, "", nil
+	}
+	return nil, "", fmt.Errorf("no package info found in brew info output")
+}
+
+// dirSize sums the sizes of the regular files under dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// DependencyTree returns the installed formulae name depends on, from
+// 'brew deps --tree'.
+func (b *BrewAdapter) DependencyTree(ctx context.Context, name string) (_ map[string]string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "dependency_tree", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"deps", "--tree", "--installed", name}, executor.ExecOpts{
+		Timeout: 60 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("read dependencies of", name, result, err)
+	}
+
+	installed, err := b.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string, len(installed))
+	for _, pkg := range installed {
+		versions[pkg.Name] = pkg.Version
+	}
+
+	tree := map[string]string{}
+	for _, dependency := range parseDepsTree(result.Stdout) {
+		tree[dependency] = versions[dependency]
+	}
+	return tree, nil
+}
+
+// parseDepsTree returns the dependencies drawn by 'brew deps --tree', whose
+// first line is the formula itself:
+//
+//	curl
+//	├── brotli
+//	└── openssl@3
+//	    └── ca-certificates
+func parseDepsTree(output string) []string {
+	var dependencies []string
+	seen := map[string]bool{}
+	for i, line := range strings.Split(output, "\n") {
+		name := strings.TrimLeft(line, "│├└─  ")
+		if i == 0 || name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		dependencies = append(dependencies, name)
+	}
+	return dependencies
+}
+
+// Files returns the files of a formula, or the artifacts of a cask, as listed
+// by 'brew list'.
+func (b *BrewAdapter) Files(ctx context.Context, name string) (_ []string, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "files", name)
+	defer func() { telemetry.End(span, err) }()
+
+	result, err := b.executor.Run(ctx, b.brewPath, []string{"list", name}, executor.ExecOpts{
+		Timeout: 30 * time.Second,
+	})
+	if err != nil || result.ExitCode != 0 {
+		return nil, commandError("list files of", name, result, err)
+	}
+	return parseBrewList(result.Stdout), nil
+}
+
+// parseBrewList parses file paths from 'brew list', dropping the cask section
+// headers and the "(12 files, 3.4MB)" summaries that follow directories.
+func parseBrewList(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "/") {
+			continue
+		}
+		if i := strings.Index(line, " ("); i > 0 {
+			line = line[:i]
+		}
+		files = append(files, line)
+	}
+	return files
+}
+
+// FileOwner resolves the symbolic links Homebrew places in its prefix, such as
+// /opt/homebrew/bin/jq, and reports the formula keg or cask the target lives in.
+func (b *BrewAdapter) FileOwner(ctx context.Context, path string) (_ *adapters.FileOwnership, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "file_owner", path)
+	defer func() { telemetry.End(span, err) }()
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s does not exist: %w", path, adapters.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	for _, flag := range []string{"--cellar", "--caskroom"} {
+		result, runErr := b.executor.Run(ctx, b.brewPath, []string{flag}, executor.ExecOpts{
+			Timeout: 30 * time.Second,
+		})
+		if runErr != nil || result.ExitCode != 0 {
+			return nil, commandError("locate the "+strings.TrimPrefix(flag, "--")+" for", path, result, runErr)
+		}
+		root := strings.TrimSpace(result.Stdout)
+		if real, err := filepath.EvalSymlinks(root); err == nil {
+			root = real
+		}
+		if owner := kegOwner(root, resolved); owner != "" {
+			return &adapters.FileOwnership{Packages: []string{owner}, Path: resolved}, nil
+		}
+	}
+	return nil, fmt.Errorf("no installed formula or cask owns %s: %w", path, adapters.ErrNotFound)
+}
+
+// kegOwner returns the formula or cask directory under root that contains
+// path, e.g. "jq" for <cellar>/jq/1.7.1/bin/jq, or "" when path is outside root.
+func kegOwner(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	owner, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	return owner
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package brew
+
+import (
+	"context"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// ListUpdates returns the outdated formulae and casks. The shared inventory
+// already records outdated and pinned status for every package. Homebrew does
+// not distinguish security updates.
+func (b *BrewAdapter) ListUpdates(ctx context.Context) (_ []adapters.AvailableUpdate, err error) {
+	ctx, span := adapters.StartSpan(ctx, "brew", "list_updates")
+	defer func() { telemetry.End(span, err) }()
+
+	installed, err := b.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return outdatedPackages(installed), nil
+}
+
+// outdatedPackages returns the installed packages reported as outdated.
+func outdatedPackages(installed []adapters.PackageInfo) []adapters.AvailableUpdate {
+	var updates []adapters.AvailableUpdate
+	for _, pkg := range installed {
+		if !pkg.Outdated {
+			continue
+		}
+
+		latestVersion := ""
+		if len(pkg.AvailableVersions) > 0 {
+			latestVersion = pkg.AvailableVersions[0]
+		}
+
+		updates = append(updates, adapters.AvailableUpdate{
+			Name:           pkg.Name,
+			CurrentVersion: pkg.Version,
+			LatestVersion:  latestVersion,
+			Pinned:         pkg.Pinned,
+			Origin:         pkg.Repository,
+		})
+	}
+	return updates
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// Error kinds reported by adapters. Use errors.Is to test an adapter error
+// against them; OperationError wraps one when a failure could be classified.
+var (
+	// ErrNotFound means the package, cask or repository does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrVersionUnavailable means the package exists but not at the requested version
+	ErrVersionUnavailable = errors.New("requested version is not available")
+	// ErrLockHeld means another process holds the package manager's lock
+	ErrLockHeld = errors.New("package manager lock is held by another process")
+	// ErrPermissionDenied means the operation needs privileges the provider does not have
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrNetwork means a download or repository fetch failed
+	ErrNetwork = errors.New("network error")
+	// ErrDependencyConflict means the package's dependencies cannot be satisfied
+	ErrDependencyConflict = errors.New("dependency conflict")
+	// ErrInterrupted means the command was cancelled or timed out
+	ErrInterrupted = errors.New("operation interrupted")
+)
+
+// OperationError reports a failed package manager command.
+type OperationError struct {
+	// Kind is one of the Err* kinds above, or nil if the failure was not recognized
+	Kind      error
+	Manager   string
+	Operation string
+	// Target is what the operation acted on: package names, a tap or the cache
+	Target   string
+	ExitCode int
+	Stderr   string
+	// Err is the error returned by the executor, if any
+	Err error
+	// Recovery describes the repair the adapter ran after the failure, empty if
+	// none ran or it did not succeed
+	Recovery string
+
+	// redactor hides the command's secrets in the error message
+	redactor *executor.Redactor
+}
+
+// Error implements the error interface. Secrets the command was given, such as
+// credentials in a repository URL, are redacted since the message ends up in
+// diagnostics and traces.
+func (e *OperationError) Error() string {
+	message := fmt.Sprintf("failed to %s %s: exit code %d, error: %v, stderr: %s",
+		e.Operation, e.Target, e.ExitCode, e.Err, e.Stderr)
+	if e.redactor == nil {
+		return message
+	}
+	return e.redactor.String(message)
+}
+
+// Unwrap exposes both the error kind and the executor error to errors.Is and errors.As.
+func (e *OperationError) Unwrap() []error {
+	var wrapped []error
+	if e.Kind != nil {
+		wrapped = append(wrapped, e.Kind)
+	}
+	if e.Err != nil {
+		wrapped = append(wrapped, e.Err)
+	}
+	return wrapped
+}
+
+// Classifier maps a failed command's output to an error kind, or nil if it
+// does not recognize the failure.
+type Classifier func(stdout, stderr string) error
+
+// NewOperationError builds an OperationError for a failed command, classifying
+// it from the command's output. Interrupted commands are always ErrInterrupted.
+// Stderr and the error message are redacted with the command's redactor, or
+// the default rules if the executor did not supply one.
+func NewOperationError(manager, operation, target string, result executor.ExecResult, err error,
+	classify Classifier) *OperationError {
+	redactor := result.Redactor
+	if redactor == nil {
+		redactor, _ = executor.NewRedactor(nil, nil)
+	}
+	opErr := &OperationError{
+		Manager:   manager,
+		Operation: operation,
+		Target:    target,
+		ExitCode:  result.ExitCode,
+		Stderr:    redactor.String(result.Stderr),
+		Err:       err,
+		redactor:  redactor,
+	}
+	switch {
+	case executor.IsInterrupted(err):
+		opErr.Kind = ErrInterrupted
+	case classify != nil:
+		opErr.Kind = classify(result.Stdout, result.Stderr)
+	}
+	return opErr
+}
+
+// OutputPattern associates substrings of command output with an error kind.
+type OutputPattern struct {
+	Kind       error
+	Substrings []string
+}
+
+// MatchOutput returns the kind of the first pattern with a substring found in
+// output, ignoring case. Patterns are checked in order, so more specific ones
+// should come first.
+func MatchOutput(patterns []OutputPattern, output string) error {
+	lower := strings.ToLower(output)
+	for _, pattern := range patterns {
+		for _, substring := range pattern.Substrings {
+			if strings.Contains(lower, strings.ToLower(substring)) {
+				return pattern.Kind
+			}
+		}
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ package adapters
 
 import (
 	"context"
+	"time"
 )
 
 // PackageType represents the type of package
@@ -45,6 +46,11 @@ type PackageInfo struct {
 	Pinned            bool
 	Repository        string
 	Type              PackageType // Type of package (formula, cask, etc.)
+	Outdated          bool        // Whether a newer version is available, when the manager reports it
+	// Provides lists the virtual packages an installed package provides, when the manager reports them
+	Provides []string
+	// Dependencies are the relationships of an installed package, when the manager reports them
+	Dependencies []Dependency
 }
 
 // PackageManager defines the interface that all package manager adapters must implement.
@@ -102,3 +108,391 @@ type RepositoryInfo struct {
 	GPGKey  string
 	Enabled bool
 }
+
+// InstalledLister is implemented by package managers that can enumerate every
+// installed package in a single query.
+type InstalledLister interface {
+	// ListInstalled returns all packages currently installed by the manager
+	ListInstalled(ctx context.Context) ([]PackageInfo, error)
+}
+
+// StateRecoverer is implemented by package managers whose package database can
+// be left inconsistent by an interrupted operation (e.g. dpkg).
+type StateRecoverer interface {
+	// Recover repairs half-configured packages and broken dependencies
+	Recover(ctx context.Context) error
+}
+
+// CacheAger is implemented by package managers that can report when their
+// package index was last refreshed.
+type CacheAger interface {
+	// CacheLastUpdated returns the time of the last successful cache update
+	CacheLastUpdated(ctx context.Context) (time.Time, error)
+}
+
+// BatchPackage describes a single package within a batch install request.
+type BatchPackage struct {
+	Name    string
+	Version string
+	Type    PackageType
+}
+
+// BatchInstaller is implemented by package managers that can install several
+// packages in a single transaction.
+type BatchInstaller interface {
+	// InstallBatch installs all packages with one package manager invocation
+	InstallBatch(ctx context.Context, packages []BatchPackage) error
+}
+
+// BatchRemover is implemented by package managers that can remove several
+// packages in a single transaction.
+type BatchRemover interface {
+	// RemoveBatch removes all packages with one package manager invocation
+	RemoveBatch(ctx context.Context, packages []BatchPackage) error
+}
+
+// ManualInstallLister is implemented by package managers that distinguish
+// packages installed on request from those pulled in as dependencies.
+type ManualInstallLister interface {
+	// ListManuallyInstalled returns the names of packages installed on request
+	ListManuallyInstalled(ctx context.Context) ([]string, error)
+}
+
+// EssentialLister is implemented by package managers that mark packages the
+// system cannot do without.
+type EssentialLister interface {
+	// ListEssential returns the packages that must never be removed implicitly
+	ListEssential(ctx context.Context) ([]string, error)
+}
+
+// InventoryBacked is implemented by package managers that can answer
+// installed-package queries from a shared Inventory instead of spawning a
+// process per lookup. Adapters must invalidate the inventory after every
+// operation that changes the installed set.
+type InventoryBacked interface {
+	// SetInventory attaches the inventory used to serve installed-package lookups
+	SetInventory(inventory *Inventory)
+}
+
+// VersionCandidates describes the versions a package manager can install for a package.
+type VersionCandidates struct {
+	// Candidate is the version an install without a version constraint would select
+	Candidate string
+	// Available lists every installable version, in the manager's order of preference
+	Available []string
+}
+
+// CandidateResolver is implemented by package managers that can report which
+// version an install would select without changing the system.
+type CandidateResolver interface {
+	// Candidates returns the installable versions of a package, or an error
+	// wrapping ErrNotFound if no configured repository provides it
+	Candidates(ctx context.Context, name string) (*VersionCandidates, error)
+}
+
+// Upgrader is implemented by package managers that can upgrade an installed
+// package to its candidate version.
+type Upgrader interface {
+	// Upgrade upgrades an installed package to the newest available version
+	Upgrade(ctx context.Context, name string, packageType PackageType) error
+}
+
+// SimulatedPackage is a package affected by a simulated operation.
+type SimulatedPackage struct {
+	Name string
+	// Version is the version installed by the operation, or removed by it for removals
+	Version string
+}
+
+// Simulation lists the packages an operation would install, upgrade or remove.
+type Simulation struct {
+	Installs []SimulatedPackage
+	Removals []SimulatedPackage
+}
+
+// Simulator is implemented by package managers that can preview the packages
+// an install or removal would change, without changing the system.
+type Simulator interface {
+	// SimulateInstall reports the changes installing name at version would make
+	SimulateInstall(ctx context.Context, name, version string) (*Simulation, error)
+
+	// SimulateRemove reports the changes removing name would make
+	SimulateRemove(ctx context.Context, name string) (*Simulation, error)
+}
+
+// DriftChecker is implemented by package managers that can check an installed
+// package against what the package manager installed.
+type DriftChecker interface {
+	// VerifyIntegrity describes the installed files of name that are missing or
+	// modified; it returns nil when the package is intact
+	VerifyIntegrity(ctx context.Context, name string) ([]string, error)
+
+	// MissingDependencies returns the dependencies of name that are not installed
+	MissingDependencies(ctx context.Context, name string) ([]string, error)
+}
+
+// Reinstaller is implemented by package managers that can reinstall the
+// installed version of a package.
+type Reinstaller interface {
+	// Reinstall installs the installed version of name again, restoring its files
+	// and any missing dependencies
+	Reinstall(ctx context.Context, name string, packageType PackageType) error
+}
+
+// PackageMetadata describes an installed package.
+type PackageMetadata struct {
+	Description string
+	Homepage    string
+	License     string
+	// InstalledSize is the disk space used by the package in bytes, or 0 if unknown
+	InstalledSize int64
+}
+
+// MetadataReader is implemented by package managers that can describe an
+// installed package.
+type MetadataReader interface {
+	// Metadata returns the description, homepage, license and size of name
+	Metadata(ctx context.Context, name string) (*PackageMetadata, error)
+}
+
+// DependencyTreeReader is implemented by package managers that can list the
+// installed dependencies of a package.
+type DependencyTreeReader interface {
+	// DependencyTree returns the installed direct and transitive dependencies
+	// of name, mapped to their installed versions
+	DependencyTree(ctx context.Context, name string) (map[string]string, error)
+}
+
+// FileLister is implemented by package managers that can list the files an
+// installed package owns.
+type FileLister interface {
+	// Files returns the absolute paths installed by name
+	Files(ctx context.Context, name string) ([]string, error)
+}
+
+// FileOwnership names the installed packages a file belongs to.
+type FileOwnership struct {
+	// Packages lists the owning packages; several packages can share a directory
+	Packages []string
+	// Path is the path the package manager records, which may differ from the
+	// path looked up when it is reached through symbolic links
+	Path string
+}
+
+// FileOwnerResolver is implemented by package managers that can find the
+// installed package that provides a file.
+type FileOwnerResolver interface {
+	// FileOwner returns the packages owning path, or an ErrNotFound error when
+	// no installed package does
+	FileOwner(ctx context.Context, path string) (*FileOwnership, error)
+}
+
+// AvailableUpdate is a newer version of an installed package.
+type AvailableUpdate struct {
+	Name           string
+	CurrentVersion string
+	LatestVersion  string
+	// Pinned is set for packages held at their installed version
+	Pinned bool
+	// Security is set for updates published by a security archive
+	Security bool
+	// Origin names where the update comes from, e.g. "jammy-updates,jammy-security"
+	Origin string
+}
+
+// UpdateLister is implemented by package managers that can list the pending
+// updates of installed packages.
+type UpdateLister interface {
+	// ListUpdates returns every installed package with a newer version available
+	ListUpdates(ctx context.Context) ([]AvailableUpdate, error)
+}
+
+// DependencyType classifies a dependency of a package.
+type DependencyType string
+
+const (
+	// DependencyRuntime is required for the package to run
+	DependencyRuntime DependencyType = "runtime"
+	// DependencyBuild is only required to build the package from source
+	DependencyBuild DependencyType = "build"
+	// DependencyOptional is suggested but not installed along with the package
+	DependencyOptional DependencyType = "optional"
+)
+
+// Dependency is a direct dependency of a package.
+type Dependency struct {
+	Name string
+	// Constraint is the required version, e.g. ">= 2.14", or empty when any version satisfies it
+	Constraint string
+	Type       DependencyType
+	// Relation is the manager's name for the relationship, e.g. "Depends" or "Recommends"
+	Relation string
+	// Alternatives lists the other packages that satisfy the same relationship,
+	// e.g. "wget" for "curl | wget"
+	Alternatives []string
+}
+
+// DependencyResolver is implemented by package managers that can list the
+// direct dependencies of a package, installed or not.
+type DependencyResolver interface {
+	// Dependencies returns the runtime, build and optional dependencies of name
+	Dependencies(ctx context.Context, name string) ([]Dependency, error)
+}
+
+// ReverseDependencyResolver is implemented by package managers that can list
+// the installed packages depending on a package.
+type ReverseDependencyResolver interface {
+	// ReverseDependencies returns the installed packages that depend on name.
+	// Each Dependency names the dependent package and describes its
+	// relationship to name; Alternatives lists the other packages that would
+	// also satisfy it.
+	ReverseDependencies(ctx context.Context, name string) ([]Dependency, error)
+}
+
+// VersionRelease is a version of a package and where it is published.
+type VersionRelease struct {
+	Version string
+	// Origin is the repository, tap or archive publishing the version,
+	// e.g. "http://archive.ubuntu.com/ubuntu" or "homebrew/core"
+	Origin string
+	// Suite is the distribution suite and component, e.g. "jammy-updates/main",
+	// for managers that have them
+	Suite string
+}
+
+// VersionHistorian is implemented by package managers that can list every
+// version of a package they are able to install.
+type VersionHistorian interface {
+	// VersionHistory returns the installable versions of name, in the manager's
+	// order of preference. A version published by several sources is listed
+	// once per source.
+	VersionHistory(ctx context.Context, name string) ([]VersionRelease, error)
+}
+
+// SnapshotHistorian is implemented by package managers that can list the
+// historical versions of a package kept by a snapshot archive, such as
+// snapshot.debian.org or a mirror of it.
+type SnapshotHistorian interface {
+	// SnapshotHistory returns the versions of name in the snapshot archive at baseURL
+	SnapshotHistory(ctx context.Context, baseURL, name string) ([]VersionRelease, error)
+}
+
+// RepositoryPackage is a package published by a repository.
+type RepositoryPackage struct {
+	Name        string
+	Version     string
+	Description string
+}
+
+// RepositoryBrowser is implemented by package managers that can list the
+// packages a configured repository provides.
+type RepositoryBrowser interface {
+	// RepositoryPackages returns up to limit packages from repository, sorted by name
+	RepositoryPackages(ctx context.Context, repository string, limit int) ([]RepositoryPackage, error)
+}
+
+// ManagerDetails describes the package manager executable.
+type ManagerDetails struct {
+	Version string
+	// Path is the location of the executable, resolved through PATH when possible
+	Path string
+}
+
+// ManagerDescriber is implemented by package managers that can report their
+// own version.
+type ManagerDescriber interface {
+	// Describe returns the version and location of the package manager
+	Describe(ctx context.Context) (*ManagerDetails, error)
+}
+
+// EcosystemReporter is implemented by package managers that can name the OSV
+// ecosystem (https://ossf.github.io/osv-schema/#affectedpackage-field) their
+// packages belong to, used to look up security advisories.
+type EcosystemReporter interface {
+	// Ecosystem returns the ecosystem and release, e.g. "Debian:12",
+	// "Ubuntu:22.04" or "Homebrew"
+	Ecosystem(ctx context.Context) (string, error)
+}
+
+// SourcePackage is the source package an installed binary package was built from.
+type SourcePackage struct {
+	Name    string
+	Version string
+}
+
+// SourceLister is implemented by package managers whose security advisories
+// are published for source packages rather than the binary packages built
+// from them.
+type SourceLister interface {
+	// ListSources maps every installed package to its source package
+	ListSources(ctx context.Context) (map[string]SourcePackage, error)
+}
+
+// Component describes an installed package for a software bill of materials.
+type Component struct {
+	Name    string
+	Version string
+	// Architecture is the architecture the package was built for, e.g. "amd64",
+	// "arm64" or "all", or empty if unknown
+	Architecture string
+	// Purl is the package URL (https://github.com/package-url/purl-spec)
+	Purl string
+	// License is the license declared by the package, as an SPDX expression when
+	// the manager records one
+	License string
+	// Supplier is the maintainer or organization distributing the package
+	Supplier string
+	// SourceRepository is the archive or tap the package was installed from,
+	// e.g. "http://deb.debian.org/debian bookworm/main" or "homebrew/core"
+	SourceRepository string
+	Homepage         string
+	Description      string
+}
+
+// ComponentLister is implemented by package managers that can describe every
+// installed package for a software bill of materials.
+type ComponentLister interface {
+	// ListComponents returns every installed package, sorted by name
+	ListComponents(ctx context.Context) ([]Component, error)
+}
+
+// SearchResult is a catalog package matching a search.
+type SearchResult struct {
+	Name        string
+	Description string
+	// Version is the version the manager would install, or empty when the
+	// search does not report it
+	Version string
+	// Section is the archive section, e.g. "web" or "universe/net", for managers
+	// that have them
+	Section string
+	// Repository is the repository or tap providing the package, when known
+	Repository string
+	Installed  bool
+	Type       PackageType
+}
+
+// CatalogSearcher is implemented by package managers that can search their
+// catalog with a regular expression.
+type CatalogSearcher interface {
+	// SearchCatalog returns the packages whose name, or description where the
+	// manager searches descriptions, matches pattern, in the manager's order.
+	// When keep is not nil, only the packages whose name it keeps are
+	// described and returned
+	SearchCatalog(ctx context.Context, pattern string, keep func(name string) bool) ([]SearchResult, error)
+}
+
+// KeepSearchResults returns the results whose name keep keeps, or all of them
+// when keep is nil.
+func KeepSearchResults(results []SearchResult, keep func(name string) bool) []SearchResult {
+	if keep == nil {
+		return results
+	}
+	kept := []SearchResult{}
+	for _, result := range results {
+		if keep(result.Name) {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package adapters
+
+import (
+	"context"
+	"sync"
+)
+
+// Inventory caches the complete set of packages installed by one package
+// manager so that per-package lookups can be answered without spawning a
+// process each time. It is loaded lazily on first use and must be invalidated
+// after anything that changes the installed set.
+type Inventory struct {
+	mu       sync.Mutex
+	loaded   bool
+	packages []PackageInfo
+	byName   map[string]PackageInfo
+}
+
+// NewInventory creates an empty, unloaded inventory.
+func NewInventory() *Inventory {
+	return &Inventory{}
+}
+
+// Packages returns the installed package set, calling load if the inventory
+// has not been populated since it was created or last invalidated.
+func (i *Inventory) Packages(ctx context.Context, load func(context.Context) ([]PackageInfo, error)) ([]PackageInfo, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err := i.ensureLoaded(ctx, load); err != nil {
+		return nil, err
+	}
+
+	packages := make([]PackageInfo, len(i.packages))
+	copy(packages, i.packages)
+	return packages, nil
+}
+
+// Lookup returns the installed package with the given name. The boolean is
+// false if the package is not in the inventory.
+func (i *Inventory) Lookup(
+	ctx context.Context, name string, load func(context.Context) ([]PackageInfo, error)) (PackageInfo, bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err := i.ensureLoaded(ctx, load); err != nil {
+		return PackageInfo{}, false, err
+	}
+
+	info, ok := i.byName[name]
+	return info, ok, nil
+}
+
+// Invalidate discards the cached package set so the next lookup reloads it.
+// It is safe to call on a nil Inventory.
+func (i *Inventory) Invalidate() {
+	if i == nil {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.loaded = false
+	i.packages = nil
+	i.byName = nil
+}
+
+// ensureLoaded populates the inventory. The caller must hold i.mu.
+func (i *Inventory) ensureLoaded(ctx context.Context, load func(context.Context) ([]PackageInfo, error)) error {
+	if i.loaded {
+		return nil
+	}
+
+	packages, err := load(ctx)
+	if err != nil {
+		return err
+	}
+
+	i.packages = packages
+	i.byName = make(map[string]PackageInfo, len(packages))
+	for _, pkg := range packages {
+		i.byName[pkg.Name] = pkg
+		// Also index tap- or repository-qualified names such as "hashicorp/tap/terraform".
+		if pkg.Repository != "" {
+			i.byName[pkg.Repository+"/"+pkg.Name] = pkg
+		}
+	}
+	i.loaded = true
+	return nil
+}
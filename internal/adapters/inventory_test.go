@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory_LoadsOnceUntilInvalidated(t *testing.T) {
+	inventory := NewInventory()
+	loads := 0
+	load := func(context.Context) ([]PackageInfo, error) {
+		loads++
+		return []PackageInfo{
+			{Name: "jq", Version: "1.7", Installed: true, Repository: "homebrew/core"},
+			{Name: "terraform", Version: "1.9.0", Installed: true, Repository: "hashicorp/tap"},
+		}, nil
+	}
+
+	info, found, err := inventory.Lookup(context.Background(), "jq", load)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "1.7", info.Version)
+
+	_, found, err = inventory.Lookup(context.Background(), "hashicorp/tap/terraform", load)
+	require.NoError(t, err)
+	assert.True(t, found, "repository-qualified names should resolve")
+
+	_, found, err = inventory.Lookup(context.Background(), "htop", load)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	packages, err := inventory.Packages(context.Background(), load)
+	require.NoError(t, err)
+	assert.Len(t, packages, 2)
+	assert.Equal(t, 1, loads)
+
+	inventory.Invalidate()
+	_, _, err = inventory.Lookup(context.Background(), "jq", load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+}
+
+func TestInventory_LoadErrorIsNotCached(t *testing.T) {
+	inventory := NewInventory()
+	fail := true
+	load := func(context.Context) ([]PackageInfo, error) {
+		if fail {
+			return nil, fmt.Errorf("listing failed")
+		}
+		return []PackageInfo{{Name: "jq", Installed: true}}, nil
+	}
+
+	_, _, err := inventory.Lookup(context.Background(), "jq", load)
+	assert.Error(t, err)
+
+	fail = false
+	_, found, err := inventory.Lookup(context.Background(), "jq", load)
+	require.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestInventory_InvalidateNil(t *testing.T) {
+	var inventory *Inventory
+	assert.NotPanics(t, inventory.Invalidate)
+}
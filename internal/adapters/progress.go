@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package adapters
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Progress phases reported by package manager adapters.
+const (
+	ProgressPhaseDownload  = "download"
+	ProgressPhaseUnpack    = "unpack"
+	ProgressPhaseInstall   = "install"
+	ProgressPhaseConfigure = "configure"
+	ProgressPhaseRemove    = "remove"
+	ProgressPhaseComplete  = "complete"
+	ProgressPhaseError     = "error"
+)
+
+// ProgressEvent describes how far a long-running package operation has got.
+type ProgressEvent struct {
+	Manager string
+	Phase   string
+	Package string
+	// Percent is the overall completion from 0 to 100, or -1 if the manager
+	// does not report it for this phase.
+	Percent float64
+	Message string
+}
+
+// ReportProgress logs a progress event so that long operations are visible
+// while they run rather than only once they finish.
+func ReportProgress(ctx context.Context, event ProgressEvent) {
+	fields := map[string]interface{}{
+		"manager": event.Manager,
+		"phase":   event.Phase,
+		"package": event.Package,
+		"message": event.Message,
+	}
+	if event.Percent >= 0 {
+		fields["percent"] = event.Percent
+	}
+
+	if event.Phase == ProgressPhaseError {
+		tflog.Warn(ctx, "Package operation progress", fields)
+		return
+	}
+	tflog.Info(ctx, "Package operation progress", fields)
+}
@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package adapters
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// PackageURL builds a package URL such as
+// "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12".
+// The namespace and version may be empty; empty qualifiers are omitted.
+func PackageURL(purlType, namespace, name, version string, qualifiers map[string]string) string {
+	var purl strings.Builder
+	purl.WriteString("pkg:")
+	purl.WriteString(purlType)
+	purl.WriteString("/")
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			purl.WriteString(purlEscape(segment))
+			purl.WriteString("/")
+		}
+	}
+	purl.WriteString(purlEscape(name))
+	if version != "" {
+		purl.WriteString("@")
+		purl.WriteString(purlEscape(version))
+	}
+
+	keys := make([]string, 0, len(qualifiers))
+	for key, value := range qualifiers {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		if i == 0 {
+			purl.WriteString("?")
+		} else {
+			purl.WriteString("&")
+		}
+		purl.WriteString(key)
+		purl.WriteString("=")
+		purl.WriteString(purlEscape(qualifiers[key]))
+	}
+	return purl.String()
+}
+
+// purlEscape percent-encodes a purl component. Only unreserved characters
+// are kept, so "+" in versions such as "10+deb12u5" becomes "%2B" and an
+// epoch separator ":" becomes "%3A".
+func purlEscape(component string) string {
+	escaped := url.PathEscape(component)
+	return strings.NewReplacer("+", "%2B", ":", "%3A", "@", "%40", "&", "%26", "=", "%3D", "$", "%24").Replace(escaped)
+}
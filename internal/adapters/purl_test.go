@@ -0,0 +1,35 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		purlType   string
+		namespace  string
+		pkg        string
+		version    string
+		qualifiers map[string]string
+		expected   string
+	}{
+		{"debian", "deb", "debian", "curl", "7.88.1-10+deb12u5",
+			map[string]string{"distro": "debian-12", "arch": "amd64"},
+			"pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12"},
+		{"epoch", "deb", "ubuntu", "openssh-server", "1:8.9p1-3ubuntu0.10", nil,
+			"pkg:deb/ubuntu/openssh-server@1%3A8.9p1-3ubuntu0.10"},
+		{"no namespace", "brew", "", "jq", "1.7.1", map[string]string{"tap": ""}, "pkg:brew/jq@1.7.1"},
+		{"qualifier escaping", "brew", "", "tool", "2.0", map[string]string{"tap": "acme/tools"},
+			"pkg:brew/tool@2.0?tap=acme%2Ftools"},
+		{"no version", "deb", "debian", "libc6", "", nil, "pkg:deb/debian/libc6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PackageURL(tt.purlType, tt.namespace, tt.pkg, tt.version, tt.qualifiers))
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package audit records every system mutation made by the provider in an
+// append-only, hash-chained JSON-lines log.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Record is a single audit log entry. Each record carries the hash of the one
+// before it, so editing, inserting or deleting an entry breaks the chain.
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	// ResourceType and ResourceID identify the Terraform resource being applied.
+	// Terraform does not pass configuration addresses to providers, so the
+	// resource's ID stands in for its address.
+	ResourceType       string `json:"resource_type,omitempty"`
+	ResourceID         string `json:"resource_id,omitempty"`
+	TerraformOperation string `json:"terraform_operation,omitempty"`
+	Manager            string `json:"manager"`
+	Operation          string `json:"operation"`
+	Package            string `json:"package,omitempty"`
+	VersionBefore      string `json:"version_before,omitempty"`
+	VersionAfter       string `json:"version_after,omitempty"`
+	Command            string `json:"command"`
+	ExitCode           int    `json:"exit_code"`
+	DurationMs         int64  `json:"duration_ms"`
+	User               string `json:"user"`
+	Sudo               bool   `json:"sudo"`
+	Error              string `json:"error,omitempty"`
+	PrevHash           string `json:"prev_hash"`
+	Hash               string `json:"hash"`
+}
+
+// computeHash returns the hex SHA-256 of the record with its Hash field cleared.
+// PrevHash is part of the hashed content, which is what links the chain.
+func (r Record) computeHash() (string, error) {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Logger appends records to an audit log file. Several loggers, in this or
+// other provider processes, may share one file: every append takes an
+// exclusive lock on it and chains to whatever record is last at that moment.
+type Logger struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLogger opens the audit log at path, creating it if needed.
+func NewLogger(path string) (*Logger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	return &Logger{path: path}, nil
+}
+
+// Path returns the audit log file path.
+func (l *Logger) Path() string {
+	return l.path
+}
+
+// Append chains record to the previous entry and writes it to the log.
+func (l *Logger) Append(record Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", l.path, err)
+	}
+	defer file.Close()
+
+	// Hold the lock from reading the last hash until the record is written,
+	// so that concurrent writers cannot fork the chain
+	if err := lockFile(file); err != nil {
+		return fmt.Errorf("failed to lock audit log %s: %w", l.path, err)
+	}
+	defer func() { _ = unlockFile(file) }()
+
+	prevHash, err := lastRecordHash(file)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log %s: %w", l.path, err)
+	}
+
+	record.PrevHash = prevHash
+	hash, err := record.computeHash()
+	if err != nil {
+		return fmt.Errorf("failed to hash audit record: %w", err)
+	}
+	record.Hash = hash
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log %s: %w", l.path, err)
+	}
+
+	return nil
+}
+
+// tailChunkSize is how much of the log lastRecordHash reads per step.
+const tailChunkSize = 64 * 1024
+
+// lastRecordHash returns the hash of the last record in file. It reads
+// backwards from the end so that appends do not rescan the whole log. If the
+// last entry cannot be decoded, the last readable record is used instead;
+// ReadRecent reports the damage as a broken chain.
+func lastRecordHash(file *os.File) (string, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		n := min(int64(tailChunkSize), offset)
+		offset -= n
+		chunk := make([]byte, n)
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			return "", err
+		}
+		tail = append(chunk, tail...)
+
+		trimmed := bytes.TrimRight(tail, "\n")
+		start := bytes.LastIndexByte(trimmed, '\n')
+		if start < 0 && offset > 0 {
+			continue
+		}
+		if len(trimmed) == 0 {
+			return "", nil
+		}
+
+		var record Record
+		if err := json.Unmarshal(trimmed[start+1:], &record); err == nil {
+			return record.Hash, nil
+		}
+		break
+	}
+	if len(tail) == 0 {
+		return "", nil
+	}
+
+	var hash string
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	err = scanRecords(file, func(_ int, record Record, decodeErr error) {
+		if decodeErr == nil {
+			hash = record.Hash
+		}
+	})
+	return hash, err
+}
+
+// ChainError describes the first entry at which the hash chain is broken.
+type ChainError struct {
+	// Line is the 1-based line number of the offending entry
+	Line   int
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("audit log chain broken at line %d: %s", e.Line, e.Reason)
+}
+
+// ReadRecent returns up to limit of the most recent records, oldest first, and
+// verifies the whole chain. A broken chain is reported as a *ChainError
+// alongside the records that could be read.
+func ReadRecent(path string, limit int) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var (
+		records  []Record
+		prevHash string
+		chainErr *ChainError
+	)
+	err = scanRecords(file, func(line int, record Record, decodeErr error) {
+		if decodeErr != nil {
+			if chainErr == nil {
+				chainErr = &ChainError{Line: line, Reason: "entry is not valid JSON"}
+			}
+			return
+		}
+		if chainErr == nil {
+			if record.PrevHash != prevHash {
+				chainErr = &ChainError{Line: line, Reason: "previous hash does not match the preceding entry"}
+			} else if hash, err := record.computeHash(); err != nil || hash != record.Hash {
+				chainErr = &ChainError{Line: line, Reason: "entry hash does not match its contents"}
+			}
+		}
+		prevHash = record.Hash
+
+		records = append(records, record)
+		if limit > 0 && len(records) > limit {
+			records = records[1:]
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+
+	if chainErr != nil {
+		return records, chainErr
+	}
+	return records, nil
+}
+
+// scanRecords decodes one JSON record per non-empty line of r, passing the
+// 1-based line number and any decode error to fn.
+func scanRecords(r io.Reader, fn func(line int, record Record, decodeErr error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		fn(line, record, err)
+	}
+	return scanner.Err()
+}
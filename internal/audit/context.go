@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package audit
+
+import "context"
+
+// Scope describes the Terraform operation on whose behalf commands are run.
+// Adapters do not know which resource invoked them, so resources attach a
+// scope to the context and the auditing executor copies it into each record.
+type Scope struct {
+	ResourceType       string
+	ResourceID         string
+	TerraformOperation string
+	Manager            string
+	Package            string
+	VersionBefore      string
+	VersionAfter       string
+	// ResolveVersionAfter, if set, looks up the package's version once a
+	// command has run and replaces VersionAfter in its record
+	ResolveVersionAfter func(ctx context.Context) string
+}
+
+type scopeKey struct{}
+
+// WithScope returns a context carrying scope for audit records.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFrom returns the scope attached to ctx, if any.
+func ScopeFrom(ctx context.Context) (Scope, bool) {
+	scope, ok := ctx.Value(scopeKey{}).(Scope)
+	return scope, ok
+}
+
+// WithPackage returns ctx with its scope narrowed to name, for commands a
+// resource runs on behalf of another package, such as a dependency install.
+// The versions of the resource's own package no longer apply and are cleared.
+func WithPackage(ctx context.Context, name string) context.Context {
+	scope, _ := ScopeFrom(ctx)
+	scope.Package = name
+	scope.VersionBefore = ""
+	scope.VersionAfter = ""
+	scope.ResolveVersionAfter = nil
+	return WithScope(ctx, scope)
+}
+
+// WithoutPackage returns ctx with the package cleared from its scope, for
+// commands that act on the package manager as a whole, such as cache updates.
+func WithoutPackage(ctx context.Context) context.Context {
+	if _, ok := ScopeFrom(ctx); !ok {
+		return ctx
+	}
+	return WithPackage(ctx, "")
+}
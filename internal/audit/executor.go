@@ -0,0 +1,227 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// mutatingSubcommands lists, per command, the subcommands that change the system.
+var mutatingSubcommands = map[string]map[string]bool{
+	"apt-get": {
+		"install": true, "remove": true, "purge": true, "upgrade": true,
+		"dist-upgrade": true, "autoremove": true, "update": true,
+	},
+	"apt-mark": {"hold": true, "unhold": true, "auto": true, "manual": true},
+	"brew": {
+		"install": true, "reinstall": true, "uninstall": true, "upgrade": true,
+		"pin": true, "unpin": true, "update": true, "tap": true, "untap": true,
+		"link": true, "unlink": true, "services": true,
+	},
+	"systemctl": {
+		"start": true, "stop": true, "restart": true, "reload": true,
+		"enable": true, "disable": true,
+	},
+	"launchctl": {
+		"start": true, "stop": true, "load": true, "unload": true,
+		"bootstrap": true, "bootout": true, "enable": true, "disable": true,
+		"kickstart": true,
+	},
+}
+
+// mutatingDpkgFlags are the dpkg actions that change installed packages.
+var mutatingDpkgFlags = map[string]bool{
+	"-i": true, "--install": true, "-r": true, "--remove": true,
+	"-P": true, "--purge": true, "--configure": true,
+}
+
+// aptSimulateFlags make apt-get only report what it would do.
+var aptSimulateFlags = map[string]bool{
+	"-s": true, "--simulate": true, "--just-print": true, "--dry-run": true, "--recon": true, "--no-act": true,
+}
+
+// readOnlyBrewServices are the `brew services` subcommands that only report state.
+var readOnlyBrewServices = map[string]bool{"list": true, "info": true}
+
+// IsMutating reports whether running command with args changes the system, and
+// returns the operation name recorded for it.
+func IsMutating(command string, args []string) (string, bool) {
+	name := strings.TrimSuffix(filepath.Base(command), ".exe")
+
+	if name == "dpkg" {
+		for _, arg := range args {
+			if mutatingDpkgFlags[arg] {
+				return strings.TrimLeft(arg, "-"), true
+			}
+		}
+		return "", false
+	}
+
+	if name == "powershell" || name == "pwsh" {
+		script := strings.Join(args, " ")
+		for _, verb := range []string{"Start-Service", "Stop-Service", "Restart-Service", "Set-Service"} {
+			if strings.Contains(script, verb) {
+				return verb, true
+			}
+		}
+		return "", false
+	}
+
+	subcommands, ok := mutatingSubcommands[name]
+	if !ok {
+		return "", false
+	}
+	if name == "apt-get" {
+		for _, arg := range args {
+			if aptSimulateFlags[arg] {
+				return "", false
+			}
+		}
+	}
+	rest := positionalArgs(args)
+	if len(rest) == 0 || !subcommands[rest[0]] {
+		return "", false
+	}
+	if name == "brew" && rest[0] == "services" {
+		if len(rest) < 2 || readOnlyBrewServices[rest[1]] {
+			return "", false
+		}
+		return "services " + rest[1], true
+	}
+	// A bare `brew tap` lists taps rather than adding one
+	if name == "brew" && rest[0] == "tap" && len(rest) == 1 {
+		return "", false
+	}
+	return rest[0], true
+}
+
+// positionalArgs drops flags, and the values of apt-style `-o key=value` options.
+func positionalArgs(args []string) []string {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-o" {
+			i++
+			continue
+		}
+		if strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	return rest
+}
+
+// auditingExecutor records every mutating command run through it.
+type auditingExecutor struct {
+	inner    executor.Executor
+	logger   *Logger
+	redactor *executor.Redactor
+
+	userOnce sync.Once
+	userName string
+}
+
+// NewExecutor wraps inner so that every command that changes the system is
+// appended to logger. Commands are redacted with redactor before being recorded;
+// a nil redactor uses the default rules.
+func NewExecutor(inner executor.Executor, logger *Logger, redactor *executor.Redactor) executor.Executor {
+	if redactor == nil {
+		// The default rules have no user patterns, so this cannot fail
+		redactor, _ = executor.NewRedactor(nil, nil)
+	}
+	return &auditingExecutor{inner: inner, logger: logger, redactor: redactor}
+}
+
+// Run executes the command and, if it mutates the system, records it.
+func (e *auditingExecutor) Run(ctx context.Context, cmd string, args []string, opts executor.ExecOpts) (executor.ExecResult, error) {
+	operation, mutating := IsMutating(cmd, args)
+	if !mutating {
+		return e.inner.Run(ctx, cmd, args, opts)
+	}
+
+	start := time.Now()
+	result, err := e.inner.Run(ctx, cmd, args, opts)
+	duration := time.Since(start)
+
+	scope, _ := ScopeFrom(ctx)
+	manager := scope.Manager
+	if manager == "" {
+		manager = strings.TrimSuffix(filepath.Base(cmd), ".exe")
+	}
+
+	redactor := e.redactor.ForCommand(opts.Env, args, opts.SensitiveArgs)
+	command := strings.Join(append([]string{cmd}, redactor.Args(args, opts.SensitiveArgs)...), " ")
+
+	record := Record{
+		Timestamp:          start.UTC(),
+		ResourceType:       scope.ResourceType,
+		ResourceID:         scope.ResourceID,
+		TerraformOperation: scope.TerraformOperation,
+		Manager:            manager,
+		Operation:          operation,
+		Package:            scope.Package,
+		VersionBefore:      scope.VersionBefore,
+		VersionAfter:       scope.VersionAfter,
+		Command:            command,
+		ExitCode:           result.ExitCode,
+		DurationMs:         duration.Milliseconds(),
+		User:               e.currentUser(),
+		Sudo:               opts.UseSudo,
+	}
+	if err != nil {
+		record.Error = redactor.String(err.Error())
+	}
+	if scope.ResolveVersionAfter != nil {
+		record.VersionAfter = scope.ResolveVersionAfter(ctx)
+	}
+
+	// A failed audit write must not hide the outcome of the command itself
+	if appendErr := e.logger.Append(record); appendErr != nil {
+		tflog.Error(ctx, "Failed to write audit log entry", map[string]interface{}{
+			"path":    e.logger.Path(),
+			"command": command,
+			"error":   appendErr.Error(),
+		})
+	}
+
+	return result, err
+}
+
+// currentUser returns the name of the user the provider runs as.
+func (e *auditingExecutor) currentUser() string {
+	e.userOnce.Do(func() {
+		if u, err := user.Current(); err == nil {
+			e.userName = u.Username
+		}
+	})
+	return e.userName
+}
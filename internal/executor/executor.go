@@ -25,6 +25,8 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -33,6 +35,9 @@ type ExecResult struct {
 	Stdout   string
 	Stderr   string
 	ExitCode int
+	// Redactor hides the command's secrets, including its sensitive arguments,
+	// in anything built from the result. It is nil if the executor does not redact.
+	Redactor *Redactor
 }
 
 // ExecOpts provides options for command execution.
@@ -42,9 +47,58 @@ type ExecOpts struct {
 	Env            []string
 	UseSudo        bool
 	NonInteractive bool
+	// TerminationGracePeriod overrides how long a cancelled command is given to
+	// exit after SIGTERM before it is killed. Zero uses the executor's default.
+	TerminationGracePeriod time.Duration
+	// OnOutput enables streaming: it is called with each line of output while
+	// the command runs, and lines are logged as they arrive. Output is still
+	// captured in ExecResult.
+	OnOutput func(line OutputLine)
+	// StatusFd attaches a pipe as file descriptor 3 in the child, for tools such
+	// as apt-get -o APT::Status-Fd=3. Its lines are delivered to OnOutput with
+	// StreamStatus and are not captured. Ignored on Windows.
+	StatusFd bool
+	// MaxOutputBytes caps how much of each of stdout and stderr is kept in
+	// ExecResult; only the most recent output is retained. Zero keeps everything.
+	MaxOutputBytes int
+	// SensitiveArgs lists indices into args that hold secrets, such as URLs with
+	// embedded credentials. They are masked in logs, along with any place their
+	// values are echoed in the command's output.
+	SensitiveArgs []int
 }
 
 // Executor defines the interface for executing system commands.
 type Executor interface {
 	Run(ctx context.Context, cmd string, args []string, opts ExecOpts) (ExecResult, error)
 }
+
+// InterruptedError reports that a command was stopped because its context was
+// cancelled or its timeout expired, rather than exiting on its own. Callers can
+// use it to run recovery for tools that leave state behind when interrupted.
+type InterruptedError struct {
+	Command string
+	// Cause is context.Canceled or context.DeadlineExceeded
+	Cause error
+	// Killed is true if the command ignored SIGTERM and had to be killed
+	Killed bool
+}
+
+// Error implements the error interface.
+func (e *InterruptedError) Error() string {
+	how := "terminated"
+	if e.Killed {
+		how = "killed"
+	}
+	return fmt.Sprintf("command %s interrupted and %s: %v", e.Command, how, e.Cause)
+}
+
+// Unwrap returns the context error that caused the interruption.
+func (e *InterruptedError) Unwrap() error {
+	return e.Cause
+}
+
+// IsInterrupted reports whether err, or any error it wraps, is an InterruptedError.
+func IsInterrupted(err error) bool {
+	var interrupted *InterruptedError
+	return errors.As(err, &interrupted)
+}
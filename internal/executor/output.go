@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package executor
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Stream identifies where a line of command output came from.
+type Stream string
+
+const (
+	// StreamStdout is the command's standard output.
+	StreamStdout Stream = "stdout"
+	// StreamStderr is the command's standard error.
+	StreamStderr Stream = "stderr"
+	// StreamStatus is the machine-readable status pipe requested with ExecOpts.StatusFd.
+	StreamStatus Stream = "status"
+)
+
+// OutputLine is a single line of command output delivered while the command runs.
+type OutputLine struct {
+	Stream Stream
+	Text   string
+}
+
+// truncatedMarker prefixes captured output whose beginning was discarded.
+const truncatedMarker = "... (truncated)\n"
+
+// outputCapture is an io.Writer that keeps the command's output for ExecResult,
+// optionally bounded to the most recent maxBytes, and hands each complete line
+// to onLine as soon as it is written.
+type outputCapture struct {
+	stream    Stream
+	maxBytes  int
+	onLine    func(OutputLine)
+	buf       bytes.Buffer
+	truncated bool
+	partial   []byte
+}
+
+// newOutputCapture creates a capture for stream. A maxBytes of zero keeps all
+// output; onLine may be nil when streaming is not wanted.
+func newOutputCapture(stream Stream, maxBytes int, onLine func(OutputLine)) *outputCapture {
+	return &outputCapture{
+		stream:   stream,
+		maxBytes: maxBytes,
+		onLine:   onLine,
+	}
+}
+
+// Write implements io.Writer.
+func (c *outputCapture) Write(p []byte) (int, error) {
+	c.buf.Write(p)
+	if c.maxBytes > 0 && c.buf.Len() > c.maxBytes {
+		// Keep the tail, which is where package managers print their errors
+		tail := append([]byte(nil), c.buf.Bytes()[c.buf.Len()-c.maxBytes:]...)
+		c.buf.Reset()
+		c.buf.Write(tail)
+		c.truncated = true
+	}
+
+	if c.onLine != nil {
+		c.partial = append(c.partial, p...)
+		for {
+			i := bytes.IndexByte(c.partial, '\n')
+			if i < 0 {
+				break
+			}
+			c.emit(c.partial[:i])
+			c.partial = c.partial[i+1:]
+		}
+		if c.maxBytes > 0 && len(c.partial) > c.maxBytes {
+			// Progress output redrawn with \r may never end a line
+			c.partial = append([]byte(nil), c.partial[len(c.partial)-c.maxBytes:]...)
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush delivers any trailing output that did not end with a newline.
+func (c *outputCapture) Flush() {
+	if c.onLine != nil && len(c.partial) > 0 {
+		c.emit(c.partial)
+		c.partial = nil
+	}
+}
+
+// String returns the captured output.
+func (c *outputCapture) String() string {
+	if c.truncated {
+		return truncatedMarker + c.buf.String()
+	}
+	return c.buf.String()
+}
+
+func (c *outputCapture) emit(line []byte) {
+	c.onLine(OutputLine{Stream: c.stream, Text: string(bytes.TrimRight(line, "\r"))})
+}
+
+// serializeLines wraps onLine so that lines from concurrently written streams
+// are delivered one at a time.
+func serializeLines(onLine func(OutputLine)) func(OutputLine) {
+	if onLine == nil {
+		return nil
+	}
+	var mu sync.Mutex
+	return func(line OutputLine) {
+		mu.Lock()
+		defer mu.Unlock()
+		onLine(line)
+	}
+}
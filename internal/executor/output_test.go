@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputCapture_StreamsCompleteLines(t *testing.T) {
+	var lines []OutputLine
+	capture := newOutputCapture(StreamStdout, 0, func(line OutputLine) {
+		lines = append(lines, line)
+	})
+
+	_, _ = capture.Write([]byte("first\nsec"))
+	_, _ = capture.Write([]byte("ond\r\nthird"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 complete lines before flush, got %d", len(lines))
+	}
+
+	capture.Flush()
+	want := []string{"first", "second", "third"}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %d", len(want), len(lines))
+	}
+	for i, line := range lines {
+		if line.Text != want[i] || line.Stream != StreamStdout {
+			t.Errorf("Line %d: expected %q on stdout, got %q on %s", i, want[i], line.Text, line.Stream)
+		}
+	}
+
+	if capture.String() != "first\nsecond\r\nthird" {
+		t.Errorf("Captured output should be unchanged, got %q", capture.String())
+	}
+}
+
+func TestOutputCapture_KeepsTailWhenCapped(t *testing.T) {
+	capture := newOutputCapture(StreamStderr, 10, nil)
+
+	_, _ = capture.Write([]byte("0123456789"))
+	_, _ = capture.Write([]byte("abcdef"))
+
+	got := capture.String()
+	if !strings.HasPrefix(got, truncatedMarker) {
+		t.Errorf("Expected truncation marker, got %q", got)
+	}
+	if !strings.HasSuffix(got, "6789abcdef") {
+		t.Errorf("Expected the last 10 bytes to be kept, got %q", got)
+	}
+}
+
+func TestOutputCapture_CapsUnterminatedLine(t *testing.T) {
+	var lines []OutputLine
+	capture := newOutputCapture(StreamStdout, 16, func(line OutputLine) {
+		lines = append(lines, line)
+	})
+
+	for i := 0; i < 100; i++ {
+		_, _ = capture.Write([]byte("\rProgress: 42%"))
+	}
+	if len(capture.partial) > 16 {
+		t.Errorf("Unterminated line should be capped at 16 bytes, holds %d", len(capture.partial))
+	}
+
+	_, _ = capture.Write([]byte(" done\n"))
+	if len(lines) != 1 || !strings.HasSuffix(lines[0].Text, "42% done") {
+		t.Errorf("Expected the tail of the line to be delivered, got %v", lines)
+	}
+}
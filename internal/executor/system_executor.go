@@ -23,45 +23,104 @@
 package executor
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
 )
 
+// defaultTerminationGracePeriod is how long a cancelled command may take to
+// exit after SIGTERM before its process group is killed.
+const defaultTerminationGracePeriod = 10 * time.Second
+
 // SystemExecutor implements the Executor interface using os/exec.
+// Each command runs in its own process group so that cancellation reaches the
+// helpers it spawns (dpkg, brew's ruby processes) and not just the direct child.
 type SystemExecutor struct {
-	logger *log.Logger
+	logger                 *log.Logger
+	terminationGracePeriod time.Duration
+	redactor               *Redactor
 }
 
 // NewSystemExecutor creates a new SystemExecutor.
 func NewSystemExecutor() *SystemExecutor {
 	return &SystemExecutor{
-		logger: log.New(os.Stderr, "[executor] ", log.LstdFlags),
+		logger:                 log.New(os.Stderr, "[executor] ", log.LstdFlags),
+		terminationGracePeriod: defaultTerminationGracePeriod,
+		redactor:               defaultRedactor(),
+	}
+}
+
+// defaultRedactor builds a redactor from the built-in names and patterns,
+// which are known to be valid.
+func defaultRedactor() *Redactor {
+	redactor, err := NewRedactor(nil, nil)
+	if err != nil {
+		panic(err)
+	}
+	return redactor
+}
+
+// SetRedactor replaces the redactor applied to everything the executor logs.
+// A nil redactor is ignored so that logging is never left unredacted.
+func (e *SystemExecutor) SetRedactor(redactor *Redactor) {
+	if redactor != nil {
+		e.redactor = redactor
+	}
+}
+
+// SetTerminationGracePeriod sets how long cancelled commands are given to exit
+// after SIGTERM before SIGKILL. Negative values are treated as zero.
+func (e *SystemExecutor) SetTerminationGracePeriod(gracePeriod time.Duration) {
+	if gracePeriod < 0 {
+		gracePeriod = 0
 	}
+	e.terminationGracePeriod = gracePeriod
 }
 
-// Run executes a command with the given options.
+// Run executes a command with the given options, recording it as a trace span.
 func (e *SystemExecutor) Run(ctx context.Context, command string, args []string, opts ExecOpts) (ExecResult, error) {
+	redactedArgs := e.redactor.ForCommand(opts.Env, args, opts.SensitiveArgs).Args(args, opts.SensitiveArgs)
+	ctx, span := telemetry.Start(ctx, "exec "+filepath.Base(command),
+		telemetry.AttrCommand.String(strings.Join(append([]string{command}, redactedArgs...), " ")),
+		telemetry.AttrSudo.Bool(opts.UseSudo),
+	)
+
+	result, err := e.run(ctx, command, args, opts)
+
+	span.SetAttributes(telemetry.AttrExitCode.Int(result.ExitCode))
+	telemetry.End(span, err)
+	return result, err
+}
+
+// run executes a command with the given options.
+func (e *SystemExecutor) run(ctx context.Context, command string, args []string, opts ExecOpts) (ExecResult, error) {
 	startTime := time.Now()
 
+	// Everything logged below goes through the redactor; the command itself
+	// still runs with the real arguments and environment.
+	redactor := e.redactor.ForCommand(opts.Env, args, opts.SensitiveArgs)
+	logArgs := redactor.Args(args, opts.SensitiveArgs)
+
 	// Check if context is already cancelled/expired before starting
 	select {
 	case <-ctx.Done():
 		tflog.Debug(ctx, "Context already expired before command execution", map[string]interface{}{
 			"command": command,
-			"args":    args,
+			"args":    logArgs,
 			"error":   ctx.Err().Error(),
 		})
-		return ExecResult{ExitCode: -1}, fmt.Errorf("context already expired before execution: %w", ctx.Err())
+		return ExecResult{ExitCode: -1, Redactor: redactor}, fmt.Errorf("context already expired before execution: %w", ctx.Err())
 	default:
 		// Context is still valid, proceed
 	}
@@ -70,7 +129,7 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 	tflog.Debug(ctx, "SystemExecutor.Run starting",
 		map[string]interface{}{
 			"command":         command,
-			"args":            args,
+			"args":            logArgs,
 			"timeout":         opts.Timeout.String(),
 			"work_dir":        opts.WorkDir,
 			"env_count":       len(opts.Env),
@@ -92,20 +151,23 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 
 	// Prepare command with sudo if needed
 	finalCmd, finalArgs := e.prepareCommand(command, args, opts)
+	_, logFinalArgs := e.prepareCommand(command, logArgs, opts)
 
 	// DEBUG: Log the final prepared command
 	tflog.Debug(ctx, "Command prepared",
 		map[string]interface{}{
 			"original_command": command,
 			"final_command":    finalCmd,
-			"original_args":    args,
-			"final_args":       finalArgs,
+			"original_args":    logArgs,
+			"final_args":       logFinalArgs,
 			"sudo_applied":     finalCmd == "sudo" || (len(finalArgs) > 0 && finalArgs[0] == command),
 		})
 
-	// Create the command
+	// Create the command. Cancellation is handled by runInProcessGroup rather than
+	// exec.CommandContext, which would only kill the direct child.
 	// #nosec G204 - Command construction is controlled and validated through prepareCommand
-	cmd := exec.CommandContext(ctx, finalCmd, finalArgs...)
+	cmd := exec.Command(finalCmd, finalArgs...)
+	startInProcessGroup(cmd)
 
 	// Set working directory if specified
 	if opts.WorkDir != "" {
@@ -119,28 +181,44 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 	if len(opts.Env) > 0 {
 		cmd.Env = append(os.Environ(), opts.Env...)
 		tflog.Debug(ctx, "Environment variables set", map[string]interface{}{
-			"env_vars":       opts.Env,
+			"env_vars":       redactor.Env(opts.Env),
 			"total_env_vars": len(cmd.Env),
 		})
 	}
 
-	// Capture stdout and stderr
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// Capture stdout and stderr, streaming lines as they arrive if requested
+	onLine := e.streamHandler(ctx, finalCmd, redactor, opts.OnOutput)
+	stdout := newOutputCapture(StreamStdout, opts.MaxOutputBytes, onLine)
+	stderr := newOutputCapture(StreamStderr, opts.MaxOutputBytes, onLine)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	statusDone, closeStatus, err := attachStatusPipe(cmd, opts.StatusFd, onLine)
+	if err != nil {
+		return ExecResult{ExitCode: -1, Redactor: redactor}, fmt.Errorf("failed to create status pipe: %w", err)
+	}
 
 	// Log the command being executed (without sensitive info)
-	e.logger.Printf("Executing command: %s %s", finalCmd, strings.Join(finalArgs, " "))
+	e.logger.Printf("Executing command: %s %s", finalCmd, strings.Join(logFinalArgs, " "))
 
 	// DEBUG: Log execution start
 	tflog.Debug(ctx, "Starting command execution",
 		map[string]interface{}{
-			"full_command": fmt.Sprintf("%s %s", finalCmd, strings.Join(finalArgs, " ")),
+			"full_command": fmt.Sprintf("%s %s", finalCmd, strings.Join(logFinalArgs, " ")),
 			"pid":          cmd.Process,
 		})
 
+	gracePeriod := e.terminationGracePeriod
+	if opts.TerminationGracePeriod > 0 {
+		gracePeriod = opts.TerminationGracePeriod
+	}
+
 	// Execute the command
-	err := cmd.Run()
+	err = e.runInProcessGroup(ctx, cmd, finalCmd, gracePeriod)
+	closeStatus()
+	<-statusDone
+	stdout.Flush()
+	stderr.Flush()
 
 	executionDuration := time.Since(startTime)
 
@@ -148,12 +226,19 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 		Stdout:   stdout.String(),
 		Stderr:   stderr.String(),
 		ExitCode: 0,
+		Redactor: redactor,
 	}
 
 	// Get exit code if command failed
 	if err != nil {
 		var exitError *exec.ExitError
-		if errors.As(err, &exitError) {
+		if IsInterrupted(err) {
+			result.ExitCode = -1
+			tflog.Debug(ctx, "Command interrupted", map[string]interface{}{
+				"exit_code": result.ExitCode,
+				"error":     err.Error(),
+			})
+		} else if errors.As(err, &exitError) {
 			result.ExitCode = exitError.ExitCode()
 			tflog.Debug(ctx, "Command failed with exit error", map[string]interface{}{
 				"exit_code": result.ExitCode,
@@ -173,7 +258,7 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 	// DEBUG: Log comprehensive results
 	tflog.Debug(ctx, "Command execution completed",
 		map[string]interface{}{
-			"command":           fmt.Sprintf("%s %s", finalCmd, strings.Join(finalArgs, " ")),
+			"command":           fmt.Sprintf("%s %s", finalCmd, strings.Join(logFinalArgs, " ")),
 			"exit_code":         result.ExitCode,
 			"execution_time_ms": executionDuration.Milliseconds(),
 			"stdout_length":     len(result.Stdout),
@@ -184,14 +269,14 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 	// DEBUG: Log stdout/stderr content (truncated for safety)
 	if len(result.Stdout) > 0 {
 		tflog.Debug(ctx, "Command stdout output", map[string]interface{}{
-			"stdout":             e.truncateOutput(result.Stdout, 2000),
+			"stdout":             e.truncateOutput(redactor.String(result.Stdout), 2000),
 			"stdout_full_length": len(result.Stdout),
 		})
 	}
 
 	if len(result.Stderr) > 0 {
 		tflog.Debug(ctx, "Command stderr output", map[string]interface{}{
-			"stderr":             e.truncateOutput(result.Stderr, 2000),
+			"stderr":             e.truncateOutput(redactor.String(result.Stderr), 2000),
 			"stderr_full_length": len(result.Stderr),
 		})
 	}
@@ -199,7 +284,7 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 	// Log results (truncated for readability) - keeping existing logger for compatibility
 	e.logger.Printf("Command completed with exit code: %d", result.ExitCode)
 	if result.ExitCode != 0 {
-		e.logger.Printf("Command stderr: %s", e.truncateOutput(result.Stderr, 500))
+		e.logger.Printf("Command stderr: %s", e.truncateOutput(redactor.String(result.Stderr), 500))
 	}
 
 	// DEBUG: Final summary
@@ -212,6 +297,133 @@ func (e *SystemExecutor) Run(ctx context.Context, command string, args []string,
 	return result, err
 }
 
+// runInProcessGroup starts cmd and waits for it to exit. If ctx ends first, the
+// command's whole process group is sent SIGTERM and, if it is still running once
+// gracePeriod has elapsed, SIGKILL. Interruptions are reported as *InterruptedError.
+func (e *SystemExecutor) runInProcessGroup(
+	ctx context.Context, cmd *exec.Cmd, command string, gracePeriod time.Duration) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	// The command may have finished at the same moment the context ended
+	select {
+	case err := <-done:
+		return err
+	default:
+	}
+
+	interrupted := &InterruptedError{Command: command, Cause: ctx.Err()}
+	e.logger.Printf("Interrupting command %s (pid %d): %v", command, cmd.Process.Pid, ctx.Err())
+	tflog.Debug(ctx, "Sending SIGTERM to command process group", map[string]interface{}{
+		"command":                  command,
+		"pid":                      cmd.Process.Pid,
+		"termination_grace_period": gracePeriod.String(),
+		"cause":                    ctx.Err().Error(),
+	})
+	if err := signalProcessGroup(cmd, terminateSignal); err != nil {
+		e.logger.Printf("Failed to signal process group of %s: %v", command, err)
+	}
+
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return interrupted
+	case <-timer.C:
+	}
+
+	interrupted.Killed = true
+	e.logger.Printf("Command %s did not exit within %s, killing process group", command, gracePeriod)
+	if err := signalProcessGroup(cmd, os.Kill); err != nil {
+		e.logger.Printf("Failed to kill process group of %s: %v", command, err)
+	}
+	<-done
+
+	return interrupted
+}
+
+// streamHandler returns the line callback used while a command runs, or nil if
+// streaming was not requested. Each line is logged before being passed on.
+func (e *SystemExecutor) streamHandler(
+	ctx context.Context, command string, redactor *Redactor, onOutput func(OutputLine)) func(OutputLine) {
+	if onOutput == nil {
+		return nil
+	}
+
+	return serializeLines(func(line OutputLine) {
+		tflog.Debug(ctx, "Command output", map[string]interface{}{
+			"command": command,
+			"stream":  string(line.Stream),
+			"line":    redactor.String(line.Text),
+		})
+		onOutput(line)
+	})
+}
+
+// statusPipeDrainTimeout bounds how long to wait for the status pipe to reach
+// EOF after the command exits, in case a daemon it started inherited the pipe.
+const statusPipeDrainTimeout = 2 * time.Second
+
+// attachStatusPipe passes a pipe to cmd as file descriptor 3 and forwards what
+// is written to it as StreamStatus lines. The returned close function must be
+// called once the command has exited; the channel is closed when forwarding ends.
+func attachStatusPipe(cmd *exec.Cmd, enabled bool, onLine func(OutputLine)) (<-chan struct{}, func(), error) {
+	done := make(chan struct{})
+	if !enabled || runtime.GOOS == "windows" {
+		close(done)
+		return done, func() {}, nil
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd.ExtraFiles = []*os.File{writer}
+
+	// Status lines are only streamed, so keep just a small tail
+	status := newOutputCapture(StreamStatus, 4096, onLine)
+	go func() {
+		defer close(done)
+		buf := make([]byte, 4096)
+		for {
+			n, err := reader.Read(buf)
+			if n > 0 && onLine != nil {
+				_, _ = status.Write(buf[:n])
+			}
+			if err != nil {
+				status.Flush()
+				return
+			}
+		}
+	}()
+
+	closeFn := func() {
+		_ = writer.Close()
+		select {
+		case <-done:
+		case <-time.After(statusPipeDrainTimeout):
+			_ = reader.Close()
+			<-done
+		}
+		_ = reader.Close()
+	}
+
+	return done, closeFn, nil
+}
+
 // prepareCommand prepares the final command and arguments, adding sudo if needed.
 func (e *SystemExecutor) prepareCommand(command string, args []string, opts ExecOpts) (string, []string) {
 	if !opts.UseSudo {
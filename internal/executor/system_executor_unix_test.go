@@ -0,0 +1,147 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSystemExecutor_InterruptReturnsTypedError(t *testing.T) {
+	executor := NewSystemExecutor()
+
+	_, err := executor.Run(context.Background(), "sleep", []string{"5"}, ExecOpts{
+		Timeout: 100 * time.Millisecond,
+	})
+
+	if !IsInterrupted(err) {
+		t.Fatalf("Expected InterruptedError, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected error to wrap context.DeadlineExceeded, got %v", err)
+	}
+
+	var interrupted *InterruptedError
+	if errors.As(err, &interrupted) && interrupted.Killed {
+		t.Error("sleep exits on SIGTERM and should not need to be killed")
+	}
+}
+
+func TestSystemExecutor_CancelReachesWholeProcessGroup(t *testing.T) {
+	executor := NewSystemExecutor()
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	// The backgrounded sleep inherits stdout, so Run only returns promptly if the
+	// grandchild is terminated along with the shell.
+	start := time.Now()
+	result, err := executor.Run(ctx, "sh", []string{"-c", "sleep 30 & wait"}, ExecOpts{
+		Timeout: time.Minute,
+	})
+
+	if !IsInterrupted(err) {
+		t.Fatalf("Expected InterruptedError, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected error to wrap context.Canceled, got %v", err)
+	}
+	if result.ExitCode != -1 {
+		t.Errorf("Expected exit code -1 for interrupted command, got %d", result.ExitCode)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run took %s; grandchild process was not terminated", elapsed)
+	}
+}
+
+func TestSystemExecutor_KillsAfterTerminationGracePeriod(t *testing.T) {
+	executor := NewSystemExecutor()
+
+	// Ignoring SIGTERM is inherited by the sleeps, so only SIGKILL stops the group
+	start := time.Now()
+	_, err := executor.Run(context.Background(), "sh", []string{"-c", `trap "" TERM; while true; do sleep 1; done`}, ExecOpts{
+		Timeout:                200 * time.Millisecond,
+		TerminationGracePeriod: 300 * time.Millisecond,
+	})
+
+	var interrupted *InterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("Expected InterruptedError, got %v", err)
+	}
+	if !interrupted.Killed {
+		t.Error("Expected command ignoring SIGTERM to be killed after the grace period")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run took %s; process group was not killed", elapsed)
+	}
+}
+
+func TestSystemExecutor_StreamsOutputAndStatusPipe(t *testing.T) {
+	executor := NewSystemExecutor()
+
+	var stdoutLines, statusLines []string
+	result, err := executor.Run(context.Background(), "sh",
+		[]string{"-c", "echo one; echo pmstatus:curl:50:Unpacking >&3; echo two"},
+		ExecOpts{
+			Timeout:  10 * time.Second,
+			StatusFd: true,
+			OnOutput: func(line OutputLine) {
+				switch line.Stream {
+				case StreamStdout:
+					stdoutLines = append(stdoutLines, line.Text)
+				case StreamStatus:
+					statusLines = append(statusLines, line.Text)
+				}
+			},
+		})
+
+	if err != nil {
+		t.Fatalf("Command execution failed: %v", err)
+	}
+	if len(stdoutLines) != 2 || stdoutLines[0] != "one" || stdoutLines[1] != "two" {
+		t.Errorf("Expected stdout lines [one two], got %v", stdoutLines)
+	}
+	if len(statusLines) != 1 || statusLines[0] != "pmstatus:curl:50:Unpacking" {
+		t.Errorf("Expected one status line, got %v", statusLines)
+	}
+	if result.Stdout != "one\ntwo\n" {
+		t.Errorf("Expected status lines to stay out of captured stdout, got %q", result.Stdout)
+	}
+}
+
+func TestSystemExecutor_RecordsSpan(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracerProvider)
+	defer otel.SetTracerProvider(previous)
+
+	executor := NewSystemExecutor()
+	_, _ = executor.Run(context.Background(), "/bin/sh", []string{"-c", "exit 3"}, ExecOpts{})
+
+	ended := spans.GetSpans()
+	if len(ended) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(ended))
+	}
+	span := ended[0]
+	if span.Name != "exec sh" {
+		t.Errorf("Expected span name 'exec sh', got %q", span.Name)
+	}
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["pkg.command"].AsString(); got != "/bin/sh -c exit 3" {
+		t.Errorf("Expected command attribute '/bin/sh -c exit 3', got %q", got)
+	}
+	if got := attrs["pkg.exit_code"].AsInt64(); got != 3 {
+		t.Errorf("Expected exit code attribute 3, got %d", got)
+	}
+}
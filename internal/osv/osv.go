@@ -0,0 +1,403 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package osv matches installed packages against a local copy of an
+// OSV-format vulnerability database (https://ossf.github.io/osv-schema/).
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Vulnerability is an OSV advisory.
+type Vulnerability struct {
+	ID               string         `json:"id"`
+	Summary          string         `json:"summary"`
+	Details          string         `json:"details"`
+	Aliases          []string       `json:"aliases"`
+	Upstream         []string       `json:"upstream"`
+	Withdrawn        string         `json:"withdrawn"`
+	Severity         []Severity     `json:"severity"`
+	Affected         []Affected     `json:"affected"`
+	References       []Reference    `json:"references"`
+	DatabaseSpecific map[string]any `json:"database_specific"`
+}
+
+// Severity is a severity score, such as a CVSS vector.
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected lists the affected versions of one package.
+type Affected struct {
+	Package           Package        `json:"package"`
+	Ranges            []Range        `json:"ranges"`
+	Versions          []string       `json:"versions"`
+	Severity          []Severity     `json:"severity"`
+	EcosystemSpecific map[string]any `json:"ecosystem_specific"`
+	DatabaseSpecific  map[string]any `json:"database_specific"`
+}
+
+// Package identifies a package within an ecosystem, e.g. "Debian:12".
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl"`
+}
+
+// Range is a range of affected versions described by events.
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event introduces, fixes or bounds a range of affected versions.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Reference is a link to more information about an advisory.
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Database indexes the advisories of an OSV database by ecosystem and package.
+type Database struct {
+	// index maps "<ecosystem>/<package>", without the ecosystem release, to
+	// the advisories affecting the package
+	index map[string][]*Vulnerability
+	// Count is the number of advisories loaded
+	Count int
+}
+
+// Load reads an OSV database from a directory of advisory JSON files,
+// searched recursively, or from a zip archive of them such as the
+// <ecosystem>/all.zip exports published by osv.dev.
+func Load(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vulnerability database: %w", err)
+	}
+
+	db := &Database{index: map[string][]*Vulnerability{}}
+	if info.IsDir() {
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+				return err
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return db.add(file, data)
+		})
+	} else {
+		err = db.loadZip(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// loadZip adds every advisory JSON file in a zip archive.
+func (db *Database) loadZip(path string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("failed to open vulnerability database %s: %w", path, err)
+	}
+	defer func() { _ = archive.Close() }()
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s from %s: %w", file.Name, path, err)
+		}
+		data, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s from %s: %w", file.Name, path, err)
+		}
+		if err := db.add(file.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add indexes one advisory. Withdrawn advisories are skipped.
+func (db *Database) add(name string, data []byte) error {
+	var vuln Vulnerability
+	if err := json.Unmarshal(data, &vuln); err != nil {
+		return fmt.Errorf("failed to parse OSV advisory %s: %w", name, err)
+	}
+	if vuln.ID == "" || vuln.Withdrawn != "" {
+		return nil
+	}
+
+	db.Count++
+	seen := map[string]bool{}
+	for _, affected := range vuln.Affected {
+		key := indexKey(affected.Package.Ecosystem, affected.Package.Name)
+		if !seen[key] {
+			seen[key] = true
+			db.index[key] = append(db.index[key], &vuln)
+		}
+	}
+	return nil
+}
+
+// indexKey is the index key of a package, ignoring the ecosystem release.
+func indexKey(ecosystem, name string) string {
+	base, _, _ := strings.Cut(ecosystem, ":")
+	return base + "/" + name
+}
+
+// Finding is an advisory affecting a package version.
+type Finding struct {
+	Vulnerability *Vulnerability
+	// Affected is the entry of the advisory that matched
+	Affected *Affected
+	// Fixed is the first version fixing the advisory, or empty if no fix is known
+	Fixed string
+}
+
+// Match returns the advisories affecting version of the package name in
+// ecosystem, such as "Debian:12", "Ubuntu:22.04" or "Homebrew". Advisories
+// for other releases of the ecosystem are ignored; an ecosystem without a
+// release matches every release. Versions are compared with the scheme of
+// the ecosystem.
+func (db *Database) Match(ecosystem, name, version string) []Finding {
+	compare := comparerFor(ecosystem)
+	findings := []Finding{}
+	for _, vuln := range db.index[indexKey(ecosystem, name)] {
+		for i := range vuln.Affected {
+			affected := &vuln.Affected[i]
+			if affected.Package.Name != name || !ecosystemMatches(ecosystem, affected.Package.Ecosystem) {
+				continue
+			}
+			if hit, fixed := affects(affected, version, compare); hit {
+				findings = append(findings, Finding{Vulnerability: vuln, Affected: affected, Fixed: fixed})
+				break
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Vulnerability.ID < findings[j].Vulnerability.ID })
+	return findings
+}
+
+// ecosystemMatches reports whether an advisory for ecosystem applies to the
+// queried ecosystem. Releases are the colon-separated segments after the
+// ecosystem name, e.g. "22.04" in "Ubuntu:Pro:22.04:LTS".
+func ecosystemMatches(query, ecosystem string) bool {
+	queryBase, queryRelease, _ := strings.Cut(query, ":")
+	base, release, _ := strings.Cut(ecosystem, ":")
+	if queryBase != base {
+		return false
+	}
+	if queryRelease == "" || release == "" {
+		return true
+	}
+	for _, segment := range strings.Split(release, ":") {
+		if segment == queryRelease {
+			return true
+		}
+	}
+	return false
+}
+
+// affects reports whether version is affected, and the first fixed version
+// after it. Only ECOSYSTEM and SEMVER ranges are evaluated; GIT ranges name
+// commits, not package versions.
+func affects(affected *Affected, version string, compare func(a, b string) int) (bool, string) {
+	hit := false
+	for _, listed := range affected.Versions {
+		if compare(listed, version) == 0 {
+			hit = true
+		}
+	}
+
+	fixed := ""
+	for _, r := range affected.Ranges {
+		if r.Type != "ECOSYSTEM" && r.Type != "SEMVER" {
+			continue
+		}
+		inRange, rangeFixed := inRange(r.Events, version, compare)
+		if inRange {
+			hit = true
+			if rangeFixed != "" && (fixed == "" || compare(rangeFixed, fixed) < 0) {
+				fixed = rangeFixed
+			}
+		}
+	}
+	return hit, fixed
+}
+
+// inRange evaluates range events in version order: an introduced event at or
+// below version starts an affected span, a fixed event at or below it ends
+// the span, as does a last_affected event below it.
+func inRange(events []Event, version string, compare func(a, b string) int) (bool, string) {
+	sorted := append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareEvent(sorted[i], sorted[j], compare) < 0
+	})
+
+	affected := false
+	fixed := ""
+	for _, event := range sorted {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" || compare(version, event.Introduced) >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if compare(version, event.Fixed) >= 0 {
+				affected = false
+			} else if affected && fixed == "" {
+				fixed = event.Fixed
+			}
+		case event.LastAffected != "":
+			if compare(version, event.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	if !affected {
+		return false, ""
+	}
+	return true, fixed
+}
+
+// compareEvent orders events by the version they name, with introduced "0"
+// before every version.
+func compareEvent(a, b Event, compare func(a, b string) int) int {
+	av, bv := eventVersion(a), eventVersion(b)
+	switch {
+	case av == bv:
+		return 0
+	case av == "0" && a.Introduced != "":
+		return -1
+	case bv == "0" && b.Introduced != "":
+		return 1
+	}
+	return compare(av, bv)
+}
+
+// eventVersion returns the version named by an event.
+func eventVersion(event Event) string {
+	for _, version := range []string{event.Introduced, event.Fixed, event.LastAffected, event.Limit} {
+		if version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// CVEs returns the CVE identifiers of the advisory, from its ID, aliases and
+// upstream advisories.
+func (v *Vulnerability) CVEs() []string {
+	seen := map[string]bool{}
+	cves := []string{}
+	for _, id := range append(append([]string{v.ID}, v.Aliases...), v.Upstream...) {
+		if strings.HasPrefix(id, "CVE-") && !seen[id] {
+			seen[id] = true
+			cves = append(cves, id)
+		}
+	}
+	sort.Strings(cves)
+	return cves
+}
+
+// URL returns the advisory page, preferring ADVISORY then WEB references, or
+// the osv.dev page for the advisory.
+func (v *Vulnerability) URL() string {
+	for _, refType := range []string{"ADVISORY", "WEB"} {
+		for _, ref := range v.References {
+			if ref.Type == refType {
+				return ref.URL
+			}
+		}
+	}
+	if len(v.References) > 0 {
+		return v.References[0].URL
+	}
+	return "https://osv.dev/vulnerability/" + v.ID
+}
+
+// Cache keeps loaded databases in memory, reloading one when its file or
+// directory modification time changes.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	modTime time.Time
+	db      *Database
+}
+
+// NewCache creates an empty database cache.
+func NewCache() *Cache {
+	return &Cache{entries: map[string]cacheEntry{}}
+}
+
+// Load returns the database at path, loading it if it is not cached or has
+// changed on disk since it was loaded.
+func (c *Cache) Load(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vulnerability database: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[path]; ok && entry.modTime.Equal(info.ModTime()) {
+		return entry.db, nil
+	}
+
+	db, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	c.entries[path] = cacheEntry{modTime: info.ModTime(), db: db}
+	return db, nil
+}
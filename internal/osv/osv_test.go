@@ -0,0 +1,209 @@
+package osv
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const debianAdvisory = `{
+  "id": "DSA-5750-1",
+  "summary": "curl security update",
+  "details": "Multiple vulnerabilities were found in curl.",
+  "upstream": ["CVE-2024-7264", "CVE-2024-2398"],
+  "affected": [{
+    "package": {"ecosystem": "Debian:12", "name": "curl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "7.88.1-10+deb12u7"}]}]
+  }, {
+    "package": {"ecosystem": "Debian:11", "name": "curl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "7.74.0-1.3+deb11u13"}]}]
+  }],
+  "references": [{"type": "WEB", "url": "https://www.debian.org/security/2024/dsa-5750"}]
+}`
+
+const ubuntuAdvisory = `{
+  "id": "UBUNTU-CVE-2024-6387",
+  "summary": "regreSSHion",
+  "upstream": ["CVE-2024-6387"],
+  "severity": [{"type": "Ubuntu", "score": "high"}],
+  "affected": [{
+    "package": {"ecosystem": "Ubuntu:22.04:LTS", "name": "openssh"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "1:8.9p1-3ubuntu0.10"}]}]
+  }]
+}`
+
+const homebrewAdvisory = `{
+  "id": "GHSA-xxxx-yyyy-zzzz",
+  "aliases": ["CVE-2023-0001"],
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+  "affected": [{
+    "package": {"ecosystem": "Homebrew", "name": "jq"},
+    "ranges": [
+      {"type": "SEMVER", "events": [{"introduced": "1.5.0"}, {"fixed": "1.6.0"}]},
+      {"type": "SEMVER", "events": [{"introduced": "1.7.0"}, {"last_affected": "1.7.1"}]},
+      {"type": "GIT", "events": [{"introduced": "0"}, {"fixed": "abc123"}]}
+    ],
+    "versions": ["1.4.9"]
+  }],
+  "references": [{"type": "ADVISORY", "url": "https://github.com/advisories/GHSA-xxxx-yyyy-zzzz"}]
+}`
+
+const withdrawnAdvisory = `{
+  "id": "DSA-0000-1",
+  "withdrawn": "2024-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Debian:12", "name": "curl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}]}]
+  }]
+}`
+
+func writeAdvisories(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"Debian/DSA-5750-1.json":           debianAdvisory,
+		"Debian/DSA-0000-1.json":           withdrawnAdvisory,
+		"Ubuntu/UBUNTU-CVE-2024-6387.json": ubuntuAdvisory,
+		"GHSA-xxxx-yyyy-zzzz.json":         homebrewAdvisory,
+		"README.md":                        "not an advisory",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestLoad_Directory(t *testing.T) {
+	db, err := Load(writeAdvisories(t))
+	require.NoError(t, err)
+	assert.Equal(t, 3, db.Count)
+}
+
+func TestLoad_Zip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "all.zip")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	archive := zip.NewWriter(file)
+	writer, err := archive.Create("DSA-5750-1.json")
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(debianAdvisory))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+	require.NoError(t, file.Close())
+
+	db, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.Count)
+	assert.Len(t, db.Match("Debian:12", "curl", "7.88.1-10+deb12u5"), 1)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0o600))
+	_, err = Load(dir)
+	assert.ErrorContains(t, err, "failed to parse OSV advisory")
+}
+
+func TestDatabase_Match(t *testing.T) {
+	db, err := Load(writeAdvisories(t))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		ecosystem string
+		pkg       string
+		version   string
+		ids       []string
+		fixed     string
+	}{
+		{"debian affected", "Debian:12", "curl", "7.88.1-10+deb12u5", []string{"DSA-5750-1"}, "7.88.1-10+deb12u7"},
+		{"debian fixed", "Debian:12", "curl", "7.88.1-10+deb12u7", nil, ""},
+		{"other debian release", "Debian:11", "curl", "7.74.0-1.3+deb11u12", []string{"DSA-5750-1"}, "7.74.0-1.3+deb11u13"},
+		{"unlisted release", "Debian:13", "curl", "7.0", nil, ""},
+		{"ubuntu release segment", "Ubuntu:22.04", "openssh", "1:8.9p1-3ubuntu0.6", []string{"UBUNTU-CVE-2024-6387"}, "1:8.9p1-3ubuntu0.10"},
+		{"ubuntu epoch fixed", "Ubuntu:22.04", "openssh", "1:8.9p1-3ubuntu0.10", nil, ""},
+		{"homebrew range", "Homebrew", "jq", "1.5.1", []string{"GHSA-xxxx-yyyy-zzzz"}, "1.6.0"},
+		{"homebrew last affected", "Homebrew", "jq", "1.7.1", []string{"GHSA-xxxx-yyyy-zzzz"}, ""},
+		{"homebrew after last affected", "Homebrew", "jq", "1.7.2", nil, ""},
+		{"homebrew explicit version", "Homebrew", "jq", "1.4.9", []string{"GHSA-xxxx-yyyy-zzzz"}, ""},
+		{"homebrew unaffected", "Homebrew", "jq", "1.6.0", nil, ""},
+		{"wrong ecosystem", "Alpine:v3.19", "curl", "7.0", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := db.Match(tt.ecosystem, tt.pkg, tt.version)
+			ids := []string(nil)
+			for _, finding := range findings {
+				ids = append(ids, finding.Vulnerability.ID)
+			}
+			assert.Equal(t, tt.ids, ids)
+			if len(findings) > 0 {
+				assert.Equal(t, tt.fixed, findings[0].Fixed)
+			}
+		})
+	}
+}
+
+func TestVulnerability_Helpers(t *testing.T) {
+	db, err := Load(writeAdvisories(t))
+	require.NoError(t, err)
+
+	debian := db.Match("Debian:12", "curl", "7.88.1-10")[0]
+	assert.Equal(t, []string{"CVE-2024-2398", "CVE-2024-7264"}, debian.Vulnerability.CVEs())
+	assert.Equal(t, "https://www.debian.org/security/2024/dsa-5750", debian.Vulnerability.URL())
+	assert.Equal(t, SeverityUnknown, debian.SeverityLevel())
+
+	ubuntu := db.Match("Ubuntu:22.04", "openssh", "1:8.9p1-3")[0]
+	assert.Equal(t, SeverityHigh, ubuntu.SeverityLevel())
+	assert.Equal(t, "https://osv.dev/vulnerability/UBUNTU-CVE-2024-6387", ubuntu.Vulnerability.URL())
+
+	brew := db.Match("Homebrew", "jq", "1.5.0")[0]
+	assert.Equal(t, []string{"CVE-2023-0001"}, brew.Vulnerability.CVEs())
+	assert.Equal(t, SeverityCritical, brew.SeverityLevel())
+	assert.Equal(t, "https://github.com/advisories/GHSA-xxxx-yyyy-zzzz", brew.Vulnerability.URL())
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	tests := []struct {
+		vector   string
+		expected float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+		{"CVSS:3.0/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N", 5.5},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:N/A:N", 0},
+	}
+	for _, tt := range tests {
+		score, ok := CVSS3BaseScore(tt.vector)
+		assert.True(t, ok, tt.vector)
+		assert.Equal(t, tt.expected, score, tt.vector)
+	}
+
+	_, ok := CVSS3BaseScore("AV:N/AC:L")
+	assert.False(t, ok)
+	_, ok = CVSS3BaseScore("CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	assert.False(t, ok)
+}
+
+func TestCache_Load(t *testing.T) {
+	dir := writeAdvisories(t)
+	cache := NewCache()
+
+	first, err := cache.Load(dir)
+	require.NoError(t, err)
+	second, err := cache.Load(dir)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	_, err = cache.Load(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package osv
+
+import (
+	"math"
+	"strings"
+)
+
+// Severity levels reported for advisories.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityUnknown  = "unknown"
+)
+
+// severityAliases normalizes distribution severity names.
+var severityAliases = map[string]string{
+	"critical":    SeverityCritical,
+	"high":        SeverityHigh,
+	"important":   SeverityHigh,
+	"medium":      SeverityMedium,
+	"moderate":    SeverityMedium,
+	"low":         SeverityLow,
+	"negligible":  SeverityLow,
+	"unimportant": SeverityLow,
+}
+
+// SeverityLevel returns the severity of the finding: the severity assigned
+// by the distribution when there is one, otherwise the rating of the CVSS v3
+// base score, otherwise "unknown".
+func (f Finding) SeverityLevel() string {
+	for _, specific := range []map[string]any{f.Affected.EcosystemSpecific, f.Affected.DatabaseSpecific, f.Vulnerability.DatabaseSpecific} {
+		if level, ok := specific["severity"].(string); ok {
+			if normalized, ok := severityAliases[strings.ToLower(level)]; ok {
+				return normalized
+			}
+		}
+	}
+
+	for _, scores := range [][]Severity{f.Affected.Severity, f.Vulnerability.Severity} {
+		for _, score := range scores {
+			if score.Type == "Ubuntu" {
+				if normalized, ok := severityAliases[strings.ToLower(score.Score)]; ok {
+					return normalized
+				}
+			}
+		}
+		for _, score := range scores {
+			if score.Type == "CVSS_V3" {
+				if base, ok := CVSS3BaseScore(score.Score); ok {
+					return cvssRating(base)
+				}
+			}
+		}
+	}
+	return SeverityUnknown
+}
+
+// cvssRating is the qualitative rating of a CVSS base score.
+func cvssRating(score float64) string {
+	switch {
+	case score == 0:
+		return SeverityUnknown
+	case score < 4:
+		return SeverityLow
+	case score < 7:
+		return SeverityMedium
+	case score < 9:
+		return SeverityHigh
+	default:
+		return SeverityCritical
+	}
+}
+
+// cvss3Weights are the CVSS v3.x base metric weights. The privileges
+// required weights depend on the scope and are handled separately.
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSS3BaseScore computes the base score of a CVSS v3.0 or v3.1 vector such
+// as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H".
+func CVSS3BaseScore(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, false
+	}
+	metrics := map[string]string{}
+	for _, part := range parts[1:] {
+		if key, value, ok := strings.Cut(part, ":"); ok {
+			metrics[key] = value
+		}
+	}
+
+	weight := func(metric string) (float64, bool) {
+		w, ok := cvss3Weights[metric][metrics[metric]]
+		return w, ok
+	}
+	av, ok1 := weight("AV")
+	ac, ok2 := weight("AC")
+	ui, ok3 := weight("UI")
+	c, ok4 := weight("C")
+	i, ok5 := weight("I")
+	a, ok6 := weight("A")
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 || !ok6 {
+		return 0, false
+	}
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+	var pr float64
+	switch metrics["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if changed {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if changed {
+			pr = 0.5
+		}
+	default:
+		return 0, false
+	}
+
+	iss := 1 - (1-c)*(1-i)*(1-a)
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+	exploitability := 8.22 * av * ac * pr * ui
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp rounds up to one decimal place as specified by CVSS v3.1.
+func roundUp(value float64) float64 {
+	scaled := int(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return (math.Floor(float64(scaled)/10000) + 1) / 10
+}
@@ -0,0 +1,294 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package osv
+
+import (
+	"strings"
+)
+
+// comparerFor returns the version comparison of an ecosystem.
+func comparerFor(ecosystem string) func(a, b string) int {
+	base, _, _ := strings.Cut(ecosystem, ":")
+	switch base {
+	case "Debian", "Ubuntu":
+		return CompareDebian
+	case "Alpine":
+		return CompareAlpine
+	default:
+		return CompareGeneric
+	}
+}
+
+// CompareDebian compares two Debian package versions ([epoch:]upstream[-revision])
+// the way dpkg does, returning -1, 0 or 1.
+func CompareDebian(a, b string) int {
+	aEpoch, aUpstream, aRevision := splitDebian(a)
+	bEpoch, bUpstream, bRevision := splitDebian(b)
+	if c := compareNumeric(aEpoch, bEpoch); c != 0 {
+		return c
+	}
+	if c := verrevcmp(aUpstream, bUpstream); c != 0 {
+		return c
+	}
+	return verrevcmp(aRevision, bRevision)
+}
+
+// splitDebian splits a Debian version into epoch, upstream version and revision.
+func splitDebian(version string) (string, string, string) {
+	epoch := "0"
+	if before, after, ok := strings.Cut(version, ":"); ok {
+		epoch, version = before, after
+	}
+	revision := ""
+	if i := strings.LastIndex(version, "-"); i >= 0 {
+		version, revision = version[:i], version[i+1:]
+	}
+	return epoch, version, revision
+}
+
+// debianOrder is the sort weight of a character in a non-digit part of a
+// Debian version: "~" sorts before everything, even the end of the part,
+// and letters sort before other characters.
+func debianOrder(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case c >= '0' && c <= '9':
+		return 0
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+// verrevcmp is dpkg's comparison of upstream versions and revisions.
+func verrevcmp(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ac, bc := 0, 0
+			if i < len(a) && !isDigit(a[i]) {
+				ac = debianOrder(a[i])
+			}
+			if j < len(b) && !isDigit(b[j]) {
+				bc = debianOrder(b[j])
+			}
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		diff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if diff == 0 {
+				diff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if diff != 0 {
+			return sign(diff)
+		}
+	}
+	return 0
+}
+
+// CompareAlpine compares two Alpine package versions
+// (digits{.digits}[letter][_suffix[digits]...][-r<revision>]), returning -1, 0 or 1.
+func CompareAlpine(a, b string) int {
+	aVersion, aRevision, _ := strings.Cut(a, "-r")
+	bVersion, bRevision, _ := strings.Cut(b, "-r")
+	aParts, bParts := alpineTokens(aVersion), alpineTokens(bVersion)
+	for k := 0; k < len(aParts) || k < len(bParts); k++ {
+		var at, bt alpineToken
+		if k < len(aParts) {
+			at = aParts[k]
+		}
+		if k < len(bParts) {
+			bt = bParts[k]
+		}
+		if c := at.compare(bt); c != 0 {
+			return c
+		}
+	}
+	return compareNumeric(aRevision, bRevision)
+}
+
+// alpineSuffixes orders the pre-release (negative) and post-release
+// (positive) suffixes of Alpine versions.
+var alpineSuffixes = map[string]int{
+	"alpha": -4, "beta": -3, "pre": -2, "rc": -1,
+	"cvs": 1, "svn": 2, "git": 3, "hg": 4, "p": 5,
+}
+
+// alpineToken is a number, letter or suffix of an Alpine version. The zero
+// value marks the end of a version.
+type alpineToken struct {
+	kind  int // 0 end, 1 number, 2 letter, 3 suffix
+	value string
+}
+
+func alpineTokens(version string) []alpineToken {
+	tokens := []alpineToken{}
+	for _, part := range strings.Split(version, "_") {
+		if len(tokens) > 0 {
+			name := strings.TrimRightFunc(part, func(r rune) bool { return r >= '0' && r <= '9' })
+			tokens = append(tokens, alpineToken{kind: 3, value: name})
+			if number := part[len(name):]; number != "" {
+				tokens = append(tokens, alpineToken{kind: 1, value: number})
+			}
+			continue
+		}
+		for _, field := range strings.Split(part, ".") {
+			number := strings.TrimRightFunc(field, func(r rune) bool { return r < '0' || r > '9' })
+			tokens = append(tokens, alpineToken{kind: 1, value: number})
+			if letter := field[len(number):]; letter != "" {
+				tokens = append(tokens, alpineToken{kind: 2, value: letter})
+			}
+		}
+	}
+	return tokens
+}
+
+// compare orders two tokens at the same position. A pre-release suffix sorts
+// before the end of a version and everything else sorts after it.
+func (t alpineToken) compare(o alpineToken) int {
+	if t.kind != o.kind {
+		return sign(t.weight() - o.weight())
+	}
+	switch t.kind {
+	case 1:
+		return compareNumeric(t.value, o.value)
+	case 2:
+		return strings.Compare(t.value, o.value)
+	case 3:
+		return sign(alpineSuffixes[t.value] - alpineSuffixes[o.value])
+	}
+	return 0
+}
+
+func (t alpineToken) weight() int {
+	if t.kind == 3 && alpineSuffixes[t.value] < 0 {
+		return -1
+	}
+	return t.kind
+}
+
+// CompareGeneric compares versions such as Homebrew or semantic versions by
+// their numeric and alphabetic runs, ignoring separators. Numbers compare
+// numerically and sort after letters, so "1.0rc1" < "1.0" < "1.0.1", and a
+// semantic version pre-release sorts before its release. Build metadata and
+// Homebrew revisions ("_1") compare as trailing numbers.
+func CompareGeneric(a, b string) int {
+	a, b = strings.TrimPrefix(a, "v"), strings.TrimPrefix(b, "v")
+	aParts, bParts := genericTokens(a), genericTokens(b)
+	for k := 0; k < len(aParts) || k < len(bParts); k++ {
+		switch {
+		case k >= len(aParts):
+			// a trailing pre-release tag makes b older
+			if !isDigit(bParts[k][0]) {
+				return 1
+			}
+			return -1
+		case k >= len(bParts):
+			if !isDigit(aParts[k][0]) {
+				return -1
+			}
+			return 1
+		}
+		at, bt := aParts[k], bParts[k]
+		aNum, bNum := isDigit(at[0]), isDigit(bt[0])
+		var c int
+		switch {
+		case aNum && bNum:
+			c = compareNumeric(at, bt)
+		case aNum:
+			c = 1
+		case bNum:
+			c = -1
+		default:
+			c = strings.Compare(strings.ToLower(at), strings.ToLower(bt))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// genericTokens splits a version into runs of digits and runs of letters.
+func genericTokens(version string) []string {
+	tokens := []string{}
+	start := -1
+	for i := 0; i <= len(version); i++ {
+		if start >= 0 && (i == len(version) || !isAlnum(version[i]) || isDigit(version[i]) != isDigit(version[start])) {
+			tokens = append(tokens, version[start:i])
+			start = -1
+		}
+		if start < 0 && i < len(version) && isAlnum(version[i]) {
+			start = i
+		}
+	}
+	return tokens
+}
+
+// compareNumeric compares two strings of digits by value.
+func compareNumeric(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareDebian(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0-1", "1.0-2", -1},
+		{"1:1.0", "2.0", 1},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0", "1.0+deb12u1", -1},
+		{"7.88.1-10+deb12u5", "7.88.1-10+deb12u12", -1},
+		{"3.0.2-0ubuntu1.15", "3.0.2-0ubuntu1.9", 1},
+		{"1.2a", "1.2", 1},
+		{"1.002", "1.2", 0},
+		{"2.36-9+deb12u4", "2.36-9+deb12u4", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CompareDebian(tt.a, tt.b))
+			assert.Equal(t, -tt.expected, CompareDebian(tt.b, tt.a))
+		})
+	}
+}
+
+func TestCompareAlpine(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2.3-r0", "1.2.3-r1", -1},
+		{"1.2.3-r10", "1.2.3-r9", 1},
+		{"1.2.3_rc1-r0", "1.2.3-r0", -1},
+		{"1.2.3_p1-r0", "1.2.3-r0", 1},
+		{"1.2.10", "1.2.9", 1},
+		{"3.1.4a", "3.1.4", 1},
+		{"1.36.1-r15", "1.36.1-r15", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CompareAlpine(tt.a, tt.b))
+			assert.Equal(t, -tt.expected, CompareAlpine(tt.b, tt.a))
+		})
+	}
+}
+
+func TestCompareGeneric(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.2.10", "1.2.9", 1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"3.1.4_1", "3.1.4", 1},
+		{"v2.0.0", "2.0.0", 0},
+		{"1.0", "1.0.1", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CompareGeneric(tt.a, tt.b))
+			assert.Equal(t, -tt.expected, CompareGeneric(tt.b, tt.a))
+		})
+	}
+}
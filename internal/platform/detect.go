@@ -0,0 +1,250 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package platform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// osReleasePaths are the os-release files in order of precedence.
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// cgroupRuntimes maps names found in the control groups of process 1 to the
+// container runtime they reveal, most specific first.
+var cgroupRuntimes = []struct {
+	name    string
+	runtime string
+}{
+	{name: "kubepods", runtime: "kubernetes"},
+	{name: "docker", runtime: "docker"},
+	{name: "containerd", runtime: "containerd"},
+	{name: "lxc", runtime: "lxc"},
+}
+
+// Detector gathers platform facts from the files and commands of the host.
+type Detector struct {
+	executor executor.Executor
+	// root prefixes the files read, so tests can supply their own
+	root   string
+	goos   string
+	goarch string
+	getenv func(string) string
+}
+
+// NewDetector creates a Detector for the host, running commands through exec.
+func NewDetector(exec executor.Executor) *Detector {
+	return &Detector{
+		executor: exec,
+		root:     "/",
+		goos:     runtime.GOOS,
+		goarch:   runtime.GOARCH,
+		getenv:   os.Getenv,
+	}
+}
+
+// Identify returns the operating system and distribution, read from
+// os-release on Linux, without running any command. The architecture is the
+// one the provider was built for, and the remaining facts are left empty.
+func (d *Detector) Identify() *Platform {
+	p := &Platform{OS: d.goos, Arch: d.goarch, InitSystem: InitUnknown}
+	switch d.goos {
+	case "linux":
+		release := d.osRelease()
+		p.Family = family(release)
+		p.Distribution = release["ID"]
+		p.DistributionName = release["PRETTY_NAME"]
+		if p.DistributionName == "" {
+			p.DistributionName = release["NAME"]
+		}
+		p.Version = release["VERSION_ID"]
+		p.Codename = release["VERSION_CODENAME"]
+	case "darwin":
+		p.Family = FamilyDarwin
+		p.Distribution = "macos"
+	case "windows":
+		p.Family = FamilyWindows
+		p.Distribution = "windows"
+	}
+	return p
+}
+
+// Detect returns every platform fact. Facts that cannot be determined are
+// left empty, except that a failure to run sw_vers on macOS is an error.
+func (d *Detector) Detect(ctx context.Context) (*Platform, error) {
+	p := d.Identify()
+	switch d.goos {
+	case "linux":
+		p.Arch = d.machineArch(ctx)
+		p.ContainerRuntime = d.containerRuntime()
+		p.WSL = d.isWSL()
+		p.InitSystem = d.linuxInitSystem()
+	case "darwin":
+		if err := d.detectMacOS(ctx, p); err != nil {
+			return nil, err
+		}
+		p.Arch = d.darwinArch(ctx)
+		p.InitSystem = InitLaunchd
+	}
+	return p, nil
+}
+
+// path returns the location of a host file below the detector's root.
+func (d *Detector) path(name string) string {
+	return filepath.Join(d.root, name)
+}
+
+// exists reports whether a host file exists.
+func (d *Detector) exists(name string) bool {
+	_, err := os.Stat(d.path(name))
+	return err == nil
+}
+
+// readFile returns the contents of a host file, or "" if it cannot be read.
+func (d *Detector) readFile(name string) string {
+	data, err := os.ReadFile(d.path(name))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// osRelease returns the fields of the first os-release file found.
+func (d *Detector) osRelease() map[string]string {
+	for _, name := range osReleasePaths {
+		if content := d.readFile(name); content != "" {
+			return ParseOSRelease(content)
+		}
+	}
+	return map[string]string{}
+}
+
+// output runs a command and returns its trimmed output, or "" if it fails.
+func (d *Detector) output(ctx context.Context, cmd string, args ...string) string {
+	if d.executor == nil {
+		return ""
+	}
+	result, err := d.executor.Run(ctx, cmd, args, executor.ExecOpts{Timeout: 10 * time.Second})
+	if err != nil || result.ExitCode != 0 {
+		return ""
+	}
+	return strings.TrimSpace(result.Stdout)
+}
+
+// machineArch returns the machine architecture from 'uname -m', falling back
+// to the architecture the provider was built for.
+func (d *Detector) machineArch(ctx context.Context) string {
+	if machine := d.output(ctx, "uname", "-m"); machine != "" {
+		return NormalizeArch(machine)
+	}
+	return d.goarch
+}
+
+// darwinArch reports arm64 on Apple silicon even when the provider runs
+// under Rosetta 2, where 'uname -m' reports x86_64.
+func (d *Detector) darwinArch(ctx context.Context) string {
+	if d.output(ctx, "sysctl", "-n", "hw.optional.arm64") == "1" {
+		return "arm64"
+	}
+	return d.machineArch(ctx)
+}
+
+// detectMacOS reads the macOS version from sw_vers.
+func (d *Detector) detectMacOS(ctx context.Context, p *Platform) error {
+	result, err := d.executor.Run(ctx, "sw_vers", nil, executor.ExecOpts{Timeout: 10 * time.Second})
+	if err != nil || result.ExitCode != 0 {
+		return fmt.Errorf("failed to read the macOS version with sw_vers: exit code %d, error: %w, stderr: %s",
+			result.ExitCode, err, result.Stderr)
+	}
+
+	fields := parseSwVers(result.Stdout)
+	p.Version = fields["ProductVersion"]
+	major, _, _ := strings.Cut(p.Version, ".")
+	p.Codename = macOSNames[major]
+	p.DistributionName = strings.TrimSpace(fields["ProductName"] + " " + p.Version)
+	return nil
+}
+
+// containerRuntime identifies the container runtime from the marker files and
+// environment variables runtimes set up, and from the control groups of
+// process 1.
+func (d *Detector) containerRuntime() string {
+	switch {
+	case d.getenv("KUBERNETES_SERVICE_HOST") != "":
+		return "kubernetes"
+	case d.exists("/.dockerenv"):
+		return "docker"
+	case d.exists("/run/.containerenv"):
+		return "podman"
+	case d.getenv("container") != "":
+		// Set by systemd-nspawn, LXC and podman
+		return d.getenv("container")
+	}
+
+	cgroup := d.readFile("/proc/1/cgroup")
+	for _, marker := range cgroupRuntimes {
+		if strings.Contains(cgroup, marker.name) {
+			return marker.runtime
+		}
+	}
+	return ""
+}
+
+// isWSL reports whether the kernel is a Windows Subsystem for Linux one.
+func (d *Detector) isWSL() bool {
+	if d.getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(d.readFile("/proc/sys/kernel/osrelease")), "microsoft")
+}
+
+// linuxInitSystem identifies the init system from the runtime directories
+// systemd and OpenRC create, and otherwise from the name of process 1.
+func (d *Detector) linuxInitSystem() string {
+	switch {
+	case d.exists("/run/systemd/system"):
+		return InitSystemd
+	case d.exists("/run/openrc"):
+		return InitOpenRC
+	}
+
+	switch strings.TrimSpace(d.readFile("/proc/1/comm")) {
+	case "systemd":
+		return InitSystemd
+	case "openrc-init":
+		return InitOpenRC
+	case "runit", "runit-init":
+		return InitRunit
+	case "init":
+		return InitSysVinit
+	default:
+		return InitUnknown
+	}
+}
@@ -0,0 +1,196 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package platform detects facts about the host the provider runs on: its
+// operating system and distribution, CPU architecture, whether it runs in a
+// container or under WSL, and its init system.
+package platform
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Distribution families, following the distribution a derivative names first
+// in ID_LIKE.
+const (
+	FamilyDebian  = "debian"
+	FamilyRHEL    = "rhel"
+	FamilyAlpine  = "alpine"
+	FamilyArch    = "arch"
+	FamilySUSE    = "suse"
+	FamilyDarwin  = "darwin"
+	FamilyWindows = "windows"
+)
+
+// Init systems reported in Platform.InitSystem.
+const (
+	InitSystemd  = "systemd"
+	InitOpenRC   = "openrc"
+	InitLaunchd  = "launchd"
+	InitRunit    = "runit"
+	InitSysVinit = "sysvinit"
+	InitUnknown  = "unknown"
+)
+
+// familyIDs maps os-release IDs to their family.
+var familyIDs = map[string]string{
+	"debian":   FamilyDebian,
+	"ubuntu":   FamilyDebian,
+	"rhel":     FamilyRHEL,
+	"centos":   FamilyRHEL,
+	"fedora":   FamilyRHEL,
+	"alpine":   FamilyAlpine,
+	"arch":     FamilyArch,
+	"suse":     FamilySUSE,
+	"opensuse": FamilySUSE,
+	"sles":     FamilySUSE,
+}
+
+// macOSNames maps macOS major versions to their marketing names.
+var macOSNames = map[string]string{
+	"11": "Big Sur",
+	"12": "Monterey",
+	"13": "Ventura",
+	"14": "Sonoma",
+	"15": "Sequoia",
+	"26": "Tahoe",
+}
+
+// Platform describes the host the provider runs on.
+type Platform struct {
+	// OS is the operating system as named by Go, e.g. "linux" or "darwin"
+	OS string
+	// Arch is the CPU architecture as named by Go, e.g. "amd64" or "arm64". It
+	// is the machine's architecture, even when the provider runs under
+	// emulation such as Rosetta 2
+	Arch string
+	// Family is the distribution family, e.g. "debian" for Ubuntu, or the
+	// distribution ID when the family is not known. Empty when the
+	// distribution cannot be identified
+	Family string
+	// Distribution is the os-release ID, e.g. "ubuntu", or "macos" and
+	// "windows" for those systems
+	Distribution string
+	// DistributionName is the human-readable name, e.g. "Ubuntu 22.04.4 LTS"
+	DistributionName string
+	// Version is the release number, e.g. "22.04" or "14.5", and empty for
+	// rolling releases
+	Version string
+	// Codename is the release codename, e.g. "jammy" or "Sonoma"
+	Codename string
+	// ContainerRuntime names the container runtime the provider runs under,
+	// e.g. "docker", "podman" or "kubernetes", and is empty outside containers
+	ContainerRuntime string
+	// WSL reports whether the provider runs under the Windows Subsystem for Linux
+	WSL bool
+	// InitSystem is one of the Init* constants
+	InitSystem string
+}
+
+// Container reports whether the provider runs inside a container.
+func (p *Platform) Container() bool {
+	return p.ContainerRuntime != ""
+}
+
+// DefaultManager returns the package manager the provider uses on the
+// platform, or "" if it supports none. Linux systems whose distribution
+// cannot be identified are assumed to use APT.
+func (p *Platform) DefaultManager() string {
+	switch p.OS {
+	case "darwin":
+		return "brew"
+	case "linux":
+		if p.Family == FamilyDebian || p.Family == "" {
+			return "apt"
+		}
+	}
+	return ""
+}
+
+// String describes the platform, e.g. "linux (fedora 40)".
+func (p *Platform) String() string {
+	if p.Distribution == "" {
+		return p.OS
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s (%s %s)", p.OS, p.Distribution, p.Version))
+}
+
+// ParseOSRelease parses the KEY=value lines of an os-release file, removing
+// the quotes around values.
+func ParseOSRelease(content string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if found {
+			fields[key] = strings.Trim(value, `"'`)
+		}
+	}
+	return fields
+}
+
+// family returns the family of the distribution described by os-release
+// fields: the family of its ID or, for derivatives, of the first ID_LIKE
+// entry with a known family.
+func family(release map[string]string) string {
+	ids := append([]string{release["ID"]}, strings.Fields(release["ID_LIKE"])...)
+	for _, id := range ids {
+		if family, ok := familyIDs[id]; ok {
+			return family
+		}
+	}
+	return release["ID"]
+}
+
+// NormalizeArch converts a machine name reported by 'uname -m' to Go's
+// architecture name, e.g. "x86_64" to "amd64". Unknown names are returned
+// unchanged.
+func NormalizeArch(machine string) string {
+	switch machine {
+	case "x86_64", "amd64":
+		return "amd64"
+	case "aarch64", "arm64":
+		return "arm64"
+	case "i386", "i686", "386":
+		return "386"
+	case "armv6l", "armv7l", "arm":
+		return "arm"
+	default:
+		return machine
+	}
+}
+
+// parseSwVers parses the "Key: value" lines printed by macOS's sw_vers.
+func parseSwVers(output string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if found {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
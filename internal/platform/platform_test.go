@@ -0,0 +1,194 @@
+package platform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockExecutor for testing
+type MockExecutor struct {
+	mock.Mock
+}
+
+func (m *MockExecutor) Run(ctx context.Context, command string, args []string, opts executor.ExecOpts) (executor.ExecResult, error) {
+	ret := m.Called(ctx, command, args, opts)
+	return ret.Get(0).(executor.ExecResult), ret.Error(1)
+}
+
+// testDetector returns a detector for goos that reads files written below a
+// temporary root and sees only the given environment variables.
+func testDetector(t *testing.T, exec executor.Executor, goos string, files map[string]string,
+	env map[string]string) *Detector {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		if content == "/" {
+			require.NoError(t, os.MkdirAll(path, 0o755))
+			continue
+		}
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return &Detector{
+		executor: exec,
+		root:     root,
+		goos:     goos,
+		goarch:   "amd64",
+		getenv:   func(key string) string { return env[key] },
+	}
+}
+
+const ubuntuRelease = `PRETTY_NAME="Ubuntu 22.04.4 LTS"
+NAME="Ubuntu"
+VERSION_ID="22.04"
+VERSION_CODENAME=jammy
+ID=ubuntu
+ID_LIKE=debian
+`
+
+func TestParseOSRelease(t *testing.T) {
+	fields := ParseOSRelease("# comment\n" + ubuntuRelease + "\nNAME_ONLY\n")
+	assert.Equal(t, "ubuntu", fields["ID"])
+	assert.Equal(t, "Ubuntu 22.04.4 LTS", fields["PRETTY_NAME"])
+	assert.Equal(t, "22.04", fields["VERSION_ID"])
+	assert.NotContains(t, fields, "NAME_ONLY")
+}
+
+func TestFamily(t *testing.T) {
+	for want, release := range map[string]map[string]string{
+		FamilyDebian: {"ID": "linuxmint", "ID_LIKE": "ubuntu debian"},
+		FamilyRHEL:   {"ID": "rocky", "ID_LIKE": "rhel centos fedora"},
+		FamilySUSE:   {"ID": "opensuse-leap", "ID_LIKE": "suse opensuse"},
+		FamilyAlpine: {"ID": "alpine"},
+		"nixos":      {"ID": "nixos"},
+		"":           {},
+	} {
+		assert.Equal(t, want, family(release), release["ID"])
+	}
+}
+
+func TestNormalizeArch(t *testing.T) {
+	assert.Equal(t, "amd64", NormalizeArch("x86_64"))
+	assert.Equal(t, "arm64", NormalizeArch("aarch64"))
+	assert.Equal(t, "arm", NormalizeArch("armv7l"))
+	assert.Equal(t, "386", NormalizeArch("i686"))
+	assert.Equal(t, "riscv64", NormalizeArch("riscv64"))
+}
+
+func TestDefaultManager(t *testing.T) {
+	assert.Equal(t, "brew", (&Platform{OS: "darwin", Family: FamilyDarwin}).DefaultManager())
+	assert.Equal(t, "apt", (&Platform{OS: "linux", Family: FamilyDebian}).DefaultManager())
+	assert.Equal(t, "apt", (&Platform{OS: "linux"}).DefaultManager())
+	assert.Empty(t, (&Platform{OS: "linux", Family: FamilyRHEL}).DefaultManager())
+	assert.Empty(t, (&Platform{OS: "windows", Family: FamilyWindows}).DefaultManager())
+}
+
+func TestDetector_Identify(t *testing.T) {
+	detector := testDetector(t, nil, "linux", map[string]string{"/usr/lib/os-release": ubuntuRelease}, nil)
+
+	assert.Equal(t, &Platform{
+		OS:               "linux",
+		Arch:             "amd64",
+		Family:           FamilyDebian,
+		Distribution:     "ubuntu",
+		DistributionName: "Ubuntu 22.04.4 LTS",
+		Version:          "22.04",
+		Codename:         "jammy",
+		InitSystem:       InitUnknown,
+	}, detector.Identify())
+	assert.Equal(t, "linux (ubuntu 22.04)", detector.Identify().String())
+}
+
+func TestDetector_DetectLinux(t *testing.T) {
+	exec := &MockExecutor{}
+	exec.On("Run", mock.Anything, "uname", []string{"-m"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "aarch64\n"}, nil).Once()
+
+	detector := testDetector(t, exec, "linux", map[string]string{
+		"/etc/os-release":            ubuntuRelease,
+		"/.dockerenv":                "",
+		"/proc/sys/kernel/osrelease": "5.15.146.1-microsoft-standard-WSL2\n",
+		"/run/systemd/system":        "/",
+		"/proc/1/cgroup":             "0::/\n",
+		"/proc/1/comm":               "systemd\n",
+	}, nil)
+
+	p, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "arm64", p.Arch)
+	assert.Equal(t, "ubuntu", p.Distribution)
+	assert.Equal(t, "docker", p.ContainerRuntime)
+	assert.True(t, p.Container())
+	assert.True(t, p.WSL)
+	assert.Equal(t, InitSystemd, p.InitSystem)
+	exec.AssertExpectations(t)
+}
+
+func TestDetector_ContainerRuntime(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		env   map[string]string
+		want  string
+	}{
+		{name: "kubernetes", env: map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"}, want: "kubernetes"},
+		{name: "podman", files: map[string]string{"/run/.containerenv": ""}, want: "podman"},
+		{name: "nspawn", env: map[string]string{"container": "systemd-nspawn"}, want: "systemd-nspawn"},
+		{name: "cgroup v1", files: map[string]string{"/proc/1/cgroup": "12:pids:/kubepods/besteffort/pod1\n"},
+			want: "kubernetes"},
+		{name: "host", files: map[string]string{"/proc/1/cgroup": "0::/init.scope\n"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, testDetector(t, nil, "linux", tt.files, tt.env).containerRuntime())
+		})
+	}
+}
+
+func TestDetector_LinuxInitSystem(t *testing.T) {
+	for want, files := range map[string]map[string]string{
+		InitSystemd:  {"/proc/1/comm": "systemd\n"},
+		InitOpenRC:   {"/run/openrc": "/"},
+		InitRunit:    {"/proc/1/comm": "runit\n"},
+		InitSysVinit: {"/proc/1/comm": "init\n"},
+		InitUnknown:  {"/proc/1/comm": "tini\n"},
+	} {
+		assert.Equal(t, want, testDetector(t, nil, "linux", files, nil).linuxInitSystem())
+	}
+}
+
+func TestDetector_DetectDarwin(t *testing.T) {
+	exec := &MockExecutor{}
+	exec.On("Run", mock.Anything, "sw_vers", []string(nil), mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0,
+			Stdout: "ProductName:\t\tmacOS\nProductVersion:\t\t14.5\nBuildVersion:\t\t23F79\n"}, nil).Once()
+	exec.On("Run", mock.Anything, "sysctl", []string{"-n", "hw.optional.arm64"}, mock.Anything).
+		Return(executor.ExecResult{ExitCode: 0, Stdout: "1\n"}, nil).Once()
+
+	p, err := testDetector(t, exec, "darwin", nil, nil).Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &Platform{
+		OS:               "darwin",
+		Arch:             "arm64",
+		Family:           FamilyDarwin,
+		Distribution:     "macos",
+		DistributionName: "macOS 14.5",
+		Version:          "14.5",
+		Codename:         "Sonoma",
+		InitSystem:       InitLaunchd,
+	}, p)
+	exec.AssertExpectations(t)
+
+	failing := &MockExecutor{}
+	failing.On("Run", mock.Anything, "sw_vers", []string(nil), mock.Anything).
+		Return(executor.ExecResult{ExitCode: 1, Stderr: "boom"}, nil).Once()
+	_, err = testDetector(t, failing, "darwin", nil, nil).Detect(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,286 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
+)
+
+// defaultAuditLogLimit is how many entries are returned when limit is not set.
+const defaultAuditLogLimit = 50
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &AuditLogDataSource{}
+
+// NewAuditLogDataSource creates a new audit log data source.
+func NewAuditLogDataSource() datasource.DataSource {
+	return &AuditLogDataSource{}
+}
+
+// AuditLogDataSource defines the data source implementation.
+type AuditLogDataSource struct {
+	providerData *ProviderData
+}
+
+// AuditLogDataSourceModel describes the data source data model.
+type AuditLogDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Limit      types.Int64  `tfsdk:"limit"`
+	Path       types.String `tfsdk:"path"`
+	ChainValid types.Bool   `tfsdk:"chain_valid"`
+	ChainError types.String `tfsdk:"chain_error"`
+	Entries    types.List   `tfsdk:"entries"`
+}
+
+// AuditLogEntryModel represents a single audit log entry.
+type AuditLogEntryModel struct {
+	Timestamp          types.String `tfsdk:"timestamp"`
+	ResourceType       types.String `tfsdk:"resource_type"`
+	ResourceID         types.String `tfsdk:"resource_id"`
+	TerraformOperation types.String `tfsdk:"terraform_operation"`
+	Manager            types.String `tfsdk:"manager"`
+	Operation          types.String `tfsdk:"operation"`
+	Package            types.String `tfsdk:"package"`
+	VersionBefore      types.String `tfsdk:"version_before"`
+	VersionAfter       types.String `tfsdk:"version_after"`
+	Command            types.String `tfsdk:"command"`
+	ExitCode           types.Int64  `tfsdk:"exit_code"`
+	DurationMs         types.Int64  `tfsdk:"duration_ms"`
+	User               types.String `tfsdk:"user"`
+	Sudo               types.Bool   `tfsdk:"sudo"`
+	Error              types.String `tfsdk:"error"`
+	Hash               types.String `tfsdk:"hash"`
+}
+
+// auditLogEntryAttrTypes are the attribute types of an audit log entry object.
+var auditLogEntryAttrTypes = map[string]attr.Type{
+	"timestamp":           types.StringType,
+	"resource_type":       types.StringType,
+	"resource_id":         types.StringType,
+	"terraform_operation": types.StringType,
+	"manager":             types.StringType,
+	"operation":           types.StringType,
+	"package":             types.StringType,
+	"version_before":      types.StringType,
+	"version_after":       types.StringType,
+	"command":             types.StringType,
+	"exit_code":           types.Int64Type,
+	"duration_ms":         types.Int64Type,
+	"user":                types.StringType,
+	"sudo":                types.BoolType,
+	"error":               types.StringType,
+	"hash":                types.StringType,
+}
+
+// Metadata returns the data source type name.
+func (d *AuditLogDataSource) Metadata(
+	_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_audit_log"
+}
+
+// Schema defines the data source schema.
+func (d *AuditLogDataSource) Schema(
+	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	entryString := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{MarkdownDescription: description, Computed: true}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads recent entries from the provider's `audit_log` and verifies its hash chain. " +
+			"Requires `audit_log` to be set in the provider configuration.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Data source identifier.",
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of most recent entries to return. " +
+					"Defaults to 50.",
+				Optional: true,
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Path of the audit log file.",
+				Computed:            true,
+			},
+			"chain_valid": schema.BoolAttribute{
+				MarkdownDescription: "Whether every entry in the log, not only those returned, is intact and correctly chained.",
+				Computed:            true,
+			},
+			"chain_error": schema.StringAttribute{
+				MarkdownDescription: "Where and why the hash chain is broken. Empty when `chain_valid` is true.",
+				Computed:            true,
+			},
+			"entries": schema.ListNestedAttribute{
+				MarkdownDescription: "Most recent audit log entries, oldest first.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"timestamp":           entryString("When the command started, in RFC 3339 format."),
+						"resource_type":       entryString("Type of the Terraform resource that ran the command."),
+						"resource_id":         entryString("ID of the Terraform resource that ran the command."),
+						"terraform_operation": entryString("Terraform operation being applied: create, update or delete."),
+						"manager":             entryString("Package manager or service manager used."),
+						"operation":           entryString("Operation performed (e.g., install, remove, hold, tap)."),
+						"package":             entryString("Package or packages affected."),
+						"version_before":      entryString("Installed version before the change, if known."),
+						"version_after":       entryString("Installed version after the command ran. Empty if the package is not installed."),
+						"command":             entryString("Command line, with secrets redacted."),
+						"exit_code": schema.Int64Attribute{
+							MarkdownDescription: "Exit code of the command. -1 if it was interrupted.",
+							Computed:            true,
+						},
+						"duration_ms": schema.Int64Attribute{
+							MarkdownDescription: "How long the command ran, in milliseconds.",
+							Computed:            true,
+						},
+						"user": entryString("User the provider ran as."),
+						"sudo": schema.BoolAttribute{
+							MarkdownDescription: "Whether the command was run with sudo.",
+							Computed:            true,
+						},
+						"error": entryString("Error reported for the command, if it failed."),
+						"hash":  entryString("SHA-256 hash of the entry, chained to the previous entry."),
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure configures the data source with provider data.
+func (d *AuditLogDataSource) Configure(
+	_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+// Read returns the most recent audit log entries.
+func (d *AuditLogDataSource) Read(
+	ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data AuditLogDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.providerData == nil || d.providerData.AuditLog == nil {
+		resp.Diagnostics.AddError(
+			"Audit Log Not Configured",
+			"Set audit_log in the provider configuration to record and read audit entries.",
+		)
+		return
+	}
+
+	limit := int64(defaultAuditLogLimit)
+	if !data.Limit.IsNull() {
+		limit = data.Limit.ValueInt64()
+	}
+	if limit < 1 {
+		resp.Diagnostics.AddError("Invalid limit", fmt.Sprintf("limit must be at least 1, got: %d", limit))
+		return
+	}
+
+	logPath := d.providerData.AuditLog.Path()
+	records, err := audit.ReadRecent(logPath, int(limit))
+	var chainErr *audit.ChainError
+	if err != nil && !errors.As(err, &chainErr) {
+		resp.Diagnostics.AddError(
+			"Failed to Read Audit Log",
+			fmt.Sprintf("Failed to read audit log: %v", err),
+		)
+		return
+	}
+
+	data.ID = types.StringValue(logPath)
+	data.Path = types.StringValue(logPath)
+	data.Limit = types.Int64Value(limit)
+	data.ChainValid = types.BoolValue(chainErr == nil)
+	data.ChainError = types.StringValue("")
+	if chainErr != nil {
+		data.ChainError = types.StringValue(chainErr.Error())
+	}
+
+	entries := make([]AuditLogEntryModel, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, auditLogEntryFromRecord(record))
+	}
+
+	entriesList, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: auditLogEntryAttrTypes}, entries)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Entries = entriesList
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// auditLogEntryFromRecord converts an audit record to its Terraform model.
+func auditLogEntryFromRecord(record audit.Record) AuditLogEntryModel {
+	return AuditLogEntryModel{
+		Timestamp:          types.StringValue(record.Timestamp.Format(time.RFC3339)),
+		ResourceType:       types.StringValue(record.ResourceType),
+		ResourceID:         types.StringValue(record.ResourceID),
+		TerraformOperation: types.StringValue(record.TerraformOperation),
+		Manager:            types.StringValue(record.Manager),
+		Operation:          types.StringValue(record.Operation),
+		Package:            types.StringValue(record.Package),
+		VersionBefore:      types.StringValue(record.VersionBefore),
+		VersionAfter:       types.StringValue(record.VersionAfter),
+		Command:            types.StringValue(record.Command),
+		ExitCode:           types.Int64Value(int64(record.ExitCode)),
+		DurationMs:         types.Int64Value(record.DurationMs),
+		User:               types.StringValue(record.User),
+		Sudo:               types.BoolValue(record.Sudo),
+		Error:              types.StringValue(record.Error),
+		Hash:               types.StringValue(record.Hash),
+	}
+}
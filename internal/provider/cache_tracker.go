@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
+)
+
+// Cache update policies accepted by the provider's update_cache setting.
+const (
+	cachePolicyNever    = "never"
+	cachePolicyOnChange = "on_change"
+	cachePolicyAlways   = "always"
+)
+
+// CacheTracker decides when a package manager's cache should be refreshed and
+// ensures each manager's cache is refreshed at most once per provider process,
+// no matter how many resources ask for it concurrently.
+type CacheTracker struct {
+	policy   string
+	validFor time.Duration
+
+	mu       sync.Mutex
+	managers map[string]*managerCacheState
+}
+
+// managerCacheState tracks the refresh state of a single package manager.
+type managerCacheState struct {
+	mu        sync.Mutex
+	attempted bool
+}
+
+// NewCacheTracker creates a CacheTracker for the given update_cache policy.
+// validFor is the cache_valid_time; caches younger than this are not refreshed
+// under the on_change policy.
+func NewCacheTracker(policy string, validFor time.Duration) *CacheTracker {
+	return &CacheTracker{
+		policy:   policy,
+		validFor: validFor,
+		managers: make(map[string]*managerCacheState),
+	}
+}
+
+// EnsureFresh refreshes the manager's cache if the policy requires it.
+// changing reports whether the caller is about to install or upgrade packages.
+//
+//   - never: the cache is never refreshed.
+//   - on_change: refreshed once before the first change, unless the cache is
+//     younger than cache_valid_time.
+//   - always: refreshed once on first use of the manager, whether or not a
+//     change follows, regardless of cache age.
+//
+// Only the first caller for a manager performs the refresh; concurrent callers
+// wait for it to complete. A failed refresh is not retried within the same run.
+func (t *CacheTracker) EnsureFresh(ctx context.Context, manager adapters.PackageManager, changing bool) error {
+	if t == nil || t.policy == cachePolicyNever {
+		return nil
+	}
+	if t.policy == cachePolicyOnChange && !changing {
+		return nil
+	}
+
+	state := t.stateFor(manager.GetManagerName())
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.attempted {
+		return nil
+	}
+	state.attempted = true
+
+	if t.policy == cachePolicyOnChange && t.validFor > 0 {
+		if ager, ok := manager.(adapters.CacheAger); ok {
+			lastUpdated, err := ager.CacheLastUpdated(ctx)
+			if err == nil && time.Since(lastUpdated) < t.validFor {
+				tflog.Debug(ctx, "Package cache is fresh, skipping update", map[string]interface{}{
+					"manager":          manager.GetManagerName(),
+					"last_updated":     lastUpdated.Format(time.RFC3339),
+					"cache_valid_time": t.validFor.String(),
+				})
+				return nil
+			}
+		}
+	}
+
+	tflog.Debug(ctx, "Updating package cache", map[string]interface{}{
+		"manager": manager.GetManagerName(),
+		"policy":  t.policy,
+	})
+
+	// The update serves every resource of the manager, not the one that asked
+	return manager.UpdateCache(audit.WithoutPackage(ctx))
+}
+
+// stateFor returns the refresh state for a manager, creating it if needed.
+func (t *CacheTracker) stateFor(managerName string) *managerCacheState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.managers[managerName]
+	if !ok {
+		state = &managerCacheState{}
+		t.managers[managerName] = state
+	}
+	return state
+}
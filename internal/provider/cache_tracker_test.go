@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheTracker_Policies(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		changing      bool
+		expectedCalls int32
+	}{
+		{name: "never_skips_changes", policy: cachePolicyNever, changing: true, expectedCalls: 0},
+		{name: "on_change_skips_reads", policy: cachePolicyOnChange, changing: false, expectedCalls: 0},
+		{name: "on_change_updates_before_change", policy: cachePolicyOnChange, changing: true, expectedCalls: 1},
+		{name: "always_updates_on_read", policy: cachePolicyAlways, changing: false, expectedCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newFakePackageManager(nil)
+			tracker := NewCacheTracker(tt.policy, 0)
+
+			assert.NoError(t, tracker.EnsureFresh(context.Background(), manager, tt.changing))
+			assert.Equal(t, tt.expectedCalls, manager.updateCalls.Load())
+		})
+	}
+}
+
+func TestCacheTracker_UpdatesOncePerRun(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	tracker := NewCacheTracker(cachePolicyOnChange, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = tracker.EnsureFresh(context.Background(), manager, true)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), manager.updateCalls.Load())
+}
+
+func TestCacheTracker_TracksManagersIndependently(t *testing.T) {
+	apt := newFakePackageManager(nil)
+	apt.name = "apt"
+	brew := newFakePackageManager(nil)
+	brew.name = "brew"
+	tracker := NewCacheTracker(cachePolicyAlways, 0)
+
+	assert.NoError(t, tracker.EnsureFresh(context.Background(), apt, false))
+	assert.NoError(t, tracker.EnsureFresh(context.Background(), brew, false))
+	assert.NoError(t, tracker.EnsureFresh(context.Background(), apt, true))
+
+	assert.Equal(t, int32(1), apt.updateCalls.Load())
+	assert.Equal(t, int32(1), brew.updateCalls.Load())
+}
+
+func TestCacheTracker_CacheValidTime(t *testing.T) {
+	fresh := newFakePackageManager(nil)
+	fresh.cacheUpdatedAt = time.Now().Add(-10 * time.Minute)
+	stale := newFakePackageManager(nil)
+	stale.cacheUpdatedAt = time.Now().Add(-2 * time.Hour)
+	unknown := newFakePackageManager(nil)
+
+	for _, manager := range []*fakePackageManager{fresh, stale, unknown} {
+		tracker := NewCacheTracker(cachePolicyOnChange, time.Hour)
+		assert.NoError(t, tracker.EnsureFresh(context.Background(), manager, true))
+	}
+
+	assert.Equal(t, int32(0), fresh.updateCalls.Load(), "cache younger than cache_valid_time should not be refreshed")
+	assert.Equal(t, int32(1), stale.updateCalls.Load())
+	assert.Equal(t, int32(1), unknown.updateCalls.Load(), "unknown cache age should be treated as stale")
+}
+
+func TestCacheTracker_AlwaysIgnoresCacheValidTime(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	manager.cacheUpdatedAt = time.Now()
+	tracker := NewCacheTracker(cachePolicyAlways, time.Hour)
+
+	assert.NoError(t, tracker.EnsureFresh(context.Background(), manager, true))
+	assert.Equal(t, int32(1), manager.updateCalls.Load())
+}
+
+func TestCacheTracker_FailedUpdateNotRetried(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	manager.updateErr = fmt.Errorf("network unreachable")
+	tracker := NewCacheTracker(cachePolicyOnChange, 0)
+
+	assert.Error(t, tracker.EnsureFresh(context.Background(), manager, true))
+	assert.NoError(t, tracker.EnsureFresh(context.Background(), manager, true))
+	assert.Equal(t, int32(1), manager.updateCalls.Load())
+}
+
+func TestCacheTracker_NilTrackerIsNoop(t *testing.T) {
+	var tracker *CacheTracker
+	manager := newFakePackageManager(nil)
+
+	assert.NoError(t, tracker.EnsureFresh(context.Background(), manager, true))
+	assert.Equal(t, int32(0), manager.updateCalls.Load())
+}
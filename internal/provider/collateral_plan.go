@@ -0,0 +1,196 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// planCollateral previews the other packages the planned change will install,
+// upgrade or remove, and sets plan.CollateralInstalls and plan.CollateralRemovals.
+// It must run after planVersion, which marks version_actual unknown when the
+// package itself changes. The lists are left unknown if the simulation fails.
+func (r *PackageResource) planCollateral(ctx context.Context, manager adapters.PackageManager, packageName string,
+	plan, prior *PackageResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Without a package change nothing else changes either
+	if prior != nil && !plan.VersionActual.IsUnknown() {
+		plan.CollateralInstalls = emptyStringList()
+		plan.CollateralRemovals = emptyStringList()
+		return diags
+	}
+
+	simulator, ok := manager.(adapters.Simulator)
+	if !ok {
+		plan.CollateralInstalls = emptyStringList()
+		plan.CollateralRemovals = emptyStringList()
+		return diags
+	}
+
+	var simulation *adapters.Simulation
+	var err error
+	if plan.State.ValueString() == stateAbsent {
+		simulation, err = simulator.SimulateRemove(ctx, packageName)
+	} else {
+		version := ""
+		if !plan.Version.IsNull() && !plan.VersionTarget.IsUnknown() {
+			version = plan.VersionTarget.ValueString()
+		}
+		simulation, err = simulator.SimulateInstall(ctx, packageName, version)
+	}
+	if err != nil {
+		tflog.Warn(ctx, "Failed to simulate package change during plan", map[string]interface{}{
+			"package_name": packageName,
+			"error":        err.Error(),
+		})
+		plan.CollateralInstalls = types.ListUnknown(types.StringType)
+		plan.CollateralRemovals = types.ListUnknown(types.StringType)
+		return diags
+	}
+
+	installs := collateralPackages(simulation.Installs, packageName)
+	removals := collateralPackages(simulation.Removals, packageName)
+	plan.CollateralInstalls = stringListValue(installs)
+	plan.CollateralRemovals = stringListValue(removals)
+
+	if !plan.AllowCollateralRemoval.IsUnknown() && !plan.AllowCollateralRemoval.ValueBool() {
+		if manual := manualRemovals(ctx, manager, simulation.Removals, packageName); len(manual) > 0 {
+			diags.AddAttributeError(
+				tfpath.Root("allow_collateral_removal"),
+				"Collateral Package Removal",
+				fmt.Sprintf("Changing %s would also remove manually installed packages: %s. "+
+					"Resolve the conflict, or set allow_collateral_removal = true to accept the removal.",
+					packageName, strings.Join(manual, ", ")),
+			)
+			return diags
+		}
+	}
+	if len(installs) > 0 || len(removals) > 0 {
+		diags.AddAttributeWarning(
+			tfpath.Root("name"),
+			"Collateral Package Changes",
+			collateralSummary(packageName, installs, removals),
+		)
+	}
+
+	return diags
+}
+
+// collateralPackages formats the simulated changes to packages other than
+// packageName as "name=version".
+func collateralPackages(changes []adapters.SimulatedPackage, packageName string) []string {
+	packages := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.Name == packageName || strings.HasPrefix(change.Name, packageName+":") {
+			continue
+		}
+		if change.Version == "" {
+			packages = append(packages, change.Name)
+			continue
+		}
+		packages = append(packages, change.Name+"="+change.Version)
+	}
+	return packages
+}
+
+// manualRemovals returns the collateral removals, formatted like
+// collateralPackages, of packages that were installed explicitly rather than
+// only as dependencies of others. It relies on the package database alone, so
+// the outcome does not depend on the order in which resources are planned.
+// Every removal counts as manual if the manager cannot tell them apart.
+func manualRemovals(ctx context.Context, manager adapters.PackageManager, removals []adapters.SimulatedPackage,
+	packageName string) []string {
+	var manual map[string]bool
+	if lister, ok := manager.(adapters.ManualInstallLister); ok {
+		names, err := lister.ListManuallyInstalled(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "Could not list manually installed packages, treating every removal as manual",
+				map[string]interface{}{"error": err.Error()})
+		} else {
+			manual = make(map[string]bool, len(names))
+			for _, name := range names {
+				manual[packageBaseName(name)] = true
+			}
+		}
+	}
+
+	explicit := make([]adapters.SimulatedPackage, 0, len(removals))
+	for _, removal := range removals {
+		if manual == nil || manual[packageBaseName(removal.Name)] {
+			explicit = append(explicit, removal)
+		}
+	}
+	return collateralPackages(explicit, packageName)
+}
+
+// packageBaseName strips an architecture qualifier such as ":amd64".
+func packageBaseName(name string) string {
+	name, _, _ = strings.Cut(name, ":")
+	return name
+}
+
+func collateralSummary(packageName string, installs, removals []string) string {
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "Changing %s will also change other packages.", packageName)
+	if len(installs) > 0 {
+		fmt.Fprintf(&summary, "\n\nInstalled or upgraded: %s", strings.Join(installs, ", "))
+	}
+	if len(removals) > 0 {
+		fmt.Fprintf(&summary, "\n\nRemoved: %s", strings.Join(removals, ", "))
+	}
+	return summary.String()
+}
+
+// settleCollateral replaces unknown or missing collateral previews with empty
+// lists, as state cannot hold unknown values.
+func settleCollateral(data *PackageResourceModel) {
+	if data.CollateralInstalls.IsUnknown() || data.CollateralInstalls.IsNull() {
+		data.CollateralInstalls = emptyStringList()
+	}
+	if data.CollateralRemovals.IsUnknown() || data.CollateralRemovals.IsNull() {
+		data.CollateralRemovals = emptyStringList()
+	}
+}
+
+func emptyStringList() types.List {
+	return types.ListValueMust(types.StringType, []attr.Value{})
+}
+
+func stringListValue(values []string) types.List {
+	elements := make([]attr.Value, len(values))
+	for i, value := range values {
+		elements[i] = types.StringValue(value)
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func collateralPlan(state string) *PackageResourceModel {
+	plan := packagePlan(state, "")
+	plan.Name = types.StringValue("nginx")
+	plan.AllowCollateralRemoval = types.BoolValue(true)
+	plan.CollateralInstalls = types.ListUnknown(types.StringType)
+	plan.CollateralRemovals = types.ListUnknown(types.StringType)
+	return plan
+}
+
+func nginxManager() *fakePackageManager {
+	manager := newFakePackageManager(map[string]string{"apache2": "2.4.52"})
+	manager.simulations = map[string]*adapters.Simulation{
+		"install nginx": {
+			Installs: []adapters.SimulatedPackage{
+				{Name: "nginx-common", Version: "1.18.0"},
+				{Name: "nginx", Version: "1.18.0"},
+			},
+			Removals: []adapters.SimulatedPackage{{Name: "apache2", Version: "2.4.52"}},
+		},
+		"remove nginx": {
+			Removals: []adapters.SimulatedPackage{{Name: "nginx", Version: "1.18.0"}},
+		},
+	}
+	return manager
+}
+
+func TestPackageResource_PlanCollateral_Install(t *testing.T) {
+	plan := collateralPlan(statePresent)
+
+	diags := (&PackageResource{}).planCollateral(context.Background(), nginxManager(), "nginx", plan, nil)
+
+	assert.False(t, diags.HasError())
+	require.Len(t, diags.Warnings(), 1)
+	assert.Equal(t, "Collateral Package Changes", diags.Warnings()[0].Summary())
+	assert.Contains(t, diags.Warnings()[0].Detail(), "Removed: apache2=2.4.52")
+	assert.Equal(t, stringListValue([]string{"nginx-common=1.18.0"}), plan.CollateralInstalls)
+	assert.Equal(t, stringListValue([]string{"apache2=2.4.52"}), plan.CollateralRemovals)
+}
+
+func TestPackageResource_PlanCollateral_RemovalGuard(t *testing.T) {
+	plan := collateralPlan(statePresent)
+	plan.AllowCollateralRemoval = types.BoolValue(false)
+	manager := nginxManager()
+	manager.manual = []string{"apache2:amd64"}
+
+	diags := (&PackageResource{}).planCollateral(context.Background(), manager, "nginx", plan, nil)
+
+	require.True(t, diags.HasError(), "removing a manually installed package must fail the plan")
+	assert.Equal(t, "Collateral Package Removal", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "apache2=2.4.52")
+}
+
+func TestPackageResource_PlanCollateral_RemoveOnlyPackage(t *testing.T) {
+	plan := collateralPlan(stateAbsent)
+	plan.AllowCollateralRemoval = types.BoolValue(false)
+
+	diags := (&PackageResource{}).planCollateral(context.Background(), nginxManager(), "nginx", plan, nil)
+
+	assert.Empty(t, diags)
+	assert.Equal(t, emptyStringList(), plan.CollateralInstalls)
+	assert.Equal(t, emptyStringList(), plan.CollateralRemovals)
+}
+
+func TestPackageResource_PlanCollateral_DependencyRemovalAllowed(t *testing.T) {
+	plan := collateralPlan(statePresent)
+	plan.AllowCollateralRemoval = types.BoolValue(false)
+	manager := nginxManager()
+	manager.manual = []string{"curl"}
+
+	diags := (&PackageResource{}).planCollateral(context.Background(), manager, "nginx", plan, nil)
+
+	assert.False(t, diags.HasError(), "removing a package installed only as a dependency should not fail the plan")
+	require.Len(t, diags.Warnings(), 1)
+	assert.Equal(t, "Collateral Package Changes", diags.Warnings()[0].Summary())
+	assert.Equal(t, stringListValue([]string{"apache2=2.4.52"}), plan.CollateralRemovals)
+}
+
+func TestPackageResource_PlanCollateral_NoChangeClearsPreview(t *testing.T) {
+	plan := collateralPlan(statePresent)
+	prior := priorState(plan, "1.18.0")
+	prior.CollateralInstalls = stringListValue([]string{"nginx-common=1.18.0"})
+	prior.CollateralRemovals = emptyStringList()
+	plan.VersionActual = prior.VersionActual
+
+	manager := nginxManager()
+	manager.simulateErr = errors.New("must not simulate")
+	diags := (&PackageResource{}).planCollateral(context.Background(), manager, "nginx", plan, prior)
+
+	assert.Empty(t, diags)
+	assert.Equal(t, emptyStringList(), plan.CollateralInstalls)
+	assert.Equal(t, emptyStringList(), plan.CollateralRemovals)
+}
+
+func TestPackageResource_PlanCollateral_SimulationFails(t *testing.T) {
+	plan := collateralPlan(statePresent)
+	manager := nginxManager()
+	manager.simulateErr = errors.New("apt-get failed")
+
+	diags := (&PackageResource{}).planCollateral(context.Background(), manager, "nginx", plan, nil)
+
+	assert.Empty(t, diags)
+	assert.True(t, plan.CollateralInstalls.IsUnknown())
+	assert.True(t, plan.CollateralRemovals.IsUnknown())
+}
@@ -24,23 +24,28 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"runtime"
-	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 const (
 	managerAuto    = "auto"
 	managerBrew    = "brew"
+	managerApt     = "apt"
 	platformDarwin = "darwin"
+
+	// dependencyTypeAll selects dependencies of every type
+	dependencyTypeAll = "all"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -62,15 +67,37 @@ type DependenciesDataSourceModel struct {
 	Name         types.String `tfsdk:"name"`
 	Manager      types.String `tfsdk:"manager"`
 	Type         types.String `tfsdk:"type"`
+	Direction    types.String `tfsdk:"direction"`
+	Recursive    types.Bool   `tfsdk:"recursive"`
+	MaxDepth     types.Int64  `tfsdk:"max_depth"`
 	Dependencies types.List   `tfsdk:"dependencies"`
+	Nodes        types.List   `tfsdk:"nodes"`
+	Edges        types.List   `tfsdk:"edges"`
 }
 
 // DependencyInfo represents information about a package dependency.
 type DependencyInfo struct {
-	Name     types.String `tfsdk:"name"`
+	Name         types.String `tfsdk:"name"`
+	Version      types.String `tfsdk:"version"`
+	Type         types.String `tfsdk:"type"`
+	Optional     types.Bool   `tfsdk:"optional"`
+	Relation     types.String `tfsdk:"relation"`
+	Alternatives types.List   `tfsdk:"alternatives"`
+}
+
+// DependencyNodeInfo represents a package in the dependency graph.
+type DependencyNodeInfo struct {
+	Name  types.String `tfsdk:"name"`
+	Depth types.Int64  `tfsdk:"depth"`
+}
+
+// DependencyEdgeInfo represents a relationship in the dependency graph.
+type DependencyEdgeInfo struct {
+	From     types.String `tfsdk:"from"`
+	To       types.String `tfsdk:"to"`
 	Version  types.String `tfsdk:"version"`
 	Type     types.String `tfsdk:"type"`
-	Optional types.Bool   `tfsdk:"optional"`
+	Relation types.String `tfsdk:"relation"`
 }
 
 // Metadata returns the data source type name.
@@ -98,19 +125,51 @@ func (d *DependenciesDataSource) Schema(
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to query. " +
-					"Valid values: 'auto', 'brew'. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
 					"Defaults to 'auto'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
 			},
 			"type": schema.StringAttribute{
 				MarkdownDescription: "Type of dependencies to retrieve. " +
 					"Valid values: 'runtime', 'build', " +
 					"'optional', 'all'. Defaults to 'runtime'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(dependencyTypeAll, string(adapters.DependencyRuntime),
+						string(adapters.DependencyBuild), string(adapters.DependencyOptional)),
+				},
+			},
+			"direction": schema.StringAttribute{
+				MarkdownDescription: "Direction to follow relationships. " +
+					"'forward' lists what the package depends on, 'reverse' lists the installed packages " +
+					"that depend on it. Defaults to 'forward'.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(directionForward, directionReverse),
+				},
+			},
+			"recursive": schema.BoolAttribute{
+				MarkdownDescription: "Whether to follow relationships transitively, reporting the graph " +
+					"in `nodes` and `edges` down to `max_depth`. Defaults to false.",
+				Optional: true,
+			},
+			"max_depth": schema.Int64Attribute{
+				MarkdownDescription: "How many levels of relationships a recursive walk follows from the package; " +
+					"packages at this depth are reported but not expanded. Only used when `recursive` is true. " +
+					fmt.Sprintf("Defaults to %d.", defaultDependencyMaxDepth),
+				Optional: true,
+				Computed: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
 			"dependencies": schema.ListNestedAttribute{
-				MarkdownDescription: "List of package dependencies.",
-				Computed:            true,
+				MarkdownDescription: "List of direct package dependencies, or of direct dependents " +
+					"when `direction` is 'reverse'.",
+				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"name": schema.StringAttribute{
@@ -129,6 +188,62 @@ func (d *DependenciesDataSource) Schema(
 							MarkdownDescription: "Whether this dependency is optional.",
 							Computed:            true,
 						},
+						"relation": schema.StringAttribute{
+							MarkdownDescription: "Relationship as named by the package manager, " +
+								"e.g. 'Depends', 'Pre-Depends', 'Recommends' or 'Suggests' for APT " +
+								"and 'depends_on :build' for Homebrew.",
+							Computed: true,
+						},
+						"alternatives": schema.ListAttribute{
+							ElementType: types.StringType,
+							MarkdownDescription: "Other packages that satisfy the same relationship, " +
+								"e.g. 'wget' for 'curl | wget'.",
+							Computed: true,
+						},
+					},
+				},
+			},
+			"nodes": schema.ListNestedAttribute{
+				MarkdownDescription: "Packages in the dependency graph, starting with the queried package.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Package name.",
+							Computed:            true,
+						},
+						"depth": schema.Int64Attribute{
+							MarkdownDescription: "Number of relationships between the queried package and this one.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"edges": schema.ListNestedAttribute{
+				MarkdownDescription: "Relationships in the dependency graph. Each edge means `from` depends on `to`.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"from": schema.StringAttribute{
+							MarkdownDescription: "Dependent package name.",
+							Computed:            true,
+						},
+						"to": schema.StringAttribute{
+							MarkdownDescription: "Dependency package name.",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "Required version or version constraint.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Dependency type (runtime, build, optional).",
+							Computed:            true,
+						},
+						"relation": schema.StringAttribute{
+							MarkdownDescription: "Relationship as named by the package manager.",
+							Computed:            true,
+						},
 					},
 				},
 			},
@@ -174,36 +289,35 @@ func (d *DependenciesDataSource) Read(
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	if managerName == managerAuto {
-		if runtime.GOOS != platformDarwin {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		managerName = managerBrew
-	}
-
-	// Only support brew in Phase 2
-	if managerName != managerBrew {
-		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", managerName),
-		)
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
 		return
 	}
 
 	// Get dependency type
-	depType := "runtime"
+	depType := string(adapters.DependencyRuntime)
 	if !data.Type.IsNull() {
 		depType = data.Type.ValueString()
 	}
 
+	direction := directionForward
+	if !data.Direction.IsNull() {
+		direction = data.Direction.ValueString()
+	}
+	recursive := data.Recursive.ValueBool()
+	maxDepth := int64(defaultDependencyMaxDepth)
+	if !data.MaxDepth.IsNull() && !data.MaxDepth.IsUnknown() {
+		maxDepth = data.MaxDepth.ValueInt64()
+	}
+	walkDepth := 1
+	if recursive {
+		walkDepth = int(maxDepth)
+	}
+
 	// Get dependencies
 	packageName := data.Name.ValueString()
-	dependencies, err := d.getBrewDependencies(ctx, packageName, depType)
+	graph, err := d.getDependencyGraph(ctx, managerName, packageName, depType, direction, walkDepth)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to Get Dependencies",
@@ -213,101 +327,125 @@ func (d *DependenciesDataSource) Read(
 	}
 
 	// Set computed values
-	data.ID = types.StringValue(fmt.Sprintf("%s:deps:%s:%s", managerName, packageName, depType))
+	idKind := "deps"
+	if direction == directionReverse {
+		idKind = "rdeps"
+	}
+	id := fmt.Sprintf("%s:%s:%s:%s", managerName, idKind, packageName, depType)
+	if recursive {
+		id += ":recursive"
+	}
+	data.ID = types.StringValue(id)
 	data.Manager = types.StringValue(managerName)
 	data.Type = types.StringValue(depType)
+	data.Direction = types.StringValue(direction)
+	data.Recursive = types.BoolValue(recursive)
+	data.MaxDepth = types.Int64Value(maxDepth)
 
-	// Convert dependencies to list
-	depsList, diags := types.ListValueFrom(ctx, types.ObjectType{
+	// Convert the graph to lists
+	var diags diag.Diagnostics
+	data.Dependencies, diags = types.ListValueFrom(ctx, types.ObjectType{
 		AttrTypes: map[string]attr.Type{
-			"name":     types.StringType,
+			"name":         types.StringType,
+			"version":      types.StringType,
+			"type":         types.StringType,
+			"optional":     types.BoolType,
+			"relation":     types.StringType,
+			"alternatives": types.ListType{ElemType: types.StringType},
+		},
+	}, dependencyInfos(graph.Direct))
+	resp.Diagnostics.Append(diags...)
+
+	data.Nodes, diags = types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"name":  types.StringType,
+			"depth": types.Int64Type,
+		},
+	}, dependencyNodeInfos(graph.Nodes))
+	resp.Diagnostics.Append(diags...)
+
+	data.Edges, diags = types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"from":     types.StringType,
+			"to":       types.StringType,
 			"version":  types.StringType,
 			"type":     types.StringType,
-			"optional": types.BoolType,
+			"relation": types.StringType,
 		},
-	}, dependencies)
+	}, dependencyEdgeInfos(graph.Edges))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	data.Dependencies = depsList
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (d *DependenciesDataSource) getBrewDependencies(ctx context.Context, packageName, depType string) ([]DependencyInfo, error) {
-	brewPath := d.providerData.Config.BrewPath.ValueString()
-	if brewPath == "" {
-		brewPath = managerBrew
+func (d *DependenciesDataSource) getDependencyGraph(ctx context.Context,
+	managerName, packageName, depType, direction string, maxDepth int) (*dependencyGraph, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get detailed package information with dependencies
-	result, err := d.providerData.Executor.Run(ctx, brewPath, []string{"info", "--json", packageName}, executor.ExecOpts{
-		Timeout: 2 * time.Minute,
-	})
-
-	if err != nil || result.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to get package info: exit code %d, error: %w", result.ExitCode, err)
+	var lookup dependencyLookup
+	if direction == directionReverse {
+		resolver, ok := manager.(adapters.ReverseDependencyResolver)
+		if !ok {
+			return nil, fmt.Errorf("package manager %s cannot resolve reverse dependencies", manager.GetManagerName())
+		}
+		lookup = resolver.ReverseDependencies
+	} else {
+		resolver, ok := manager.(adapters.DependencyResolver)
+		if !ok {
+			return nil, fmt.Errorf("package manager %s cannot resolve dependencies", manager.GetManagerName())
+		}
+		lookup = resolver.Dependencies
 	}
 
-	// Parse JSON response
-	var infos []map[string]interface{}
-	if err := json.Unmarshal([]byte(result.Stdout), &infos); err != nil {
-		return nil, fmt.Errorf("failed to parse brew info JSON: %w", err)
-	}
+	return walkDependencies(ctx, lookup, packageName, depType, direction == directionReverse, maxDepth)
+}
 
-	if len(infos) == 0 {
-		return []DependencyInfo{}, nil
+// dependencyInfos converts dependencies to data source entries.
+func dependencyInfos(dependencies []adapters.Dependency) []DependencyInfo {
+	infos := make([]DependencyInfo, 0, len(dependencies))
+	for _, dep := range dependencies {
+		infos = append(infos, DependencyInfo{
+			Name:         types.StringValue(dep.Name),
+			Version:      types.StringValue(dep.Constraint),
+			Type:         types.StringValue(string(dep.Type)),
+			Optional:     types.BoolValue(dep.Type == adapters.DependencyOptional),
+			Relation:     types.StringValue(dep.Relation),
+			Alternatives: stringListValue(dep.Alternatives),
+		})
 	}
+	return infos
+}
 
-	info := infos[0]
-	var dependencies []DependencyInfo
-
-	// Extract dependencies based on type
-	switch depType {
-	case "runtime", "all":
-		if deps, ok := info["dependencies"].([]interface{}); ok {
-			for _, dep := range deps {
-				if depStr, ok := dep.(string); ok {
-					dependencies = append(dependencies, DependencyInfo{
-						Name:     types.StringValue(depStr),
-						Version:  types.StringValue(""), // Brew doesn't specify version constraints
-						Type:     types.StringValue("runtime"),
-						Optional: types.BoolValue(false),
-					})
-				}
-			}
-		}
-
-	case "build":
-		if deps, ok := info["build_dependencies"].([]interface{}); ok {
-			for _, dep := range deps {
-				if depStr, ok := dep.(string); ok {
-					dependencies = append(dependencies, DependencyInfo{
-						Name:     types.StringValue(depStr),
-						Version:  types.StringValue(""),
-						Type:     types.StringValue("build"),
-						Optional: types.BoolValue(false),
-					})
-				}
-			}
-		}
-
-	case "optional":
-		if deps, ok := info["optional_dependencies"].([]interface{}); ok {
-			for _, dep := range deps {
-				if depStr, ok := dep.(string); ok {
-					dependencies = append(dependencies, DependencyInfo{
-						Name:     types.StringValue(depStr),
-						Version:  types.StringValue(""),
-						Type:     types.StringValue("optional"),
-						Optional: types.BoolValue(true),
-					})
-				}
-			}
-		}
+// dependencyNodeInfos converts graph nodes to data source entries.
+func dependencyNodeInfos(nodes []dependencyNode) []DependencyNodeInfo {
+	infos := make([]DependencyNodeInfo, 0, len(nodes))
+	for _, node := range nodes {
+		infos = append(infos, DependencyNodeInfo{
+			Name:  types.StringValue(node.Name),
+			Depth: types.Int64Value(int64(node.Depth)),
+		})
 	}
+	return infos
+}
 
-	return dependencies, nil
+// dependencyEdgeInfos converts graph edges to data source entries.
+func dependencyEdgeInfos(edges []dependencyEdge) []DependencyEdgeInfo {
+	infos := make([]DependencyEdgeInfo, 0, len(edges))
+	for _, edge := range edges {
+		infos = append(infos, DependencyEdgeInfo{
+			From:     types.StringValue(edge.From),
+			To:       types.StringValue(edge.To),
+			Version:  types.StringValue(edge.Dependency.Constraint),
+			Type:     types.StringValue(string(edge.Dependency.Type)),
+			Relation: types.StringValue(edge.Dependency.Relation),
+		})
+	}
+	return infos
 }
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestDependencyInfos(t *testing.T) {
+	infos := dependencyInfos([]adapters.Dependency{
+		{Name: "libc6", Constraint: ">= 2.34", Type: adapters.DependencyRuntime, Relation: "Depends"},
+		{Name: "curl-doc", Type: adapters.DependencyOptional, Relation: "Suggests", Alternatives: []string{"wget"}},
+	})
+
+	assert.Equal(t, []DependencyInfo{
+		{
+			Name:         types.StringValue("libc6"),
+			Version:      types.StringValue(">= 2.34"),
+			Type:         types.StringValue("runtime"),
+			Optional:     types.BoolValue(false),
+			Relation:     types.StringValue("Depends"),
+			Alternatives: emptyStringList(),
+		},
+		{
+			Name:         types.StringValue("curl-doc"),
+			Version:      types.StringValue(""),
+			Type:         types.StringValue("optional"),
+			Optional:     types.BoolValue(true),
+			Relation:     types.StringValue("Suggests"),
+			Alternatives: stringListValue([]string{"wget"}),
+		},
+	}, infos)
+	assert.NotNil(t, dependencyInfos(nil))
+}
+
+func TestDependencyEdgeInfos(t *testing.T) {
+	infos := dependencyEdgeInfos([]dependencyEdge{{
+		From: "curl", To: "libc6",
+		Dependency: adapters.Dependency{Name: "libc6", Constraint: ">= 2.34", Type: adapters.DependencyRuntime,
+			Relation: "Depends"},
+	}})
+
+	assert.Equal(t, []DependencyEdgeInfo{{
+		From:     types.StringValue("curl"),
+		To:       types.StringValue("libc6"),
+		Version:  types.StringValue(">= 2.34"),
+		Type:     types.StringValue("runtime"),
+		Relation: types.StringValue("Depends"),
+	}}, infos)
+}
@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+const (
+	directionForward = "forward"
+	directionReverse = "reverse"
+)
+
+// defaultDependencyMaxDepth bounds recursive walks, which could otherwise look
+// up most of the installed system for widely used packages.
+const defaultDependencyMaxDepth = 10
+
+// dependencyLookup returns the direct dependencies, or the direct dependents,
+// of a package.
+type dependencyLookup func(ctx context.Context, name string) ([]adapters.Dependency, error)
+
+// dependencyNode is a package in a dependency graph, at its shortest distance
+// from the queried package.
+type dependencyNode struct {
+	Name  string
+	Depth int
+}
+
+// dependencyEdge records that From has a relationship on To.
+type dependencyEdge struct {
+	From       string
+	To         string
+	Dependency adapters.Dependency
+}
+
+// dependencyGraph is the result of walking relationships from a package.
+type dependencyGraph struct {
+	// Direct holds the relationships of the queried package itself
+	Direct []adapters.Dependency
+	Nodes  []dependencyNode
+	Edges  []dependencyEdge
+}
+
+// walkDependencies follows the relationships of depType from root breadth
+// first, looking up packages less than maxDepth levels from root; a maxDepth
+// of 1 lists only the direct relationships. In reverse the lookup returns
+// dependents, so edges point from them to the package looked up. Packages
+// other than root that the manager cannot resolve, such as Debian virtual
+// packages, are kept as leaves.
+func walkDependencies(ctx context.Context, lookup dependencyLookup, root, depType string,
+	reverse bool, maxDepth int) (*dependencyGraph, error) {
+	graph := &dependencyGraph{Nodes: []dependencyNode{{Name: root}}}
+	depths := map[string]int{root: 0}
+
+	for queue := []string{root}; len(queue) > 0; queue = queue[1:] {
+		name := queue[0]
+		related, err := lookup(ctx, name)
+		if err != nil {
+			if name != root && errors.Is(err, adapters.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		related = filterDependencies(related, depType)
+		if name == root {
+			graph.Direct = related
+		}
+
+		for _, dep := range related {
+			edge := dependencyEdge{From: name, To: dep.Name, Dependency: dep}
+			if reverse {
+				edge.From, edge.To = dep.Name, name
+			}
+			graph.Edges = append(graph.Edges, edge)
+
+			if _, seen := depths[dep.Name]; seen {
+				continue
+			}
+			depths[dep.Name] = depths[name] + 1
+			graph.Nodes = append(graph.Nodes, dependencyNode{Name: dep.Name, Depth: depths[dep.Name]})
+			if depths[dep.Name] < maxDepth {
+				queue = append(queue, dep.Name)
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// filterDependencies keeps the dependencies of the requested type, or of every
+// type for "all".
+func filterDependencies(dependencies []adapters.Dependency, depType string) []adapters.Dependency {
+	filtered := make([]adapters.Dependency, 0, len(dependencies))
+	for _, dep := range dependencies {
+		if depType == dependencyTypeAll || string(dep.Type) == depType {
+			filtered = append(filtered, dep)
+		}
+	}
+	return filtered
+}
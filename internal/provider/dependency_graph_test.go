@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// graphLookup serves a dependency lookup from a fixed map; unknown packages
+// are not found.
+func graphLookup(graph map[string][]adapters.Dependency) dependencyLookup {
+	return func(_ context.Context, name string) ([]adapters.Dependency, error) {
+		deps, ok := graph[name]
+		if !ok {
+			return nil, fmt.Errorf("package %s: %w", name, adapters.ErrNotFound)
+		}
+		return deps, nil
+	}
+}
+
+var testDependencyGraph = map[string][]adapters.Dependency{
+	"curl": {
+		{Name: "libcurl4", Type: adapters.DependencyRuntime, Relation: "Depends"},
+		{Name: "libc6", Type: adapters.DependencyRuntime, Relation: "Depends"},
+		{Name: "ca-certificates", Type: adapters.DependencyOptional, Relation: "Recommends"},
+	},
+	"libcurl4": {
+		{Name: "libc6", Type: adapters.DependencyRuntime, Relation: "Depends"},
+		{Name: "mail-transport-agent", Type: adapters.DependencyRuntime, Relation: "Depends"},
+	},
+	"libc6": {},
+}
+
+func TestWalkDependencies_Direct(t *testing.T) {
+	graph, err := walkDependencies(context.Background(), graphLookup(testDependencyGraph), "curl", "runtime", false, 1)
+	require.NoError(t, err)
+
+	assert.Len(t, graph.Direct, 2)
+	assert.Equal(t, []dependencyNode{{Name: "curl"}, {Name: "libcurl4", Depth: 1}, {Name: "libc6", Depth: 1}}, graph.Nodes)
+	assert.Len(t, graph.Edges, 2)
+}
+
+func TestWalkDependencies_Recursive(t *testing.T) {
+	graph, err := walkDependencies(context.Background(), graphLookup(testDependencyGraph), "curl", "all", false, defaultDependencyMaxDepth)
+	require.NoError(t, err)
+
+	assert.Len(t, graph.Direct, 3)
+	assert.Equal(t, []dependencyNode{
+		{Name: "curl"},
+		{Name: "libcurl4", Depth: 1},
+		{Name: "libc6", Depth: 1},
+		{Name: "ca-certificates", Depth: 1},
+		{Name: "mail-transport-agent", Depth: 2},
+	}, graph.Nodes)
+
+	var edges []string
+	for _, edge := range graph.Edges {
+		edges = append(edges, edge.From+"->"+edge.To)
+	}
+	assert.Equal(t, []string{
+		"curl->libcurl4", "curl->libc6", "curl->ca-certificates",
+		"libcurl4->libc6", "libcurl4->mail-transport-agent",
+	}, edges)
+}
+
+func TestWalkDependencies_MaxDepth(t *testing.T) {
+	chain := map[string][]adapters.Dependency{}
+	for i := 0; i < 5; i++ {
+		chain[fmt.Sprintf("pkg%d", i)] = []adapters.Dependency{
+			{Name: fmt.Sprintf("pkg%d", i+1), Type: adapters.DependencyRuntime},
+		}
+	}
+	graph, err := walkDependencies(context.Background(), graphLookup(chain), "pkg0", "runtime", false, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []dependencyNode{{Name: "pkg0"}, {Name: "pkg1", Depth: 1}, {Name: "pkg2", Depth: 2}}, graph.Nodes)
+	assert.Len(t, graph.Edges, 2, "packages at max_depth are not looked up")
+}
+
+func TestWalkDependencies_Reverse(t *testing.T) {
+	dependents := map[string][]adapters.Dependency{
+		"libc6":    {{Name: "curl", Type: adapters.DependencyRuntime}, {Name: "libcurl4", Type: adapters.DependencyRuntime}},
+		"libcurl4": {{Name: "curl", Type: adapters.DependencyRuntime}},
+		"curl":     {},
+	}
+	graph, err := walkDependencies(context.Background(), graphLookup(dependents), "libc6", "runtime", true, defaultDependencyMaxDepth)
+	require.NoError(t, err)
+
+	assert.Len(t, graph.Nodes, 3)
+	assert.Equal(t, "curl", graph.Edges[0].From)
+	assert.Equal(t, "libc6", graph.Edges[0].To)
+	assert.Equal(t, "curl", graph.Edges[2].From)
+	assert.Equal(t, "libcurl4", graph.Edges[2].To)
+}
+
+func TestWalkDependencies_Errors(t *testing.T) {
+	_, err := walkDependencies(context.Background(), graphLookup(testDependencyGraph), "missing", "runtime", false, defaultDependencyMaxDepth)
+	assert.ErrorIs(t, err, adapters.ErrNotFound)
+
+	failing := func(_ context.Context, name string) ([]adapters.Dependency, error) {
+		if name == "curl" {
+			return testDependencyGraph["curl"], nil
+		}
+		return nil, errors.New("apt-cache crashed")
+	}
+	_, err = walkDependencies(context.Background(), failing, "curl", "runtime", false, defaultDependencyMaxDepth)
+	assert.EqualError(t, err, "apt-cache crashed")
+}
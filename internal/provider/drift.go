@@ -0,0 +1,251 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// Drift remediation strategies. 'auto' and 'manual' are the original names of
+// 'reinstall' and 'warn'.
+const (
+	remediationReinstall = "reinstall"
+	remediationWarn      = "warn"
+	remediationFail      = "fail"
+	remediationAuto      = "auto"
+	remediationManual    = "manual"
+)
+
+// Prefixes of the entries of drift_findings, one per kind of drift.
+const (
+	findingVersion    = "version: "
+	findingIntegrity  = "integrity: "
+	findingDependency = "dependency: "
+)
+
+// driftChecks are the drift checks enabled for a package.
+type driftChecks struct {
+	version      bool
+	integrity    bool
+	dependencies bool
+}
+
+// enabledDriftChecks returns the configured checks. Without a drift_detection
+// block only the version is checked, as the other checks run commands.
+func enabledDriftChecks(data *PackageResourceModel) driftChecks {
+	config := data.DriftDetection
+	if config == nil {
+		return driftChecks{version: true}
+	}
+	enabled := func(value types.Bool) bool { return value.IsNull() || value.IsUnknown() || value.ValueBool() }
+	return driftChecks{
+		version: enabled(config.CheckVersion),
+		// Verifying every file of a package is slow, so it runs only when asked for
+		integrity:    config.CheckIntegrity.ValueBool(),
+		dependencies: enabled(config.CheckDependencies),
+	}
+}
+
+// allows reports whether a drift finding comes from an enabled check.
+func (c driftChecks) allows(finding string) bool {
+	switch {
+	case strings.HasPrefix(finding, findingVersion):
+		return c.version
+	case strings.HasPrefix(finding, findingIntegrity):
+		return c.integrity
+	case strings.HasPrefix(finding, findingDependency):
+		return c.dependencies
+	}
+	return false
+}
+
+// driftRemediation returns the effective remediation strategy, which is 'warn'
+// when reinstall_on_drift is false.
+func driftRemediation(data *PackageResourceModel) string {
+	remediation := remediationReinstall
+	if data.DriftDetection != nil && !data.DriftDetection.Remediation.IsNull() &&
+		!data.DriftDetection.Remediation.IsUnknown() {
+		remediation = data.DriftDetection.Remediation.ValueString()
+	}
+	switch remediation {
+	case remediationAuto:
+		remediation = remediationReinstall
+	case remediationManual:
+		remediation = remediationWarn
+	}
+	if remediation == remediationReinstall && !data.ReinstallOnDrift.IsNull() && !data.ReinstallOnDrift.IsUnknown() &&
+		!data.ReinstallOnDrift.ValueBool() {
+		remediation = remediationWarn
+	}
+	return remediation
+}
+
+// remediatesVersionDrift reports whether a package whose version drifted away
+// from the configured one is to be changed back.
+func remediatesVersionDrift(data *PackageResourceModel) bool {
+	return enabledDriftChecks(data).version && driftRemediation(data) == remediationReinstall
+}
+
+// Findings describes the detected drift as drift_findings entries.
+func (d DriftInfo) Findings() []string {
+	var findings []string
+	if d.HasVersionDrift {
+		findings = append(findings, fmt.Sprintf("%sinstalled %s does not match %s",
+			findingVersion, d.CurrentVersion, d.DesiredVersion))
+	}
+	for _, issue := range d.IntegrityIssues {
+		findings = append(findings, findingIntegrity+issue)
+	}
+	for _, dependency := range d.MissingDependencies {
+		findings = append(findings, findingDependency+dependency+" is not installed")
+	}
+	return findings
+}
+
+// detectDrift runs the enabled drift checks against an installed package. A
+// check that fails is reported as a warning and skipped.
+func (r *PackageResource) detectDrift(ctx context.Context, manager adapters.PackageManager, packageName string,
+	data *PackageResourceModel, diags *diag.Diagnostics) DriftInfo {
+	drift := DriftInfo{RemediationStrategy: driftRemediation(data)}
+	if data.State.ValueString() == stateAbsent || data.VersionActual.ValueString() == "" {
+		return drift
+	}
+
+	checks := enabledDriftChecks(data)
+	if checks.version && data.State.ValueString() != stateLatest {
+		versionDrift := r.detectVersionDrift(
+			PackageState{Name: packageName, Version: data.VersionActual.ValueString(), Installed: true},
+			PackageState{Name: packageName, Version: data.Version.ValueString(), Installed: true},
+		)
+		drift.HasVersionDrift = versionDrift.HasVersionDrift
+		drift.CurrentVersion = versionDrift.CurrentVersion
+		drift.DesiredVersion = versionDrift.DesiredVersion
+	}
+
+	checker, ok := manager.(adapters.DriftChecker)
+	if !ok {
+		return drift
+	}
+	if checks.integrity {
+		issues, err := checker.VerifyIntegrity(ctx, packageName)
+		if err != nil {
+			diags.AddWarning("Drift Check Failed",
+				fmt.Sprintf("Failed to verify the integrity of package %s: %v", packageName, err))
+		}
+		drift.IntegrityIssues = issues
+		drift.HasIntegrityDrift = len(issues) > 0
+	}
+	if checks.dependencies {
+		missing, err := checker.MissingDependencies(ctx, packageName)
+		if err != nil {
+			diags.AddWarning("Drift Check Failed",
+				fmt.Sprintf("Failed to check the dependencies of package %s: %v", packageName, err))
+		}
+		drift.MissingDependencies = missing
+		drift.HasDependencyDrift = len(missing) > 0
+	}
+
+	return drift
+}
+
+// planDrift applies the remediation strategy to the drift found by the last
+// refresh, and sets plan.DriftFindings. Version drift is remediated by
+// planVersion; other drift is remediated by reinstalling the package.
+func planDrift(packageName string, plan, prior *PackageResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	plan.DriftFindings = emptyStringList()
+	if prior == nil || plan.State.ValueString() == stateAbsent {
+		return diags
+	}
+
+	findings := enabledDriftFindings(plan, prior.DriftFindings)
+	if len(findings) == 0 {
+		return diags
+	}
+
+	detail := fmt.Sprintf("Package %s has drifted from its configuration:\n  - %s",
+		packageName, strings.Join(findings, "\n  - "))
+	switch driftRemediation(plan) {
+	case remediationFail:
+		diags.AddAttributeError(tfpath.Root("drift_detection"), "Package Drift Detected",
+			detail+"\n\nRepair the package, or set drift_detection.remediation to 'reinstall' or 'warn'.")
+	case remediationWarn:
+		diags.AddAttributeWarning(tfpath.Root("drift_detection"), "Package Drift Detected", detail)
+		plan.DriftFindings = stringListValue(findings)
+	default:
+		// Plan an update so that apply reinstalls the package
+		plan.VersionActual = types.StringUnknown()
+	}
+
+	return diags
+}
+
+// enabledDriftFindings returns the entries of findings that come from checks
+// enabled for data.
+func enabledDriftFindings(data *PackageResourceModel, findings types.List) []string {
+	checks := enabledDriftChecks(data)
+	var enabled []string
+	for _, element := range findings.Elements() {
+		finding, ok := element.(types.String)
+		if ok && checks.allows(finding.ValueString()) {
+			enabled = append(enabled, finding.ValueString())
+		}
+	}
+	return enabled
+}
+
+// needsReinstall reports whether the update planned by planDrift is to
+// reinstall the package because of integrity or dependency drift.
+func needsReinstall(data *PackageResourceModel, priorFindings types.List) bool {
+	if driftRemediation(data) != remediationReinstall {
+		return false
+	}
+	for _, finding := range enabledDriftFindings(data, priorFindings) {
+		if !strings.HasPrefix(finding, findingVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+// reinstallPackage reinstalls the installed version of a package, falling back
+// to an install for managers that cannot reinstall.
+func (r *PackageResource) reinstallPackage(ctx context.Context, manager adapters.PackageManager,
+	packageName string, data *PackageResourceModel) error {
+	reinstaller, ok := manager.(adapters.Reinstaller)
+	if !ok {
+		return r.installDesiredVersion(ctx, manager, packageName, data)
+	}
+	return r.rollbackScoped(ctx, manager, func() error {
+		return reinstaller.Reinstall(ctx, packageName, r.getPackageType(data.PackageType))
+	})
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func driftConfig(remediation string) *DriftDetectionConfig {
+	config := &DriftDetectionConfig{
+		CheckVersion:      types.BoolNull(),
+		CheckIntegrity:    types.BoolNull(),
+		CheckDependencies: types.BoolNull(),
+		Remediation:       types.StringNull(),
+	}
+	if remediation != "" {
+		config.Remediation = types.StringValue(remediation)
+	}
+	return config
+}
+
+func TestDriftRemediation(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *DriftDetectionConfig
+		reinstall bool
+		want      string
+	}{
+		{"default", nil, true, remediationReinstall},
+		{"reinstall_on_drift off", nil, false, remediationWarn},
+		{"block default", driftConfig(""), true, remediationReinstall},
+		{"fail", driftConfig(remediationFail), true, remediationFail},
+		{"fail ignores reinstall_on_drift", driftConfig(remediationFail), false, remediationFail},
+		{"auto alias", driftConfig(remediationAuto), true, remediationReinstall},
+		{"manual alias", driftConfig(remediationManual), true, remediationWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &PackageResourceModel{ReinstallOnDrift: types.BoolValue(tt.reinstall), DriftDetection: tt.config}
+			assert.Equal(t, tt.want, driftRemediation(data))
+		})
+	}
+}
+
+func TestPackageResource_DetectDrift(t *testing.T) {
+	manager := newFakePackageManager(map[string]string{"curl": "7.81.0-1"})
+	manager.integrity = map[string][]string{"curl": {"/usr/bin/curl has been modified"}}
+	manager.missingDeps = map[string][]string{"curl": {"libcurl4"}}
+
+	data := packagePlan(statePresent, "7.81.0-1ubuntu1.*")
+	data.VersionActual = types.StringValue("7.81.0-1")
+
+	var diags diag.Diagnostics
+	drift := (&PackageResource{}).detectDrift(context.Background(), manager, "curl", data, &diags)
+	assert.Equal(t, []string{"version: installed 7.81.0-1 does not match 7.81.0-1ubuntu1.*"}, drift.Findings())
+
+	data.DriftDetection = driftConfig("")
+	data.DriftDetection.CheckVersion = types.BoolValue(false)
+	drift = (&PackageResource{}).detectDrift(context.Background(), manager, "curl", data, &diags)
+	assert.Equal(t, []string{"dependency: libcurl4 is not installed"}, drift.Findings(),
+		"integrity is only checked when enabled")
+
+	data.DriftDetection.CheckIntegrity = types.BoolValue(true)
+	drift = (&PackageResource{}).detectDrift(context.Background(), manager, "curl", data, &diags)
+	assert.Equal(t, []string{
+		"integrity: /usr/bin/curl has been modified",
+		"dependency: libcurl4 is not installed",
+	}, drift.Findings())
+	assert.Empty(t, diags)
+
+	data.State = types.StringValue(stateAbsent)
+	assert.Empty(t, (&PackageResource{}).detectDrift(context.Background(), manager, "curl", data, &diags).Findings())
+}
+
+func driftedPackage(remediation string) (plan, prior *PackageResourceModel) {
+	plan = packagePlan(statePresent, "")
+	plan.DriftDetection = driftConfig(remediation)
+	plan.DriftDetection.CheckIntegrity = types.BoolValue(true)
+	prior = priorState(plan, "7.81.0-1")
+	prior.DriftFindings = stringListValue([]string{"integrity: /usr/bin/curl is missing"})
+	plan.VersionActual = prior.VersionActual
+	return plan, prior
+}
+
+func TestPlanDrift_Reinstall(t *testing.T) {
+	plan, prior := driftedPackage(remediationReinstall)
+
+	diags := planDrift("curl", plan, prior)
+
+	assert.Empty(t, diags)
+	assert.True(t, plan.VersionActual.IsUnknown())
+	assert.Equal(t, emptyStringList(), plan.DriftFindings)
+	assert.True(t, needsReinstall(plan, prior.DriftFindings))
+}
+
+func TestPlanDrift_Warn(t *testing.T) {
+	plan, prior := driftedPackage(remediationWarn)
+
+	diags := planDrift("curl", plan, prior)
+
+	require.Len(t, diags.Warnings(), 1)
+	assert.Contains(t, diags.Warnings()[0].Detail(), "integrity: /usr/bin/curl is missing")
+	assert.Equal(t, prior.VersionActual, plan.VersionActual)
+	assert.Equal(t, prior.DriftFindings, plan.DriftFindings)
+}
+
+func TestPlanDrift_Fail(t *testing.T) {
+	plan, prior := driftedPackage(remediationFail)
+
+	diags := planDrift("curl", plan, prior)
+
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Package Drift Detected", diags.Errors()[0].Summary())
+}
+
+func TestPlanDrift_DisabledCheck(t *testing.T) {
+	plan, prior := driftedPackage(remediationFail)
+	plan.DriftDetection.CheckIntegrity = types.BoolValue(false)
+
+	diags := planDrift("curl", plan, prior)
+
+	assert.Empty(t, diags)
+	assert.Equal(t, emptyStringList(), plan.DriftFindings)
+	assert.False(t, needsReinstall(plan, prior.DriftFindings))
+}
+
+func TestPackageResource_ReinstallPackage(t *testing.T) {
+	manager := newFakePackageManager(map[string]string{"curl": "7.81.0-1"})
+
+	err := (&PackageResource{}).reinstallPackage(context.Background(), manager, "curl", packagePlan(statePresent, ""))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"curl"}, manager.reinstalled)
+	assert.Empty(t, manager.singleRuns)
+}
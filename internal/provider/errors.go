@@ -23,9 +23,13 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 // PackageError represents a package management error.
@@ -125,3 +129,84 @@ func (d *DiagnosticHelpers) NotFoundWarningDiagnostic(packageName, manager strin
 		packageName, manager)
 	return diag.NewWarningDiagnostic(summary, detail)
 }
+
+// adapterErrorGuidance describes each adapter error kind with remediation text.
+// Kinds are matched in order; an error only ever carries one kind.
+var adapterErrorGuidance = []struct {
+	kind        error
+	summary     string
+	remediation string
+}{
+	{
+		kind:    adapters.ErrInterrupted,
+		summary: "Operation Interrupted",
+		remediation: "The command was cancelled or ran past its timeout. Increase the resource's timeouts if the " +
+			"operation needs longer.",
+	},
+	{
+		kind:    adapters.ErrNotFound,
+		summary: "Not Found",
+		remediation: "Check the name for typos, make sure the repository or tap that provides it is configured " +
+			"(see pkg_repo), and refresh the package cache with update_cache = \"always\" if it was added recently.",
+	},
+	{
+		kind:    adapters.ErrVersionUnavailable,
+		summary: "Version Unavailable",
+		remediation: "The package exists but not at the requested version. List the available versions with the " +
+			"pkg_version_history data source (or `apt-cache policy <name>`), or remove the version constraint.",
+	},
+	{
+		kind:    adapters.ErrLockHeld,
+		summary: "Package Manager Locked",
+		remediation: "Another package manager process, such as unattended-upgrades, a software updater or another " +
+			"Terraform run, holds the lock. Wait for it to finish; for APT, increase lock_timeout in the provider " +
+			"block to wait longer.",
+	},
+	{
+		kind:    adapters.ErrPermissionDenied,
+		summary: "Insufficient Privileges",
+		remediation: "Set sudo_enabled = true in the provider block and allow the Terraform user to run the package " +
+			"manager with passwordless sudo, or run Terraform as a user with the required privileges. " +
+			"Homebrew must not be run as root.",
+	},
+	{
+		kind:    adapters.ErrNetwork,
+		summary: "Network Error",
+		remediation: "Check connectivity and proxy settings for the package repositories. Download failures are " +
+			"often transient; running terraform apply again retries the operation.",
+	},
+	{
+		kind:    adapters.ErrDependencyConflict,
+		summary: "Dependency Conflict",
+		remediation: "The package's dependencies conflict with installed packages. Inspect the output above, then " +
+			"remove, upgrade or unpin the conflicting package, or request a compatible version.",
+	},
+}
+
+// ErrorAttributes maps adapter error kinds to the attribute a diagnostic of that
+// kind should point at.
+type ErrorAttributes map[error]path.Path
+
+// AdapterErrorDiagnostic creates a diagnostic for a failed adapter operation.
+// Errors of a known kind get a specific summary, remediation text, and the
+// attribute path registered for the kind in attributes, if any.
+func (d *DiagnosticHelpers) AdapterErrorDiagnostic(summary, detail string, err error,
+	attributes ErrorAttributes) diag.Diagnostic {
+	detail = fmt.Sprintf("%s: %v", detail, err)
+	for _, guidance := range adapterErrorGuidance {
+		if !errors.Is(err, guidance.kind) {
+			continue
+		}
+		summary = fmt.Sprintf("%s: %s", summary, guidance.summary)
+		detail = fmt.Sprintf("%s\n\n%s", detail, guidance.remediation)
+		var opErr *adapters.OperationError
+		if errors.As(err, &opErr) && opErr.Recovery != "" {
+			detail = fmt.Sprintf("%s %s", detail, opErr.Recovery)
+		}
+		if attributePath, ok := attributes[guidance.kind]; ok {
+			return diag.NewAttributeErrorDiagnostic(attributePath, summary, detail)
+		}
+		break
+	}
+	return diag.NewErrorDiagnostic(summary, detail)
+}
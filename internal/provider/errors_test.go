@@ -24,8 +24,14 @@ package provider
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 func TestPackageError(t *testing.T) {
@@ -224,3 +230,73 @@ func TestDiagnosticHelpers_NotFoundWarningDiagnostic(t *testing.T) {
 		t.Errorf("Expected detail to contain drift explanation: %s", detail)
 	}
 }
+
+func TestDiagnosticHelpers_AdapterErrorDiagnostic(t *testing.T) {
+	helpers := NewDiagnosticHelpers()
+	attributes := ErrorAttributes{adapters.ErrNotFound: path.Root("name")}
+	err := &adapters.OperationError{
+		Kind: adapters.ErrNotFound, Operation: "install", Target: "nosuchpkg", ExitCode: 100,
+		Stderr: "E: Unable to locate package nosuchpkg",
+	}
+
+	d := helpers.AdapterErrorDiagnostic("Package Installation Failed", "Failed to install package", err, attributes)
+
+	if d.Summary() != "Package Installation Failed: Not Found" {
+		t.Errorf("Unexpected summary %q", d.Summary())
+	}
+	if !strings.Contains(d.Detail(), "Unable to locate package") || !strings.Contains(d.Detail(), "pkg_repo") {
+		t.Errorf("Expected detail to include stderr and remediation, got %q", d.Detail())
+	}
+	withPath, ok := d.(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("name")) {
+		t.Errorf("Expected diagnostic on attribute name, got %#v", d)
+	}
+}
+
+func TestDiagnosticHelpers_AdapterErrorDiagnostic_Unclassified(t *testing.T) {
+	helpers := NewDiagnosticHelpers()
+
+	d := helpers.AdapterErrorDiagnostic("Package Installation Failed", "Failed to install package",
+		errors.New("boom"), ErrorAttributes{adapters.ErrNotFound: path.Root("name")})
+
+	if d.Summary() != "Package Installation Failed" {
+		t.Errorf("Unexpected summary %q", d.Summary())
+	}
+	if d.Detail() != "Failed to install package: boom" {
+		t.Errorf("Unexpected detail %q", d.Detail())
+	}
+	if _, ok := d.(diag.DiagnosticWithPath); ok {
+		t.Error("Unclassified errors should not point at an attribute")
+	}
+}
+
+func TestDiagnosticHelpers_AdapterErrorDiagnostic_KindWithoutAttribute(t *testing.T) {
+	helpers := NewDiagnosticHelpers()
+	err := fmt.Errorf("install failed: %w", adapters.ErrLockHeld)
+
+	d := helpers.AdapterErrorDiagnostic("Package Installation Failed", "Failed to install package", err, nil)
+
+	if d.Summary() != "Package Installation Failed: Package Manager Locked" {
+		t.Errorf("Unexpected summary %q", d.Summary())
+	}
+	if !strings.Contains(d.Detail(), "lock_timeout") {
+		t.Errorf("Expected lock remediation, got %q", d.Detail())
+	}
+}
+
+func TestDiagnosticHelpers_AdapterErrorDiagnostic_Interrupted(t *testing.T) {
+	helpers := NewDiagnosticHelpers()
+	err := &adapters.OperationError{Kind: adapters.ErrInterrupted, Manager: "brew", Operation: "install", Target: "jq"}
+
+	d := helpers.AdapterErrorDiagnostic("Package Installation Failed", "Failed to install package", err, nil)
+	if strings.Contains(d.Detail(), "dpkg") {
+		t.Errorf("Expected no repair claim without a recovery, got %q", d.Detail())
+	}
+
+	err.Manager = "apt"
+	err.Recovery = "The package database was repaired with `dpkg --configure -a`."
+	d = helpers.AdapterErrorDiagnostic("Package Installation Failed", "Failed to install package", err, nil)
+	if !strings.Contains(d.Detail(), "dpkg --configure -a") {
+		t.Errorf("Expected the recovery to be reported, got %q", d.Detail())
+	}
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// protectedPackages are never removed by exclusive mode, even where the
+// package manager does not mark them essential: without them a host may no
+// longer boot or accept logins. Entries are shell patterns.
+var protectedPackages = []string{
+	"apt", "base-files", "ca-certificates", "cloud-init", "dpkg", "grub-*", "ifupdown", "init",
+	"initramfs-tools", "linux-generic*", "linux-image-*", "linux-virtual*", "netplan.io", "network-manager",
+	"openssh-server", "shim-signed", "sudo", "systemd", "systemd-sysv", "ubuntu-minimal", "ubuntu-server",
+	"ubuntu-standard",
+}
+
+// removablePackages returns the manually installed packages exclusive mode
+// may remove: those the package manager does not consider essential, that are
+// not built-in protected and that keep does not match.
+func removablePackages(ctx context.Context, manager adapters.PackageManager, keep []string) ([]string, error) {
+	lister, ok := manager.(adapters.ManualInstallLister)
+	if !ok {
+		return nil, fmt.Errorf("exclusive mode is not supported by package manager %s", manager.GetManagerName())
+	}
+	manual, err := lister.ListManuallyInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	essential := make(map[string]bool)
+	if essentialLister, ok := manager.(adapters.EssentialLister); ok {
+		names, err := essentialLister.ListEssential(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			essential[name] = true
+		}
+	}
+
+	removable := make([]string, 0, len(manual))
+	for _, name := range manual {
+		if essential[name] || matchesAnyPattern(protectedPackages, name) || matchesAnyPattern(keep, name) {
+			continue
+		}
+		removable = append(removable, name)
+	}
+	return removable, nil
+}
+
+// matchesAnyPattern reports whether name equals or matches one of the shell
+// patterns.
+func matchesAnyPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
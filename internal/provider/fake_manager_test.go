@@ -0,0 +1,254 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// fakePackageManager is an in-memory package manager used by provider unit tests.
+type fakePackageManager struct {
+	name        string
+	installed   map[string]string
+	removeErr   map[string]error
+	requiredBy  map[string]string
+	removed     []string
+	recovered   bool
+	recoverErr  error
+	listErr     error
+	installFunc func(name, version string) error
+	candidates  map[string]*adapters.VersionCandidates
+	upgraded    []string
+	simulations map[string]*adapters.Simulation
+	simulateErr error
+	integrity   map[string][]string
+	missingDeps map[string][]string
+	reinstalled []string
+	metadata    map[string]*adapters.PackageMetadata
+	deps        map[string]map[string]string
+	files       map[string][]string
+	manual      []string
+	essential   []string
+
+	mu         sync.Mutex
+	batches    [][]string
+	batchErr   error
+	batchFunc  func(ctx context.Context) error
+	singleRuns []string
+
+	updateCalls    atomic.Int32
+	updateErr      error
+	cacheUpdatedAt time.Time
+}
+
+func newFakePackageManager(installed map[string]string) *fakePackageManager {
+	if installed == nil {
+		installed = map[string]string{}
+	}
+	return &fakePackageManager{name: "fake", installed: installed, removeErr: map[string]error{}}
+}
+
+func (f *fakePackageManager) DetectInstalled(_ context.Context, name string) (*adapters.PackageInfo, error) {
+	version, ok := f.installed[name]
+	return &adapters.PackageInfo{Name: name, Version: version, Installed: ok}, nil
+}
+
+func (f *fakePackageManager) Install(ctx context.Context, name, version string) error {
+	return f.InstallWithType(ctx, name, version, adapters.PackageTypeAuto)
+}
+
+func (f *fakePackageManager) InstallWithType(_ context.Context, name, version string, _ adapters.PackageType) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.singleRuns = append(f.singleRuns, name)
+	if f.installFunc != nil {
+		return f.installFunc(name, version)
+	}
+	f.installed[name] = version
+	return nil
+}
+
+func (f *fakePackageManager) InstallBatch(ctx context.Context, packages []adapters.BatchPackage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+	f.batches = append(f.batches, names)
+	if f.batchFunc != nil {
+		if err := f.batchFunc(ctx); err != nil {
+			return err
+		}
+	}
+	if f.batchErr != nil {
+		return f.batchErr
+	}
+	for _, pkg := range packages {
+		f.installed[pkg.Name] = pkg.Version
+	}
+	return nil
+}
+
+func (f *fakePackageManager) Remove(ctx context.Context, name string) error {
+	return f.RemoveWithType(ctx, name, adapters.PackageTypeAuto)
+}
+
+func (f *fakePackageManager) RemoveWithType(_ context.Context, name string, _ adapters.PackageType) error {
+	if err := f.removeErr[name]; err != nil {
+		return err
+	}
+	if dependent, ok := f.requiredBy[name]; ok {
+		if _, installed := f.installed[dependent]; installed {
+			return fmt.Errorf("%s is required by %s", name, dependent)
+		}
+	}
+	delete(f.installed, name)
+	f.removed = append(f.removed, name)
+	return nil
+}
+
+func (f *fakePackageManager) Pin(_ context.Context, _ string, _ bool) error { return nil }
+
+func (f *fakePackageManager) UpdateCache(_ context.Context) error {
+	f.updateCalls.Add(1)
+	return f.updateErr
+}
+
+func (f *fakePackageManager) CacheLastUpdated(_ context.Context) (time.Time, error) {
+	if f.cacheUpdatedAt.IsZero() {
+		return time.Time{}, fmt.Errorf("cache age unknown")
+	}
+	return f.cacheUpdatedAt, nil
+}
+
+func (f *fakePackageManager) Search(_ context.Context, _ string) ([]adapters.PackageInfo, error) {
+	return nil, nil
+}
+
+func (f *fakePackageManager) Info(ctx context.Context, name string) (*adapters.PackageInfo, error) {
+	return f.DetectInstalled(ctx, name)
+}
+
+func (f *fakePackageManager) GetManagerName() string { return f.name }
+
+func (f *fakePackageManager) IsAvailable(_ context.Context) bool { return true }
+
+func (f *fakePackageManager) ListInstalled(_ context.Context) ([]adapters.PackageInfo, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	packages := make([]adapters.PackageInfo, 0, len(f.installed))
+	for name, version := range f.installed {
+		packages = append(packages, adapters.PackageInfo{Name: name, Version: version, Installed: true})
+	}
+	return packages, nil
+}
+
+func (f *fakePackageManager) Candidates(_ context.Context, name string) (*adapters.VersionCandidates, error) {
+	candidates, ok := f.candidates[name]
+	if !ok {
+		return nil, fmt.Errorf("package %s %w", name, adapters.ErrNotFound)
+	}
+	return candidates, nil
+}
+
+func (f *fakePackageManager) Upgrade(_ context.Context, name string, _ adapters.PackageType) error {
+	f.upgraded = append(f.upgraded, name)
+	if candidates, ok := f.candidates[name]; ok {
+		f.installed[name] = candidates.Candidate
+	}
+	return nil
+}
+
+func (f *fakePackageManager) SimulateInstall(_ context.Context, name, _ string) (*adapters.Simulation, error) {
+	return f.simulate("install " + name)
+}
+
+func (f *fakePackageManager) SimulateRemove(_ context.Context, name string) (*adapters.Simulation, error) {
+	return f.simulate("remove " + name)
+}
+
+// simulate returns the simulation registered for "install <name>" or "remove <name>".
+func (f *fakePackageManager) simulate(operation string) (*adapters.Simulation, error) {
+	if f.simulateErr != nil {
+		return nil, f.simulateErr
+	}
+	if simulation, ok := f.simulations[operation]; ok {
+		return simulation, nil
+	}
+	return &adapters.Simulation{}, nil
+}
+
+func (f *fakePackageManager) VerifyIntegrity(_ context.Context, name string) ([]string, error) {
+	return f.integrity[name], nil
+}
+
+func (f *fakePackageManager) MissingDependencies(_ context.Context, name string) ([]string, error) {
+	return f.missingDeps[name], nil
+}
+
+func (f *fakePackageManager) Reinstall(_ context.Context, name string, _ adapters.PackageType) error {
+	f.reinstalled = append(f.reinstalled, name)
+	return nil
+}
+
+func (f *fakePackageManager) Metadata(_ context.Context, name string) (*adapters.PackageMetadata, error) {
+	metadata, ok := f.metadata[name]
+	if !ok {
+		return nil, fmt.Errorf("package %s %w", name, adapters.ErrNotFound)
+	}
+	return metadata, nil
+}
+
+func (f *fakePackageManager) DependencyTree(_ context.Context, name string) (map[string]string, error) {
+	return f.deps[name], nil
+}
+
+func (f *fakePackageManager) Files(_ context.Context, name string) ([]string, error) {
+	return f.files[name], nil
+}
+
+func (f *fakePackageManager) ListManuallyInstalled(_ context.Context) ([]string, error) {
+	return f.manual, nil
+}
+
+func (f *fakePackageManager) ListEssential(_ context.Context) ([]string, error) {
+	return f.essential, nil
+}
+
+func (f *fakePackageManager) Recover(_ context.Context) error {
+	f.recovered = true
+	return f.recoverErr
+}
+
+// nonListingManager implements only the base PackageManager interface.
+type nonListingManager struct {
+	adapters.PackageManager
+}
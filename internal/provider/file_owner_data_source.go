@@ -0,0 +1,243 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &FileOwnerDataSource{}
+
+// NewFileOwnerDataSource creates a new file owner data source.
+func NewFileOwnerDataSource() datasource.DataSource {
+	return &FileOwnerDataSource{}
+}
+
+// FileOwnerDataSource defines the data source implementation.
+type FileOwnerDataSource struct {
+	providerData *ProviderData
+}
+
+// FileOwnerDataSourceModel describes the data source data model.
+type FileOwnerDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Path         types.String `tfsdk:"path"`
+	Manager      types.String `tfsdk:"manager"`
+	Found        types.Bool   `tfsdk:"found"`
+	Package      types.String `tfsdk:"package"`
+	Packages     types.List   `tfsdk:"packages"`
+	ResolvedPath types.String `tfsdk:"resolved_path"`
+	FileType     types.String `tfsdk:"file_type"`
+}
+
+// Metadata returns the data source type name.
+// Metadata returns the data source type name.
+func (d *FileOwnerDataSource) Metadata(
+	_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_file_owner"
+}
+
+// Schema defines the data source schema.
+// Schema defines the data source schema.
+func (d *FileOwnerDataSource) Schema(
+	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Finds the installed package that provides a file, with `dpkg-query --search` on APT " +
+			"systems and by resolving Homebrew's symbolic links to the formula keg or cask on macOS.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Data source identifier.",
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the file to look up (e.g., '/usr/bin/curl').",
+				Required:            true,
+				Validators: []validator.String{
+					absolutePath(),
+				},
+			},
+			"manager": schema.StringAttribute{
+				MarkdownDescription: "Package manager to query. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
+					"Defaults to 'auto'.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
+			},
+			"found": schema.BoolAttribute{
+				MarkdownDescription: "Whether an installed package owns the file.",
+				Computed:            true,
+			},
+			"package": schema.StringAttribute{
+				MarkdownDescription: "Name of the package owning the file. Empty when no package owns it.",
+				Computed:            true,
+			},
+			"packages": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "All packages owning the file. " +
+					"APT packages can share a path, most often a directory.",
+				Computed: true,
+			},
+			"resolved_path": schema.StringAttribute{
+				MarkdownDescription: "Path the package manager records for the file, reached from `path` through " +
+					"symbolic links or the merged /usr layout (e.g., '/opt/homebrew/Cellar/jq/1.7.1/bin/jq' " +
+					"for '/opt/homebrew/bin/jq'). Empty when no package owns the file.",
+				Computed: true,
+			},
+			"file_type": schema.StringAttribute{
+				MarkdownDescription: "Type of the file judged by its location, as used by the `file_type` filter of " +
+					"`pkg_package_files`: 'binary', 'config', 'doc' or 'other'.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Configure configures the data source with provider data.
+// Configure configures the data source with provider data.
+func (d *FileOwnerDataSource) Configure(
+	_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *FileOwnerDataSource) Read(
+	ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FileOwnerDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Determine package manager
+	managerName := managerAuto
+	if !data.Manager.IsNull() {
+		managerName = data.Manager.ValueString()
+	}
+
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
+		return
+	}
+
+	path := data.Path.ValueString()
+	ownership, err := d.findOwner(ctx, managerName, path)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Find File Owner",
+			fmt.Sprintf("Failed to find the package owning %s: %v", path, err),
+		)
+		return
+	}
+
+	// Set computed values
+	data.ID = types.StringValue(fmt.Sprintf("%s:owner:%s", managerName, path))
+	data.Manager = types.StringValue(managerName)
+	setFileOwnership(&data, ownership)
+
+	packagesList, diags := types.ListValueFrom(ctx, types.StringType, ownerPackages(ownership))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Packages = packagesList
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findOwner returns the packages owning path, or nil when no installed
+// package does.
+func (d *FileOwnerDataSource) findOwner(
+	ctx context.Context, managerName, path string) (*adapters.FileOwnership, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, ok := manager.(adapters.FileOwnerResolver)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot find file owners", manager.GetManagerName())
+	}
+
+	ownership, err := resolver.FileOwner(ctx, path)
+	if errors.Is(err, adapters.ErrNotFound) {
+		return nil, nil
+	}
+	return ownership, err
+}
+
+// setFileOwnership sets the scalar ownership attributes, classifying the
+// recorded path when a package owns the file and the configured path otherwise.
+func setFileOwnership(data *FileOwnerDataSourceModel, ownership *adapters.FileOwnership) {
+	if ownership == nil || len(ownership.Packages) == 0 {
+		data.Found = types.BoolValue(false)
+		data.Package = types.StringValue("")
+		data.ResolvedPath = types.StringValue("")
+		data.FileType = types.StringValue(fileType(data.Path.ValueString()))
+		return
+	}
+
+	data.Found = types.BoolValue(true)
+	data.Package = types.StringValue(ownership.Packages[0])
+	data.ResolvedPath = types.StringValue(ownership.Path)
+	data.FileType = types.StringValue(fileType(ownership.Path))
+}
+
+// ownerPackages returns the owning packages, empty when there are none.
+func ownerPackages(ownership *adapters.FileOwnership) []string {
+	if ownership == nil {
+		return []string{}
+	}
+	return append([]string{}, ownership.Packages...)
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestSetFileOwnership(t *testing.T) {
+	data := FileOwnerDataSourceModel{Path: types.StringValue("/opt/homebrew/bin/jq")}
+	ownership := &adapters.FileOwnership{Packages: []string{"jq"}, Path: "/opt/homebrew/Cellar/jq/1.7.1/bin/jq"}
+	setFileOwnership(&data, ownership)
+
+	assert.Equal(t, types.BoolValue(true), data.Found)
+	assert.Equal(t, types.StringValue("jq"), data.Package)
+	assert.Equal(t, types.StringValue("/opt/homebrew/Cellar/jq/1.7.1/bin/jq"), data.ResolvedPath)
+	assert.Equal(t, types.StringValue(fileTypeBinary), data.FileType)
+	assert.Equal(t, []string{"jq"}, ownerPackages(ownership))
+
+	data = FileOwnerDataSourceModel{Path: types.StringValue("/etc/hosts")}
+	setFileOwnership(&data, nil)
+
+	assert.Equal(t, types.BoolValue(false), data.Found)
+	assert.Equal(t, types.StringValue(""), data.Package)
+	assert.Equal(t, types.StringValue(""), data.ResolvedPath)
+	assert.Equal(t, types.StringValue(fileTypeConfig), data.FileType)
+	assert.Equal(t, []string{}, ownerPackages(nil))
+}
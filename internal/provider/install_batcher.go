@@ -0,0 +1,322 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// defaultBatchTimeout bounds a batched install when none of the callers
+// supplied a deadline.
+const defaultBatchTimeout = 30 * time.Minute
+
+// InstallBatcher coalesces concurrent install requests for the same package
+// manager into a single transaction. Requests arriving within the batch window
+// of the first one are installed together; if the combined install fails, each
+// package is retried on its own so the failure is attributed to the right
+// resource. With rollback enabled every flush runs in one rollback scope, so a
+// failed install only removes what its own transaction added.
+type InstallBatcher struct {
+	window   time.Duration
+	rollback bool
+
+	mu      sync.Mutex
+	pending map[string]*installBatch
+}
+
+// installBatch collects the requests queued for one package manager.
+type installBatch struct {
+	manager  adapters.PackageManager
+	requests []*batchRequest
+}
+
+// batchRequest is a single caller waiting on a batched install.
+type batchRequest struct {
+	ctx    context.Context
+	pkg    adapters.BatchPackage
+	result chan error
+}
+
+// NewInstallBatcher creates an InstallBatcher. A window of zero disables
+// batching and installs every package immediately. When rollback is set,
+// failed installs are rolled back as for cleanup_on_error.
+func NewInstallBatcher(window time.Duration, rollback bool) *InstallBatcher {
+	return &InstallBatcher{
+		window:   window,
+		rollback: rollback,
+		pending:  make(map[string]*installBatch),
+	}
+}
+
+// Install installs a package, batching it with other requests for the same
+// manager when possible. It blocks until the package has been installed. If
+// the context is cancelled before the batch starts the request is withdrawn;
+// once the batch is running Install waits for it, so callers never act on the
+// package while its install is still in progress. The batch itself is
+// cancelled when all of its callers are.
+func (b *InstallBatcher) Install(
+	ctx context.Context, manager adapters.PackageManager, name, version string, packageType adapters.PackageType) error {
+	if _, ok := manager.(adapters.BatchInstaller); b == nil || b.window <= 0 || !ok {
+		rollback := b != nil && b.rollback
+		return rollbackScoped(ctx, manager, rollback, func() error {
+			return manager.InstallWithType(ctx, name, version, packageType)
+		})
+	}
+
+	request := &batchRequest{
+		ctx:    ctx,
+		pkg:    adapters.BatchPackage{Name: name, Version: version, Type: packageType},
+		result: make(chan error, 1),
+	}
+
+	key := manager.GetManagerName()
+
+	b.mu.Lock()
+	batch, exists := b.pending[key]
+	if !exists {
+		batch = &installBatch{manager: manager}
+		b.pending[key] = batch
+		time.AfterFunc(b.window, func() { b.flush(key) })
+	}
+	batch.requests = append(batch.requests, request)
+	b.mu.Unlock()
+
+	select {
+	case err := <-request.result:
+		return err
+	case <-ctx.Done():
+	}
+
+	if b.withdraw(key, batch, request) {
+		return fmt.Errorf("waiting for batched install of %s: %w", name, ctx.Err())
+	}
+	return <-request.result
+}
+
+// withdraw removes a request from a batch that has not started yet. It
+// reports false once the batch has been handed to flush.
+func (b *InstallBatcher) withdraw(key string, batch *installBatch, request *batchRequest) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending[key] != batch {
+		return false
+	}
+	for i, queued := range batch.requests {
+		if queued == request {
+			batch.requests = append(batch.requests[:i], batch.requests[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// flush installs every request queued for a manager and reports the outcome
+// back to each caller.
+func (b *InstallBatcher) flush(key string) {
+	b.mu.Lock()
+	batch := b.pending[key]
+	delete(b.pending, key)
+	b.mu.Unlock()
+
+	if batch == nil || len(batch.requests) == 0 {
+		return
+	}
+
+	ctx, cancel := batchContext(batch.requests)
+	defer cancel()
+
+	manager := batch.manager
+	managerName := manager.GetManagerName()
+
+	scope := b.openScope(ctx, manager)
+	defer scope.end()
+
+	// A lone request gains nothing from batching and keeps the adapter's
+	// per-package idempotency checks.
+	if len(batch.requests) == 1 {
+		request := batch.requests[0]
+		request.result <- scope.undo(ctx, installRequest(ctx, manager, request))
+		return
+	}
+
+	packages := make([]adapters.BatchPackage, 0, len(batch.requests))
+	for _, request := range batch.requests {
+		packages = append(packages, request.pkg)
+	}
+
+	ctx, span := telemetry.Start(ctx, "install_batch",
+		telemetry.AttrManager.String(managerName),
+		telemetry.AttrPackage.String(strings.Join(adapters.BatchPackageNames(packages), ",")),
+	)
+	defer span.End()
+
+	tflog.Debug(ctx, "Installing package batch", map[string]interface{}{
+		"manager":       managerName,
+		"package_count": len(packages),
+	})
+
+	batchErr := manager.(adapters.BatchInstaller).InstallBatch(batchAuditScope(ctx, batch.requests), packages)
+	if batchErr == nil {
+		for _, request := range batch.requests {
+			request.result <- nil
+		}
+		return
+	}
+
+	tflog.Debug(ctx, "Batch install failed, falling back to individual installs", map[string]interface{}{
+		"manager": managerName,
+		"error":   batchErr.Error(),
+	})
+	span.RecordError(batchErr)
+
+	// Undo the combined install before retrying, so each retry starts from
+	// the state its own rollback will return to
+	var rollbackErr *rollbackError
+	if errors.As(scope.undo(ctx, batchErr), &rollbackErr) {
+		tflog.Debug(ctx, "Rolled back failed package batch", map[string]interface{}{
+			"manager": managerName,
+			"removed": rollbackErr.report.Removed,
+		})
+	}
+
+	// Every caller has been cancelled; individual retries would only be cancelled too
+	if ctx.Err() != nil {
+		for _, request := range batch.requests {
+			request.result <- batchErr
+		}
+		return
+	}
+
+	for _, request := range batch.requests {
+		// Each package is installed a second time, on its own
+		trace.SpanFromContext(request.ctx).SetAttributes(telemetry.AttrRetries.Int(1))
+
+		requestScope := scope
+		if scope != nil {
+			if err := scope.retake(ctx); err != nil {
+				requestScope = nil
+			}
+		}
+		request.result <- requestScope.undo(ctx, installRequest(ctx, manager, request))
+	}
+}
+
+// installRequest installs a single queued request on the batch context.
+func installRequest(ctx context.Context, manager adapters.PackageManager, request *batchRequest) error {
+	return manager.InstallWithType(requestAuditScope(ctx, request), request.pkg.Name, request.pkg.Version, request.pkg.Type)
+}
+
+// openScope opens the scope covering one flush. It holds the manager's slot
+// and, when rollback is enabled, the installed packages before the flush.
+func (b *InstallBatcher) openScope(ctx context.Context, manager adapters.PackageManager) *installSnapshot {
+	scope, err := openRollbackScope(ctx, manager, b.rollback)
+	if err != nil {
+		tflog.Warn(ctx, "Could not record installed packages before batch, rollback disabled", map[string]interface{}{
+			"manager": manager.GetManagerName(),
+			"error":   err.Error(),
+		})
+		return nil
+	}
+	return scope
+}
+
+// requestAuditScope carries a caller's audit scope over to the detached batch context.
+func requestAuditScope(ctx context.Context, request *batchRequest) context.Context {
+	if scope, ok := audit.ScopeFrom(request.ctx); ok {
+		return audit.WithScope(ctx, scope)
+	}
+	return ctx
+}
+
+// batchAuditScope records a combined install against every resource in the batch.
+func batchAuditScope(ctx context.Context, requests []*batchRequest) context.Context {
+	var combined audit.Scope
+	var ids, names []string
+	for _, request := range requests {
+		scope, _ := audit.ScopeFrom(request.ctx)
+		if scope.ResourceID != "" {
+			ids = append(ids, scope.ResourceID)
+		}
+		names = append(names, request.pkg.Name)
+		combined.ResourceType = scope.ResourceType
+		combined.TerraformOperation = scope.TerraformOperation
+		combined.Manager = scope.Manager
+	}
+	combined.ResourceID = strings.Join(ids, ",")
+	combined.Package = strings.Join(names, ",")
+	return audit.WithScope(ctx, combined)
+}
+
+// batchContext derives the context used for a batch. It is detached from the
+// individual callers so one cancelled resource does not abort the others, but
+// it is cancelled once every caller has been cancelled, and it expires at the
+// latest deadline among the callers.
+func batchContext(requests []*batchRequest) (context.Context, context.CancelFunc) {
+	base := context.WithoutCancel(requests[0].ctx)
+
+	var latest time.Time
+	for _, request := range requests {
+		if deadline, ok := request.ctx.Deadline(); ok && deadline.After(latest) {
+			latest = deadline
+		}
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if latest.IsZero() {
+		ctx, cancel = context.WithTimeout(base, defaultBatchTimeout)
+	} else {
+		ctx, cancel = context.WithDeadline(base, latest)
+	}
+
+	remaining := int32(len(requests))
+	stops := make([]func() bool, 0, len(requests))
+	for _, request := range requests {
+		stops = append(stops, context.AfterFunc(request.ctx, func() {
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				cancel()
+			}
+		}))
+	}
+
+	return ctx, func() {
+		for _, stop := range stops {
+			stop()
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,272 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// installConcurrently installs each package from its own goroutine and returns
+// the per-package errors.
+func installConcurrently(batcher *InstallBatcher, manager adapters.PackageManager, names []string) map[string]error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := make(map[string]error, len(names))
+
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			err := batcher.Install(context.Background(), manager, name, "", adapters.PackageTypeAuto)
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func TestInstallBatcher_CoalescesConcurrentInstalls(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	batcher := NewInstallBatcher(50*time.Millisecond, false)
+
+	errs := installConcurrently(batcher, manager, []string{"curl", "git", "jq"})
+	for name, err := range errs {
+		assert.NoError(t, err, name)
+	}
+
+	require.Len(t, manager.batches, 1)
+	sort.Strings(manager.batches[0])
+	assert.Equal(t, []string{"curl", "git", "jq"}, manager.batches[0])
+	assert.Empty(t, manager.singleRuns)
+}
+
+func TestInstallBatcher_FallsBackToIndividualInstalls(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	manager.batchErr = fmt.Errorf("E: Unable to locate package nosuchpkg")
+	manager.installFunc = func(name, version string) error {
+		if name == "nosuchpkg" {
+			return fmt.Errorf("unable to locate package %s", name)
+		}
+		manager.installed[name] = version
+		return nil
+	}
+	batcher := NewInstallBatcher(50*time.Millisecond, false)
+
+	errs := installConcurrently(batcher, manager, []string{"curl", "nosuchpkg"})
+
+	assert.NoError(t, errs["curl"])
+	assert.Error(t, errs["nosuchpkg"])
+	assert.Len(t, manager.batches, 1)
+	assert.ElementsMatch(t, []string{"curl", "nosuchpkg"}, manager.singleRuns)
+}
+
+func TestInstallBatcher_FallbackRecordsRetryOnCallerSpan(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)).Tracer("test")
+	manager := newFakePackageManager(nil)
+	manager.batchErr = fmt.Errorf("E: Unable to locate package nosuchpkg")
+	batcher := NewInstallBatcher(50*time.Millisecond, false)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"curl", "jq"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			ctx, span := tracer.Start(context.Background(), name)
+			defer span.End()
+			assert.NoError(t, batcher.Install(ctx, manager, name, "", adapters.PackageTypeAuto))
+		}(name)
+	}
+	wg.Wait()
+
+	ended := spans.GetSpans()
+	require.Len(t, ended, 2)
+	for _, span := range ended {
+		assert.Contains(t, span.Attributes, telemetry.AttrRetries.Int(1), span.Name)
+	}
+}
+
+func TestInstallBatcher_SingleRequestUsesInstallWithType(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	batcher := NewInstallBatcher(10*time.Millisecond, false)
+
+	err := batcher.Install(context.Background(), manager, "curl", "", adapters.PackageTypeAuto)
+	assert.NoError(t, err)
+	assert.Empty(t, manager.batches)
+	assert.Equal(t, []string{"curl"}, manager.singleRuns)
+}
+
+func TestInstallBatcher_DisabledWindow(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	batcher := NewInstallBatcher(0, false)
+
+	errs := installConcurrently(batcher, manager, []string{"curl", "git"})
+	for name, err := range errs {
+		assert.NoError(t, err, name)
+	}
+
+	assert.Empty(t, manager.batches)
+	assert.Len(t, manager.singleRuns, 2)
+}
+
+func TestInstallBatcher_CallerCancellationWithdrawsRequest(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	batcher := NewInstallBatcher(50*time.Millisecond, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := batcher.Install(ctx, manager, "curl", "", adapters.PackageTypeAuto)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The withdrawn request must not be installed once the window closes
+	time.Sleep(150 * time.Millisecond)
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	assert.Empty(t, manager.singleRuns)
+}
+
+func TestInstallBatcher_CallerCancellationWaitsForRunningFlush(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	manager.installFunc = func(name, version string) error {
+		close(started)
+		<-release
+		manager.installed[name] = version
+		return nil
+	}
+	batcher := NewInstallBatcher(10*time.Millisecond, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- batcher.Install(ctx, manager, "curl", "", adapters.PackageTypeAuto) }()
+
+	<-started
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("Install returned while its batch was still installing")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-done)
+}
+
+func TestInstallBatcher_RollbackScopeCoversWholeFlush(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	batcher := NewInstallBatcher(50*time.Millisecond, true)
+
+	errs := installConcurrently(batcher, manager, []string{"curl", "git", "jq"})
+	for name, err := range errs {
+		assert.NoError(t, err, name)
+	}
+
+	require.Len(t, manager.batches, 1)
+	assert.Len(t, manager.batches[0], 3)
+}
+
+func TestInstallBatcher_RollbackUndoesOnlyTheFailedRetry(t *testing.T) {
+	manager := newFakePackageManager(map[string]string{"base": "1.0"})
+	manager.batchErr = fmt.Errorf("E: Sub-process /usr/bin/dpkg returned an error code (1)")
+	manager.installFunc = func(name, version string) error {
+		manager.installed[name] = version
+		if name == "broken" {
+			manager.installed["libbroken"] = "1.0"
+			return fmt.Errorf("dpkg: error processing package %s", name)
+		}
+		return nil
+	}
+	batcher := NewInstallBatcher(50*time.Millisecond, true)
+
+	errs := installConcurrently(batcher, manager, []string{"curl", "broken"})
+
+	assert.NoError(t, errs["curl"])
+	var rollbackErr *rollbackError
+	require.ErrorAs(t, errs["broken"], &rollbackErr)
+	assert.ElementsMatch(t, []string{"broken", "libbroken"}, rollbackErr.report.Removed)
+	assert.Contains(t, manager.installed, "curl")
+	assert.Contains(t, manager.installed, "base")
+	assert.NotContains(t, manager.installed, "libbroken")
+}
+
+func TestInstallBatcher_CancelsBatchWhenEveryCallerCancels(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	started := make(chan struct{})
+	var batchCtxErr error
+	manager.batchFunc = func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			batchCtxErr = ctx.Err()
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("batch was not cancelled")
+		}
+	}
+	batcher := NewInstallBatcher(50*time.Millisecond, false)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	cancels := make([]context.CancelFunc, 2)
+	for i, name := range []string{"curl", "jq"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancels[i] = cancel
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			errs[i] = batcher.Install(ctx, manager, name, "", adapters.PackageTypeAuto)
+		}(i, name)
+	}
+
+	<-started
+	for _, cancel := range cancels {
+		cancel()
+	}
+	wg.Wait()
+
+	assert.ErrorIs(t, batchCtxErr, context.Canceled)
+	for _, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	assert.Empty(t, manager.singleRuns, "cancelled batches must not be retried package by package")
+}
@@ -25,17 +25,17 @@ package provider
 import (
 	"context"
 	"fmt"
-	"runtime"
-	"strings"
-	"time"
+	"path"
+	"sort"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters/brew"
-	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -88,9 +88,12 @@ func (d *InstalledPackagesDataSource) Schema(
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to query. " +
-					"Valid values: 'auto', 'brew'. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
 					"Defaults to 'auto'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
 			},
 			"filter": schema.StringAttribute{
 				MarkdownDescription: "Optional filter pattern to match package names (supports glob patterns).",
@@ -162,29 +165,14 @@ func (d *InstalledPackagesDataSource) Read(
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	if managerName == managerAuto {
-		if runtime.GOOS != platformDarwin {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		managerName = "brew"
-	}
-
-	// Only support brew in Phase 2
-	if managerName != "brew" {
-		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", managerName),
-		)
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
 		return
 	}
 
 	// Get installed packages
-	packages, err := d.getInstalledBrewPackages(ctx, data.Filter.ValueString())
+	packages, err := d.getInstalledPackages(ctx, managerName, data.Filter.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to List Installed Packages",
@@ -216,68 +204,53 @@ func (d *InstalledPackagesDataSource) Read(
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (d *InstalledPackagesDataSource) getInstalledBrewPackages(
-	ctx context.Context, filter string) ([]InstalledPackageInfo, error) {
-	brewPath := d.providerData.Config.BrewPath.ValueString()
-	if brewPath == "" {
-		brewPath = "brew"
+func (d *InstalledPackagesDataSource) getInstalledPackages(
+	ctx context.Context, managerName, filter string) ([]InstalledPackageInfo, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get list of installed packages
-	args := []string{"list", "--versions"}
-	if filter != "" {
-		args = append(args, filter)
+	lister, ok := manager.(adapters.InstalledLister)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot list installed packages", manager.GetManagerName())
 	}
 
-	result, err := d.providerData.Executor.Run(ctx, brewPath, args, executor.ExecOpts{
-		Timeout: 5 * time.Minute,
-	})
-
-	if err != nil || result.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to list installed packages: exit code %d, error: %w", result.ExitCode, err)
+	// One listing covers every package, including pinned status and repository
+	installed, err := lister.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
-	packages := make([]InstalledPackageInfo, 0, len(lines))
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Parse line format: "package_name version1 version2..."
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
-		}
-
-		packageName := parts[0]
-		version := parts[1] // Use first version
+	return filterInstalledPackages(installed, filter)
+}
 
-		// Get additional info for this package (pinned status, repository)
-		manager := brew.NewBrewAdapter(d.providerData.Executor, brewPath)
-		info, err := manager.DetectInstalled(ctx, packageName)
-		if err != nil {
-			// Log the error but continue with basic info to maintain some fault tolerance
-			// In production, we want to be resilient to individual package info failures
-			// but in tests, the stricter assertions will catch systematic issues
-			packages = append(packages, InstalledPackageInfo{
-				Name:       types.StringValue(packageName),
-				Version:    types.StringValue(version),
-				Pinned:     types.BoolValue(false),
-				Repository: types.StringValue("unknown"), // Mark as unknown rather than empty
-			})
-			continue
+// filterInstalledPackages converts installed packages to data source entries,
+// keeping only names that match the optional glob filter.
+func filterInstalledPackages(installed []adapters.PackageInfo, filter string) ([]InstalledPackageInfo, error) {
+	packages := make([]InstalledPackageInfo, 0, len(installed))
+	for _, pkg := range installed {
+		if filter != "" {
+			matched, err := path.Match(filter, pkg.Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter pattern %q: %w", filter, err)
+			}
+			if !matched {
+				continue
+			}
 		}
 
 		packages = append(packages, InstalledPackageInfo{
-			Name:       types.StringValue(packageName),
-			Version:    types.StringValue(version),
-			Pinned:     types.BoolValue(info.Pinned),
-			Repository: types.StringValue(info.Repository),
+			Name:       types.StringValue(pkg.Name),
+			Version:    types.StringValue(pkg.Version),
+			Pinned:     types.BoolValue(pkg.Pinned),
+			Repository: types.StringValue(pkg.Repository),
 		})
 	}
 
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name.ValueString() < packages[j].Name.ValueString()
+	})
+
 	return packages, nil
 }
@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"sync"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// InventoryRegistry hands out one installed-package inventory per package
+// manager, so every resource and data source in a run shares the same snapshot
+// and a refresh of many packages lists the system only once.
+type InventoryRegistry struct {
+	mu          sync.Mutex
+	inventories map[string]*adapters.Inventory
+}
+
+// NewInventoryRegistry creates an empty inventory registry.
+func NewInventoryRegistry() *InventoryRegistry {
+	return &InventoryRegistry{
+		inventories: make(map[string]*adapters.Inventory),
+	}
+}
+
+// For returns the inventory for the named manager, creating it on first use.
+// A nil registry returns nil, which leaves adapters querying per package.
+func (r *InventoryRegistry) For(managerName string) *adapters.Inventory {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inventory, ok := r.inventories[managerName]
+	if !ok {
+		inventory = adapters.NewInventory()
+		r.inventories[managerName] = inventory
+	}
+	return inventory
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters/apt"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters/brew"
+	"github.com/jamesainslie/terraform-provider-package/internal/platform"
+)
+
+// validPackageManagers lists the package manager names accepted in configuration.
+var validPackageManagers = []string{managerAuto, "brew", "apt", "winget", "choco"}
+
+// hostPlatform identifies the operating system and distribution the provider
+// runs on. It only reads os-release, which does not change while the provider
+// runs, so it is detected once.
+var hostPlatform = sync.OnceValue(func() *platform.Platform {
+	return platform.NewDetector(nil).Identify()
+})
+
+// resolveManagerName resolves "auto" (or an empty name) to the package manager
+// for the platform the provider runs on.
+func resolveManagerName(managerName string) (string, error) {
+	if managerName != "" && managerName != managerAuto {
+		return managerName, nil
+	}
+	host := hostPlatform()
+	if manager := host.DefaultManager(); manager != "" {
+		return manager, nil
+	}
+	if host.OS == "linux" {
+		return "", fmt.Errorf("unsupported Linux distribution: %s. Supported: Debian and its derivatives (apt)",
+			host)
+	}
+	return "", fmt.Errorf("unsupported OS: %s. Supported: darwin (brew), linux (apt)", host.OS)
+}
+
+// newPackageManager creates the adapter for the named package manager,
+// resolving "auto" from the operating system, and verifies it is available.
+func newPackageManager(ctx context.Context, providerData *ProviderData, managerName string) (adapters.PackageManager, error) {
+	manager, err := buildPackageManager(providerData, managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if manager is available - this ensures we don't attempt operations with unavailable tools
+	if !manager.IsAvailable(ctx) {
+		return nil, fmt.Errorf("package manager %s is not available on this system", manager.GetManagerName())
+	}
+
+	return manager, nil
+}
+
+// buildPackageManager creates the adapter for the named package manager,
+// resolving "auto" from the operating system, without checking that it is
+// installed.
+func buildPackageManager(providerData *ProviderData, managerName string) (adapters.PackageManager, error) {
+	if providerData == nil {
+		return nil, fmt.Errorf("provider data is not configured")
+	}
+
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	var manager adapters.PackageManager
+	switch managerName {
+	case "brew":
+		manager = brew.NewBrewAdapter(providerData.Executor, providerData.Config.BrewPath.ValueString())
+	case "apt":
+		aptAdapter := apt.NewAptAdapter(providerData.Executor, providerData.Config.AptGetPath.ValueString(), "", "")
+		aptAdapter.SetLockTimeout(providerData.LockTimeout)
+		manager = aptAdapter
+	default:
+		return nil, fmt.Errorf("unsupported package manager: %s. Supported: brew, apt", managerName)
+	}
+
+	if backed, ok := manager.(adapters.InventoryBacked); ok {
+		backed.SetInventory(providerData.Inventories.For(managerName))
+	}
+
+	return manager, nil
+}
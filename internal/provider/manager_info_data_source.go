@@ -26,15 +26,15 @@ import (
 	"context"
 	"fmt"
 	"runtime"
-	"strings"
-	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters/brew"
-	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -82,9 +82,12 @@ func (d *ManagerInfoDataSource) Schema(
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to query. " +
-					"Valid values: 'auto', 'brew'. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
 					"Defaults to 'auto'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
 			},
 			"detected_manager": schema.StringAttribute{
 				MarkdownDescription: "The actual package manager that was detected or specified.",
@@ -150,25 +153,9 @@ func (d *ManagerInfoDataSource) Read(
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	detectedManager := managerName
-	if managerName == managerAuto {
-		if runtime.GOOS != platformDarwin {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		detectedManager = "brew"
-	}
-
-	// Only support brew in Phase 2
-	if detectedManager != "brew" {
-		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", detectedManager),
-		)
+	detectedManager, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
 		return
 	}
 
@@ -179,14 +166,8 @@ func (d *ManagerInfoDataSource) Read(
 	data.Platform = types.StringValue(runtime.GOOS)
 
 	// Get manager-specific information
-	switch detectedManager {
-	case "brew":
-		d.getBrewInfo(ctx, &data, resp)
-	default:
-		resp.Diagnostics.AddError(
-			"Unsupported Manager",
-			fmt.Sprintf("Manager %s is not implemented", detectedManager),
-		)
+	if err := d.getManagerInfo(ctx, &data, detectedManager); err != nil {
+		resp.Diagnostics.AddError("Unsupported Package Manager", err.Error())
 		return
 	}
 
@@ -194,10 +175,14 @@ func (d *ManagerInfoDataSource) Read(
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (d *ManagerInfoDataSource) getBrewInfo(
-	ctx context.Context, data *ManagerInfoDataSourceModel, _ *datasource.ReadResponse) {
-	brewPath := d.providerData.Config.BrewPath.ValueString()
-	manager := brew.NewBrewAdapter(d.providerData.Executor, brewPath)
+// getManagerInfo reports whether the manager is available and, if so, its
+// version and path. A version query failure leaves the version "unknown".
+func (d *ManagerInfoDataSource) getManagerInfo(
+	ctx context.Context, data *ManagerInfoDataSourceModel, managerName string) error {
+	manager, err := buildPackageManager(d.providerData, managerName)
+	if err != nil {
+		return err
+	}
 
 	// Check availability
 	available := manager.IsAvailable(ctx)
@@ -206,37 +191,26 @@ func (d *ManagerInfoDataSource) getBrewInfo(
 	if !available {
 		data.Version = types.StringValue("")
 		data.Path = types.StringValue("")
-		return
+		return nil
 	}
 
-	// Get version information
-	if brewPath == "" {
-		brewPath = "brew"
+	describer, ok := manager.(adapters.ManagerDescriber)
+	if !ok {
+		return fmt.Errorf("package manager %s cannot report its version", manager.GetManagerName())
 	}
-	data.Path = types.StringValue(brewPath)
 
-	// Get brew version
-	result, err := d.providerData.Executor.Run(ctx, brewPath, []string{"--version"}, executor.ExecOpts{
-		Timeout: 10 * time.Second,
-	})
-
-	if err != nil || result.ExitCode != 0 {
+	details, err := describer.Describe(ctx)
+	if err != nil {
+		tflog.Debug(ctx, "Failed to query package manager version", map[string]interface{}{
+			"manager": managerName,
+			"error":   err.Error(),
+		})
 		data.Version = types.StringValue("unknown")
-		return
+		data.Path = types.StringValue("")
+		return nil
 	}
 
-	// Parse version from output (first line usually contains version)
-	lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
-	if len(lines) > 0 {
-		versionLine := strings.TrimSpace(lines[0])
-		// Extract version from "Homebrew X.Y.Z" format
-		if strings.HasPrefix(versionLine, "Homebrew ") {
-			version := strings.TrimPrefix(versionLine, "Homebrew ")
-			data.Version = types.StringValue(version)
-		} else {
-			data.Version = types.StringValue(versionLine)
-		}
-	} else {
-		data.Version = types.StringValue("unknown")
-	}
+	data.Version = types.StringValue(details.Version)
+	data.Path = types.StringValue(details.Path)
+	return nil
 }
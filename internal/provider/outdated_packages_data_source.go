@@ -25,16 +25,16 @@ package provider
 import (
 	"context"
 	"fmt"
-	"runtime"
-	"strings"
-	"time"
+	"sort"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -63,6 +63,8 @@ type OutdatedPackageInfo struct {
 	CurrentVersion types.String `tfsdk:"current_version"`
 	LatestVersion  types.String `tfsdk:"latest_version"`
 	Pinned         types.Bool   `tfsdk:"pinned"`
+	Security       types.Bool   `tfsdk:"security"`
+	Origin         types.String `tfsdk:"origin"`
 }
 
 // Metadata returns the data source type name.
@@ -86,9 +88,12 @@ func (d *OutdatedPackagesDataSource) Schema(
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to query. " +
-					"Valid values: 'auto', 'brew'. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
 					"Defaults to 'auto'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
 			},
 			"packages": schema.ListNestedAttribute{
 				MarkdownDescription: "List of packages with available updates.",
@@ -108,8 +113,19 @@ func (d *OutdatedPackagesDataSource) Schema(
 							Computed:            true,
 						},
 						"pinned": schema.BoolAttribute{
-							MarkdownDescription: "Whether the package is pinned (preventing updates).",
-							Computed:            true,
+							MarkdownDescription: "Whether the package is pinned (preventing updates). " +
+								"For APT, whether it is held with `apt-mark hold`.",
+							Computed: true,
+						},
+						"security": schema.BoolAttribute{
+							MarkdownDescription: "Whether the update is published by a security archive, such as the " +
+								"`-security` pocket of Debian and Ubuntu. Always false for Homebrew.",
+							Computed: true,
+						},
+						"origin": schema.StringAttribute{
+							MarkdownDescription: "Where the update comes from: the comma-separated APT suites " +
+								"(e.g. 'jammy-updates,jammy-security'), or the Homebrew tap.",
+							Computed: true,
 						},
 					},
 				},
@@ -156,29 +172,14 @@ func (d *OutdatedPackagesDataSource) Read(
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	if managerName == "auto" {
-		if runtime.GOOS != "darwin" {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		managerName = "brew"
-	}
-
-	// Only support brew in Phase 2
-	if managerName != "brew" {
-		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", managerName),
-		)
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
 		return
 	}
 
 	// Get outdated packages
-	packages, err := d.getOutdatedBrewPackages(ctx)
+	packages, err := d.getOutdatedPackages(ctx, managerName)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to List Outdated Packages",
@@ -198,6 +199,8 @@ func (d *OutdatedPackagesDataSource) Read(
 			"current_version": types.StringType,
 			"latest_version":  types.StringType,
 			"pinned":          types.BoolType,
+			"security":        types.BoolType,
+			"origin":          types.StringType,
 		},
 	}, packages)
 	resp.Diagnostics.Append(diags...)
@@ -210,57 +213,43 @@ func (d *OutdatedPackagesDataSource) Read(
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (d *OutdatedPackagesDataSource) getOutdatedBrewPackages(ctx context.Context) ([]OutdatedPackageInfo, error) {
-	brewPath := d.providerData.Config.BrewPath.ValueString()
-	if brewPath == "" {
-		brewPath = "brew"
+func (d *OutdatedPackagesDataSource) getOutdatedPackages(ctx context.Context, managerName string) ([]OutdatedPackageInfo, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get list of outdated packages
-	result, err := d.providerData.Executor.Run(ctx, brewPath, []string{"outdated", "--verbose"}, executor.ExecOpts{
-		Timeout: 5 * time.Minute,
-	})
-
-	if err != nil || result.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to list outdated packages: exit code %d, error: %w", result.ExitCode, err)
+	lister, ok := manager.(adapters.UpdateLister)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot list available updates", manager.GetManagerName())
 	}
 
-	var packages []OutdatedPackageInfo
-	lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Parse line format: "package_name (current_version) < latest_version"
-		// Example: "git (2.41.0) < 2.42.0"
-		if strings.Contains(line, " < ") {
-			parts := strings.Split(line, " < ")
-			if len(parts) != 2 {
-				continue
-			}
-
-			leftPart := strings.TrimSpace(parts[0])
-			latestVersion := strings.TrimSpace(parts[1])
+	updates, err := lister.ListUpdates(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-			// Parse package name and current version from left part
-			// Format: "package_name (current_version)"
-			if strings.Contains(leftPart, " (") && strings.HasSuffix(leftPart, ")") {
-				nameEnd := strings.LastIndex(leftPart, " (")
-				packageName := leftPart[:nameEnd]
-				currentVersion := strings.TrimSuffix(leftPart[nameEnd+2:], ")")
+	return outdatedPackageInfos(updates), nil
+}
 
-				packages = append(packages, OutdatedPackageInfo{
-					Name:           types.StringValue(packageName),
-					CurrentVersion: types.StringValue(currentVersion),
-					LatestVersion:  types.StringValue(latestVersion),
-					Pinned:         types.BoolValue(false), // Pin status requires individual package queries
-				})
-			}
-		}
+// outdatedPackageInfos converts available updates to the packages attribute,
+// sorted by name.
+func outdatedPackageInfos(updates []adapters.AvailableUpdate) []OutdatedPackageInfo {
+	packages := make([]OutdatedPackageInfo, 0, len(updates))
+	for _, update := range updates {
+		packages = append(packages, OutdatedPackageInfo{
+			Name:           types.StringValue(update.Name),
+			CurrentVersion: types.StringValue(update.CurrentVersion),
+			LatestVersion:  types.StringValue(update.LatestVersion),
+			Pinned:         types.BoolValue(update.Pinned),
+			Security:       types.BoolValue(update.Security),
+			Origin:         types.StringValue(update.Origin),
+		})
 	}
 
-	return packages, nil
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name.ValueString() < packages[j].Name.ValueString()
+	})
+
+	return packages
 }
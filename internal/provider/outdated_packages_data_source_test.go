@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestOutdatedPackageInfos(t *testing.T) {
+	packages := outdatedPackageInfos([]adapters.AvailableUpdate{
+		{Name: "nginx", CurrentVersion: "1.18.0-6ubuntu14.3", LatestVersion: "1.18.0-6ubuntu14.4", Pinned: true,
+			Origin: "jammy-updates"},
+		{Name: "curl", CurrentVersion: "7.81.0-1ubuntu1.15", LatestVersion: "7.81.0-1ubuntu1.16", Security: true,
+			Origin: "jammy-updates,jammy-security"},
+	})
+
+	assert.Equal(t, []OutdatedPackageInfo{
+		{
+			Name:           types.StringValue("curl"),
+			CurrentVersion: types.StringValue("7.81.0-1ubuntu1.15"),
+			LatestVersion:  types.StringValue("7.81.0-1ubuntu1.16"),
+			Pinned:         types.BoolValue(false),
+			Security:       types.BoolValue(true),
+			Origin:         types.StringValue("jammy-updates,jammy-security"),
+		},
+		{
+			Name:           types.StringValue("nginx"),
+			CurrentVersion: types.StringValue("1.18.0-6ubuntu14.3"),
+			LatestVersion:  types.StringValue("1.18.0-6ubuntu14.4"),
+			Pinned:         types.BoolValue(true),
+			Security:       types.BoolValue(false),
+			Origin:         types.StringValue("jammy-updates"),
+		},
+	}, packages)
+	assert.NotNil(t, outdatedPackageInfos(nil))
+}
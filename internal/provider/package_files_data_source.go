@@ -0,0 +1,269 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// File types reported by fileType and accepted by the file_type filter.
+const (
+	fileTypeAll    = "all"
+	fileTypeBinary = "binary"
+	fileTypeConfig = "config"
+	fileTypeDoc    = "doc"
+	fileTypeOther  = "other"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PackageFilesDataSource{}
+
+// NewPackageFilesDataSource creates a new package files data source.
+func NewPackageFilesDataSource() datasource.DataSource {
+	return &PackageFilesDataSource{}
+}
+
+// PackageFilesDataSource defines the data source implementation.
+type PackageFilesDataSource struct {
+	providerData *ProviderData
+}
+
+// PackageFilesDataSourceModel describes the data source data model.
+type PackageFilesDataSourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	Manager            types.String `tfsdk:"manager"`
+	FileType           types.String `tfsdk:"file_type"`
+	IncludeDirectories types.Bool   `tfsdk:"include_directories"`
+	Files              types.List   `tfsdk:"files"`
+}
+
+// Metadata returns the data source type name.
+// Metadata returns the data source type name.
+func (d *PackageFilesDataSource) Metadata(
+	_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_package_files"
+}
+
+// Schema defines the data source schema.
+// Schema defines the data source schema.
+func (d *PackageFilesDataSource) Schema(
+	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the files an installed package placed on the system, " +
+			"from `dpkg-query --listfiles` on APT systems and `brew list` on macOS.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Data source identifier.",
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the installed package to list files for.",
+				Required:            true,
+			},
+			"manager": schema.StringAttribute{
+				MarkdownDescription: "Package manager to query. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
+					"Defaults to 'auto'.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
+			},
+			"file_type": schema.StringAttribute{
+				MarkdownDescription: "Only list files of this type, judged by their location: " +
+					"'binary' for files in a bin or sbin directory or an app bundle's Contents/MacOS, " +
+					"'config' for files under an etc directory, and 'doc' for files under share/doc, " +
+					"share/man or share/info. Valid values: 'all', 'binary', 'config', 'doc'. " +
+					"Defaults to 'all'.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(fileTypeAll, fileTypeBinary, fileTypeConfig, fileTypeDoc),
+				},
+			},
+			"include_directories": schema.BoolAttribute{
+				MarkdownDescription: "Whether to list the directories the package owns as well as its files. " +
+					"Defaults to false.",
+				Optional: true,
+			},
+			"files": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Absolute paths of the matching files, in the order the package manager lists them.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure configures the data source with provider data.
+// Configure configures the data source with provider data.
+func (d *PackageFilesDataSource) Configure(
+	_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *PackageFilesDataSource) Read(
+	ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PackageFilesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Determine package manager
+	managerName := managerAuto
+	if !data.Manager.IsNull() {
+		managerName = data.Manager.ValueString()
+	}
+
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
+		return
+	}
+
+	packageName := data.Name.ValueString()
+	files, err := d.listFiles(ctx, managerName, packageName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to List Package Files",
+			fmt.Sprintf("Failed to list files of package %s: %v", packageName, err),
+		)
+		return
+	}
+
+	fileTypeFilter := fileTypeAll
+	if !data.FileType.IsNull() {
+		fileTypeFilter = data.FileType.ValueString()
+	}
+	files = filterFiles(files, fileTypeFilter, data.IncludeDirectories.ValueBool(), isDirectory)
+
+	// Set computed values
+	data.ID = types.StringValue(fmt.Sprintf("%s:files:%s", managerName, packageName))
+	data.Manager = types.StringValue(managerName)
+
+	filesList, diags := types.ListValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Files = filesList
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *PackageFilesDataSource) listFiles(
+	ctx context.Context, managerName, packageName string) ([]string, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	lister, ok := manager.(adapters.FileLister)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot list package files", manager.GetManagerName())
+	}
+	return lister.Files(ctx, packageName)
+}
+
+// filterFiles keeps the files of the given type, dropping directories unless
+// includeDirectories is set.
+func filterFiles(files []string, fileTypeFilter string, includeDirectories bool,
+	isDir func(string) bool) []string {
+	filtered := []string{}
+	for _, file := range files {
+		if !includeDirectories && isDir(file) {
+			continue
+		}
+		if fileTypeFilter != fileTypeAll && fileType(file) != fileTypeFilter {
+			continue
+		}
+		filtered = append(filtered, strings.TrimSuffix(file, "/"))
+	}
+	return filtered
+}
+
+// isDirectory reports whether path is a directory, without following
+// symbolic links. 'brew list' marks the directories it summarizes with a
+// trailing slash.
+func isDirectory(path string) bool {
+	if strings.HasSuffix(path, "/") {
+		return true
+	}
+	info, err := os.Lstat(path)
+	return err == nil && info.IsDir()
+}
+
+// fileType classifies a path by where it is installed: 'doc' under share/doc,
+// share/man or share/info, 'config' under an etc directory, 'binary' in a bin
+// or sbin directory or an app bundle's Contents/MacOS, and 'other' otherwise.
+func fileType(path string) string {
+	slashed := filepath.ToSlash(path)
+	for _, dir := range []string{"/share/doc/", "/share/man/", "/share/info/"} {
+		if strings.Contains(slashed, dir) {
+			return fileTypeDoc
+		}
+	}
+	if strings.Contains(slashed, "/etc/") {
+		return fileTypeConfig
+	}
+	switch filepath.Base(filepath.Dir(path)) {
+	case "bin", "sbin":
+		return fileTypeBinary
+	}
+	if strings.Contains(slashed, ".app/Contents/MacOS/") {
+		return fileTypeBinary
+	}
+	return fileTypeOther
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileType(t *testing.T) {
+	for path, want := range map[string]string{
+		"/usr/bin/curl":                                    fileTypeBinary,
+		"/usr/sbin/nginx":                                  fileTypeBinary,
+		"/opt/homebrew/Cellar/jq/1.7.1/bin/jq":             fileTypeBinary,
+		"/Applications/Firefox.app/Contents/MacOS/firefox": fileTypeBinary,
+		"/etc/nginx/nginx.conf":                            fileTypeConfig,
+		"/opt/homebrew/etc/nginx/nginx.conf":               fileTypeConfig,
+		"/usr/share/doc/curl/copyright":                    fileTypeDoc,
+		"/usr/share/man/man1/curl.1.gz":                    fileTypeDoc,
+		"/opt/homebrew/Cellar/jq/1.7.1/share/info/jq":      fileTypeDoc,
+		"/usr/lib/x86_64-linux-gnu/libcurl.so.4":           fileTypeOther,
+		"/usr/bin":                                         fileTypeOther,
+	} {
+		assert.Equal(t, want, fileType(path), path)
+	}
+}
+
+func TestFilterFiles(t *testing.T) {
+	files := []string{
+		"/usr", "/usr/bin", "/usr/bin/curl", "/etc/curlrc", "/usr/share/doc/curl/", "/usr/share/doc/curl/copyright",
+	}
+	isDir := func(path string) bool {
+		return path == "/usr" || path == "/usr/bin" || path == "/usr/share/doc/curl/"
+	}
+
+	assert.Equal(t, []string{"/usr/bin/curl", "/etc/curlrc", "/usr/share/doc/curl/copyright"},
+		filterFiles(files, fileTypeAll, false, isDir))
+	assert.Equal(t, []string{"/usr/bin/curl"}, filterFiles(files, fileTypeBinary, false, isDir))
+	assert.Equal(t, []string{"/etc/curlrc"}, filterFiles(files, fileTypeConfig, false, isDir))
+	assert.Equal(t, []string{"/usr/share/doc/curl", "/usr/share/doc/curl/copyright"},
+		filterFiles(files, fileTypeDoc, true, isDir))
+	assert.Equal(t, []string{}, filterFiles(nil, fileTypeAll, true, isDir))
+}
+
+func TestIsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0o600))
+	link := filepath.Join(dir, "link")
+	assert.NoError(t, os.Symlink(dir, link))
+
+	assert.True(t, isDirectory(dir))
+	assert.True(t, isDirectory("/opt/homebrew/Cellar/jq/1.7.1/share/doc/jq/"))
+	assert.False(t, isDirectory(file))
+	assert.False(t, isDirectory(link))
+	assert.False(t, isDirectory(filepath.Join(dir, "missing")))
+}
@@ -25,16 +25,11 @@ package provider
 import (
 	"context"
 	"fmt"
-	"runtime"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters/apt"
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters/brew"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -152,50 +147,20 @@ func (d *PackageInfoDataSource) Read(
 	}
 
 	// Determine package manager
-	managerName := "auto"
+	managerName := managerAuto
 	if !data.Manager.IsNull() {
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS
-	if managerName == "auto" {
-		switch runtime.GOOS {
-		case "darwin":
-			managerName = "brew"
-		case "linux":
-			managerName = "apt"
-		default:
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) and Linux are supported, got: %s", runtime.GOOS),
-			)
-			return
-		}
-	}
-
-	// Create manager based on name
-	var manager adapters.PackageManager
-	switch managerName {
-	case "brew":
-		brewPath := d.providerData.Config.BrewPath.ValueString()
-		manager = brew.NewBrewAdapter(d.providerData.Executor, brewPath)
-	case "apt":
-		aptPath := d.providerData.Config.AptGetPath.ValueString()
-		manager = apt.NewAptAdapter(d.providerData.Executor, aptPath, "", aptPath)
-	default:
-		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' and 'apt' managers are supported, got: %s", managerName),
-		)
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
 		return
 	}
 
-	// Check if manager is available
-	if !manager.IsAvailable(ctx) {
-		resp.Diagnostics.AddError(
-			"Package Manager Not Available",
-			fmt.Sprintf("Package manager %s is not available on this system", managerName),
-		)
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
 		return
 	}
 
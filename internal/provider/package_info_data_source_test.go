@@ -42,7 +42,7 @@ func TestPackageInfoDataSource_AptLinux(t *testing.T) {
 				  manager = "choco"
 				}
 				`,
-				ExpectError: regexp.MustCompile("Unsupported Package Manager"),
+				ExpectError: regexp.MustCompile("unsupported package manager: choco"),
 			},
 		},
 	})
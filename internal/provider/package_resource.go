@@ -24,12 +24,15 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -38,17 +41,26 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters/apt"
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters/brew"
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
 )
 
+// packageErrorAttributes points package operation failures at the attribute to fix.
+var packageErrorAttributes = ErrorAttributes{
+	adapters.ErrNotFound:           path.Root("name"),
+	adapters.ErrVersionUnavailable: path.Root("version"),
+	adapters.ErrInterrupted:        path.Root("timeouts"),
+}
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &PackageResource{}
 var _ resource.ResourceWithImportState = &PackageResource{}
+var _ resource.ResourceWithValidateConfig = &PackageResource{}
 
 // NewPackageResource creates a new package resource.
 func NewPackageResource() resource.Resource {
@@ -67,6 +79,7 @@ type PackageResourceModel struct {
 	State              types.String `tfsdk:"state"`
 	Version            types.String `tfsdk:"version"`
 	VersionActual      types.String `tfsdk:"version_actual"`
+	VersionTarget      types.String `tfsdk:"version_target"`
 	Pin                types.Bool   `tfsdk:"pin"`
 	Managers           types.List   `tfsdk:"managers"`
 	Aliases            types.Map    `tfsdk:"aliases"`
@@ -77,6 +90,11 @@ type PackageResourceModel struct {
 	InstallPriority    types.Int64  `tfsdk:"install_priority"`
 	DependencyStrategy types.String `tfsdk:"dependency_strategy"`
 
+	// Collateral Change Preview
+	AllowCollateralRemoval types.Bool `tfsdk:"allow_collateral_removal"`
+	CollateralInstalls     types.List `tfsdk:"collateral_installs"`
+	CollateralRemovals     types.List `tfsdk:"collateral_removals"`
+
 	// Enhanced State Tracking
 	TrackMetadata      types.Bool   `tfsdk:"track_metadata"`
 	TrackDependencies  types.Bool   `tfsdk:"track_dependencies"`
@@ -84,12 +102,14 @@ type PackageResourceModel struct {
 	InstallationSource types.String `tfsdk:"installation_source"`
 	DependencyTree     types.Map    `tfsdk:"dependency_tree"`
 	LastAccess         types.String `tfsdk:"last_access"`
+	Metadata           types.Map    `tfsdk:"metadata"`
 
 	// Timeouts
 	Timeouts *PackageResourceTimeouts `tfsdk:"timeouts"`
 
 	// Drift Detection Configuration
 	DriftDetection *DriftDetectionConfig `tfsdk:"drift_detection"`
+	DriftFindings  types.List            `tfsdk:"drift_findings"`
 }
 
 // PackageResourceTimeouts defines timeout configurations for package operations.
@@ -136,16 +156,11 @@ type DriftInfo struct {
 	HasDependencyDrift  bool
 	CurrentVersion      string
 	DesiredVersion      string
+	IntegrityIssues     []string
+	MissingDependencies []string
 	RemediationStrategy string
 }
 
-// IntegrityDriftInfo contains information about integrity drift.
-type IntegrityDriftInfo struct {
-	HasDrift         bool
-	ExpectedChecksum string
-	ActualChecksum   string
-}
-
 // Metadata returns the resource type name.
 func (r *PackageResource) Metadata(
 	_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -173,18 +188,26 @@ func (r *PackageResource) Schema(
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"state": schema.StringAttribute{
 				MarkdownDescription: "Desired state of the package. " +
-					"Valid values: 'present', 'absent'. " +
+					"Valid values: 'present', 'absent', 'latest'. " +
+					"'latest' upgrades the package whenever the package manager offers a newer version. " +
 					"Defaults to 'present'.",
 				Optional: true,
 				Computed: true,
-				Default:  stringdefault.StaticString("present"),
+				Default:  stringdefault.StaticString(statePresent),
+				Validators: []validator.String{
+					stringvalidator.OneOf(statePresent, stateAbsent, stateLatest),
+				},
 			},
 			"version": schema.StringAttribute{
-				MarkdownDescription: "Desired version of the package. Supports exact versions, semantic version ranges, or glob patterns depending on the package manager. " +
-					"Leave empty for latest version.",
+				MarkdownDescription: "Desired version of the package: an exact version or a glob pattern such as '1.7*', " +
+					"which is matched against the versions the package manager offers. " +
+					"Leave empty to accept any installed version; new installs get the package manager's candidate version.",
 				Optional: true,
 			},
 			"version_actual": schema.StringAttribute{
@@ -192,6 +215,12 @@ func (r *PackageResource) Schema(
 					"This is computed and shows the real installed version.",
 				Computed: true,
 			},
+			"version_target": schema.StringAttribute{
+				MarkdownDescription: "Version the package will be at after the next apply, resolved from the package manager " +
+					"during plan (e.g., the `apt-cache policy` candidate). The resource is planned for update when this " +
+					"differs from `version_actual`. Unknown in the plan if it cannot be resolved before apply.",
+				Computed: true,
+			},
 			"pin": schema.BoolAttribute{
 				MarkdownDescription: "Whether to pin/hold the package at the current version to prevent upgrades. " +
 					"Defaults to false.",
@@ -205,15 +234,24 @@ func (r *PackageResource) Schema(
 					"Valid values: 'auto', 'brew', 'apt', 'winget', 'choco'. " +
 					"Defaults to ['auto'] which auto-detects based on OS.",
 				Optional: true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(validPackageManagers...)),
+				},
 			},
 			"aliases": schema.MapAttribute{
 				ElementType: types.StringType,
 				MarkdownDescription: "Platform-specific package name overrides. Keys: 'darwin', 'linux', 'windows'. " +
 					"Values: platform-specific package names.",
 				Optional: true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.OneOf("darwin", "linux", "windows")),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
 			},
 			"reinstall_on_drift": schema.BoolAttribute{
-				MarkdownDescription: "If true, reinstall the package when version drift is detected. If false, only update version_actual. " +
+				MarkdownDescription: "If true, drift found on refresh is remediated as configured by drift_detection.remediation. " +
+					"If false, drift is only reported, as if remediation were 'warn'. " +
 					"Defaults to true.",
 				Optional: true,
 				Computed: true,
@@ -234,6 +272,9 @@ func (r *PackageResource) Schema(
 				Optional: true,
 				Computed: true,
 				Default:  stringdefault.StaticString("auto"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("auto", "formula", "cask"),
+				},
 			},
 			"dependencies": schema.ListAttribute{
 				ElementType: types.StringType,
@@ -255,28 +296,54 @@ func (r *PackageResource) Schema(
 				Optional: true,
 				Computed: true,
 				Default:  stringdefault.StaticString("install_missing"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("install_missing", "require_existing", "ignore"),
+				},
 			},
 			"track_metadata": schema.BoolAttribute{
-				MarkdownDescription: "Whether to track enhanced package metadata. " +
+				MarkdownDescription: "Whether to track enhanced package metadata in the metadata attribute. " +
 					"Defaults to false.",
 				Optional: true,
 				Computed: true,
 				Default:  booldefault.StaticBool(false),
 			},
 			"track_dependencies": schema.BoolAttribute{
-				MarkdownDescription: "Whether to track package dependency relationships. " +
+				MarkdownDescription: "Whether to track the installed dependencies of the package in dependency_tree. " +
 					"Defaults to false.",
 				Optional: true,
 				Computed: true,
 				Default:  booldefault.StaticBool(false),
 			},
 			"track_usage": schema.BoolAttribute{
-				MarkdownDescription: "Whether to track package usage statistics. " +
+				MarkdownDescription: "Whether to track when the executables of the package were last used in last_access. " +
 					"Defaults to false.",
 				Optional: true,
 				Computed: true,
 				Default:  booldefault.StaticBool(false),
 			},
+			"allow_collateral_removal": schema.BoolAttribute{
+				MarkdownDescription: "Whether the plan may remove packages other than this one, e.g. when an APT install " +
+					"conflicts with an installed package. When false, the plan fails if it would remove a package that was " +
+					"installed explicitly (for APT, one listed by `apt-mark showmanual`) rather than only as a dependency. " +
+					"Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"collateral_installs": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Other packages, as 'name=version', that the planned change will install or upgrade. " +
+					"Resolved during plan by simulating the change (`apt-get -s`); empty for managers that cannot simulate, " +
+					"and once the change has been applied and refreshed.",
+				Computed: true,
+			},
+			"collateral_removals": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Other packages, as 'name=version', that the planned change will remove. " +
+					"Resolved during plan by simulating the change (`apt-get -s`); empty for managers that cannot simulate, " +
+					"and once the change has been applied and refreshed.",
+				Computed: true,
+			},
 			"installation_source": schema.StringAttribute{
 				MarkdownDescription: "Source from which the package was installed. " +
 					"Computed automatically.",
@@ -284,15 +351,30 @@ func (r *PackageResource) Schema(
 			},
 			"dependency_tree": schema.MapAttribute{
 				ElementType: types.StringType,
-				MarkdownDescription: "Map of dependencies and their versions. " +
+				MarkdownDescription: "Map of the installed direct and transitive dependencies of the package to their " +
+					"versions, from `apt-cache depends` or `brew deps --tree`. " +
 					"Computed when track_dependencies is enabled.",
 				Computed: true,
 			},
+			"drift_findings": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Drift found by the last refresh, one finding per entry, prefixed with its kind " +
+					"('version: ', 'integrity: ' or 'dependency: '). Cleared when the drift is remediated.",
+				Computed: true,
+			},
 			"last_access": schema.StringAttribute{
-				MarkdownDescription: "Timestamp of last package access. " +
+				MarkdownDescription: "RFC 3339 timestamp of the last access to any executable of the package, from file " +
+					"access times, which filesystems mounted with `relatime` update at most daily. " +
 					"Computed when track_usage is enabled.",
 				Computed: true,
 			},
+			"metadata": schema.MapAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Package metadata with the keys 'description', 'homepage', 'license' and " +
+					"'installed_size' (in bytes). Values the package manager does not report are empty. " +
+					"Computed when track_metadata is enabled.",
+				Computed: true,
+			},
 		},
 
 		Blocks: map[string]schema.Block{
@@ -302,27 +384,32 @@ func (r *PackageResource) Schema(
 					"create": schema.StringAttribute{
 						MarkdownDescription: "Timeout for package installation. " +
 							"Defaults to '15m'.",
-						Optional: true,
+						Optional:   true,
+						Validators: []validator.String{positiveDuration()},
 					},
 					"read": schema.StringAttribute{
 						MarkdownDescription: "Timeout for reading package information. " +
 							"Defaults to '2m'.",
-						Optional: true,
+						Optional:   true,
+						Validators: []validator.String{positiveDuration()},
 					},
 					"update": schema.StringAttribute{
 						MarkdownDescription: "Timeout for package updates. " +
 							"Defaults to '15m'.",
-						Optional: true,
+						Optional:   true,
+						Validators: []validator.String{positiveDuration()},
 					},
 					"delete": schema.StringAttribute{
 						MarkdownDescription: "Timeout for package removal. " +
 							"Defaults to '10m'.",
-						Optional: true,
+						Optional:   true,
+						Validators: []validator.String{positiveDuration()},
 					},
 				},
 			},
 			"drift_detection": schema.SingleNestedBlock{
-				MarkdownDescription: "Configuration for drift detection and remediation.",
+				MarkdownDescription: "Configuration for drift detection and remediation. Without this block only version " +
+					"drift is checked.",
 				Attributes: map[string]schema.Attribute{
 					"check_version": schema.BoolAttribute{
 						MarkdownDescription: "Whether to check for version drift. " +
@@ -330,20 +417,27 @@ func (r *PackageResource) Schema(
 						Optional: true,
 					},
 					"check_integrity": schema.BoolAttribute{
-						MarkdownDescription: "Whether to check package file integrity. " +
-							"Defaults to true.",
+						MarkdownDescription: "Whether to check package file integrity (`dpkg --verify` for APT, the keg's " +
+							"install receipt for Homebrew formulae). This reads every file of the package on each refresh, so it " +
+							"is off unless enabled. Defaults to false.",
 						Optional: true,
 					},
 					"check_dependencies": schema.BoolAttribute{
-						MarkdownDescription: "Whether to check dependency drift. " +
+						MarkdownDescription: "Whether to check that the dependencies of the package are installed. " +
 							"Defaults to true.",
 						Optional: true,
 					},
 					"remediation": schema.StringAttribute{
-						MarkdownDescription: "Remediation strategy for detected drift. " +
-							"Valid values: 'auto', 'manual', 'warn'. " +
-							"Defaults to 'auto'.",
+						MarkdownDescription: "Remediation applied during the next plan and apply when drift is detected. " +
+							"Valid values: 'reinstall' (reinstall or change the version of the package), 'warn' (report the " +
+							"drift as a plan warning), 'fail' (fail the plan). 'auto' and 'manual' are accepted as aliases of " +
+							"'reinstall' and 'warn'. " +
+							"Defaults to 'reinstall'.",
 						Optional: true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(remediationReinstall, remediationWarn, remediationFail,
+								remediationAuto, remediationManual),
+						},
 					},
 				},
 			},
@@ -351,6 +445,79 @@ func (r *PackageResource) Schema(
 	}
 }
 
+// ValidateConfig checks attribute combinations that the schema validators cannot express.
+func (r *PackageResource) ValidateConfig(
+	ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data PackageResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.State.ValueString() == stateLatest {
+		if !data.Version.IsNull() && !data.Version.IsUnknown() && data.Version.ValueString() != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("version"),
+				"Invalid Attribute Combination",
+				"version cannot be set when state is 'latest'. Use state = \"present\" to install a specific version.",
+			)
+		}
+		if data.Pin.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("pin"),
+				"Invalid Attribute Combination",
+				"pin cannot be true when state is 'latest': a held package cannot be upgraded.",
+			)
+		}
+	}
+
+	if data.State.ValueString() == stateAbsent {
+		if data.Pin.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("pin"),
+				"Invalid Attribute Combination",
+				"pin cannot be true when state is 'absent': a package that is removed cannot be held at its version.",
+			)
+		}
+		if data.HoldDependencies.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("hold_dependencies"),
+				"Invalid Attribute Combination",
+				"hold_dependencies cannot be true when state is 'absent'.",
+			)
+		}
+		if !data.Version.IsNull() && !data.Version.IsUnknown() && data.Version.ValueString() != "" {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("version"),
+				"Ignored Attribute",
+				"version is ignored when state is 'absent'.",
+			)
+		}
+	}
+
+	packageType := data.PackageType.ValueString()
+	if packageType != "formula" && packageType != "cask" {
+		return
+	}
+	managerName := managerAuto
+	if !data.Managers.IsUnknown() && len(data.Managers.Elements()) > 0 {
+		first, ok := data.Managers.Elements()[0].(types.String)
+		if !ok || first.IsUnknown() {
+			return
+		}
+		managerName = first.ValueString()
+	}
+	if managerName, err := resolveManagerName(managerName); err == nil && managerName != managerBrew {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("package_type"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("package_type '%s' is only supported by Homebrew, but this package is managed by %s. "+
+				"Remove package_type or set it to 'auto'.", packageType, managerName),
+		)
+	}
+}
+
 // Configure configures the resource with provider data.
 func (r *PackageResource) Configure(
 	_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -375,6 +542,9 @@ func (r *PackageResource) Configure(
 // Create creates a new resource.
 func (r *PackageResource) Create(
 	ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_package", "create")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data PackageResourceModel
 
 	// DEBUG: Log resource creation start
@@ -410,6 +580,10 @@ func (r *PackageResource) Create(
 		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
 		return
 	}
+	span.SetAttributes(
+		telemetry.AttrManager.String(manager.GetManagerName()),
+		telemetry.AttrPackage.String(packageName),
+	)
 
 	// DEBUG: Log resolved package manager and name
 	tflog.Debug(ctx, "Package manager resolved", map[string]interface{}{
@@ -428,6 +602,7 @@ func (r *PackageResource) Create(
 	// Create context with timeout
 	createCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	createCtx = r.auditScope(createCtx, "create", manager, packageName, r.auditedVersion(createCtx, manager, packageName))
 
 	// IDEMPOTENCY CHECK: Before attempting any installation, check if the package is already
 	// in the desired state. This prevents unnecessary operations and handles cases where
@@ -437,7 +612,7 @@ func (r *PackageResource) Create(
 		"desired_state": data.State.ValueString(),
 	})
 
-	if data.State.ValueString() == "present" {
+	if data.State.ValueString() == statePresent || data.State.ValueString() == stateLatest {
 		tflog.Debug(ctx, "Desired state is 'present', checking if package is already installed", map[string]interface{}{
 			"package_name": packageName,
 		})
@@ -458,7 +633,12 @@ func (r *PackageResource) Create(
 
 				// Package is already installed - check if version matches (if specified)
 				desiredVersion := data.Version.ValueString()
-				if desiredVersion == "" || desiredVersion == info.Version {
+				satisfied := versionSatisfies(info.Version, desiredVersion)
+				if data.State.ValueString() == stateLatest {
+					// Only skip the upgrade if the plan resolved the candidate to the installed version
+					satisfied = data.VersionTarget.ValueString() == info.Version
+				}
+				if satisfied {
 					// Package is already in desired state, just update computed attributes and return
 					tflog.Debug(ctx, "Package already in desired state, skipping installation", map[string]interface{}{
 						"package_name":      packageName,
@@ -468,6 +648,7 @@ func (r *PackageResource) Create(
 
 					data.ID = types.StringValue(fmt.Sprintf("%s:%s", manager.GetManagerName(), packageName))
 					data.VersionActual = types.StringValue(info.Version)
+					settleVersionTarget(&data)
 
 					// Handle pinning if requested
 					if data.Pin.ValueBool() {
@@ -523,18 +704,16 @@ func (r *PackageResource) Create(
 		})
 	}
 
-	// Update cache if configured
-	if r.providerData.Config.UpdateCache.ValueString() == "on_change" ||
-		r.providerData.Config.UpdateCache.ValueString() == "always" {
-		if err := manager.UpdateCache(createCtx); err != nil {
-			resp.Diagnostics.AddWarning(
-				"Cache Update Failed",
-				fmt.Sprintf("Failed to update package cache: %v", err),
-			)
-		}
+	// Refresh the package cache according to the provider's update_cache policy
+	if err := r.providerData.CacheTracker.EnsureFresh(createCtx, manager, true); err != nil {
+		resp.Diagnostics.AddWarning(
+			"Cache Update Failed",
+			fmt.Sprintf("Failed to update package cache: %v", err),
+		)
 	}
 
 	// Handle dependencies if specified
+	var installedDeps []string
 	if !data.Dependencies.IsNull() && len(data.Dependencies.Elements()) > 0 {
 		// Extract dependency list
 		dependencies := make([]string, 0)
@@ -572,27 +751,36 @@ func (r *PackageResource) Create(
 		// Install dependencies in order based on strategy
 		if strategy == "install_missing" {
 			for _, dep := range resolution.InstallOrder {
+				// Remember dependencies this create adds so a failed main install can remove them again
+				added := r.providerData.cleanupOnError() && !r.isInstalled(createCtx, manager, dep)
+
 				// Install dependency with auto type detection
-				if err := manager.InstallWithType(createCtx, dep, "", adapters.PackageTypeAuto); err != nil {
+				depCtx := audit.WithPackage(createCtx, dep)
+				if err := r.installPackage(depCtx, manager, dep, "", adapters.PackageTypeAuto); err != nil {
 					resp.Diagnostics.AddWarning(
 						"Dependency Installation Failed",
 						fmt.Sprintf("Failed to install dependency %s for package %s: %v", dep, packageName, err),
 					)
+					reportRollback(createCtx, manager, err, &resp.Diagnostics)
 					// Continue with main package installation even if dependency fails
+					continue
+				}
+				if added {
+					installedDeps = append(installedDeps, dep)
 				}
 			}
 		}
 	}
 
-	// Install the main package with specified type
-	version := data.Version.ValueString()
-	packageType := r.getPackageType(data.PackageType)
-
-	if err := manager.InstallWithType(createCtx, packageName, version, packageType); err != nil {
-		resp.Diagnostics.AddError(
+	// Install the main package at the configured version
+	if err := r.installDesiredVersion(createCtx, manager, packageName, &data); err != nil {
+		resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
 			"Package Installation Failed",
-			fmt.Sprintf("Failed to install package %s: %v", packageName, err),
-		)
+			fmt.Sprintf("Failed to install package %s", packageName),
+			err, packageErrorAttributes,
+		))
+		reportRollback(createCtx, manager, err, &resp.Diagnostics)
+		r.providerData.rollbackDependencies(createCtx, manager, installedDeps, &resp.Diagnostics)
 		return
 	}
 
@@ -617,6 +805,7 @@ func (r *PackageResource) Create(
 
 	// Set the ID
 	data.ID = types.StringValue(fmt.Sprintf("%s:%s", manager.GetManagerName(), packageName))
+	settleVersionTarget(&data)
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -624,6 +813,9 @@ func (r *PackageResource) Create(
 
 func (r *PackageResource) Read(
 	ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_package", "read")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data PackageResourceModel
 
 	// Read Terraform prior state data into the model
@@ -638,6 +830,10 @@ func (r *PackageResource) Read(
 		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
 		return
 	}
+	span.SetAttributes(
+		telemetry.AttrManager.String(manager.GetManagerName()),
+		telemetry.AttrPackage.String(packageName),
+	)
 
 	// Get timeout for read operation
 	var readTimeout types.String
@@ -650,6 +846,14 @@ func (r *PackageResource) Read(
 	readCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	// Refresh the package cache if the policy is 'always'
+	if err := r.providerData.CacheTracker.EnsureFresh(readCtx, manager, false); err != nil {
+		resp.Diagnostics.AddWarning(
+			"Cache Update Failed",
+			fmt.Sprintf("Failed to update package cache: %v", err),
+		)
+	}
+
 	// Read the package state
 	if err := r.readPackageState(readCtx, manager, packageName, &data); err != nil {
 		resp.Diagnostics.AddError(
@@ -658,6 +862,12 @@ func (r *PackageResource) Read(
 		)
 		return
 	}
+	// Describe the refreshed system; ModifyPlan sets the target again from configuration
+	data.VersionTarget = data.VersionActual
+	// The collateral preview described the last applied change, which is done
+	data.CollateralInstalls = emptyStringList()
+	data.CollateralRemovals = emptyStringList()
+	data.DriftFindings = stringListValue(r.detectDrift(readCtx, manager, packageName, &data, &resp.Diagnostics).Findings())
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -666,6 +876,9 @@ func (r *PackageResource) Read(
 // Update updates an existing resource.
 func (r *PackageResource) Update(
 	ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_package", "update")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data PackageResourceModel
 
 	// Read Terraform plan data into the model
@@ -680,6 +893,10 @@ func (r *PackageResource) Update(
 		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
 		return
 	}
+	span.SetAttributes(
+		telemetry.AttrManager.String(manager.GetManagerName()),
+		telemetry.AttrPackage.String(packageName),
+	)
 
 	// Get timeout for update operation
 	var updateTimeout types.String
@@ -692,26 +909,45 @@ func (r *PackageResource) Update(
 	updateCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	var priorVersion types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("version_actual"), &priorVersion)...)
+	var priorFindings types.List
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("drift_findings"), &priorFindings)...)
+	updateCtx = r.auditScope(updateCtx, "update", manager, packageName, priorVersion.ValueString())
+
 	// Handle state change (present -> absent or absent -> present)
 	packageType := r.getPackageType(data.PackageType)
 
-	if data.State.ValueString() == "absent" {
+	if data.State.ValueString() == stateAbsent {
 		// Remove the package with specified type
 		if err := manager.RemoveWithType(updateCtx, packageName, packageType); err != nil {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
 				"Package Removal Failed",
-				fmt.Sprintf("Failed to remove package %s: %v", packageName, err),
-			)
+				fmt.Sprintf("Failed to remove package %s", packageName),
+				err, packageErrorAttributes,
+			))
 			return
 		}
 	} else {
-		// Install/update the package with specified type
-		version := data.Version.ValueString()
-		if err := manager.InstallWithType(updateCtx, packageName, version, packageType); err != nil {
-			resp.Diagnostics.AddError(
-				"Package Installation Failed",
-				fmt.Sprintf("Failed to install/update package %s: %v", packageName, err),
+		// Install/update the package to the configured version
+		if err := r.providerData.CacheTracker.EnsureFresh(updateCtx, manager, true); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Cache Update Failed",
+				fmt.Sprintf("Failed to update package cache: %v", err),
 			)
+		}
+
+		install := r.installDesiredVersion
+		if data.VersionTarget.Equal(priorVersion) && needsReinstall(&data, priorFindings) {
+			install = r.reinstallPackage
+		}
+		if err := install(updateCtx, manager, packageName, &data); err != nil {
+			resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
+				"Package Installation Failed",
+				fmt.Sprintf("Failed to install/update package %s", packageName),
+				err, packageErrorAttributes,
+			))
+			reportRollback(updateCtx, manager, err, &resp.Diagnostics)
 			return
 		}
 
@@ -732,6 +968,7 @@ func (r *PackageResource) Update(
 		)
 		return
 	}
+	settleVersionTarget(&data)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -740,6 +977,9 @@ func (r *PackageResource) Update(
 // Delete removes a resource.
 func (r *PackageResource) Delete(
 	ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_package", "delete")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var data PackageResourceModel
 
 	// Read Terraform prior state data into the model
@@ -754,6 +994,10 @@ func (r *PackageResource) Delete(
 		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
 		return
 	}
+	span.SetAttributes(
+		telemetry.AttrManager.String(manager.GetManagerName()),
+		telemetry.AttrPackage.String(packageName),
+	)
 
 	// Get timeout for delete operation
 	var deleteTimeout types.String
@@ -765,6 +1009,7 @@ func (r *PackageResource) Delete(
 	// Create context with timeout
 	deleteCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	deleteCtx = r.auditScope(deleteCtx, "delete", manager, packageName, data.VersionActual.ValueString())
 
 	// Unpin the package first if it's pinned
 	if data.Pin.ValueBool() {
@@ -779,10 +1024,19 @@ func (r *PackageResource) Delete(
 	// Remove the package with specified type
 	packageType := r.getPackageType(data.PackageType)
 	if err := manager.RemoveWithType(deleteCtx, packageName, packageType); err != nil {
-		resp.Diagnostics.AddError(
+		// A package that is already gone is the desired end state
+		if errors.Is(err, adapters.ErrNotFound) {
+			resp.Diagnostics.AddWarning(
+				"Package Already Removed",
+				fmt.Sprintf("Package %s was not installed; removing it from state: %v", packageName, err),
+			)
+			return
+		}
+		resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
 			"Package Removal Failed",
-			fmt.Sprintf("Failed to remove package %s: %v", packageName, err),
-		)
+			fmt.Sprintf("Failed to remove package %s", packageName),
+			err, packageErrorAttributes,
+		))
 		return
 	}
 }
@@ -843,34 +1097,9 @@ func (r *PackageResource) resolvePackageManager(
 		}
 	}
 
-	// Auto-detect manager based on OS
-	if managerName == "auto" {
-		switch runtime.GOOS {
-		case "darwin":
-			managerName = "brew"
-		case "linux":
-			managerName = "apt"
-		default:
-			return nil, "", fmt.Errorf("unsupported OS: %s. Supported: darwin (brew), linux (apt)", runtime.GOOS)
-		}
-	}
-
-	// Create the appropriate manager
-	var manager adapters.PackageManager
-	switch managerName {
-	case "brew":
-		brewPath := r.providerData.Config.BrewPath.ValueString()
-		manager = brew.NewBrewAdapter(r.providerData.Executor, brewPath)
-	case "apt":
-		aptGetPath := r.providerData.Config.AptGetPath.ValueString()
-		manager = apt.NewAptAdapter(r.providerData.Executor, aptGetPath, "", r.providerData.Config.AptGetPath.ValueString())
-	default:
-		return nil, "", fmt.Errorf("unsupported package manager: %s. Supported: brew, apt", managerName)
-	}
-
-	// Check if manager is available - this ensures we don't attempt operations with unavailable tools
-	if !manager.IsAvailable(ctx) {
-		return nil, "", fmt.Errorf("package manager %s is not available on this system", managerName)
+	manager, err := newPackageManager(ctx, r.providerData, managerName)
+	if err != nil {
+		return nil, "", err
 	}
 
 	// Resolve package name
@@ -923,15 +1152,12 @@ func (r *PackageResource) readPackageState(ctx context.Context, manager adapters
 		data.InstallationSource = types.StringValue(manager.GetManagerName())
 	}
 
-	if data.DependencyTree.IsUnknown() {
-		// Set to empty map if not tracking dependencies
-		data.DependencyTree = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	settleCollateral(data)
+	if data.DriftFindings.IsUnknown() || data.DriftFindings.IsNull() {
+		data.DriftFindings = emptyStringList()
 	}
 
-	if data.LastAccess.IsUnknown() {
-		// Set to empty string if not tracking usage
-		data.LastAccess = types.StringValue("")
-	}
+	r.readTrackedState(ctx, manager, packageName, info.Installed, data)
 
 	// Don't override the configured state and pin values
 	// These should only be updated by user configuration, not by what we detect
@@ -939,6 +1165,59 @@ func (r *PackageResource) readPackageState(ctx context.Context, manager adapters
 	return nil
 }
 
+// installPackage installs a package through the provider's install batcher so
+// that concurrent resources share a single package manager transaction.
+func (r *PackageResource) installPackage(ctx context.Context, manager adapters.PackageManager,
+	name, version string, packageType adapters.PackageType) error {
+	return r.providerData.Batcher.Install(ctx, manager, name, version, packageType)
+}
+
+// rollbackScoped runs a mutation that bypasses the install batcher in a
+// rollback scope of its own when cleanup_on_error is enabled.
+func (r *PackageResource) rollbackScoped(ctx context.Context, manager adapters.PackageManager, mutate func() error) error {
+	return rollbackScoped(ctx, manager, r.providerData.cleanupOnError(), mutate)
+}
+
+// isInstalled reports whether a package is currently installed.
+func (r *PackageResource) isInstalled(ctx context.Context, manager adapters.PackageManager, name string) bool {
+	info, err := manager.DetectInstalled(ctx, name)
+	return err == nil && info.Installed
+}
+
+// auditScope attaches the resource being applied to ctx for the audit log.
+// Each record's version_after is the version installed once its command has run.
+func (r *PackageResource) auditScope(ctx context.Context, operation string, manager adapters.PackageManager,
+	packageName, versionBefore string) context.Context {
+	return audit.WithScope(ctx, audit.Scope{
+		ResourceType:       "pkg_package",
+		ResourceID:         fmt.Sprintf("%s:%s", manager.GetManagerName(), packageName),
+		TerraformOperation: operation,
+		Manager:            manager.GetManagerName(),
+		Package:            packageName,
+		VersionBefore:      versionBefore,
+		ResolveVersionAfter: func(ctx context.Context) string {
+			if r.providerData != nil {
+				// The command may have changed packages behind the shared inventory
+				r.providerData.Inventories.For(manager.GetManagerName()).Invalidate()
+			}
+			return r.auditedVersion(ctx, manager, packageName)
+		},
+	})
+}
+
+// auditedVersion returns the installed version of a package for the audit log.
+// It is only looked up when auditing is enabled.
+func (r *PackageResource) auditedVersion(ctx context.Context, manager adapters.PackageManager, packageName string) string {
+	if r.providerData == nil || r.providerData.AuditLog == nil {
+		return ""
+	}
+	info, err := manager.DetectInstalled(ctx, packageName)
+	if err != nil || !info.Installed {
+		return ""
+	}
+	return info.Version
+}
+
 func (r *PackageResource) getTimeout(timeoutStr types.String, defaultTimeout string) time.Duration {
 	if timeoutStr.IsNull() || timeoutStr.ValueString() == "" {
 		timeout, err := time.ParseDuration(defaultTimeout)
@@ -1078,8 +1357,7 @@ func (r *PackageResource) detectVersionDrift(current, desired PackageState) Drif
 		DesiredVersion: desired.Version,
 	}
 
-	// Simple version comparison for now
-	drift.HasVersionDrift = current.Version != desired.Version
+	drift.HasVersionDrift = !versionSatisfies(current.Version, desired.Version)
 
 	return drift
 }
@@ -25,14 +25,27 @@ package provider
 import (
 	"context"
 	"fmt"
-	"runtime"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/jamesainslie/terraform-provider-package/internal/adapters/brew"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// Matching modes of the package search data source.
+const (
+	matchSearch = "search"
+	matchExact  = "exact"
+	matchPrefix = "prefix"
+	matchRegex  = "regex"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -53,13 +66,18 @@ type PackageSearchDataSourceModel struct {
 	ID      types.String `tfsdk:"id"`
 	Query   types.String `tfsdk:"query"`
 	Manager types.String `tfsdk:"manager"`
+	Match   types.String `tfsdk:"match"`
 	Results types.List   `tfsdk:"results"`
 }
 
 // PackageSearchResult represents a single search result.
 type PackageSearchResult struct {
-	Name       types.String `tfsdk:"name"`
-	Repository types.String `tfsdk:"repository"`
+	Name        types.String `tfsdk:"name"`
+	Repository  types.String `tfsdk:"repository"`
+	Description types.String `tfsdk:"description"`
+	Version     types.String `tfsdk:"version"`
+	Section     types.String `tfsdk:"section"`
+	Installed   types.Bool   `tfsdk:"installed"`
 }
 
 // Metadata returns the data source type name.
@@ -74,7 +92,10 @@ func (d *PackageSearchDataSource) Metadata(
 func (d *PackageSearchDataSource) Schema(
 	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Searches for packages in the package manager catalog.",
+		MarkdownDescription: "Searches for packages in the package manager catalog. " +
+			"Results are ranked by relevance: an exact name match first, then names starting with the query, " +
+			"names containing it and finally packages matched by their description, with installed packages " +
+			"and shorter names first among equally relevant results.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -82,17 +103,32 @@ func (d *PackageSearchDataSource) Schema(
 				MarkdownDescription: "Data source identifier.",
 			},
 			"query": schema.StringAttribute{
-				MarkdownDescription: "Search query string to find packages.",
-				Required:            true,
+				MarkdownDescription: "Search query string to find packages. " +
+					"A regular expression in RE2 syntax when `match` is 'regex'.",
+				Required: true,
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to search. " +
-					"Valid values: 'auto', 'brew'. " +
-					"Defaults to 'auto' which auto-detects based on OS.",
+					"Valid values: 'auto', 'brew', 'apt'. " +
+					"Defaults to 'auto'.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
+			},
+			"match": schema.StringAttribute{
+				MarkdownDescription: "How packages are matched against `query`: 'search' for the package manager's " +
+					"own search, which covers names and, with APT, descriptions; 'exact' for packages named `query`; " +
+					"'prefix' for names starting with `query`; and 'regex' for names matching the regular expression " +
+					"`query`. All matches ignore case. Names of packages from third-party Homebrew taps are matched without the tap. " +
+					"Valid values: 'search', 'exact', 'prefix', 'regex'. Defaults to 'search'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(matchSearch, matchExact, matchPrefix, matchRegex),
+				},
 			},
 			"results": schema.ListNestedAttribute{
-				MarkdownDescription: "List of packages matching the search query.",
+				MarkdownDescription: "List of packages matching the search query, most relevant first.",
 				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
@@ -101,7 +137,24 @@ func (d *PackageSearchDataSource) Schema(
 							Computed:            true,
 						},
 						"repository": schema.StringAttribute{
-							MarkdownDescription: "Repository or tap that provides this package.",
+							MarkdownDescription: "Repository or tap that provides this package. Empty for APT.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Short description of the package.",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "Version that would be installed: the APT candidate version or " +
+								"the stable Homebrew version.",
+							Computed: true,
+						},
+						"section": schema.StringAttribute{
+							MarkdownDescription: "Archive section of the package (e.g., 'web'). Empty for Homebrew.",
+							Computed:            true,
+						},
+						"installed": schema.BoolAttribute{
+							MarkdownDescription: "Whether the package is currently installed.",
 							Computed:            true,
 						},
 					},
@@ -144,48 +197,42 @@ func (d *PackageSearchDataSource) Read(
 	}
 
 	// Determine package manager
-	managerName := "auto"
+	managerName := managerAuto
 	if !data.Manager.IsNull() {
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	if managerName == "auto" {
-		if runtime.GOOS != "darwin" {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		managerName = "brew"
-	}
-
-	// Only support brew in Phase 2
-	if managerName != "brew" {
-		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", managerName),
-		)
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
 		return
 	}
 
-	// Create Homebrew adapter
-	brewPath := d.providerData.Config.BrewPath.ValueString()
-	manager := brew.NewBrewAdapter(d.providerData.Executor, brewPath)
+	query := data.Query.ValueString()
+	mode := matchSearch
+	if !data.Match.IsNull() {
+		mode = data.Match.ValueString()
+	}
 
-	// Check if manager is available
-	if !manager.IsAvailable(ctx) {
-		resp.Diagnostics.AddError(
-			"Package Manager Not Available",
-			fmt.Sprintf("Package manager %s is not available on this system", managerName),
-		)
-		return
+	var namePattern *regexp.Regexp
+	if mode == matchRegex {
+		namePattern, err = compileNamePattern(query)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("query"),
+				"Invalid Regular Expression",
+				fmt.Sprintf("Query %q is not a valid regular expression: %v", query, err),
+			)
+			return
+		}
 	}
 
 	// Perform the search
-	query := data.Query.ValueString()
-	searchResults, err := manager.Search(ctx, query)
+	var keep func(name string) bool
+	if namePattern != nil {
+		keep = func(name string) bool { return namePattern.MatchString(baseName(name)) }
+	}
+	searchResults, err := d.search(ctx, managerName, searchPattern(query, mode), keep)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Package Search Failed",
@@ -193,15 +240,7 @@ func (d *PackageSearchDataSource) Read(
 		)
 		return
 	}
-
-	// Convert search results to data source model
-	results := make([]PackageSearchResult, len(searchResults))
-	for i, result := range searchResults {
-		results[i] = PackageSearchResult{
-			Name:       types.StringValue(result.Name),
-			Repository: types.StringValue(result.Repository),
-		}
-	}
+	searchResults = rankSearchResults(filterSearchResults(searchResults, query, mode, namePattern), query)
 
 	// Set computed values
 	data.ID = types.StringValue(fmt.Sprintf("%s:%s", managerName, query))
@@ -210,10 +249,14 @@ func (d *PackageSearchDataSource) Read(
 	// Convert results to list
 	resultsList, diags := types.ListValueFrom(ctx, types.ObjectType{
 		AttrTypes: map[string]attr.Type{
-			"name":       types.StringType,
-			"repository": types.StringType,
+			"name":        types.StringType,
+			"repository":  types.StringType,
+			"description": types.StringType,
+			"version":     types.StringType,
+			"section":     types.StringType,
+			"installed":   types.BoolType,
 		},
-	}, results)
+	}, packageSearchResults(searchResults))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -223,3 +266,128 @@ func (d *PackageSearchDataSource) Read(
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+func (d *PackageSearchDataSource) search(
+	ctx context.Context, managerName, pattern string, keep func(name string) bool) ([]adapters.SearchResult, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	searcher, ok := manager.(adapters.CatalogSearcher)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot search its catalog", manager.GetManagerName())
+	}
+	return searcher.SearchCatalog(ctx, pattern, keep)
+}
+
+// searchPattern returns the regular expression handed to the package manager
+// for a query. Exact and prefix patterns also accept a tap-qualified name. A
+// regex query is not handed over, as RE2 syntax differs from the package
+// managers' own; every package is fetched and the names are filtered by the
+// pattern from compileNamePattern before the packages are described.
+func searchPattern(query, mode string) string {
+	quoted := regexp.QuoteMeta(query)
+	switch mode {
+	case matchExact:
+		return "(^|/)" + quoted + "$"
+	case matchPrefix:
+		return "(^|/)" + quoted
+	case matchRegex:
+		return "."
+	default:
+		return quoted
+	}
+}
+
+// compileNamePattern compiles a regex query, ignoring case like exact and
+// prefix matches do.
+func compileNamePattern(query string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + query)
+}
+
+// filterSearchResults keeps the results whose name matches query in the given
+// mode. The package manager's own search matches descriptions as well, so its
+// results are kept as they are.
+func filterSearchResults(results []adapters.SearchResult, query, mode string,
+	namePattern *regexp.Regexp) []adapters.SearchResult {
+	filtered := []adapters.SearchResult{}
+	for _, result := range results {
+		name := strings.ToLower(baseName(result.Name))
+		var keep bool
+		switch mode {
+		case matchExact:
+			keep = name == strings.ToLower(query)
+		case matchPrefix:
+			keep = strings.HasPrefix(name, strings.ToLower(query))
+		case matchRegex:
+			keep = namePattern.MatchString(baseName(result.Name))
+		default:
+			keep = true
+		}
+		if keep {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// rankSearchResults orders results by relevance to query, then installed
+// packages first, then shorter and alphabetically earlier names.
+func rankSearchResults(results []adapters.SearchResult, query string) []adapters.SearchResult {
+	query = strings.ToLower(query)
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if ra, rb := searchRelevance(a, query), searchRelevance(b, query); ra != rb {
+			return ra < rb
+		}
+		if a.Installed != b.Installed {
+			return a.Installed
+		}
+		if la, lb := len(baseName(a.Name)), len(baseName(b.Name)); la != lb {
+			return la < lb
+		}
+		return a.Name < b.Name
+	})
+	return results
+}
+
+// searchRelevance ranks how a result matches a lowercase query, from 0 for an
+// exact name to 4 for a match the query does not literally appear in.
+func searchRelevance(result adapters.SearchResult, query string) int {
+	name := strings.ToLower(baseName(result.Name))
+	switch {
+	case name == query:
+		return 0
+	case strings.HasPrefix(name, query):
+		return 1
+	case strings.Contains(name, query):
+		return 2
+	case strings.Contains(strings.ToLower(result.Description), query):
+		return 3
+	default:
+		return 4
+	}
+}
+
+// baseName strips the tap from a Homebrew name such as
+// "hashicorp/tap/terraform".
+func baseName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// packageSearchResults converts search results to data source entries.
+func packageSearchResults(results []adapters.SearchResult) []PackageSearchResult {
+	entries := make([]PackageSearchResult, 0, len(results))
+	for _, result := range results {
+		entries = append(entries, PackageSearchResult{
+			Name:        types.StringValue(result.Name),
+			Repository:  types.StringValue(result.Repository),
+			Description: types.StringValue(result.Description),
+			Version:     types.StringValue(result.Version),
+			Section:     types.StringValue(result.Section),
+			Installed:   types.BoolValue(result.Installed),
+		})
+	}
+	return entries
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestSearchPattern(t *testing.T) {
+	assert.Equal(t, `g\+\+`, searchPattern("g++", matchSearch))
+	assert.Equal(t, `(^|/)curl$`, searchPattern("curl", matchExact))
+	assert.Equal(t, `(^|/)python3\.`, searchPattern("python3.", matchPrefix))
+	assert.Equal(t, `.`, searchPattern("^lib.*-dev$", matchRegex))
+}
+
+func TestFilterSearchResults(t *testing.T) {
+	results := []adapters.SearchResult{
+		{Name: "curl"},
+		{Name: "libcurl4"},
+		{Name: "curlftpfs"},
+		{Name: "hashicorp/tap/terraform"},
+		{Name: "wget", Description: "retrieves files with curl-like syntax"},
+	}
+	names := func(results []adapters.SearchResult) []string {
+		names := []string{}
+		for _, result := range results {
+			names = append(names, result.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"curl", "libcurl4", "curlftpfs", "hashicorp/tap/terraform", "wget"},
+		names(filterSearchResults(results, "curl", matchSearch, nil)))
+	assert.Equal(t, []string{"curl"}, names(filterSearchResults(results, "CURL", matchExact, nil)))
+	assert.Equal(t, []string{"curl", "curlftpfs"}, names(filterSearchResults(results, "curl", matchPrefix, nil)))
+	assert.Equal(t, []string{"hashicorp/tap/terraform"},
+		names(filterSearchResults(results, "terraform", matchExact, nil)))
+	assert.Equal(t, []string{"libcurl4"},
+		names(filterSearchResults(results, "^lib", matchRegex, regexp.MustCompile("^lib"))))
+
+	pattern, err := compileNamePattern("^CURL(ftp)?")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"curl", "curlftpfs"}, names(filterSearchResults(results, "^CURL(ftp)?", matchRegex, pattern)))
+}
+
+func TestRankSearchResults(t *testing.T) {
+	results := rankSearchResults([]adapters.SearchResult{
+		{Name: "wget", Description: "Retrieves files like curl"},
+		{Name: "libcurl4"},
+		{Name: "curlftpfs"},
+		{Name: "curl-dev", Installed: true},
+		{Name: "curl"},
+		{Name: "aria2"},
+	}, "Curl")
+
+	names := []string{}
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+	assert.Equal(t, []string{"curl", "curl-dev", "curlftpfs", "libcurl4", "wget", "aria2"}, names)
+}
+
+func TestPackageSearchResults(t *testing.T) {
+	assert.Equal(t, []PackageSearchResult{}, packageSearchResults(nil))
+	assert.Len(t, packageSearchResults([]adapters.SearchResult{{Name: "curl", Installed: true}}), 1)
+}
@@ -0,0 +1,704 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PackagesResource{}
+var _ resource.ResourceWithModifyPlan = &PackagesResource{}
+var _ resource.ResourceWithValidateConfig = &PackagesResource{}
+
+// Timeouts for pkg_packages operations. A whole package set is applied in one
+// transaction, so these are more generous than for pkg_package.
+const (
+	packagesApplyTimeout = 60 * time.Minute
+	packagesReadTimeout  = 5 * time.Minute
+)
+
+// NewPackagesResource creates a new package set resource.
+func NewPackagesResource() resource.Resource {
+	return &PackagesResource{}
+}
+
+// PackagesResource manages a whole set of packages for one package manager.
+type PackagesResource struct {
+	providerData *ProviderData
+}
+
+// PackagesResourceModel describes the resource data model.
+type PackagesResourceModel struct {
+	ID                types.String                `tfsdk:"id"`
+	Manager           types.String                `tfsdk:"manager"`
+	Packages          map[string]PackageSpecModel `tfsdk:"packages"`
+	Exclusive         types.Bool                  `tfsdk:"exclusive"`
+	Keep              types.List                  `tfsdk:"keep"`
+	VersionsActual    types.Map                   `tfsdk:"versions_actual"`
+	UnmanagedPackages types.List                  `tfsdk:"unmanaged_packages"`
+}
+
+// PackageSpecModel describes the desired state of one package in a set.
+type PackageSpecModel struct {
+	Version types.String `tfsdk:"version"`
+	Pin     types.Bool   `tfsdk:"pin"`
+	Type    types.String `tfsdk:"type"`
+}
+
+// packageSetDiff is the set of changes needed to reach the desired package set.
+type packageSetDiff struct {
+	Install []adapters.BatchPackage
+	Remove  []adapters.BatchPackage
+	Pin     []string
+	Unpin   []string
+}
+
+// IsEmpty reports whether the diff contains no changes.
+func (d packageSetDiff) IsEmpty() bool {
+	return len(d.Install) == 0 && len(d.Remove) == 0 && len(d.Pin) == 0 && len(d.Unpin) == 0
+}
+
+// Metadata returns the resource type name.
+func (r *PackagesResource) Metadata(
+	_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_packages"
+}
+
+// Schema defines the resource schema.
+func (r *PackagesResource) Schema(
+	_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a set of packages for a single package manager as one resource. " +
+			"Changes are computed against a single query of installed packages and applied in one transaction, " +
+			"which is much faster than many `pkg_package` resources.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				MarkdownDescription: "Resource identifier in the format 'manager:packages:hash', where hash is " +
+					"derived from the package names the set was created with.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"manager": schema.StringAttribute{
+				MarkdownDescription: "Package manager that owns the set. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
+					"Defaults to 'auto' which auto-detects based on OS.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(managerAuto),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"packages": schema.MapNestedAttribute{
+				MarkdownDescription: "Packages to manage, keyed by the package manager's package name.",
+				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"version": schema.StringAttribute{
+							MarkdownDescription: "Exact version to install. Leave unset to accept any installed version.",
+							Optional:            true,
+						},
+						"pin": schema.BoolAttribute{
+							MarkdownDescription: "Whether to pin/hold the package at its installed version.",
+							Optional:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Package type. Valid values: 'auto', 'formula', 'cask'. " +
+								"Only meaningful for Homebrew.",
+							Optional: true,
+						},
+					},
+				},
+			},
+			"exclusive": schema.BoolAttribute{
+				MarkdownDescription: "If true, manually installed packages that are not listed in `packages` or " +
+					"matched by `keep` are removed, which requires `keep` to be set. Packages installed only as " +
+					"dependencies, packages the package manager marks Essential or of priority required or important, " +
+					"and core system packages such as `openssh-server`, `sudo` and `systemd` are never removed. " +
+					"Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"keep": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Packages that exclusive mode must not remove although they are not listed in " +
+					"`packages`. Entries may be shell patterns such as `linux-headers-*`. " +
+					"Set `keep = []` to confirm that nothing beyond `packages` and the protected system packages is kept.",
+				Optional: true,
+			},
+			"versions_actual": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Installed version of each managed package.",
+				Computed:            true,
+			},
+			"unmanaged_packages": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Manually installed packages that are not listed in `packages`. " +
+					"Only populated in exclusive mode, where they are removed on the next apply. Exclusive mode " +
+					"only removes packages listed here, so creating the set or enabling `exclusive` removes nothing " +
+					"until a later plan has shown the packages.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Configure configures the resource with provider data.
+func (r *PackagesResource) Configure(
+	_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	r.providerData = providerData
+}
+
+// ValidateConfig requires an explicit keep list before exclusive mode may remove anything.
+func (r *PackagesResource) ValidateConfig(
+	ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data PackagesResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Exclusive.ValueBool() && data.Keep.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("keep"),
+			"Missing Attribute Configuration",
+			"keep must be set when exclusive is true. List the packages, or shell patterns, that must survive "+
+				"besides those in packages, or set keep = [] to confirm that every other manually installed "+
+				"package may be removed.",
+		)
+	}
+}
+
+// ModifyPlan forces an update, and warns about the removals, when exclusive
+// mode has found unmanaged packages.
+func (r *PackagesResource) ModifyPlan(
+	ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan PackagesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Exclusive.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("unmanaged_packages"),
+			types.ListValueMust(types.StringType, []attr.Value{}))...)
+		return
+	}
+
+	if pending := unmanagedPackages(&state); len(pending) > 0 {
+		// Apply removes these but recomputes the list afterwards, which may
+		// find packages installed since this plan, so it cannot be known yet
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("unmanaged_packages"),
+			types.ListUnknown(types.StringType))...)
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("unmanaged_packages"),
+			"Unmanaged Packages Will Be Removed",
+			fmt.Sprintf("Exclusive mode will remove these packages unless they are listed in packages or keep: %s",
+				strings.Join(pending, ", ")),
+		)
+	}
+}
+
+// Create installs the package set.
+func (r *PackagesResource) Create(
+	ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PackagesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	manager, err := newPackageManager(ctx, r.providerData, data.Manager.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
+		return
+	}
+
+	data.ID = types.StringValue(packageSetID(manager.GetManagerName(), data.Packages))
+
+	applyCtx, cancel := context.WithTimeout(ctx, packagesApplyTimeout)
+	defer cancel()
+	applyCtx = packagesAuditScope(applyCtx, data.ID.ValueString(), manager, "create")
+
+	if err := r.apply(applyCtx, manager, nil, nil, &data, &resp.Diagnostics); err != nil {
+		resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
+			"Package Set Apply Failed", "Failed to apply package set", err, packageSetErrorAttributes))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the package set from a single installed-packages query.
+func (r *PackagesResource) Read(
+	ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PackagesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	manager, err := newPackageManager(ctx, r.providerData, data.Manager.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
+		return
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, packagesReadTimeout)
+	defer cancel()
+
+	installed, err := listInstalledPackages(readCtx, manager)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to List Installed Packages", err.Error())
+		return
+	}
+
+	// Sets created before IDs were derived from their packages all shared one ID
+	if data.ID.ValueString() == manager.GetManagerName()+":packages" {
+		data.ID = types.StringValue(packageSetID(manager.GetManagerName(), data.Packages))
+	}
+
+	// Reflect drift back into the configured attribute so Terraform plans a fix:
+	// missing packages disappear from state and mismatched versions show the
+	// version that is actually installed.
+	for name, spec := range data.Packages {
+		info, ok := installed[name]
+		if !ok {
+			delete(data.Packages, name)
+			continue
+		}
+		if !spec.Version.IsNull() && spec.Version.ValueString() != "" && spec.Version.ValueString() != info.Version {
+			spec.Version = types.StringValue(info.Version)
+			data.Packages[name] = spec
+		}
+	}
+
+	if err := r.readComputed(readCtx, manager, installed, &data); err != nil {
+		resp.Diagnostics.AddError("Failed to Read Package Set", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update applies changes between the prior and planned package sets.
+func (r *PackagesResource) Update(
+	ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state PackagesResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	manager, err := newPackageManager(ctx, r.providerData, plan.Manager.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
+		return
+	}
+
+	applyCtx, cancel := context.WithTimeout(ctx, packagesApplyTimeout)
+	defer cancel()
+	applyCtx = packagesAuditScope(applyCtx, state.ID.ValueString(), manager, "update")
+
+	if err := r.apply(applyCtx, manager, state.Packages, unmanagedPackages(&state),
+		&plan, &resp.Diagnostics); err != nil {
+		resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
+			"Package Set Apply Failed", "Failed to apply package set", err, packageSetErrorAttributes))
+		return
+	}
+
+	plan.ID = state.ID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes every package in the set.
+func (r *PackagesResource) Delete(
+	ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PackagesResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	manager, err := newPackageManager(ctx, r.providerData, data.Manager.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Package Manager Resolution Failed", err.Error())
+		return
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, packagesApplyTimeout)
+	defer cancel()
+	deleteCtx = packagesAuditScope(deleteCtx, data.ID.ValueString(), manager, "delete")
+
+	diff := packageSetDiff{}
+	for _, name := range sortedPackageNames(data.Packages) {
+		spec := data.Packages[name]
+		if spec.Pin.ValueBool() {
+			diff.Unpin = append(diff.Unpin, name)
+		}
+		diff.Remove = append(diff.Remove, adapters.BatchPackage{Name: name, Type: packageSpecType(spec)})
+	}
+
+	if err := applyPackageSetDiff(deleteCtx, manager, diff); err != nil {
+		resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
+			"Package Set Removal Failed", "Failed to remove package set", err, packageSetErrorAttributes))
+	}
+}
+
+// apply brings the system to the planned package set and fills computed
+// attributes. Exclusive mode only removes packages the prior state listed as
+// unmanaged, which is what the plan showed; anything found since is reported
+// in unmanaged_packages and removed by the following apply.
+func (r *PackagesResource) apply(ctx context.Context, manager adapters.PackageManager,
+	prior map[string]PackageSpecModel, priorUnmanaged []string, data *PackagesResourceModel, diags *diag.Diagnostics) error {
+	installed, err := listInstalledPackages(ctx, manager)
+	if err != nil {
+		return err
+	}
+
+	var removable []string
+	if data.Exclusive.ValueBool() && len(priorUnmanaged) > 0 {
+		allowed, err := removablePackages(ctx, manager, keepPatterns(data))
+		if err != nil {
+			return err
+		}
+		for _, name := range priorUnmanaged {
+			if slices.Contains(allowed, name) {
+				removable = append(removable, name)
+			}
+		}
+	}
+
+	diff := computePackageSetDiff(data.Packages, prior, installed, removable, data.Exclusive.ValueBool())
+
+	tflog.Debug(ctx, "Applying package set changes", map[string]interface{}{
+		"manager": manager.GetManagerName(),
+		"install": len(diff.Install),
+		"remove":  len(diff.Remove),
+		"pin":     len(diff.Pin),
+		"unpin":   len(diff.Unpin),
+	})
+
+	if len(diff.Install) > 0 {
+		if err := r.providerData.CacheTracker.EnsureFresh(ctx, manager, true); err != nil {
+			diags.AddWarning("Cache Update Failed", fmt.Sprintf("Failed to update package cache: %v", err))
+		}
+	}
+
+	snapshot := r.providerData.beginRollbackScope(ctx, manager, diags)
+	defer snapshot.end()
+	if err := applyPackageSetDiff(ctx, manager, diff); err != nil {
+		r.providerData.rollbackOnFailure(ctx, snapshot, diags)
+		return err
+	}
+
+	if !diff.IsEmpty() {
+		if installed, err = listInstalledPackages(ctx, manager); err != nil {
+			return err
+		}
+	}
+
+	return r.readComputed(ctx, manager, installed, data)
+}
+
+// readComputed fills versions_actual and unmanaged_packages from the installed set.
+func (r *PackagesResource) readComputed(ctx context.Context, manager adapters.PackageManager,
+	installed map[string]adapters.PackageInfo, data *PackagesResourceModel) error {
+	versions := make(map[string]attr.Value, len(data.Packages))
+	for name := range data.Packages {
+		versions[name] = types.StringValue(installed[name].Version)
+	}
+	data.VersionsActual = types.MapValueMust(types.StringType, versions)
+
+	unmanaged := []attr.Value{}
+	if data.Exclusive.ValueBool() {
+		removable, err := removablePackages(ctx, manager, keepPatterns(data))
+		if err != nil {
+			return err
+		}
+		sort.Strings(removable)
+		for _, name := range removable {
+			if _, managed := data.Packages[name]; !managed {
+				unmanaged = append(unmanaged, types.StringValue(name))
+			}
+		}
+	}
+	data.UnmanagedPackages = types.ListValueMust(types.StringType, unmanaged)
+
+	return nil
+}
+
+// listInstalledPackages loads the full installed set keyed by package name.
+func listInstalledPackages(ctx context.Context, manager adapters.PackageManager) (map[string]adapters.PackageInfo, error) {
+	lister, ok := manager.(adapters.InstalledLister)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot list installed packages", manager.GetManagerName())
+	}
+
+	packages, err := lister.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string]adapters.PackageInfo, len(packages))
+	for _, pkg := range packages {
+		installed[pkg.Name] = pkg
+	}
+	return installed, nil
+}
+
+// computePackageSetDiff works out which packages to install, remove, pin and
+// unpin to move from the prior set to the desired one. In exclusive mode every
+// removable package not in the desired set is removed as well.
+func computePackageSetDiff(desired, prior map[string]PackageSpecModel,
+	installed map[string]adapters.PackageInfo, removable []string, exclusive bool) packageSetDiff {
+	var diff packageSetDiff
+
+	for _, name := range sortedPackageNames(desired) {
+		spec := desired[name]
+		info, isInstalled := installed[name]
+		version := spec.Version.ValueString()
+
+		needsInstall := !isInstalled || (version != "" && info.Version != version)
+		if needsInstall {
+			diff.Install = append(diff.Install, adapters.BatchPackage{
+				Name:    name,
+				Version: version,
+				Type:    packageSpecType(spec),
+			})
+		}
+
+		priorSpec, wasManaged := prior[name]
+		priorPin := wasManaged && priorSpec.Pin.ValueBool()
+		switch {
+		case spec.Pin.ValueBool() && (!priorPin || needsInstall):
+			diff.Pin = append(diff.Pin, name)
+		case !spec.Pin.ValueBool() && priorPin:
+			diff.Unpin = append(diff.Unpin, name)
+		}
+	}
+
+	removing := make(map[string]bool)
+	for _, name := range sortedPackageNames(prior) {
+		if _, keep := desired[name]; keep {
+			continue
+		}
+		if _, isInstalled := installed[name]; !isInstalled {
+			continue
+		}
+		if prior[name].Pin.ValueBool() {
+			diff.Unpin = append(diff.Unpin, name)
+		}
+		diff.Remove = append(diff.Remove, adapters.BatchPackage{Name: name, Type: packageSpecType(prior[name])})
+		removing[name] = true
+	}
+
+	if exclusive {
+		sorted := append([]string(nil), removable...)
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			if _, keep := desired[name]; keep || removing[name] {
+				continue
+			}
+			info, isInstalled := installed[name]
+			if !isInstalled {
+				continue
+			}
+			diff.Remove = append(diff.Remove, adapters.BatchPackage{Name: name, Type: info.Type})
+			removing[name] = true
+		}
+	}
+
+	return diff
+}
+
+// applyPackageSetDiff applies a diff using batch operations where the manager
+// supports them. A failed batch install is retried package by package so the
+// error names the packages that actually failed.
+func applyPackageSetDiff(ctx context.Context, manager adapters.PackageManager, diff packageSetDiff) error {
+	for _, name := range diff.Unpin {
+		if err := manager.Pin(ctx, name, false); err != nil {
+			return fmt.Errorf("failed to unpin %s: %w", name, err)
+		}
+	}
+
+	if len(diff.Remove) > 0 {
+		if remover, ok := manager.(adapters.BatchRemover); ok {
+			if err := remover.RemoveBatch(ctx, diff.Remove); err != nil {
+				return err
+			}
+		} else {
+			for _, pkg := range diff.Remove {
+				if err := manager.RemoveWithType(ctx, pkg.Name, pkg.Type); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(diff.Install) > 0 {
+		installer, ok := manager.(adapters.BatchInstaller)
+		if !ok || installer.InstallBatch(ctx, diff.Install) != nil {
+			var failures []string
+			for _, pkg := range diff.Install {
+				if err := manager.InstallWithType(ctx, pkg.Name, pkg.Version, pkg.Type); err != nil {
+					failures = append(failures, fmt.Sprintf("%s: %v", pkg.Name, err))
+				}
+			}
+			if len(failures) > 0 {
+				return fmt.Errorf("failed to install %d of %d packages:\n  %s",
+					len(failures), len(diff.Install), strings.Join(failures, "\n  "))
+			}
+		}
+	}
+
+	for _, name := range diff.Pin {
+		if err := manager.Pin(ctx, name, true); err != nil {
+			return fmt.Errorf("failed to pin %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// packageSetErrorAttributes points package set failures at the attribute to fix.
+var packageSetErrorAttributes = ErrorAttributes{
+	adapters.ErrNotFound:           path.Root("packages"),
+	adapters.ErrVersionUnavailable: path.Root("packages"),
+}
+
+// packagesAuditScope attaches the package set resource to ctx for the audit log.
+// Batch commands name their packages, so no single package is recorded.
+func packagesAuditScope(ctx context.Context, id string, manager adapters.PackageManager,
+	operation string) context.Context {
+	return audit.WithScope(ctx, audit.Scope{
+		ResourceType:       "pkg_packages",
+		ResourceID:         id,
+		TerraformOperation: operation,
+		Manager:            manager.GetManagerName(),
+	})
+}
+
+// packageSetID identifies a package set by its manager and the package names
+// it was created with, so that several sets on one manager stay distinct.
+func packageSetID(managerName string, packages map[string]PackageSpecModel) string {
+	sum := sha256.Sum256([]byte(strings.Join(sortedPackageNames(packages), "\n")))
+	return fmt.Sprintf("%s:packages:%s", managerName, hex.EncodeToString(sum[:])[:12])
+}
+
+// keepPatterns returns the configured keep entries.
+func keepPatterns(data *PackagesResourceModel) []string {
+	var keep []string
+	for _, value := range data.Keep.Elements() {
+		if name, ok := value.(types.String); ok && !name.IsNull() && !name.IsUnknown() {
+			keep = append(keep, name.ValueString())
+		}
+	}
+	return keep
+}
+
+// unmanagedPackages returns the unmanaged packages recorded in the model.
+func unmanagedPackages(data *PackagesResourceModel) []string {
+	var names []string
+	for _, value := range data.UnmanagedPackages.Elements() {
+		if name, ok := value.(types.String); ok && !name.IsNull() && !name.IsUnknown() {
+			names = append(names, name.ValueString())
+		}
+	}
+	return names
+}
+
+// packageSpecType converts the configured type to an adapter package type.
+func packageSpecType(spec PackageSpecModel) adapters.PackageType {
+	switch spec.Type.ValueString() {
+	case "formula":
+		return adapters.PackageTypeFormula
+	case "cask":
+		return adapters.PackageTypeCask
+	default:
+		return adapters.PackageTypeAuto
+	}
+}
+
+// sortedPackageNames returns the keys of a package map in a stable order.
+func sortedPackageNames(packages map[string]PackageSpecModel) []string {
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
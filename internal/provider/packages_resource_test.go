@@ -0,0 +1,214 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func spec(version string, pin bool) PackageSpecModel {
+	v := types.StringNull()
+	if version != "" {
+		v = types.StringValue(version)
+	}
+	return PackageSpecModel{Version: v, Pin: types.BoolValue(pin), Type: types.StringNull()}
+}
+
+func installedSet(versions map[string]string) map[string]adapters.PackageInfo {
+	installed := make(map[string]adapters.PackageInfo, len(versions))
+	for name, version := range versions {
+		installed[name] = adapters.PackageInfo{Name: name, Version: version, Installed: true}
+	}
+	return installed
+}
+
+func batchNames(packages []adapters.BatchPackage) []string {
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Name)
+	}
+	return names
+}
+
+func TestComputePackageSetDiff(t *testing.T) {
+	desired := map[string]PackageSpecModel{
+		"curl": spec("", false),    // already installed, any version
+		"jq":   spec("1.7", false), // installed at the wrong version
+		"git":  spec("", true),     // missing and pinned
+		"htop": spec("", false),    // pin removed
+		"vim":  spec("", true),     // newly pinned
+	}
+	prior := map[string]PackageSpecModel{
+		"curl": spec("", false),
+		"htop": spec("", true),
+		"vim":  spec("", false),
+		"nano": spec("", true), // dropped from config
+	}
+	installed := installedSet(map[string]string{
+		"curl": "7.81", "jq": "1.6", "htop": "3.0", "vim": "9.0", "nano": "6.2", "emacs": "28",
+	})
+
+	diff := computePackageSetDiff(desired, prior, installed, nil, false)
+
+	assert.Equal(t, []string{"git", "jq"}, batchNames(diff.Install))
+	assert.Equal(t, "1.7", diff.Install[1].Version)
+	assert.Equal(t, []string{"nano"}, batchNames(diff.Remove))
+	assert.Equal(t, []string{"git", "vim"}, diff.Pin)
+	assert.Equal(t, []string{"htop", "nano"}, diff.Unpin)
+}
+
+func TestComputePackageSetDiff_Exclusive(t *testing.T) {
+	desired := map[string]PackageSpecModel{"curl": spec("", false)}
+	installed := installedSet(map[string]string{"curl": "7.81", "emacs": "28", "libfoo": "1.0"})
+
+	// libfoo is a dependency, not manually installed, so it must survive
+	diff := computePackageSetDiff(desired, nil, installed, []string{"emacs", "curl", "gone"}, true)
+
+	assert.Empty(t, diff.Install)
+	assert.Equal(t, []string{"emacs"}, batchNames(diff.Remove))
+
+	diff = computePackageSetDiff(desired, nil, installed, []string{"emacs"}, false)
+	assert.True(t, diff.IsEmpty(), "non-exclusive mode must not remove unmanaged packages")
+}
+
+func TestRemovablePackages(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	manager.manual = []string{"bash", "emacs", "linux-headers-6.8", "linux-image-6.8", "openssh-server", "vim"}
+	manager.essential = []string{"bash"}
+
+	removable, err := removablePackages(context.Background(), manager, []string{"linux-headers-*", "vim"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"emacs"}, removable)
+}
+
+func TestPackageSetID(t *testing.T) {
+	first := packageSetID("apt", map[string]PackageSpecModel{"curl": spec("", false), "jq": spec("", false)})
+	second := packageSetID("apt", map[string]PackageSpecModel{"git": spec("", false)})
+
+	assert.Regexp(t, `^apt:packages:[0-9a-f]{12}$`, first)
+	assert.NotEqual(t, first, second, "sets on one manager must not share an ID")
+	assert.Equal(t, first, packageSetID("apt", map[string]PackageSpecModel{"jq": spec("1.7", true), "curl": spec("", false)}))
+}
+
+func TestApplyPackageSetDiff_BatchesInstallsAndRemovals(t *testing.T) {
+	manager := newFakePackageManager(map[string]string{"nano": "6.2"})
+
+	err := applyPackageSetDiff(context.Background(), manager, packageSetDiff{
+		Install: []adapters.BatchPackage{{Name: "curl"}, {Name: "git"}},
+		Remove:  []adapters.BatchPackage{{Name: "nano"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"curl", "git"}}, manager.batches)
+	assert.Empty(t, manager.singleRuns)
+	assert.NotContains(t, manager.installed, "nano")
+}
+
+func TestApplyPackageSetDiff_PinpointsFailures(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	manager.batchErr = fmt.Errorf("E: Unable to locate package nosuchpkg")
+	manager.installFunc = func(name, version string) error {
+		if name == "nosuchpkg" {
+			return fmt.Errorf("unable to locate package")
+		}
+		manager.installed[name] = version
+		return nil
+	}
+
+	err := applyPackageSetDiff(context.Background(), manager, packageSetDiff{
+		Install: []adapters.BatchPackage{{Name: "curl"}, {Name: "nosuchpkg"}},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to install 1 of 2 packages")
+	assert.Contains(t, err.Error(), "nosuchpkg")
+	assert.Contains(t, manager.installed, "curl")
+}
+
+func TestPackagesResource_ExclusiveRemovesOnlyPlannedPackages(t *testing.T) {
+	ctx := context.Background()
+	manager := newFakePackageManager(map[string]string{"curl": "7.81", "emacs": "28", "vim": "9.0"})
+	manager.manual = []string{"curl", "emacs", "vim"}
+	r := &PackagesResource{providerData: &ProviderData{}}
+	data := &PackagesResourceModel{
+		Packages:  map[string]PackageSpecModel{"curl": spec("", false)},
+		Exclusive: types.BoolValue(true),
+		Keep:      types.ListValueMust(types.StringType, []attr.Value{}),
+	}
+
+	// Creating the set only reports the unmanaged packages
+	var diags diag.Diagnostics
+	assert.NoError(t, r.apply(ctx, manager, nil, nil, data, &diags))
+	assert.Empty(t, manager.removed)
+	assert.Equal(t, []string{"emacs", "vim"}, unmanagedPackages(data))
+
+	// The next apply removes what the plan showed, except packages kept since
+	prior := unmanagedPackages(data)
+	data.Keep = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("vim")})
+	manager.manual = append(manager.manual, "nano")
+	manager.installed["nano"] = "6.2"
+	assert.NoError(t, r.apply(ctx, manager, data.Packages, prior, data, &diags))
+	assert.Equal(t, []string{"emacs"}, manager.removed, "nano was not in the plan and must survive")
+}
+
+func TestPackagesResource_ModifyPlanLeavesExclusiveRemovalsUnknown(t *testing.T) {
+	ctx := context.Background()
+	r := &PackagesResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	require.False(t, state.Set(ctx, &PackagesResourceModel{
+		ID:                types.StringValue("apt:packages:0123456789ab"),
+		Manager:           types.StringValue("apt"),
+		Packages:          map[string]PackageSpecModel{"curl": spec("", false)},
+		Exclusive:         types.BoolValue(true),
+		Keep:              types.ListValueMust(types.StringType, []attr.Value{}),
+		VersionsActual:    types.MapValueMust(types.StringType, map[string]attr.Value{"curl": types.StringValue("7.81")}),
+		UnmanagedPackages: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("emacs")}),
+	}).HasError())
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: state.Raw.Copy()}
+
+	resp := &resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{State: state, Plan: plan}, resp)
+
+	// Apply recomputes the list, so planning a known value could contradict it
+	var unmanaged types.List
+	require.False(t, resp.Plan.GetAttribute(ctx, path.Root("unmanaged_packages"), &unmanaged).HasError())
+	assert.True(t, unmanaged.IsUnknown())
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	assert.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "emacs")
+}
@@ -0,0 +1,236 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/platform"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &PlatformDataSource{}
+
+// NewPlatformDataSource creates a new platform data source.
+func NewPlatformDataSource() datasource.DataSource {
+	return &PlatformDataSource{}
+}
+
+// PlatformDataSource defines the data source implementation.
+type PlatformDataSource struct {
+	providerData *ProviderData
+}
+
+// PlatformDataSourceModel describes the data source data model.
+type PlatformDataSourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	OS                types.String `tfsdk:"os"`
+	Arch              types.String `tfsdk:"arch"`
+	Family            types.String `tfsdk:"family"`
+	Distribution      types.String `tfsdk:"distribution"`
+	DistributionName  types.String `tfsdk:"distribution_name"`
+	Version           types.String `tfsdk:"version"`
+	Codename          types.String `tfsdk:"codename"`
+	Container         types.Bool   `tfsdk:"container"`
+	ContainerRuntime  types.String `tfsdk:"container_runtime"`
+	WSL               types.Bool   `tfsdk:"wsl"`
+	InitSystem        types.String `tfsdk:"init_system"`
+	DefaultManager    types.String `tfsdk:"default_manager"`
+	AvailableManagers types.List   `tfsdk:"available_managers"`
+}
+
+// Metadata returns the data source type name.
+// Metadata returns the data source type name.
+func (d *PlatformDataSource) Metadata(
+	_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_platform"
+}
+
+// Schema defines the data source schema.
+// Schema defines the data source schema.
+func (d *PlatformDataSource) Schema(
+	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Describes the platform the provider runs on, so configurations can branch on the " +
+			"distribution release, CPU architecture or available package managers. Facts come from " +
+			"/etc/os-release on Linux and `sw_vers` on macOS.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Data source identifier.",
+			},
+			"os": schema.StringAttribute{
+				MarkdownDescription: "Operating system as named by Go (e.g., 'linux', 'darwin', 'windows').",
+				Computed:            true,
+			},
+			"arch": schema.StringAttribute{
+				MarkdownDescription: "CPU architecture of the machine as named by Go (e.g., 'amd64', 'arm64'). " +
+					"Reports 'arm64' on Apple silicon even when the provider runs under Rosetta 2.",
+				Computed: true,
+			},
+			"family": schema.StringAttribute{
+				MarkdownDescription: "Distribution family: 'debian', 'rhel', 'alpine', 'arch' or 'suse' on Linux, " +
+					"found through ID_LIKE for derivatives, 'darwin' on macOS and 'windows' on Windows. " +
+					"The distribution ID when its family is not known, and empty when it cannot be identified.",
+				Computed: true,
+			},
+			"distribution": schema.StringAttribute{
+				MarkdownDescription: "Distribution ID from os-release (e.g., 'ubuntu', 'debian'), " +
+					"or 'macos' and 'windows'.",
+				Computed: true,
+			},
+			"distribution_name": schema.StringAttribute{
+				MarkdownDescription: "Human-readable distribution name (e.g., 'Ubuntu 22.04.4 LTS', 'macOS 14.5').",
+				Computed:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Release number (e.g., '22.04', '12', '14.5'). Empty for rolling releases.",
+				Computed:            true,
+			},
+			"codename": schema.StringAttribute{
+				MarkdownDescription: "Release codename (e.g., 'jammy', 'bookworm', 'Sonoma').",
+				Computed:            true,
+			},
+			"container": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider runs inside a container.",
+				Computed:            true,
+			},
+			"container_runtime": schema.StringAttribute{
+				MarkdownDescription: "Container runtime the provider runs under (e.g., 'docker', 'podman', " +
+					"'kubernetes', 'lxc'). Empty outside containers.",
+				Computed: true,
+			},
+			"wsl": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider runs under the Windows Subsystem for Linux.",
+				Computed:            true,
+			},
+			"init_system": schema.StringAttribute{
+				MarkdownDescription: "Init system managing services: 'systemd', 'openrc', 'runit', 'sysvinit', " +
+					"'launchd' or 'unknown'.",
+				Computed: true,
+			},
+			"default_manager": schema.StringAttribute{
+				MarkdownDescription: "Package manager that 'auto' resolves to on this platform. " +
+					"Empty when the platform has no supported package manager.",
+				Computed: true,
+			},
+			"available_managers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Supported package managers installed on the system (e.g., ['apt']).",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure configures the data source with provider data.
+// Configure configures the data source with provider data.
+func (d *PlatformDataSource) Configure(
+	_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *PlatformDataSource) Read(
+	ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data PlatformDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.providerData == nil {
+		resp.Diagnostics.AddError("Provider Not Configured", "provider data is not configured")
+		return
+	}
+
+	host, err := platform.NewDetector(d.providerData.Executor).Detect(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Detect Platform", err.Error())
+		return
+	}
+
+	setPlatform(&data, host)
+
+	availableList, diags := types.ListValueFrom(ctx, types.StringType, d.availableManagers(ctx))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.AvailableManagers = availableList
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// availableManagers returns the supported package managers installed on the
+// system.
+func (d *PlatformDataSource) availableManagers(ctx context.Context) []string {
+	available := []string{}
+	for _, name := range []string{managerBrew, managerApt} {
+		manager, err := buildPackageManager(d.providerData, name)
+		if err == nil && manager.IsAvailable(ctx) {
+			available = append(available, name)
+		}
+	}
+	return available
+}
+
+// setPlatform copies platform facts to the data source model.
+func setPlatform(data *PlatformDataSourceModel, host *platform.Platform) {
+	data.ID = types.StringValue(fmt.Sprintf("%s:%s:%s:%s", host.OS, host.Distribution, host.Version, host.Arch))
+	data.OS = types.StringValue(host.OS)
+	data.Arch = types.StringValue(host.Arch)
+	data.Family = types.StringValue(host.Family)
+	data.Distribution = types.StringValue(host.Distribution)
+	data.DistributionName = types.StringValue(host.DistributionName)
+	data.Version = types.StringValue(host.Version)
+	data.Codename = types.StringValue(host.Codename)
+	data.Container = types.BoolValue(host.Container())
+	data.ContainerRuntime = types.StringValue(host.ContainerRuntime)
+	data.WSL = types.BoolValue(host.WSL)
+	data.InitSystem = types.StringValue(host.InitSystem)
+	data.DefaultManager = types.StringValue(host.DefaultManager())
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/platform"
+)
+
+func TestSetPlatform(t *testing.T) {
+	var data PlatformDataSourceModel
+	setPlatform(&data, &platform.Platform{
+		OS:               "linux",
+		Arch:             "arm64",
+		Family:           platform.FamilyDebian,
+		Distribution:     "ubuntu",
+		DistributionName: "Ubuntu 24.04 LTS",
+		Version:          "24.04",
+		Codename:         "noble",
+		ContainerRuntime: "docker",
+		InitSystem:       platform.InitUnknown,
+	})
+
+	assert.Equal(t, types.StringValue("linux:ubuntu:24.04:arm64"), data.ID)
+	assert.Equal(t, types.StringValue("debian"), data.Family)
+	assert.Equal(t, types.StringValue("noble"), data.Codename)
+	assert.Equal(t, types.BoolValue(true), data.Container)
+	assert.Equal(t, types.BoolValue(false), data.WSL)
+	assert.Equal(t, types.StringValue("apt"), data.DefaultManager)
+}
+
+func TestResolveManagerName(t *testing.T) {
+	name, err := resolveManagerName("brew")
+	assert.NoError(t, err)
+	assert.Equal(t, "brew", name)
+
+	name, err = resolveManagerName(managerAuto)
+	if manager := hostPlatform().DefaultManager(); manager != "" {
+		assert.NoError(t, err)
+		assert.Equal(t, manager, name)
+	} else {
+		assert.Error(t, err)
+	}
+}
@@ -24,22 +24,34 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 	"runtime"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
 	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/osv"
 	"github.com/jamesainslie/terraform-provider-package/internal/registry"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
 )
 
 // Ensure PackageProvider satisfies various provider interfaces.
 var _ provider.Provider = &PackageProvider{}
 var _ provider.ProviderWithFunctions = &PackageProvider{}
+var _ provider.ProviderWithValidateConfig = &PackageProvider{}
 
 // PackageProvider defines the provider implementation.
 type PackageProvider struct {
@@ -51,21 +63,29 @@ type PackageProvider struct {
 
 // PackageProviderModel describes the provider data model.
 type PackageProviderModel struct {
-	DefaultManager     types.String `tfsdk:"default_manager"`
-	AssumeYes          types.Bool   `tfsdk:"assume_yes"`
-	SudoEnabled        types.Bool   `tfsdk:"sudo_enabled"`
-	BrewPath           types.String `tfsdk:"brew_path"`
-	AptGetPath         types.String `tfsdk:"apt_get_path"`
-	WingetPath         types.String `tfsdk:"winget_path"`
-	ChocoPath          types.String `tfsdk:"choco_path"`
-	UpdateCache        types.String `tfsdk:"update_cache"`
-	LockTimeout        types.String `tfsdk:"lock_timeout"`
-	RetryCount         types.Int64  `tfsdk:"retry_count"`
-	RetryDelay         types.String `tfsdk:"retry_delay"`
-	FailOnDownload     types.Bool   `tfsdk:"fail_on_download"`
-	CleanupOnError     types.Bool   `tfsdk:"cleanup_on_error"`
-	VerifyDownloads    types.Bool   `tfsdk:"verify_downloads"`
-	ChecksumValidation types.Bool   `tfsdk:"checksum_validation"`
+	DefaultManager         types.String `tfsdk:"default_manager"`
+	AssumeYes              types.Bool   `tfsdk:"assume_yes"`
+	SudoEnabled            types.Bool   `tfsdk:"sudo_enabled"`
+	BrewPath               types.String `tfsdk:"brew_path"`
+	AptGetPath             types.String `tfsdk:"apt_get_path"`
+	WingetPath             types.String `tfsdk:"winget_path"`
+	ChocoPath              types.String `tfsdk:"choco_path"`
+	UpdateCache            types.String `tfsdk:"update_cache"`
+	CacheValidTime         types.String `tfsdk:"cache_valid_time"`
+	BatchWindow            types.String `tfsdk:"batch_window"`
+	LockTimeout            types.String `tfsdk:"lock_timeout"`
+	TerminationGracePeriod types.String `tfsdk:"termination_grace_period"`
+	SensitiveEnvVars       types.List   `tfsdk:"sensitive_env_vars"`
+	RedactPatterns         types.List   `tfsdk:"redact_patterns"`
+	AuditLog               types.String `tfsdk:"audit_log"`
+	OTLPEndpoint           types.String `tfsdk:"otlp_endpoint"`
+	VulnerabilityDB        types.String `tfsdk:"vulnerability_database"`
+	RetryCount             types.Int64  `tfsdk:"retry_count"`
+	RetryDelay             types.String `tfsdk:"retry_delay"`
+	FailOnDownload         types.Bool   `tfsdk:"fail_on_download"`
+	CleanupOnError         types.Bool   `tfsdk:"cleanup_on_error"`
+	VerifyDownloads        types.Bool   `tfsdk:"verify_downloads"`
+	ChecksumValidation     types.Bool   `tfsdk:"checksum_validation"`
 }
 
 // ProviderData holds the configured provider data that will be passed to resources and data sources.
@@ -76,6 +96,17 @@ type ProviderData struct {
 	DiagHelpers    *DiagnosticHelpers
 	DetectedOS     string
 	PrivilegeCheck bool
+	CacheTracker   *CacheTracker
+	Batcher        *InstallBatcher
+	Inventories    *InventoryRegistry
+	// LockTimeout is how long package manager commands wait for a lock held by another process
+	LockTimeout time.Duration
+	// AuditLog is nil unless the audit_log setting is configured
+	AuditLog *audit.Logger
+	// Vulnerabilities caches the OSV databases loaded by security data sources
+	Vulnerabilities *osv.Cache
+	// Version is the provider version, recorded in generated documents
+	Version string
 }
 
 // Metadata returns the provider metadata.
@@ -96,6 +127,9 @@ func (p *PackageProvider) Schema(
 					"Valid values: auto, brew, apt, winget, choco. " +
 					"Defaults to 'auto' which auto-detects based on OS.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(validPackageManagers...),
+				},
 			},
 			"assume_yes": schema.BoolAttribute{
 				MarkdownDescription: "Run package operations non-interactively, assuming 'yes' to all prompts. " +
@@ -130,23 +164,107 @@ func (p *PackageProvider) Schema(
 			"update_cache": schema.StringAttribute{
 				MarkdownDescription: "When to update package manager cache. " +
 					"Valid values: never, on_change, always. " +
+					"'on_change' refreshes once before the first install or upgrade, 'always' refreshes on first use of a manager " +
+					"even if nothing changes. Each manager's cache is refreshed at most once per Terraform run. " +
 					"Defaults to 'on_change'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("never", "on_change", "always"),
+				},
+			},
+			"cache_valid_time": schema.StringAttribute{
+				MarkdownDescription: "Skip cache updates under the 'on_change' policy if the cache was refreshed more recently than this " +
+					"duration (e.g., '1h'). Freshness is taken from /var/lib/apt/lists for APT and Homebrew's last update for brew. " +
+					"Defaults to '0s' (always refresh before changes).",
+				Optional:   true,
+				Validators: []validator.String{nonNegativeDuration()},
+			},
+			"batch_window": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for other package installs for the same manager before running them " +
+					"together as a single transaction (e.g., one `apt-get install a b c`). If the combined install fails, " +
+					"each package is retried individually so errors are reported against the right resource. " +
+					"Set to '0s' to disable batching. Defaults to '2s'.",
+				Optional:   true,
+				Validators: []validator.String{nonNegativeDuration()},
 			},
 			"lock_timeout": schema.StringAttribute{
-				MarkdownDescription: "Timeout for waiting on package manager locks (e.g., apt/dpkg). " +
+				MarkdownDescription: "How long APT commands wait for the dpkg lock held by another process, " +
+					"such as unattended-upgrades, before failing. The wait is added to the command's timeout. " +
 					"Defaults to '10m'.",
+				Optional:   true,
+				Validators: []validator.String{positiveDuration()},
+			},
+			"termination_grace_period": schema.StringAttribute{
+				MarkdownDescription: "How long an interrupted or timed-out package manager command is given to exit after " +
+					"SIGTERM before its whole process group is killed with SIGKILL. Interrupted APT operations are followed by " +
+					"`dpkg --configure -a` to repair the package database. " +
+					"Defaults to '10s'.",
+				Optional:   true,
+				Validators: []validator.String{nonNegativeDuration()},
+			},
+			"sensitive_env_vars": schema.ListAttribute{
+				MarkdownDescription: "Additional environment variable names whose values are redacted from command logs. " +
+					"Names containing TOKEN, SECRET, PASSWORD, API_KEY, ACCESS_KEY, CREDENTIAL or AUTHORIZATION, names ending in _AUTH, and " +
+					"HOMEBREW_GITHUB_API_TOKEN, are always redacted.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"redact_patterns": schema.ListAttribute{
+				MarkdownDescription: "Additional regular expressions whose matches are redacted from logged commands and output. " +
+					"If a pattern has a capture group, the text matched by the first group is kept. " +
+					"Credentials in URLs, authorization headers and GitHub tokens are always redacted.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(validRegexp()),
+				},
+			},
+			"audit_log": schema.StringAttribute{
+				MarkdownDescription: "Path of a file to which a JSON record is appended for every command that changes the system " +
+					"(installs, removals, pins, repository and service changes), including the resource, package versions, " +
+					"redacted command, exit code, duration and user. Each record carries the hash of the one before it so that " +
+					"edited or deleted entries can be detected with the `pkg_audit_log` data source. " +
+					"Defaults to no audit log.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"otlp_endpoint": schema.StringAttribute{
+				MarkdownDescription: "OTLP/HTTP endpoint to export OpenTelemetry traces of resource operations, package manager calls " +
+					"and commands to (e.g., 'http://collector:4318'). The standard OTEL_EXPORTER_OTLP_* environment variables " +
+					"are honored for headers, TLS and timeouts, and enable tracing when set. " +
+					"Defaults to OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT; tracing is disabled if neither is set.",
+				Optional:   true,
+				Validators: []validator.String{httpURL()},
+			},
+			"vulnerability_database": schema.StringAttribute{
+				MarkdownDescription: "Path of an OSV-format vulnerability database used by `pkg_security_info` and " +
+					"`pkg_vulnerability_report`: a directory of advisory JSON files, or a zip of them such as the " +
+					"`<ecosystem>/all.zip` exports from https://osv-vulnerabilities.storage.googleapis.com. " +
+					"The database is read-only to the provider and should be refreshed out of band; it is reloaded " +
+					"when its modification time changes. Defaults to no database.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"retry_count": schema.Int64Attribute{
 				MarkdownDescription: "Number of times to retry failed operations. " +
 					"Defaults to 3.",
 				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 			"retry_delay": schema.StringAttribute{
 				MarkdownDescription: "Delay between retry attempts (e.g., '30s', '1m'). " +
 					"Defaults to '30s'.",
-				Optional: true,
+				Optional:   true,
+				Validators: []validator.String{positiveDuration()},
 			},
 			"fail_on_download": schema.BoolAttribute{
 				MarkdownDescription: "Whether to fail immediately on download errors. " +
@@ -155,6 +273,10 @@ func (p *PackageProvider) Schema(
 			},
 			"cleanup_on_error": schema.BoolAttribute{
 				MarkdownDescription: "Whether to clean up partial installations on error. " +
+					"When enabled, packages installed by a failed operation (including dependencies) are removed again " +
+					"and, for APT, `dpkg --configure -a` and `apt-get -f install` are run to repair the package database. " +
+					"Install transactions, such as each batch of installs, run one at a time per package manager across all " +
+					"provider configurations, so a rollback only removes what the failed transaction installed. " +
 					"Defaults to true.",
 				Optional: true,
 			},
@@ -172,6 +294,36 @@ func (p *PackageProvider) Schema(
 	}
 }
 
+// ValidateConfig warns about settings that have no effect in combination.
+func (p *PackageProvider) ValidateConfig(
+	ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data PackageProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateCache := data.UpdateCache.ValueString()
+	if !data.CacheValidTime.IsNull() && !data.UpdateCache.IsNull() && !data.UpdateCache.IsUnknown() &&
+		updateCache != "on_change" {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("cache_valid_time"),
+			"Ignored Attribute",
+			fmt.Sprintf("cache_valid_time only applies when update_cache is 'on_change', but update_cache is '%s'.",
+				updateCache),
+		)
+	}
+	if !data.RetryDelay.IsNull() && !data.RetryCount.IsUnknown() && !data.RetryCount.IsNull() &&
+		data.RetryCount.ValueInt64() == 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("retry_delay"),
+			"Ignored Attribute",
+			"retry_delay has no effect when retry_count is 0.",
+		)
+	}
+}
+
 // Configure configures the provider with user settings.
 func (p *PackageProvider) Configure(
 	ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -196,9 +348,18 @@ func (p *PackageProvider) Configure(
 	if data.UpdateCache.IsNull() {
 		data.UpdateCache = types.StringValue("on_change")
 	}
+	if data.CacheValidTime.IsNull() {
+		data.CacheValidTime = types.StringValue("0s")
+	}
+	if data.BatchWindow.IsNull() {
+		data.BatchWindow = types.StringValue("2s")
+	}
 	if data.LockTimeout.IsNull() {
 		data.LockTimeout = types.StringValue("10m")
 	}
+	if data.TerminationGracePeriod.IsNull() {
+		data.TerminationGracePeriod = types.StringValue("10s")
+	}
 
 	// Set defaults for error handling
 	if data.RetryCount.IsNull() {
@@ -243,8 +404,92 @@ func (p *PackageProvider) Configure(
 		return
 	}
 
+	cacheValidTime, err := time.ParseDuration(data.CacheValidTime.ValueString())
+	if err != nil || cacheValidTime < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid cache_valid_time",
+			fmt.Sprintf("cache_valid_time must be a non-negative duration such as '30m' or '1h', got: %q",
+				data.CacheValidTime.ValueString()),
+		)
+		return
+	}
+
+	batchWindow, err := time.ParseDuration(data.BatchWindow.ValueString())
+	if err != nil || batchWindow < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid batch_window",
+			fmt.Sprintf("batch_window must be a non-negative duration such as '2s', got: %q",
+				data.BatchWindow.ValueString()),
+		)
+		return
+	}
+
+	gracePeriod, err := time.ParseDuration(data.TerminationGracePeriod.ValueString())
+	if err != nil || gracePeriod < 0 {
+		resp.Diagnostics.AddError(
+			"Invalid termination_grace_period",
+			fmt.Sprintf("termination_grace_period must be a non-negative duration such as '10s', got: %q",
+				data.TerminationGracePeriod.ValueString()),
+		)
+		return
+	}
+
+	lockTimeout, err := time.ParseDuration(data.LockTimeout.ValueString())
+	if err != nil || lockTimeout <= 0 {
+		resp.Diagnostics.AddError(
+			"Invalid lock_timeout",
+			fmt.Sprintf("lock_timeout must be a positive duration such as '10m', got: %q",
+				data.LockTimeout.ValueString()),
+		)
+		return
+	}
+
+	var sensitiveEnvVars, redactPatterns []string
+	if !data.SensitiveEnvVars.IsNull() {
+		resp.Diagnostics.Append(data.SensitiveEnvVars.ElementsAs(ctx, &sensitiveEnvVars, false)...)
+	}
+	if !data.RedactPatterns.IsNull() {
+		resp.Diagnostics.Append(data.RedactPatterns.ElementsAs(ctx, &redactPatterns, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	redactor, err := executor.NewRedactor(sensitiveEnvVars, redactPatterns)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid redact_patterns", err.Error())
+		return
+	}
+
+	telemetryConfig := telemetry.Config{Endpoint: data.OTLPEndpoint.ValueString(), Version: p.version}
+	if telemetryConfig.Endpoint != "" {
+		if u, err := url.Parse(telemetryConfig.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			resp.Diagnostics.AddError(
+				"Invalid otlp_endpoint",
+				fmt.Sprintf("otlp_endpoint must be an http or https URL such as 'http://collector:4318', got: %q",
+					telemetryConfig.Endpoint),
+			)
+			return
+		}
+	}
+	if err := telemetry.Setup(ctx, telemetryConfig); err != nil {
+		resp.Diagnostics.AddWarning("Tracing Disabled", "Could not set up OpenTelemetry trace export: "+err.Error())
+	}
+
 	// Create executor and registry
 	exec := executor.NewSystemExecutor()
+	exec.SetTerminationGracePeriod(gracePeriod)
+	exec.SetRedactor(redactor)
+	var auditLogger *audit.Logger
+	var commandExecutor executor.Executor = exec
+	if !data.AuditLog.IsNull() && data.AuditLog.ValueString() != "" {
+		auditLogger, err = audit.NewLogger(data.AuditLog.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid audit_log", err.Error())
+			return
+		}
+		commandExecutor = audit.NewExecutor(exec, auditLogger, redactor)
+	}
 	reg := registry.NewDefaultRegistry()
 	diagHelpers := NewDiagnosticHelpers()
 
@@ -265,12 +510,19 @@ func (p *PackageProvider) Configure(
 
 	// Create provider data
 	providerData := &ProviderData{
-		Executor:       exec,
-		Registry:       reg,
-		Config:         &data,
-		DiagHelpers:    diagHelpers,
-		DetectedOS:     detectedOS,
-		PrivilegeCheck: privilegeCheck,
+		Executor:        commandExecutor,
+		Registry:        reg,
+		Config:          &data,
+		DiagHelpers:     diagHelpers,
+		DetectedOS:      detectedOS,
+		PrivilegeCheck:  privilegeCheck,
+		CacheTracker:    NewCacheTracker(data.UpdateCache.ValueString(), cacheValidTime),
+		Batcher:         NewInstallBatcher(batchWindow, data.CleanupOnError.ValueBool()),
+		Inventories:     NewInventoryRegistry(),
+		LockTimeout:     lockTimeout,
+		AuditLog:        auditLogger,
+		Vulnerabilities: osv.NewCache(),
+		Version:         p.version,
 	}
 
 	resp.DataSourceData = providerData
@@ -281,6 +533,7 @@ func (p *PackageProvider) Configure(
 func (p *PackageProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewPackageResource,
+		NewPackagesResource,
 		NewRepositoryResource,
 		// Service management resource
 		NewServiceResource,
@@ -300,6 +553,12 @@ func (p *PackageProvider) DataSources(_ context.Context) []func() datasource.Dat
 		NewDependenciesDataSource,
 		NewVersionHistoryDataSource,
 		NewSecurityInfoDataSource,
+		NewVulnerabilityReportDataSource,
+		NewSBOMDataSource,
+		NewPackageFilesDataSource,
+		NewFileOwnerDataSource,
+		NewPlatformDataSource,
+		NewAuditLogDataSource,
 		// Service status data sources
 		NewServiceStatusDataSource,
 		NewServicesOverviewDataSource,
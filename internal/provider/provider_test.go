@@ -124,9 +124,9 @@ func TestPackageProvider_Resources(t *testing.T) {
 
 	resources := p.Resources(ctx)
 
-	// Should have 3 resources in Phase 2 (pkg_package, pkg_repo, pkg_service)
-	if len(resources) != 3 {
-		t.Errorf("Expected 3 resources in Phase 2 (including service management), got %d", len(resources))
+	// pkg_package, pkg_packages, pkg_repo, pkg_service
+	if len(resources) != 4 {
+		t.Errorf("Expected 4 resources (including package sets and service management), got %d", len(resources))
 	}
 }
 
@@ -136,9 +136,9 @@ func TestPackageProvider_DataSources(t *testing.T) {
 
 	dataSources := p.DataSources(ctx)
 
-	// Should have 12 data sources in Phase 2.4 (comprehensive data source suite + service status)
-	if len(dataSources) != 12 {
-		t.Errorf("Expected 12 data sources in Phase 2.4 (including service status), got %d", len(dataSources))
+	// Should have 18 data sources (comprehensive data source suite + service status + audit log + vulnerability report + SBOM + package files + file owner + platform)
+	if len(dataSources) != 18 {
+		t.Errorf("Expected 18 data sources (including service status, audit log, vulnerability report, SBOM, package files, file owner and platform), got %d", len(dataSources))
 	}
 }
 
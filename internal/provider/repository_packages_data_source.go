@@ -25,15 +25,16 @@ package provider
 import (
 	"context"
 	"fmt"
-	"runtime"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -86,18 +87,26 @@ func (d *RepositoryPackagesDataSource) Schema(
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to query. " +
-					"Valid values: 'auto', 'brew'. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
 					"Defaults to 'auto'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
 			},
 			"repository": schema.StringAttribute{
-				MarkdownDescription: "Repository or tap name to list packages from (e.g., 'homebrew/cask-fonts').",
-				Required:            true,
+				MarkdownDescription: "Repository or tap name to list packages from (e.g., 'homebrew/cask-fonts'). " +
+					"For APT, the source URI optionally followed by the suite and component path " +
+					"(e.g., 'deb.nodesource.com/node_20.x' or 'archive.ubuntu.com/ubuntu/dists/jammy/universe').",
+				Required: true,
 			},
 			"limit": schema.Int64Attribute{
 				MarkdownDescription: "Maximum number of packages to return. " +
 					"Defaults to 100.",
 				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 			"packages": schema.ListNestedAttribute{
 				MarkdownDescription: "List of packages available from the repository.",
@@ -156,29 +165,14 @@ func (d *RepositoryPackagesDataSource) Read(
 	}
 
 	// Determine package manager
-	managerName := "auto"
+	managerName := managerAuto
 	if !data.Manager.IsNull() {
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	if managerName == "auto" {
-		if runtime.GOOS != "darwin" {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		managerName = "brew"
-	}
-
-	// Only support brew in Phase 2
-	if managerName != "brew" {
-		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", managerName),
-		)
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
 		return
 	}
 
@@ -190,7 +184,7 @@ func (d *RepositoryPackagesDataSource) Read(
 
 	// Get packages from repository
 	repository := data.Repository.ValueString()
-	packages, err := d.getRepositoryBrewPackages(ctx, repository, limit)
+	packages, err := d.getRepositoryPackages(ctx, managerName, repository, limit)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to List Repository Packages",
@@ -221,57 +215,30 @@ func (d *RepositoryPackagesDataSource) Read(
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (d *RepositoryPackagesDataSource) getRepositoryBrewPackages(ctx context.Context, repository string, limit int64) ([]RepositoryPackageInfo, error) {
-	brewPath := d.providerData.Config.BrewPath.ValueString()
-	if brewPath == "" {
-		brewPath = "brew"
+func (d *RepositoryPackagesDataSource) getRepositoryPackages(
+	ctx context.Context, managerName, repository string, limit int64) ([]RepositoryPackageInfo, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Search for packages in the specific tap
-	// Use search with tap prefix to find packages from that tap
-	result, err := d.providerData.Executor.Run(ctx, brewPath, []string{"search", "--formula", repository + "/"}, executor.ExecOpts{
-		Timeout: 60,
-	})
-
-	if err != nil || result.ExitCode != 0 {
-		return nil, fmt.Errorf("failed to search repository packages: exit code %d, error: %w", result.ExitCode, err)
+	browser, ok := manager.(adapters.RepositoryBrowser)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot list repository packages", manager.GetManagerName())
 	}
 
-	var packages []RepositoryPackageInfo
-	lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
-	count := int64(0)
-
-	for _, line := range lines {
-		if count >= limit {
-			break
-		}
-
-		line = strings.TrimSpace(line)
-		if line == "" || strings.Contains(line, "==>") {
-			continue
-		}
-
-		// Parse package names (they might be in columns)
-		names := strings.Fields(line)
-		for _, name := range names {
-			if count >= limit {
-				break
-			}
-
-			name = strings.TrimSpace(name)
-			if name != "" && strings.HasPrefix(name, repository+"/") {
-				// Remove the tap prefix to get just the package name
-				packageName := strings.TrimPrefix(name, repository+"/")
-
-				packages = append(packages, RepositoryPackageInfo{
-					Name:        types.StringValue(packageName),
-					Description: types.StringValue(""), // Description requires individual brew info calls
-					Version:     types.StringValue(""), // Version requires individual brew info calls
-				})
-				count++
-			}
-		}
+	packages, err := browser.RepositoryPackages(ctx, repository, int(limit))
+	if err != nil {
+		return nil, err
 	}
 
-	return packages, nil
+	infos := make([]RepositoryPackageInfo, 0, len(packages))
+	for _, pkg := range packages {
+		infos = append(infos, RepositoryPackageInfo{
+			Name:        types.StringValue(pkg.Name),
+			Description: types.StringValue(pkg.Description),
+			Version:     types.StringValue(pkg.Version),
+		})
+	}
+	return infos, nil
 }
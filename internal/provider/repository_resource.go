@@ -24,24 +24,39 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"runtime"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 	"github.com/jamesainslie/terraform-provider-package/internal/adapters/brew"
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
 )
 
+// repositoryErrorAttributes points repository failures at the attribute to fix.
+var repositoryErrorAttributes = ErrorAttributes{
+	adapters.ErrNotFound: path.Root("name"),
+	adapters.ErrNetwork:  path.Root("uri"),
+}
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &RepositoryResource{}
 var _ resource.ResourceWithImportState = &RepositoryResource{}
+var _ resource.ResourceWithValidateConfig = &RepositoryResource{}
+
+// tapNamePattern matches Homebrew tap names such as 'homebrew/cask-fonts'.
+var tapNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
 
 // NewRepositoryResource creates a new repository resource.
 func NewRepositoryResource() resource.Resource {
@@ -91,21 +106,33 @@ func (r *RepositoryResource) Schema(
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerBrew, "apt", "winget", "choco"),
+				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Repository name or identifier.",
+				MarkdownDescription: "Repository name or identifier. For Homebrew taps, this is 'user/repo'.",
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"uri": schema.StringAttribute{
-				MarkdownDescription: "Repository URI. For Homebrew taps, this is the tap name (e.g., 'homebrew/cask-fonts'). " +
+				MarkdownDescription: "Repository URI. For Homebrew taps, this is the tap name (e.g., 'homebrew/cask-fonts') " +
+					"or a git remote URL for the tap given in `name`. Marked sensitive because private tap URLs often " +
+					"embed access tokens; credentials in the URL are also redacted from logs and errors. " +
 					"For APT, this is the repository line.",
-				Required: true,
+				Required:  true,
+				Sensitive: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"gpg_key": schema.StringAttribute{
 				MarkdownDescription: "GPG key URL or content for repository verification. " +
@@ -124,6 +151,55 @@ func (r *RepositoryResource) Schema(
 	}
 }
 
+// ValidateConfig checks manager-specific rules for the repository attributes.
+func (r *RepositoryResource) ValidateConfig(
+	ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data RepositoryResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Manager.IsUnknown() || data.Manager.IsNull() {
+		return
+	}
+
+	switch data.Manager.ValueString() {
+	case managerBrew:
+	case "apt", "winget", "choco":
+		resp.Diagnostics.AddAttributeError(
+			path.Root("manager"),
+			"Unsupported Repository Manager",
+			fmt.Sprintf("Repositories can currently only be managed for 'brew', got: %q", data.Manager.ValueString()),
+		)
+		return
+	default:
+		// Rejected by the schema validator
+		return
+	}
+
+	if !data.GPGKey.IsNull() && !data.GPGKey.IsUnknown() && data.GPGKey.ValueString() != "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("gpg_key"),
+			"Invalid Attribute Combination",
+			"gpg_key is not supported for Homebrew taps. Remove it from the configuration.",
+		)
+	}
+	if !data.Name.IsUnknown() && !tapNamePattern.MatchString(data.Name.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Invalid Tap Name",
+			fmt.Sprintf("Homebrew tap names must have the form 'user/repo', got: %q", data.Name.ValueString()),
+		)
+	}
+	uri := data.URI.ValueString()
+	if !data.URI.IsUnknown() && !strings.Contains(uri, "://") && !strings.HasPrefix(uri, "git@") &&
+		!tapNamePattern.MatchString(uri) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("uri"),
+			"Invalid Tap URI",
+			fmt.Sprintf("uri must be a tap name such as 'homebrew/cask-fonts' or a git remote URL, got: %q", uri),
+		)
+	}
+}
+
 // Configure configures the resource with provider data.
 func (r *RepositoryResource) Configure(
 	_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -168,11 +244,13 @@ func (r *RepositoryResource) Create(
 	uri := data.URI.ValueString()
 	gpgKey := data.GPGKey.ValueString()
 
-	if err := repoManager.AddRepository(ctx, name, uri, gpgKey); err != nil {
-		resp.Diagnostics.AddError(
+	auditCtx := repositoryAuditScope(ctx, data.Manager.ValueString(), name, "create")
+	if err := repoManager.AddRepository(auditCtx, name, uri, gpgKey); err != nil {
+		resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
 			"Repository Addition Failed",
-			fmt.Sprintf("Failed to add repository %s: %v", name, err),
-		)
+			fmt.Sprintf("Failed to add repository %s", name),
+			err, repositoryErrorAttributes,
+		))
 		return
 	}
 
@@ -266,15 +344,35 @@ func (r *RepositoryResource) Delete(
 
 	// Remove the repository
 	name := data.Name.ValueString()
-	if err := repoManager.RemoveRepository(ctx, name); err != nil {
-		resp.Diagnostics.AddError(
+	auditCtx := repositoryAuditScope(ctx, data.Manager.ValueString(), name, "delete")
+	if err := repoManager.RemoveRepository(auditCtx, name); err != nil {
+		// A repository that is already gone is the desired end state
+		if errors.Is(err, adapters.ErrNotFound) {
+			resp.Diagnostics.AddWarning(
+				"Repository Already Removed",
+				fmt.Sprintf("Repository %s was not configured; removing it from state: %v", name, err),
+			)
+			return
+		}
+		resp.Diagnostics.Append(r.providerData.DiagHelpers.AdapterErrorDiagnostic(
 			"Repository Removal Failed",
-			fmt.Sprintf("Failed to remove repository %s: %v", name, err),
-		)
+			fmt.Sprintf("Failed to remove repository %s", name),
+			err, repositoryErrorAttributes,
+		))
 		return
 	}
 }
 
+// repositoryAuditScope attaches the repository resource to ctx for the audit log.
+func repositoryAuditScope(ctx context.Context, managerName, name, operation string) context.Context {
+	return audit.WithScope(ctx, audit.Scope{
+		ResourceType:       "pkg_repo",
+		ResourceID:         fmt.Sprintf("%s:%s", managerName, name),
+		TerraformOperation: operation,
+		Manager:            managerName,
+	})
+}
+
 // ImportState imports an existing resource.
 func (r *RepositoryResource) ImportState(
 	ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -0,0 +1,385 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
+)
+
+// rollbackTimeout bounds the cleanup work performed after a failed operation.
+// Cleanup runs on a fresh context because the operation context has often
+// already expired by the time we get here.
+const rollbackTimeout = 10 * time.Minute
+
+// rollbackScopes serializes installs per package manager across the provider
+// process, including provider aliases and whether or not their rollback is
+// enabled, so the packages that appear between a snapshot and a failure were
+// installed by that operation alone and not by a concurrent resource.
+var rollbackScopes = newManagerLocks()
+
+// managerLocks hands out one exclusive slot per package manager.
+type managerLocks struct {
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// newManagerLocks creates an empty set of manager locks.
+func newManagerLocks() *managerLocks {
+	return &managerLocks{slots: make(map[string]chan struct{})}
+}
+
+// acquire waits until the manager's slot is free and returns a function that
+// frees it again, or fails once ctx is done.
+func (l *managerLocks) acquire(ctx context.Context, managerName string) (func(), error) {
+	l.mu.Lock()
+	slot, ok := l.slots[managerName]
+	if !ok {
+		slot = make(chan struct{}, 1)
+		l.slots[managerName] = slot
+	}
+	l.mu.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for another %s operation to finish: %w", managerName, ctx.Err())
+	}
+}
+
+// installSnapshot records the set of packages installed before a mutating
+// operation so that anything the operation added can be removed on failure.
+// A scope without a recorded set only holds the manager's slot.
+type installSnapshot struct {
+	manager adapters.PackageManager
+	before  map[string]bool
+
+	// release frees the manager for other rollback scopes
+	release func()
+}
+
+// RollbackReport summarises the cleanup performed after a failed operation.
+type RollbackReport struct {
+	Removed       []string
+	Failed        map[string]string
+	RecoveryError string
+}
+
+// takeInstallSnapshot captures the installed package set for the manager.
+// It returns nil if the manager cannot enumerate installed packages, in which
+// case rollback is not possible.
+func takeInstallSnapshot(ctx context.Context, manager adapters.PackageManager) (*installSnapshot, error) {
+	lister, ok := manager.(adapters.InstalledLister)
+	if !ok {
+		return nil, nil
+	}
+
+	installed, err := lister.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	before := make(map[string]bool, len(installed))
+	for _, pkg := range installed {
+		before[pkg.Name] = true
+	}
+
+	return &installSnapshot{manager: manager, before: before}, nil
+}
+
+// rollback repairs the package database where supported and removes every
+// package that was installed after the snapshot was taken. Rollback scopes of
+// a manager do not overlap, so these are the packages the failed operation
+// installed.
+func (s *installSnapshot) rollback(ctx context.Context) *RollbackReport {
+	report := &RollbackReport{Failed: map[string]string{}}
+
+	if recoverer, ok := s.manager.(adapters.StateRecoverer); ok {
+		if err := recoverer.Recover(audit.WithoutPackage(ctx)); err != nil {
+			report.RecoveryError = err.Error()
+		}
+	}
+
+	lister := s.manager.(adapters.InstalledLister)
+	installed, err := lister.ListInstalled(ctx)
+	if err != nil {
+		report.Failed["*"] = fmt.Sprintf("failed to list installed packages: %v", err)
+		return report
+	}
+
+	pending := make([]adapters.PackageInfo, 0)
+	for _, pkg := range installed {
+		if s.before[pkg.Name] {
+			continue
+		}
+		pending = append(pending, pkg)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Name < pending[j].Name })
+
+	// Newly installed packages may depend on each other, and some managers refuse
+	// to remove a package that is still required. Keep making passes while at
+	// least one removal succeeds.
+	for len(pending) > 0 {
+		var remaining []adapters.PackageInfo
+		for _, pkg := range pending {
+			if err := s.manager.RemoveWithType(audit.WithPackage(ctx, pkg.Name), pkg.Name, pkg.Type); err != nil {
+				report.Failed[pkg.Name] = err.Error()
+				remaining = append(remaining, pkg)
+				continue
+			}
+			delete(report.Failed, pkg.Name)
+			report.Removed = append(report.Removed, pkg.Name)
+		}
+		if len(remaining) == len(pending) {
+			break
+		}
+		pending = remaining
+	}
+
+	return report
+}
+
+// beginRollbackScope waits for any other scope on the same manager to end
+// and, when cleanup_on_error is enabled, snapshots the installed packages.
+// Callers must end the scope once the operation has finished. A scope without
+// a snapshot, or a nil one, means no rollback will be attempted.
+func (p *ProviderData) beginRollbackScope(
+	ctx context.Context, manager adapters.PackageManager, diags *diag.Diagnostics) *installSnapshot {
+	scope, err := openRollbackScope(ctx, manager, p.cleanupOnError())
+	if err != nil && p.cleanupOnError() {
+		diags.AddWarning("Rollback Snapshot Failed", rollbackSnapshotFailed(err))
+	}
+	return scope
+}
+
+// cleanupOnError reports whether failed operations should be rolled back.
+func (p *ProviderData) cleanupOnError() bool {
+	return p != nil && p.Config != nil && p.Config.CleanupOnError.ValueBool()
+}
+
+// openRollbackScope waits for the manager's slot and, when rollback is set,
+// snapshots the installed packages. If the snapshot fails the scope keeps the
+// slot without one and the error is returned alongside it; the scope is nil
+// only if the slot could not be acquired.
+func openRollbackScope(ctx context.Context, manager adapters.PackageManager, rollback bool) (*installSnapshot, error) {
+	release, err := rollbackScopes.acquire(ctx, manager.GetManagerName())
+	if err != nil {
+		return nil, err
+	}
+
+	scope := &installSnapshot{manager: manager, release: release}
+	if !rollback {
+		return scope, nil
+	}
+
+	snapshot, err := takeInstallSnapshot(ctx, manager)
+	if err != nil {
+		return scope, err
+	}
+	if snapshot == nil {
+		tflog.Debug(ctx, "Package manager does not support listing installed packages, rollback disabled", map[string]interface{}{
+			"manager": manager.GetManagerName(),
+		})
+		return scope, nil
+	}
+	scope.before = snapshot.before
+
+	return scope, nil
+}
+
+// rollbackScoped runs install in a scope of its own, and when enabled undoes
+// whatever it installed if it fails. The returned error carries the rollback
+// outcome for reportRollback.
+func rollbackScoped(ctx context.Context, manager adapters.PackageManager, enabled bool, install func() error) error {
+	scope, scopeErr := openRollbackScope(ctx, manager, enabled)
+	defer scope.end()
+
+	err := scope.undo(ctx, install())
+	if err != nil && scopeErr != nil && enabled {
+		return &rollbackError{err: err, snapshotErr: scopeErr}
+	}
+	return err
+}
+
+// retake records the currently installed packages as the new baseline while
+// keeping the scope's manager slot.
+func (s *installSnapshot) retake(ctx context.Context) error {
+	if s.before == nil {
+		return nil
+	}
+	fresh, err := takeInstallSnapshot(ctx, s.manager)
+	if err != nil {
+		return err
+	}
+	s.before = fresh.before
+	return nil
+}
+
+// undo rolls back the scope when err is non-nil and returns err annotated
+// with the rollback report. It is safe to call on a nil snapshot.
+func (s *installSnapshot) undo(ctx context.Context, err error) error {
+	if s == nil || s.before == nil || err == nil {
+		return err
+	}
+
+	rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	return &rollbackError{err: err, report: s.rollback(rollbackCtx)}
+}
+
+// rollbackError is an operation error annotated with the rollback that ran
+// after it, or with the reason no rollback was possible.
+type rollbackError struct {
+	err         error
+	report      *RollbackReport
+	snapshotErr error
+}
+
+func (e *rollbackError) Error() string { return e.err.Error() }
+
+func (e *rollbackError) Unwrap() error { return e.err }
+
+// rollbackSnapshotFailed renders the warning detail for a scope that could not be opened.
+func rollbackSnapshotFailed(err error) string {
+	return fmt.Sprintf("Could not record installed packages before the operation; "+
+		"partial installations will not be cleaned up on error: %v", err)
+}
+
+// end frees the manager for other rollback scopes once the operation, and
+// any rollback of it, has finished. It is safe to call on a nil snapshot.
+func (s *installSnapshot) end() {
+	if s != nil && s.release != nil {
+		s.release()
+		s.release = nil
+	}
+}
+
+// rollbackOnFailure undoes a failed operation and reports what was rolled back.
+func (p *ProviderData) rollbackOnFailure(ctx context.Context, snapshot *installSnapshot, diags *diag.Diagnostics) {
+	if snapshot == nil || snapshot.before == nil {
+		return
+	}
+
+	rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	addRollbackWarning(ctx, snapshot.manager, snapshot.rollback(rollbackCtx), diags)
+}
+
+// reportRollback adds the rollback recorded on an error returned by a
+// rollback scope, if any, to diags.
+func reportRollback(ctx context.Context, manager adapters.PackageManager, err error, diags *diag.Diagnostics) {
+	var rollbackErr *rollbackError
+	if !errors.As(err, &rollbackErr) {
+		return
+	}
+	if rollbackErr.snapshotErr != nil {
+		diags.AddWarning("Rollback Snapshot Failed", rollbackSnapshotFailed(rollbackErr.snapshotErr))
+		return
+	}
+	addRollbackWarning(ctx, manager, rollbackErr.report, diags)
+}
+
+// addRollbackWarning logs a rollback and warns about it when it changed anything.
+func addRollbackWarning(ctx context.Context, manager adapters.PackageManager, report *RollbackReport, diags *diag.Diagnostics) {
+	tflog.Debug(ctx, "Rollback completed", map[string]interface{}{
+		"manager":        manager.GetManagerName(),
+		"removed":        report.Removed,
+		"failed_count":   len(report.Failed),
+		"recovery_error": report.RecoveryError,
+	})
+
+	if len(report.Removed) == 0 && len(report.Failed) == 0 && report.RecoveryError == "" {
+		return
+	}
+
+	diags.AddWarning("Partial Installation Rolled Back", report.String())
+}
+
+// rollbackDependencies removes the dependencies a failed create installed
+// ahead of its main package. They were installed in earlier transactions, so
+// the main package's rollback scope does not cover them.
+func (p *ProviderData) rollbackDependencies(ctx context.Context, manager adapters.PackageManager,
+	dependencies []string, diags *diag.Diagnostics) {
+	if len(dependencies) == 0 {
+		return
+	}
+
+	rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	report := &RollbackReport{Failed: map[string]string{}}
+	for i := len(dependencies) - 1; i >= 0; i-- {
+		name := dependencies[i]
+		if err := manager.RemoveWithType(audit.WithPackage(rollbackCtx, name), name, adapters.PackageTypeAuto); err != nil {
+			report.Failed[name] = err.Error()
+			continue
+		}
+		report.Removed = append(report.Removed, name)
+	}
+
+	addRollbackWarning(ctx, manager, report, diags)
+}
+
+// String renders the report as diagnostic detail text.
+func (r *RollbackReport) String() string {
+	var b strings.Builder
+
+	if r.RecoveryError != "" {
+		fmt.Fprintf(&b, "Package database recovery failed: %s\n", r.RecoveryError)
+	}
+
+	if len(r.Removed) > 0 {
+		fmt.Fprintf(&b, "Removed packages installed by the failed operation: %s\n", strings.Join(r.Removed, ", "))
+	} else {
+		b.WriteString("No packages needed to be removed.\n")
+	}
+
+	if len(r.Failed) > 0 {
+		names := make([]string, 0, len(r.Failed))
+		for name := range r.Failed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		b.WriteString("The following packages could not be removed and may need manual cleanup:\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "  - %s: %s\n", name, r.Failed[name])
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,218 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallSnapshot_RollbackRemovesNewPackages(t *testing.T) {
+	ctx := context.Background()
+	manager := newFakePackageManager(map[string]string{"curl": "7.0"})
+
+	snapshot, err := takeInstallSnapshot(ctx, manager)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+
+	// Simulate a failed operation that left two dependencies behind
+	manager.installed["libfoo"] = "1.0"
+	manager.installed["libbar"] = "2.0"
+
+	report := snapshot.rollback(ctx)
+
+	assert.True(t, manager.recovered, "Recover should run before removal")
+	assert.Equal(t, []string{"libbar", "libfoo"}, report.Removed)
+	assert.Empty(t, report.Failed)
+	assert.Contains(t, manager.installed, "curl", "Pre-existing packages must not be removed")
+	assert.Len(t, manager.installed, 1)
+}
+
+func TestInstallSnapshot_RollbackRetriesDependentRemovals(t *testing.T) {
+	ctx := context.Background()
+	manager := newFakePackageManager(nil)
+
+	snapshot, err := takeInstallSnapshot(ctx, manager)
+	require.NoError(t, err)
+
+	// "aaa-lib" sorts first but cannot be removed while "app" is installed
+	manager.installed["aaa-lib"] = "1.0"
+	manager.installed["app"] = "1.0"
+	manager.requiredBy = map[string]string{"aaa-lib": "app"}
+
+	report := snapshot.rollback(ctx)
+	assert.Equal(t, []string{"app", "aaa-lib"}, report.Removed)
+	assert.Empty(t, report.Failed)
+	assert.Empty(t, manager.installed)
+}
+
+func TestInstallSnapshot_RollbackReportsUnremovable(t *testing.T) {
+	ctx := context.Background()
+	manager := newFakePackageManager(nil)
+
+	snapshot, err := takeInstallSnapshot(ctx, manager)
+	require.NoError(t, err)
+
+	manager.installed["stuck"] = "1.0"
+	manager.removeErr["stuck"] = fmt.Errorf("dpkg lock held")
+
+	report := snapshot.rollback(ctx)
+	assert.Empty(t, report.Removed)
+	assert.Equal(t, "dpkg lock held", report.Failed["stuck"])
+	assert.Contains(t, report.String(), "may need manual cleanup")
+}
+
+func TestInstallSnapshot_UnsupportedManager(t *testing.T) {
+	snapshot, err := takeInstallSnapshot(context.Background(), &nonListingManager{})
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+}
+
+func TestPackageResource_BeginRollbackScope_Disabled(t *testing.T) {
+	r := &PackageResource{providerData: &ProviderData{
+		Config: &PackageProviderModel{CleanupOnError: types.BoolValue(false)},
+	}}
+	var diags diag.Diagnostics
+
+	scope := r.providerData.beginRollbackScope(context.Background(), newFakePackageManager(nil), &diags)
+	require.NotNil(t, scope, "the manager slot is held even without rollback")
+	defer scope.end()
+	assert.Nil(t, scope.before)
+	assert.Empty(t, diags)
+}
+
+func TestRollbackScoped_DisabledWaitsForRollbackScope(t *testing.T) {
+	ctx := context.Background()
+	providerData := &ProviderData{
+		Config: &PackageProviderModel{CleanupOnError: types.BoolValue(true)},
+	}
+	manager := newFakePackageManager(nil)
+
+	scope := providerData.beginRollbackScope(ctx, manager, &diag.Diagnostics{})
+	require.NotNil(t, scope)
+
+	// An install through an alias without cleanup_on_error must not land
+	// inside another alias's snapshot, where a rollback would remove it
+	installed := make(chan struct{})
+	go func() {
+		_ = rollbackScoped(ctx, manager, false, func() error {
+			close(installed)
+			return nil
+		})
+	}()
+
+	select {
+	case <-installed:
+		t.Fatal("install ran while another rollback scope held the manager")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	scope.end()
+	select {
+	case <-installed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("install did not run once the scope ended")
+	}
+}
+
+func TestPackageResource_RollbackOnFailure_ReportsWarning(t *testing.T) {
+	ctx := context.Background()
+	manager := newFakePackageManager(nil)
+	r := &PackageResource{providerData: &ProviderData{
+		Config: &PackageProviderModel{CleanupOnError: types.BoolValue(true)},
+	}}
+	var diags diag.Diagnostics
+
+	snapshot := r.providerData.beginRollbackScope(ctx, manager, &diags)
+	require.NotNil(t, snapshot)
+	defer snapshot.end()
+
+	manager.installed["libfoo"] = "1.0"
+	r.providerData.rollbackOnFailure(ctx, snapshot, &diags)
+
+	require.Len(t, diags.Warnings(), 1)
+	assert.Equal(t, "Partial Installation Rolled Back", diags.Warnings()[0].Summary())
+	assert.Contains(t, diags.Warnings()[0].Detail(), "libfoo")
+}
+
+func TestBeginRollbackScope_SerializesPerManager(t *testing.T) {
+	ctx := context.Background()
+	providerData := &ProviderData{
+		Config: &PackageProviderModel{CleanupOnError: types.BoolValue(true)},
+	}
+
+	first := providerData.beginRollbackScope(ctx, newFakePackageManager(nil), &diag.Diagnostics{})
+	require.NotNil(t, first)
+
+	// A second scope on the same manager waits until the first one ends
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	var diags diag.Diagnostics
+	assert.Nil(t, providerData.beginRollbackScope(waitCtx, newFakePackageManager(nil), &diags))
+	require.Len(t, diags.Warnings(), 1)
+	assert.Contains(t, diags.Warnings()[0].Detail(), "waiting for another fake operation")
+
+	first.end()
+	second := providerData.beginRollbackScope(ctx, newFakePackageManager(nil), &diag.Diagnostics{})
+	require.NotNil(t, second)
+	second.end()
+	second.end()
+}
+
+func TestRollbackScoped_ReportsRollbackOnError(t *testing.T) {
+	ctx := context.Background()
+	manager := newFakePackageManager(nil)
+
+	err := rollbackScoped(ctx, manager, true, func() error {
+		manager.installed["libfoo"] = "1.0"
+		return fmt.Errorf("upgrade failed")
+	})
+	require.EqualError(t, err, "upgrade failed")
+	assert.NotContains(t, manager.installed, "libfoo")
+
+	var diags diag.Diagnostics
+	reportRollback(ctx, manager, err, &diags)
+	require.Len(t, diags.Warnings(), 1)
+	assert.Contains(t, diags.Warnings()[0].Detail(), "libfoo")
+}
+
+func TestProviderData_RollbackDependencies(t *testing.T) {
+	ctx := context.Background()
+	manager := newFakePackageManager(map[string]string{"libfoo": "1.0", "libbar": "2.0"})
+	manager.requiredBy = map[string]string{"libfoo": "libbar"}
+	var diags diag.Diagnostics
+
+	// Dependencies are removed in reverse install order
+	(&ProviderData{}).rollbackDependencies(ctx, manager, []string{"libfoo", "libbar"}, &diags)
+
+	assert.Empty(t, manager.installed)
+	require.Len(t, diags.Warnings(), 1)
+	assert.Contains(t, diags.Warnings()[0].Detail(), "libbar, libfoo")
+}
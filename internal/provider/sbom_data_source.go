@@ -0,0 +1,363 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/sbom"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &SBOMDataSource{}
+
+// NewSBOMDataSource creates a new SBOM data source.
+func NewSBOMDataSource() datasource.DataSource {
+	return &SBOMDataSource{}
+}
+
+// SBOMDataSource defines the data source implementation.
+type SBOMDataSource struct {
+	providerData *ProviderData
+}
+
+// SBOMDataSourceModel describes the data source data model.
+type SBOMDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Managers  types.List   `tfsdk:"managers"`
+	Name      types.String `tfsdk:"name"`
+	Timestamp types.String `tfsdk:"timestamp"`
+	Packages  types.List   `tfsdk:"packages"`
+	CycloneDX types.String `tfsdk:"cyclonedx"`
+	SPDX      types.String `tfsdk:"spdx"`
+}
+
+// SBOMPackageInfo represents an installed package in the bill of materials.
+type SBOMPackageInfo struct {
+	Manager          types.String `tfsdk:"manager"`
+	Name             types.String `tfsdk:"name"`
+	Version          types.String `tfsdk:"version"`
+	Arch             types.String `tfsdk:"arch"`
+	Purl             types.String `tfsdk:"purl"`
+	License          types.String `tfsdk:"license"`
+	Supplier         types.String `tfsdk:"supplier"`
+	SourceRepository types.String `tfsdk:"source_repository"`
+}
+
+// Metadata returns the data source type name.
+// Metadata returns the data source type name.
+func (d *SBOMDataSource) Metadata(
+	_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sbom"
+}
+
+// Schema defines the data source schema.
+// Schema defines the data source schema.
+func (d *SBOMDataSource) Schema(
+	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Inventories the installed packages of one or more package managers as a software bill of " +
+			"materials, rendered as CycloneDX 1.5 and SPDX 2.3 JSON documents that can be written out with " +
+			"`local_file` or shipped elsewhere.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Data source identifier.",
+			},
+			"managers": schema.ListAttribute{
+				MarkdownDescription: "Package managers whose installed packages are inventoried. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
+					"Defaults to ['auto'].",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(managerAuto, managerBrew, managerApt)),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the inventoried system recorded in the documents. Defaults to the host name.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"timestamp": schema.StringAttribute{
+				MarkdownDescription: "RFC 3339 creation time recorded in the documents. Defaults to the current time; " +
+					"set it, e.g. to `plantimestamp()` captured in state, to keep the documents unchanged while the " +
+					"installed packages are.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					rfc3339Timestamp(),
+				},
+			},
+			"packages": schema.ListNestedAttribute{
+				MarkdownDescription: "Installed packages of each manager in the order of `managers`, sorted by name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"manager": schema.StringAttribute{
+							MarkdownDescription: "Package manager that installed the package.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Package name.",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "Installed version.",
+							Computed:            true,
+						},
+						"arch": schema.StringAttribute{
+							MarkdownDescription: "Architecture the package was built for (e.g., 'amd64', 'all' or 'arm64'). " +
+								"Empty for Homebrew casks.",
+							Computed: true,
+						},
+						"purl": schema.StringAttribute{
+							MarkdownDescription: "Package URL, e.g. 'pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12' " +
+								"or 'pkg:brew/jq@1.7.1'.",
+							Computed: true,
+						},
+						"license": schema.StringAttribute{
+							MarkdownDescription: "Declared license: the SPDX expression of a Homebrew formula, or the " +
+								"comma-separated licenses of a Debian machine-readable copyright file. Empty if unknown.",
+							Computed: true,
+						},
+						"supplier": schema.StringAttribute{
+							MarkdownDescription: "Package maintainer for APT, or the tap owner for Homebrew " +
+								"('Homebrew' for the official taps).",
+							Computed: true,
+						},
+						"source_repository": schema.StringAttribute{
+							MarkdownDescription: "Archive the installed version was downloaded from " +
+								"(e.g., 'http://deb.debian.org/debian bookworm/main'), or the Homebrew tap. " +
+								"Empty for packages not installed from a configured repository.",
+							Computed: true,
+						},
+					},
+				},
+			},
+			"cyclonedx": schema.StringAttribute{
+				MarkdownDescription: "CycloneDX 1.5 JSON document listing the packages.",
+				Computed:            true,
+			},
+			"spdx": schema.StringAttribute{
+				MarkdownDescription: "SPDX 2.3 JSON document listing the packages.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure configures the data source with provider data.
+// Configure configures the data source with provider data.
+func (d *SBOMDataSource) Configure(
+	_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *SBOMDataSource) Read(
+	ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SBOMDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Determine package managers
+	managerNames := []string{managerAuto}
+	if !data.Managers.IsNull() {
+		resp.Diagnostics.Append(data.Managers.ElementsAs(ctx, &managerNames, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	managerNames, err := resolveManagerNames(managerNames)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
+		return
+	}
+
+	components, err := d.listComponents(ctx, managerNames)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Inventory Installed Packages",
+			fmt.Sprintf("Failed to inventory installed packages: %v", err),
+		)
+		return
+	}
+
+	doc := sbom.Document{Name: data.Name.ValueString(), Components: components}
+	if doc.Name == "" {
+		if doc.Name, err = os.Hostname(); err != nil {
+			resp.Diagnostics.AddError("Failed to Determine Host Name", err.Error())
+			return
+		}
+	}
+	doc.Created = time.Now()
+	if timestamp := data.Timestamp.ValueString(); timestamp != "" {
+		if doc.Created, err = time.Parse(time.RFC3339, timestamp); err != nil {
+			resp.Diagnostics.AddError("Invalid Timestamp", err.Error())
+			return
+		}
+	}
+	if d.providerData != nil {
+		doc.ToolVersion = d.providerData.Version
+	}
+
+	cycloneDX, err := sbom.CycloneDX(doc)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Render CycloneDX Document", err.Error())
+		return
+	}
+	spdx, err := sbom.SPDX(doc)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Render SPDX Document", err.Error())
+		return
+	}
+
+	// Set computed values
+	data.ID = types.StringValue("sbom:" + doc.Name)
+	data.Name = types.StringValue(doc.Name)
+	if data.Timestamp.IsNull() || data.Timestamp.IsUnknown() {
+		data.Timestamp = types.StringValue(doc.Created.UTC().Format(time.RFC3339))
+	}
+	data.CycloneDX = types.StringValue(cycloneDX)
+	data.SPDX = types.StringValue(spdx)
+
+	var diags diag.Diagnostics
+	data.Packages, diags = types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"manager":           types.StringType,
+			"name":              types.StringType,
+			"version":           types.StringType,
+			"arch":              types.StringType,
+			"purl":              types.StringType,
+			"license":           types.StringType,
+			"supplier":          types.StringType,
+			"source_repository": types.StringType,
+		},
+	}, sbomPackageInfos(components))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// listComponents inventories the installed packages of each manager in turn.
+func (d *SBOMDataSource) listComponents(ctx context.Context, managerNames []string) ([]sbom.Component, error) {
+	components := []sbom.Component{}
+	for _, managerName := range managerNames {
+		manager, err := newPackageManager(ctx, d.providerData, managerName)
+		if err != nil {
+			return nil, err
+		}
+
+		lister, ok := manager.(adapters.ComponentLister)
+		if !ok {
+			return nil, fmt.Errorf("package manager %s cannot inventory installed packages", manager.GetManagerName())
+		}
+
+		installed, err := lister.ListComponents(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", managerName, err)
+		}
+		for _, component := range installed {
+			components = append(components, sbom.Component{Component: component, Manager: managerName})
+		}
+	}
+	return components, nil
+}
+
+// resolveManagerNames resolves "auto" in a list of package managers, keeping
+// each manager once in the order given.
+func resolveManagerNames(managerNames []string) ([]string, error) {
+	resolved := make([]string, 0, len(managerNames))
+	seen := map[string]bool{}
+	for _, managerName := range managerNames {
+		managerName, err := resolveManagerName(managerName)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[managerName] {
+			seen[managerName] = true
+			resolved = append(resolved, managerName)
+		}
+	}
+	return resolved, nil
+}
+
+// sbomPackageInfos converts inventoried packages to data source entries.
+func sbomPackageInfos(components []sbom.Component) []SBOMPackageInfo {
+	packages := make([]SBOMPackageInfo, 0, len(components))
+	for _, component := range components {
+		packages = append(packages, SBOMPackageInfo{
+			Manager:          types.StringValue(component.Manager),
+			Name:             types.StringValue(component.Name),
+			Version:          types.StringValue(component.Version),
+			Arch:             types.StringValue(component.Architecture),
+			Purl:             types.StringValue(component.Purl),
+			License:          types.StringValue(component.License),
+			Supplier:         types.StringValue(component.Supplier),
+			SourceRepository: types.StringValue(component.SourceRepository),
+		})
+	}
+	return packages
+}
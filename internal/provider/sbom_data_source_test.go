@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/sbom"
+)
+
+func TestResolveManagerNames(t *testing.T) {
+	names, err := resolveManagerNames([]string{"brew", "apt", "brew"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"brew", "apt"}, names)
+
+	if runtime.GOOS == "linux" {
+		names, err = resolveManagerNames([]string{"auto", "apt"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"apt"}, names)
+	}
+}
+
+func TestSBOMPackageInfos(t *testing.T) {
+	components := []sbom.Component{{
+		Manager: "apt",
+		Component: adapters.Component{
+			Name:             "curl",
+			Version:          "7.88.1-10+deb12u5",
+			Architecture:     "amd64",
+			Purl:             "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12",
+			License:          "curl",
+			Supplier:         "Alessandro Ghedini <ghedo@debian.org>",
+			SourceRepository: "http://deb.debian.org/debian bookworm/main",
+			Homepage:         "https://curl.se/",
+		},
+	}}
+
+	assert.Equal(t, []SBOMPackageInfo{{
+		Manager:          types.StringValue("apt"),
+		Name:             types.StringValue("curl"),
+		Version:          types.StringValue("7.88.1-10+deb12u5"),
+		Arch:             types.StringValue("amd64"),
+		Purl:             types.StringValue("pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12"),
+		License:          types.StringValue("curl"),
+		Supplier:         types.StringValue("Alessandro Ghedini <ghedo@debian.org>"),
+		SourceRepository: types.StringValue("http://deb.debian.org/debian bookworm/main"),
+	}}, sbomPackageInfos(components))
+	assert.Equal(t, []SBOMPackageInfo{}, sbomPackageInfos(nil))
+}
@@ -25,12 +25,17 @@ package provider
 import (
 	"context"
 	"fmt"
-	"runtime"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -51,6 +56,9 @@ type SecurityInfoDataSourceModel struct {
 	ID            types.String `tfsdk:"id"`
 	Name          types.String `tfsdk:"name"`
 	Manager       types.String `tfsdk:"manager"`
+	Version       types.String `tfsdk:"version"`
+	Database      types.String `tfsdk:"database"`
+	Ecosystem     types.String `tfsdk:"ecosystem"`
 	HasAdvisories types.Bool   `tfsdk:"has_advisories"`
 	Advisories    types.List   `tfsdk:"advisories"`
 	LastChecked   types.String `tfsdk:"last_checked"`
@@ -58,12 +66,14 @@ type SecurityInfoDataSourceModel struct {
 
 // SecurityAdvisory represents a security advisory for a package.
 type SecurityAdvisory struct {
-	ID          types.String `tfsdk:"id"`
-	Severity    types.String `tfsdk:"severity"`
-	Title       types.String `tfsdk:"title"`
-	Description types.String `tfsdk:"description"`
-	CVE         types.String `tfsdk:"cve"`
-	URL         types.String `tfsdk:"url"`
+	ID           types.String `tfsdk:"id"`
+	Severity     types.String `tfsdk:"severity"`
+	Title        types.String `tfsdk:"title"`
+	Description  types.String `tfsdk:"description"`
+	CVE          types.String `tfsdk:"cve"`
+	CVEs         types.List   `tfsdk:"cves"`
+	FixedVersion types.String `tfsdk:"fixed_version"`
+	URL          types.String `tfsdk:"url"`
 }
 
 // Metadata returns the data source type name.
@@ -78,7 +88,8 @@ func (d *SecurityInfoDataSource) Metadata(
 func (d *SecurityInfoDataSource) Schema(
 	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Retrieves security information and advisories for a package.",
+		MarkdownDescription: "Retrieves security information and advisories for a package by matching it against " +
+			"the OSV vulnerability database configured with the provider's `vulnerability_database`.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -91,9 +102,30 @@ func (d *SecurityInfoDataSource) Schema(
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to query. " +
-					"Valid values: 'auto', 'brew'. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
 					"Defaults to 'auto'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Package version to check. Defaults to the installed version; " +
+					"the package must be installed when no version is given.",
+				Optional: true,
+				Computed: true,
+			},
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Path of the OSV vulnerability database to use instead of the provider's " +
+					"`vulnerability_database`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"ecosystem": schema.StringAttribute{
+				MarkdownDescription: "OSV ecosystem the package was matched in (e.g., 'Debian:12', 'Ubuntu:22.04' or 'Homebrew').",
+				Computed:            true,
 			},
 			"has_advisories": schema.BoolAttribute{
 				MarkdownDescription: "Whether the package has any known security advisories.",
@@ -113,8 +145,9 @@ func (d *SecurityInfoDataSource) Schema(
 							Computed:            true,
 						},
 						"severity": schema.StringAttribute{
-							MarkdownDescription: "Severity level (low, medium, high, critical).",
-							Computed:            true,
+							MarkdownDescription: "Severity level (low, medium, high, critical), or 'unknown' " +
+								"when the advisory has neither a distribution severity nor a CVSS v3 score.",
+							Computed: true,
 						},
 						"title": schema.StringAttribute{
 							MarkdownDescription: "Advisory title.",
@@ -128,6 +161,15 @@ func (d *SecurityInfoDataSource) Schema(
 							MarkdownDescription: "CVE identifier if available.",
 							Computed:            true,
 						},
+						"cves": schema.ListAttribute{
+							MarkdownDescription: "Every CVE identifier the advisory covers.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"fixed_version": schema.StringAttribute{
+							MarkdownDescription: "First version fixing the advisory, or empty if no fix is available.",
+							Computed:            true,
+						},
 						"url": schema.StringAttribute{
 							MarkdownDescription: "URL to advisory details.",
 							Computed:            true,
@@ -172,67 +214,121 @@ func (d *SecurityInfoDataSource) Read(
 	}
 
 	// Determine package manager
-	managerName := "auto"
+	managerName := managerAuto
 	if !data.Manager.IsNull() {
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	if managerName == managerAuto {
-		if runtime.GOOS != platformDarwin {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		managerName = "brew"
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
+		return
 	}
 
-	// Only support brew in Phase 2
-	if managerName != "brew" {
+	packageName := data.Name.ValueString()
+	scan, err := d.checkPackage(ctx, managerName, packageName, data.Version.ValueString(), data.Database.ValueString())
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", managerName),
+			"Failed to Check Security Advisories",
+			fmt.Sprintf("Failed to check security advisories for package %s: %v", packageName, err),
 		)
 		return
 	}
 
-	packageName := data.Name.ValueString()
-
-	// Note: Homebrew doesn't have built-in security advisory checking
-	// This is a placeholder implementation that could be enhanced to check
-	// external security databases or integrate with tools like `brew audit`
-
 	// Set computed values
 	data.ID = types.StringValue(fmt.Sprintf("%s:security:%s", managerName, packageName))
 	data.Manager = types.StringValue(managerName)
-	data.HasAdvisories = types.BoolValue(false)
-	data.LastChecked = types.StringValue("not implemented")
+	data.Version = types.StringValue(scan.Version)
+	data.Ecosystem = types.StringValue(scan.Ecosystem)
+	data.HasAdvisories = types.BoolValue(len(scan.Vulnerabilities) > 0)
+	data.LastChecked = types.StringValue(time.Now().UTC().Format(time.RFC3339))
 
-	// Empty advisories list for now
-	emptyList, diags := types.ListValueFrom(ctx, types.ObjectType{
+	advisories, diags := securityAdvisories(ctx, scan.Vulnerabilities)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Advisories, diags = types.ListValueFrom(ctx, types.ObjectType{
 		AttrTypes: map[string]attr.Type{
-			"id":          types.StringType,
-			"severity":    types.StringType,
-			"title":       types.StringType,
-			"description": types.StringType,
-			"cve":         types.StringType,
-			"url":         types.StringType,
+			"id":            types.StringType,
+			"severity":      types.StringType,
+			"title":         types.StringType,
+			"description":   types.StringType,
+			"cve":           types.StringType,
+			"cves":          types.ListType{ElemType: types.StringType},
+			"fixed_version": types.StringType,
+			"url":           types.StringType,
 		},
-	}, []SecurityAdvisory{})
+	}, advisories)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	data.Advisories = emptyList
-
-	// Add a warning that this is not fully implemented
-	resp.Diagnostics.AddWarning(
-		"Security Info Not Fully Implemented",
-		"Security advisory checking is not fully implemented for Homebrew. This data source returns empty results and could be enhanced to integrate with security databases.",
-	)
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// packageSecurityScan is the result of checking one package version.
+type packageSecurityScan struct {
+	*vulnerabilityScan
+	Version string
+}
+
+func (d *SecurityInfoDataSource) checkPackage(
+	ctx context.Context, managerName, packageName, version, database string) (*packageSecurityScan, error) {
+	db, err := loadVulnerabilityDatabase(d.providerData, database)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == "" {
+		info, err := manager.DetectInstalled(ctx, packageName)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Installed {
+			return nil, fmt.Errorf("package is not installed; set version to check a version that is not installed")
+		}
+		version = info.Version
+	}
+
+	scan, err := scanVulnerabilities(ctx, manager, db, []adapters.PackageInfo{{Name: packageName, Version: version}})
+	if err != nil {
+		return nil, err
+	}
+	return &packageSecurityScan{vulnerabilityScan: scan, Version: version}, nil
+}
+
+// securityAdvisories converts matched advisories to data source entries.
+func securityAdvisories(ctx context.Context, vulnerabilities []packageVulnerability) ([]SecurityAdvisory, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	advisories := make([]SecurityAdvisory, 0, len(vulnerabilities))
+	for _, match := range vulnerabilities {
+		vuln := match.Finding.Vulnerability
+		cves := vuln.CVEs()
+		cve := ""
+		if len(cves) > 0 {
+			cve = cves[0]
+		}
+		cveList, d := types.ListValueFrom(ctx, types.StringType, cves)
+		diags.Append(d...)
+		advisories = append(advisories, SecurityAdvisory{
+			ID:           types.StringValue(vuln.ID),
+			Severity:     types.StringValue(match.Finding.SeverityLevel()),
+			Title:        types.StringValue(vuln.Summary),
+			Description:  types.StringValue(vuln.Details),
+			CVE:          types.StringValue(cve),
+			CVEs:         cveList,
+			FixedVersion: types.StringValue(match.Finding.Fixed),
+			URL:          types.StringValue(vuln.URL()),
+		})
+	}
+	return advisories, diags
+}
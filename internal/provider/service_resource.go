@@ -27,8 +27,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -40,17 +43,28 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/audit"
 	"github.com/jamesainslie/terraform-provider-package/internal/executor"
 	"github.com/jamesainslie/terraform-provider-package/internal/services"
 	"github.com/jamesainslie/terraform-provider-package/internal/services/detectors"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
 )
 
+// serviceErrorAttributes points service failures at the attribute to fix.
+var serviceErrorAttributes = ErrorAttributes{
+	adapters.ErrNotFound:    path.Root("service_name"),
+	adapters.ErrInterrupted: path.Root("wait_timeout"),
+}
+
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &ServiceResource{}
-	_ resource.ResourceWithConfigure   = &ServiceResource{}
-	_ resource.ResourceWithImportState = &ServiceResource{}
+	_ resource.Resource                   = &ServiceResource{}
+	_ resource.ResourceWithConfigure      = &ServiceResource{}
+	_ resource.ResourceWithImportState    = &ServiceResource{}
+	_ resource.ResourceWithValidateConfig = &ServiceResource{}
 )
 
 // NewServiceResource is a helper function to simplify the provider implementation.
@@ -61,6 +75,7 @@ func NewServiceResource() resource.Resource {
 // ServiceResource defines the resource implementation.
 type ServiceResource struct {
 	executor          executor.Executor
+	diagHelpers       *DiagnosticHelpers
 	serviceManager    services.ServiceManager
 	dependencyManager *services.DependencyManager
 }
@@ -145,6 +160,9 @@ func (r *ServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"state": schema.StringAttribute{
 				Description: "Desired state of the service. Valid values: 'running', 'stopped'.",
@@ -192,28 +210,37 @@ func (r *ServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					"url": schema.StringAttribute{
 						Description: "URL for HTTP-based health checks.",
 						Optional:    true,
+						Validators:  []validator.String{httpURL()},
 					},
 					"port": schema.Int64Attribute{
 						Description: "Port number for TCP-based health checks.",
 						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.Between(1, 65535),
+						},
 					},
 					"timeout": schema.StringAttribute{
 						Description: "Timeout for the health check (e.g., '30s').",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("30s"),
+						Validators:  []validator.String{positiveDuration()},
 					},
 					"expected_code": schema.Int64Attribute{
 						Description: "Expected HTTP status code for HTTP health checks.",
 						Optional:    true,
 						Computed:    true,
 						Default:     int64default.StaticInt64(200),
+						Validators: []validator.Int64{
+							int64validator.Between(100, 599),
+						},
 					},
 					"interval": schema.StringAttribute{
 						Description: "Interval between health checks (e.g., '10s').",
 						Optional:    true,
 						Computed:    true,
 						Default:     stringdefault.StaticString("10s"),
+						Validators:  []validator.String{positiveDuration()},
 					},
 				},
 			},
@@ -222,6 +249,7 @@ func (r *ServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Optional:    true,
 				Computed:    true,
 				Default:     stringdefault.StaticString("60s"),
+				Validators:  []validator.String{positiveDuration()},
 			},
 			"wait_for_healthy": schema.BoolAttribute{
 				Description: "Whether to wait for the service to be healthy after starting.",
@@ -234,6 +262,7 @@ func (r *ServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				Optional:    true,
 				Computed:    true,
 				Default:     stringdefault.StaticString("120s"),
+				Validators:  []validator.String{positiveDuration()},
 			},
 			"management_strategy": schema.StringAttribute{
 				Description: "Strategy for managing the service. Valid values: 'auto', 'brew_services', 'direct_command', 'launchd', 'process_only'.",
@@ -252,21 +281,33 @@ func (r *ServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 						Description: "Command and arguments to start the service.",
 						Optional:    true,
 						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
 					},
 					"stop": schema.ListAttribute{
 						Description: "Command and arguments to stop the service.",
 						Optional:    true,
 						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
 					},
 					"restart": schema.ListAttribute{
 						Description: "Command and arguments to restart the service.",
 						Optional:    true,
 						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
 					},
 					"status": schema.ListAttribute{
 						Description: "Command and arguments to check service status.",
 						Optional:    true,
 						ElementType: types.StringType,
+						Validators: []validator.List{
+							listvalidator.SizeAtLeast(1),
+						},
 					},
 				},
 			},
@@ -335,6 +376,52 @@ func (r *ServiceResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 	}
 }
 
+// ValidateConfig checks that health checks have the settings their type needs
+// and that custom commands are only given to strategies that run them.
+func (r *ServiceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data ServiceResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.HealthCheck.IsNull() && !data.HealthCheck.IsUnknown() {
+		var healthCheck ServiceHealthCheckModel
+		resp.Diagnostics.Append(data.HealthCheck.As(ctx, &healthCheck, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		required := map[string]attr.Value{
+			"command": healthCheck.Command,
+			"http":    healthCheck.URL,
+			"tcp":     healthCheck.Port,
+		}
+		attribute := map[string]string{"command": "command", "http": "url", "tcp": "port"}
+		checkType := healthCheck.Type.ValueString()
+		if value, ok := required[checkType]; ok && value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("health_check").AtName(attribute[checkType]),
+				"Missing Attribute Configuration",
+				fmt.Sprintf("health_check.%s is required when health_check.type is '%s'.", attribute[checkType], checkType),
+			)
+		}
+	}
+
+	switch data.ManagementStrategy.ValueString() {
+	case string(services.StrategyBrewServices), string(services.StrategyLaunchd), string(services.StrategyProcessOnly):
+		if !data.CustomCommands.IsNull() {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("custom_commands"),
+				"Ignored Attribute",
+				fmt.Sprintf("custom_commands is not used when management_strategy is '%s'. "+
+					"Use 'direct_command' or 'auto' to run custom commands.", data.ManagementStrategy.ValueString()),
+			)
+		}
+	}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *ServiceResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -351,6 +438,7 @@ func (r *ServiceResource) Configure(_ context.Context, req resource.ConfigureReq
 	}
 
 	r.executor = providerData.Executor
+	r.diagHelpers = providerData.DiagHelpers
 	r.serviceManager = services.NewServiceManager(providerData.Executor)
 
 	// Initialize dependency manager with detectors
@@ -361,6 +449,9 @@ func (r *ServiceResource) Configure(_ context.Context, req resource.ConfigureReq
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_service", "create")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var plan ServiceResourceModel
 
 	// Read Terraform plan data into the model
@@ -368,6 +459,8 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	span.SetAttributes(telemetry.AttrService.String(plan.ServiceName.ValueString()))
+	ctx = serviceAuditScope(ctx, plan.ServiceName.ValueString(), "create")
 
 	// Set the ID
 	plan.ID = types.StringValue(plan.ServiceName.ValueString())
@@ -420,10 +513,11 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 
 	// Apply the desired state
 	if err := r.applyServiceState(ctx, &plan); err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(r.diagHelpers.AdapterErrorDiagnostic(
 			"Service State Error",
-			fmt.Sprintf("Failed to apply service state: %v", err),
-		)
+			"Failed to apply service state",
+			err, serviceErrorAttributes,
+		))
 		return
 	}
 
@@ -445,6 +539,9 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 
 // Read refreshes the Terraform state with the latest data.
 func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_service", "read")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var state ServiceResourceModel
 
 	// Read Terraform prior state data into the model
@@ -452,6 +549,7 @@ func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	span.SetAttributes(telemetry.AttrService.String(state.ServiceName.ValueString()))
 
 	// Update computed attributes
 	if err := r.updateComputedAttributes(ctx, &state); err != nil {
@@ -468,6 +566,9 @@ func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_service", "update")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var plan, state ServiceResourceModel
 
 	// Read Terraform plan and state data into the models
@@ -476,13 +577,16 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	span.SetAttributes(telemetry.AttrService.String(state.ServiceName.ValueString()))
+	ctx = serviceAuditScope(ctx, state.ServiceName.ValueString(), "update")
 
 	// Apply the desired state
 	if err := r.applyServiceState(ctx, &plan); err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(r.diagHelpers.AdapterErrorDiagnostic(
 			"Service State Error",
-			fmt.Sprintf("Failed to apply service state: %v", err),
-		)
+			"Failed to apply service state",
+			err, serviceErrorAttributes,
+		))
 		return
 	}
 
@@ -504,6 +608,9 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, span := startResourceSpan(ctx, "pkg_service", "delete")
+	defer func() { endResourceSpan(span, resp.Diagnostics) }()
+
 	var state ServiceResourceModel
 
 	// Read Terraform prior state data into the model
@@ -511,6 +618,8 @@ func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	span.SetAttributes(telemetry.AttrService.String(state.ServiceName.ValueString()))
+	ctx = serviceAuditScope(ctx, state.ServiceName.ValueString(), "delete")
 
 	// Stop the service if it's running
 	if state.State.ValueString() == "running" {
@@ -533,6 +642,17 @@ func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 }
 
+// serviceAuditScope attaches the service resource to ctx for the audit log.
+// The manager is left to the auditing executor, which records the service
+// command that ran, such as systemctl or launchctl.
+func serviceAuditScope(ctx context.Context, serviceName, operation string) context.Context {
+	return audit.WithScope(ctx, audit.Scope{
+		ResourceType:       "pkg_service",
+		ResourceID:         serviceName,
+		TerraformOperation: operation,
+	})
+}
+
 // ImportState imports the resource from the given identifier.
 func (r *ServiceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	// Set the service name from the import ID
@@ -850,6 +970,12 @@ func (r *ServiceResource) waitForHealthy(ctx context.Context, model *ServiceReso
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
+	// Record how many checks were needed on the resource's trace span
+	checks := 0
+	defer func() {
+		trace.SpanFromContext(ctx).SetAttributes(telemetry.AttrHealthChecks.Int(checks))
+	}()
+
 	for {
 		select {
 		case <-operationCtx.Done():
@@ -862,6 +988,7 @@ func (r *ServiceResource) waitForHealthy(ctx context.Context, model *ServiceReso
 			// Create fresh context for each health check command to avoid timeout chain reaction
 			// This prevents expired contexts from causing immediate command failures
 			checkCtx, checkCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			checks++
 
 			tflog.Debug(ctx, "Performing health check", map[string]interface{}{
 				"service_name":  serviceName,
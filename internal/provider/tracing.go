@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
+)
+
+// startResourceSpan starts a trace span for a resource operation, such as
+// "pkg_package.create". End it with endResourceSpan.
+func startResourceSpan(ctx context.Context, resourceType, operation string) (context.Context, trace.Span) {
+	return telemetry.Start(ctx, resourceType+"."+operation,
+		telemetry.AttrResource.String(resourceType),
+		telemetry.AttrAction.String(operation),
+	)
+}
+
+// endResourceSpan ends span, marking it failed if diags contain an error.
+func endResourceSpan(span trace.Span, diags diag.Diagnostics) {
+	var err error
+	if errs := diags.Errors(); len(errs) > 0 {
+		err = errors.New(errs[0].Summary() + ": " + errs[0].Detail())
+	}
+	telemetry.End(span, err)
+}
@@ -0,0 +1,154 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// readTrackedState sets metadata, dependency_tree and last_access as enabled by
+// the track_* attributes. Tracking is best effort: a failed query is logged and
+// leaves its attribute empty.
+func (r *PackageResource) readTrackedState(ctx context.Context, manager adapters.PackageManager, packageName string,
+	installed bool, data *PackageResourceModel) {
+	data.Metadata = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	data.DependencyTree = types.MapValueMust(types.StringType, map[string]attr.Value{})
+	data.LastAccess = types.StringValue("")
+	if !installed {
+		return
+	}
+
+	logFailure := func(attribute string, err error) {
+		tflog.Warn(ctx, "Failed to read tracked package state", map[string]interface{}{
+			"package_name": packageName,
+			"attribute":    attribute,
+			"error":        err.Error(),
+		})
+	}
+
+	if reader, ok := manager.(adapters.MetadataReader); ok && data.TrackMetadata.ValueBool() {
+		if metadata, err := reader.Metadata(ctx, packageName); err != nil {
+			logFailure("metadata", err)
+		} else {
+			data.Metadata = metadataValue(metadata)
+		}
+	}
+
+	if reader, ok := manager.(adapters.DependencyTreeReader); ok && data.TrackDependencies.ValueBool() {
+		if tree, err := reader.DependencyTree(ctx, packageName); err != nil {
+			logFailure("dependency_tree", err)
+		} else {
+			elements := make(map[string]attr.Value, len(tree))
+			for name, version := range tree {
+				elements[name] = types.StringValue(version)
+			}
+			data.DependencyTree = types.MapValueMust(types.StringType, elements)
+		}
+	}
+
+	if lister, ok := manager.(adapters.FileLister); ok && data.TrackUsage.ValueBool() {
+		if files, err := lister.Files(ctx, packageName); err != nil {
+			logFailure("last_access", err)
+		} else if accessed, ok := lastAccess(files); ok {
+			data.LastAccess = types.StringValue(accessed.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
+// planTrackedState marks metadata, dependency_tree and last_access unknown when
+// their track_* attribute is enabled and the plan changes the package, which
+// planVersion and planDrift signal by leaving version_actual unknown. Apply
+// reads them again, so the values from the last refresh no longer hold.
+func planTrackedState(plan *PackageResourceModel) {
+	if !plan.VersionActual.IsUnknown() {
+		return
+	}
+	tracked := func(value types.Bool) bool { return value.IsUnknown() || value.ValueBool() }
+	if tracked(plan.TrackMetadata) {
+		plan.Metadata = types.MapUnknown(types.StringType)
+	}
+	if tracked(plan.TrackDependencies) {
+		plan.DependencyTree = types.MapUnknown(types.StringType)
+	}
+	if tracked(plan.TrackUsage) {
+		plan.LastAccess = types.StringUnknown()
+	}
+}
+
+// metadataValue converts package metadata to the metadata attribute.
+func metadataValue(metadata *adapters.PackageMetadata) types.Map {
+	size := ""
+	if metadata.InstalledSize > 0 {
+		size = strconv.FormatInt(metadata.InstalledSize, 10)
+	}
+	return types.MapValueMust(types.StringType, map[string]attr.Value{
+		"description":    types.StringValue(metadata.Description),
+		"homepage":       types.StringValue(metadata.Homepage),
+		"license":        types.StringValue(metadata.License),
+		"installed_size": types.StringValue(size),
+	})
+}
+
+// lastAccess returns the latest access time of the executables among files,
+// or of all files for packages without executables. Directories are skipped,
+// except for macOS app bundles.
+func lastAccess(files []string) (time.Time, bool) {
+	var executables []string
+	for _, file := range files {
+		if isExecutablePath(file) {
+			executables = append(executables, file)
+		}
+	}
+	if len(executables) > 0 {
+		files = executables
+	}
+
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil || info.IsDir() && !strings.HasSuffix(file, ".app") {
+			continue
+		}
+		if accessed := accessTime(info); accessed.After(latest) {
+			latest = accessed
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// isExecutablePath reports whether file is in a bin or sbin directory.
+func isExecutablePath(file string) bool {
+	dir := filepath.Base(filepath.Dir(file))
+	return dir == "bin" || dir == "sbin"
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func trackedPackage(metadata, dependencies, usage bool) *PackageResourceModel {
+	data := packagePlan(statePresent, "")
+	data.TrackMetadata = types.BoolValue(metadata)
+	data.TrackDependencies = types.BoolValue(dependencies)
+	data.TrackUsage = types.BoolValue(usage)
+	return data
+}
+
+func TestPackageResource_ReadTrackedState(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "bin", "curl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(binary), 0o755))
+	require.NoError(t, os.WriteFile(binary, nil, 0o755))
+	accessed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(binary, accessed, accessed))
+
+	manager := newFakePackageManager(map[string]string{"curl": "7.81.0"})
+	manager.metadata = map[string]*adapters.PackageMetadata{
+		"curl": {Description: "URL transfer tool", Homepage: "https://curl.se/", License: "curl", InstalledSize: 465920},
+	}
+	manager.deps = map[string]map[string]string{"curl": {"libcurl4": "7.81.0", "libc6": "2.35"}}
+	manager.files = map[string][]string{"curl": {filepath.Dir(binary), binary}}
+
+	data := trackedPackage(true, true, true)
+	(&PackageResource{}).readTrackedState(context.Background(), manager, "curl", true, data)
+
+	assert.Equal(t, types.MapValueMust(types.StringType, map[string]attr.Value{
+		"description":    types.StringValue("URL transfer tool"),
+		"homepage":       types.StringValue("https://curl.se/"),
+		"license":        types.StringValue("curl"),
+		"installed_size": types.StringValue("465920"),
+	}), data.Metadata)
+	assert.Equal(t, types.MapValueMust(types.StringType, map[string]attr.Value{
+		"libcurl4": types.StringValue("7.81.0"),
+		"libc6":    types.StringValue("2.35"),
+	}), data.DependencyTree)
+	assert.Equal(t, types.StringValue("2026-03-01T12:00:00Z"), data.LastAccess)
+}
+
+func TestPackageResource_ReadTrackedState_Disabled(t *testing.T) {
+	manager := newFakePackageManager(map[string]string{"curl": "7.81.0"})
+	manager.deps = map[string]map[string]string{"curl": {"libc6": "2.35"}}
+
+	data := trackedPackage(false, false, false)
+	data.DependencyTree = types.MapValueMust(types.StringType, map[string]attr.Value{"libc6": types.StringValue("2.35")})
+	(&PackageResource{}).readTrackedState(context.Background(), manager, "curl", true, data)
+
+	assert.Empty(t, data.Metadata.Elements())
+	assert.Empty(t, data.DependencyTree.Elements())
+	assert.Equal(t, types.StringValue(""), data.LastAccess)
+}
+
+func TestPackageResource_ReadTrackedState_QueryFails(t *testing.T) {
+	data := trackedPackage(true, false, false)
+
+	(&PackageResource{}).readTrackedState(context.Background(), newFakePackageManager(nil), "curl", true, data)
+
+	assert.Empty(t, data.Metadata.Elements())
+}
+
+func TestPackageResource_UpgradeWithTracking(t *testing.T) {
+	manager := newFakePackageManager(map[string]string{"curl": "7.81.0-1"})
+	manager.candidates = map[string]*adapters.VersionCandidates{"curl": {Candidate: "7.81.0-1ubuntu1.16"}}
+	manager.deps = map[string]map[string]string{"curl": {"libcurl4": "7.81.0-1"}}
+	r := &PackageResource{providerData: &ProviderData{Batcher: NewInstallBatcher(0, false)}}
+
+	// Refresh the installed package, as Read does before planning
+	prior := trackedPackage(false, true, false)
+	prior.State = types.StringValue(stateLatest)
+	require.NoError(t, r.readPackageState(context.Background(), manager, "curl", prior))
+	prior.VersionTarget = prior.VersionActual
+
+	// Plan the upgrade, as ModifyPlan does
+	plan := *prior
+	require.False(t, r.planVersion(context.Background(), manager, "curl", &plan, prior).HasError())
+	planTrackedState(&plan)
+	require.True(t, plan.VersionActual.IsUnknown(), "the upgrade should be planned")
+	assert.True(t, plan.DependencyTree.IsUnknown(), "the tracked dependency tree changes with the upgrade")
+	assert.Equal(t, prior.Metadata, plan.Metadata, "untracked attributes keep their empty value")
+	assert.Equal(t, prior.LastAccess, plan.LastAccess)
+
+	// Apply it, as Update does
+	manager.deps["curl"] = map[string]string{"libcurl4": "7.81.0-1ubuntu1.16"}
+	applied := plan
+	require.NoError(t, r.installDesiredVersion(context.Background(), manager, "curl", &applied))
+	require.NoError(t, r.readPackageState(context.Background(), manager, "curl", &applied))
+
+	assert.Equal(t, types.StringValue("7.81.0-1ubuntu1.16"), applied.VersionActual)
+	assert.Equal(t, types.MapValueMust(types.StringType, map[string]attr.Value{
+		"libcurl4": types.StringValue("7.81.0-1ubuntu1.16"),
+	}), applied.DependencyTree)
+	// Every value known at plan time must survive apply unchanged
+	assert.Equal(t, plan.Metadata, applied.Metadata)
+	assert.Equal(t, plan.LastAccess, applied.LastAccess)
+}
+
+func TestLastAccess(t *testing.T) {
+	dir := t.TempDir()
+	library := filepath.Join(dir, "lib", "libcurl.so.4")
+	require.NoError(t, os.MkdirAll(filepath.Dir(library), 0o755))
+	require.NoError(t, os.WriteFile(library, nil, 0o644))
+	accessed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, os.Chtimes(library, accessed, accessed))
+
+	latest, ok := lastAccess([]string{filepath.Dir(library), library, filepath.Join(dir, "missing")})
+	require.True(t, ok)
+	assert.True(t, accessed.Equal(latest))
+
+	_, ok = lastAccess([]string{filepath.Join(dir, "bin", "missing")})
+	assert.False(t, ok)
+}
@@ -0,0 +1,219 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	_ validator.String = durationValidator{}
+	_ validator.String = httpURLValidator{}
+	_ validator.String = regexpValidator{}
+	_ validator.String = timestampValidator{}
+	_ validator.String = absolutePathValidator{}
+)
+
+// durationValidator checks that a string is a Go duration such as '30s' or '1h30m'.
+type durationValidator struct {
+	allowZero bool
+}
+
+// positiveDuration returns a validator for durations greater than zero.
+func positiveDuration() validator.String {
+	return durationValidator{}
+}
+
+// nonNegativeDuration returns a validator for durations of zero or more.
+func nonNegativeDuration() validator.String {
+	return durationValidator{allowZero: true}
+}
+
+// Description describes the validation in plain text formatting.
+func (v durationValidator) Description(_ context.Context) string {
+	if v.allowZero {
+		return "value must be a non-negative duration such as '30s' or '1h'"
+	}
+	return "value must be a positive duration such as '30s' or '1h'"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	duration, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || duration < 0 || (duration == 0 && !v.allowZero) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// httpURLValidator checks that a string is an absolute http or https URL.
+type httpURLValidator struct{}
+
+// httpURL returns a validator for absolute http or https URLs.
+func httpURL() validator.String {
+	return httpURLValidator{}
+}
+
+// Description describes the validation in plain text formatting.
+func (v httpURLValidator) Description(_ context.Context) string {
+	return "value must be an http or https URL such as 'http://localhost:8080/health'"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v httpURLValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v httpURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	u, err := url.Parse(req.ConfigValue.ValueString())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid URL",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// regexpValidator checks that a string compiles as a Go regular expression.
+type regexpValidator struct{}
+
+// validRegexp returns a validator for Go regular expressions.
+func validRegexp() validator.String {
+	return regexpValidator{}
+}
+
+// Description describes the validation in plain text formatting.
+func (v regexpValidator) Description(_ context.Context) string {
+	return "value must be a valid regular expression"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v regexpValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v regexpValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Regular Expression",
+			fmt.Sprintf("Attribute %s must be a valid regular expression: %s", req.Path, err),
+		)
+	}
+}
+
+// timestampValidator checks that a string is an RFC 3339 timestamp.
+type timestampValidator struct{}
+
+// rfc3339Timestamp returns a validator for RFC 3339 timestamps.
+func rfc3339Timestamp() validator.String {
+	return timestampValidator{}
+}
+
+// Description describes the validation in plain text formatting.
+func (v timestampValidator) Description(_ context.Context) string {
+	return "value must be an RFC 3339 timestamp such as '2024-05-01T12:00:00Z'"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v timestampValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v timestampValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Timestamp",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
+
+// absolutePathValidator checks that a string is an absolute file path.
+type absolutePathValidator struct{}
+
+// absolutePath returns a validator for absolute file paths.
+func absolutePath() validator.String {
+	return absolutePathValidator{}
+}
+
+// Description describes the validation in plain text formatting.
+func (v absolutePathValidator) Description(_ context.Context) string {
+	return "value must be an absolute path such as '/usr/bin/curl'"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v absolutePathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v absolutePathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !path.IsAbs(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Path",
+			fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), req.ConfigValue.ValueString()),
+		)
+	}
+}
@@ -0,0 +1,477 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// objectValue builds an object of type typ from values, leaving unset attributes null.
+func objectValue(typ tftypes.Type, values map[string]tftypes.Value) tftypes.Value {
+	object := typ.(tftypes.Object)
+	attributes := make(map[string]tftypes.Value, len(object.AttributeTypes))
+	for name, attributeType := range object.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		} else {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	return tftypes.NewValue(object, attributes)
+}
+
+func resourceConfigType(t *testing.T, r resource.Resource) tftypes.Type {
+	t.Helper()
+	resp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, resp)
+	require.False(t, resp.Diagnostics.HasError())
+	return resp.Schema.Type().TerraformType(context.Background())
+}
+
+// validateResourceConfig runs `terraform validate` for one resource block
+// through the provider server and returns the diagnostic summaries by severity.
+func validateResourceConfig(t *testing.T, typeName string, r resource.Resource,
+	values map[string]tftypes.Value) (errs, warnings []string) {
+	t.Helper()
+	typ := resourceConfigType(t, r)
+	config, err := tfprotov6.NewDynamicValue(typ, objectValue(typ, values))
+	require.NoError(t, err)
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	resp, err := server.ValidateResourceConfig(context.Background(), &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: typeName,
+		Config:   &config,
+	})
+	require.NoError(t, err)
+	return diagnosticSummaries(resp.Diagnostics)
+}
+
+func diagnosticSummaries(diags []*tfprotov6.Diagnostic) (errs, warnings []string) {
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			errs = append(errs, d.Summary+": "+d.Detail)
+		} else {
+			warnings = append(warnings, d.Summary+": "+d.Detail)
+		}
+	}
+	return errs, warnings
+}
+
+func str(value string) tftypes.Value {
+	return tftypes.NewValue(tftypes.String, value)
+}
+
+func stringList(values ...string) tftypes.Value {
+	elements := make([]tftypes.Value, len(values))
+	for i, value := range values {
+		elements[i] = str(value)
+	}
+	return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+}
+
+func TestDurationValidators(t *testing.T) {
+	tests := []struct {
+		value     string
+		validator validator.String
+		wantError bool
+	}{
+		{"30s", positiveDuration(), false},
+		{"1h30m", positiveDuration(), false},
+		{"0s", positiveDuration(), true},
+		{"0s", nonNegativeDuration(), false},
+		{"-5m", nonNegativeDuration(), true},
+		{"ten minutes", positiveDuration(), true},
+		{"10", nonNegativeDuration(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			tt.validator.ValidateString(context.Background(), validator.StringRequest{
+				ConfigValue: types.StringValue(tt.value),
+			}, resp)
+			assert.Equal(t, tt.wantError, resp.Diagnostics.HasError())
+		})
+	}
+}
+
+func TestStringValidators_SkipUnknownAndNull(t *testing.T) {
+	for _, v := range []validator.String{positiveDuration(), httpURL(), validRegexp(), rfc3339Timestamp(), absolutePath()} {
+		for _, value := range []types.String{types.StringNull(), types.StringUnknown()} {
+			resp := &validator.StringResponse{}
+			v.ValidateString(context.Background(), validator.StringRequest{ConfigValue: value}, resp)
+			assert.False(t, resp.Diagnostics.HasError())
+		}
+	}
+}
+
+func TestHTTPURLValidator(t *testing.T) {
+	for value, wantError := range map[string]bool{
+		"http://localhost:8080/health": false,
+		"https://collector:4318":       false,
+		"localhost:8080":               true,
+		"ftp://example.com":            true,
+		"http://":                      true,
+	} {
+		resp := &validator.StringResponse{}
+		httpURL().ValidateString(context.Background(), validator.StringRequest{
+			ConfigValue: types.StringValue(value),
+		}, resp)
+		assert.Equal(t, wantError, resp.Diagnostics.HasError(), value)
+	}
+}
+
+func TestTimestampValidator(t *testing.T) {
+	for value, wantError := range map[string]bool{
+		"2024-05-01T12:00:00Z":      false,
+		"2024-05-01T12:00:00+02:00": false,
+		"2024-05-01":                true,
+		"yesterday":                 true,
+	} {
+		resp := &validator.StringResponse{}
+		rfc3339Timestamp().ValidateString(context.Background(), validator.StringRequest{
+			ConfigValue: types.StringValue(value),
+		}, resp)
+		assert.Equal(t, wantError, resp.Diagnostics.HasError(), value)
+	}
+}
+
+func TestAbsolutePathValidator(t *testing.T) {
+	for value, wantError := range map[string]bool{
+		"/usr/bin/curl":   false,
+		"/":               false,
+		"usr/bin/curl":    true,
+		"./bin/terraform": true,
+		"":                true,
+	} {
+		resp := &validator.StringResponse{}
+		absolutePath().ValidateString(context.Background(), validator.StringRequest{
+			ConfigValue: types.StringValue(value),
+		}, resp)
+		assert.Equal(t, wantError, resp.Diagnostics.HasError(), value)
+	}
+}
+
+func TestRegexpValidator(t *testing.T) {
+	resp := &validator.StringResponse{}
+	validRegexp().ValidateString(context.Background(), validator.StringRequest{
+		ConfigValue: types.StringValue(`token=(\w+`),
+	}, resp)
+	assert.True(t, resp.Diagnostics.HasError())
+}
+
+func TestPackageResource_ValidateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]tftypes.Value
+		wantError   string
+		wantWarning string
+	}{
+		{
+			name:   "valid",
+			values: map[string]tftypes.Value{"name": str("jq"), "state": str("present")},
+		},
+		{
+			name:      "unknown state",
+			values:    map[string]tftypes.Value{"name": str("jq"), "state": str("installed")},
+			wantError: "value must be one of",
+		},
+		{
+			name: "pin with absent",
+			values: map[string]tftypes.Value{
+				"name": str("jq"), "state": str("absent"), "pin": tftypes.NewValue(tftypes.Bool, true),
+			},
+			wantError: "pin cannot be true",
+		},
+		{
+			name:      "version with latest",
+			values:    map[string]tftypes.Value{"name": str("jq"), "state": str("latest"), "version": str("1.7")},
+			wantError: "version cannot be set when state is 'latest'",
+		},
+		{
+			name:        "version with absent",
+			values:      map[string]tftypes.Value{"name": str("jq"), "state": str("absent"), "version": str("1.7")},
+			wantWarning: "version is ignored",
+		},
+		{
+			name: "cask with apt",
+			values: map[string]tftypes.Value{
+				"name": str("firefox"), "package_type": str("cask"), "managers": stringList("apt"),
+			},
+			wantError: "only supported by Homebrew, but this package is managed by apt",
+		},
+		{
+			name: "cask with brew",
+			values: map[string]tftypes.Value{
+				"name": str("firefox"), "package_type": str("cask"), "managers": stringList("brew"),
+			},
+		},
+		{
+			name:      "unknown manager",
+			values:    map[string]tftypes.Value{"name": str("jq"), "managers": stringList("yum")},
+			wantError: "value must be one of",
+		},
+		{
+			name:      "dependency strategy",
+			values:    map[string]tftypes.Value{"name": str("jq"), "dependency_strategy": str("foo")},
+			wantError: "value must be one of",
+		},
+		{
+			name: "alias platform",
+			values: map[string]tftypes.Value{
+				"name": str("jq"),
+				"aliases": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String},
+					map[string]tftypes.Value{"macos": str("jq")}),
+			},
+			wantError: "value must be one of",
+		},
+	}
+
+	timeoutsType := resourceConfigType(t, NewPackageResource()).(tftypes.Object).AttributeTypes["timeouts"]
+	tests = append(tests, struct {
+		name        string
+		values      map[string]tftypes.Value
+		wantError   string
+		wantWarning string
+	}{
+		name: "timeout",
+		values: map[string]tftypes.Value{
+			"name":     str("jq"),
+			"timeouts": objectValue(timeoutsType, map[string]tftypes.Value{"create": str("15 minutes")}),
+		},
+		wantError: "positive duration",
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, warnings := validateResourceConfig(t, "pkg_package", NewPackageResource(), tt.values)
+			assertDiagnostic(t, errs, tt.wantError)
+			assertDiagnostic(t, warnings, tt.wantWarning)
+		})
+	}
+}
+
+func TestRepositoryResource_ValidateConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    map[string]tftypes.Value
+		wantError string
+	}{
+		{
+			name:   "tap",
+			values: map[string]tftypes.Value{"manager": str("brew"), "name": str("hashicorp/tap"), "uri": str("hashicorp/tap")},
+		},
+		{
+			name: "tap with remote",
+			values: map[string]tftypes.Value{
+				"manager": str("brew"), "name": str("acme/tools"), "uri": str("https://github.com/acme/homebrew-tools"),
+			},
+		},
+		{
+			name:      "apt",
+			values:    map[string]tftypes.Value{"manager": str("apt"), "name": str("docker"), "uri": str("deb https://x y")},
+			wantError: "only be managed for 'brew'",
+		},
+		{
+			name:      "unknown manager",
+			values:    map[string]tftypes.Value{"manager": str("yum"), "name": str("a/b"), "uri": str("a/b")},
+			wantError: "value must be one of",
+		},
+		{
+			name: "gpg key",
+			values: map[string]tftypes.Value{
+				"manager": str("brew"), "name": str("a/b"), "uri": str("a/b"), "gpg_key": str("https://example.com/key"),
+			},
+			wantError: "gpg_key is not supported",
+		},
+		{
+			name:      "tap name",
+			values:    map[string]tftypes.Value{"manager": str("brew"), "name": str("tools"), "uri": str("a/b")},
+			wantError: "'user/repo'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, _ := validateResourceConfig(t, "pkg_repo", NewRepositoryResource(), tt.values)
+			assertDiagnostic(t, errs, tt.wantError)
+		})
+	}
+}
+
+func TestPackagesResource_ValidateConfig(t *testing.T) {
+	packagesType := resourceConfigType(t, NewPackagesResource()).(tftypes.Object).AttributeTypes["packages"].(tftypes.Map)
+	packages := tftypes.NewValue(packagesType, map[string]tftypes.Value{
+		"curl": objectValue(packagesType.ElementType, map[string]tftypes.Value{}),
+	})
+
+	tests := []struct {
+		name      string
+		values    map[string]tftypes.Value
+		wantError string
+	}{
+		{
+			name:   "not exclusive",
+			values: map[string]tftypes.Value{"packages": packages, "exclusive": tftypes.NewValue(tftypes.Bool, false)},
+		},
+		{
+			name:      "exclusive without keep",
+			values:    map[string]tftypes.Value{"packages": packages, "exclusive": tftypes.NewValue(tftypes.Bool, true)},
+			wantError: "keep must be set when exclusive is true",
+		},
+		{
+			name: "exclusive with empty keep",
+			values: map[string]tftypes.Value{
+				"packages":  packages,
+				"exclusive": tftypes.NewValue(tftypes.Bool, true),
+				"keep":      tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, _ := validateResourceConfig(t, "pkg_packages", NewPackagesResource(), tt.values)
+			assertDiagnostic(t, errs, tt.wantError)
+		})
+	}
+}
+
+func TestServiceResource_ValidateConfig(t *testing.T) {
+	healthCheckType := resourceConfigType(t, NewServiceResource()).(tftypes.Object).AttributeTypes["health_check"]
+	customCommandsType := resourceConfigType(t, NewServiceResource()).(tftypes.Object).AttributeTypes["custom_commands"]
+
+	tests := []struct {
+		name        string
+		values      map[string]tftypes.Value
+		wantError   string
+		wantWarning string
+	}{
+		{
+			name: "http health check",
+			values: map[string]tftypes.Value{
+				"service_name": str("nginx"),
+				"health_check": objectValue(healthCheckType, map[string]tftypes.Value{
+					"type": str("http"), "url": str("http://localhost/health"),
+				}),
+			},
+		},
+		{
+			name: "http health check without url",
+			values: map[string]tftypes.Value{
+				"service_name": str("nginx"),
+				"health_check": objectValue(healthCheckType, map[string]tftypes.Value{"type": str("http")}),
+			},
+			wantError: "health_check.url is required",
+		},
+		{
+			name: "tcp port range",
+			values: map[string]tftypes.Value{
+				"service_name": str("redis"),
+				"health_check": objectValue(healthCheckType, map[string]tftypes.Value{
+					"type": str("tcp"), "port": tftypes.NewValue(tftypes.Number, 70000),
+				}),
+			},
+			wantError: "must be between 1 and 65535",
+		},
+		{
+			name:      "wait timeout",
+			values:    map[string]tftypes.Value{"service_name": str("nginx"), "wait_timeout": str("2 minutes")},
+			wantError: "positive duration",
+		},
+		{
+			name: "custom commands ignored",
+			values: map[string]tftypes.Value{
+				"service_name":        str("nginx"),
+				"management_strategy": str("launchd"),
+				"custom_commands":     objectValue(customCommandsType, map[string]tftypes.Value{"start": stringList("nginx")}),
+			},
+			wantWarning: "custom_commands is not used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, warnings := validateResourceConfig(t, "pkg_service", NewServiceResource(), tt.values)
+			assertDiagnostic(t, errs, tt.wantError)
+			assertDiagnostic(t, warnings, tt.wantWarning)
+		})
+	}
+}
+
+func TestPackageProvider_ValidateConfig(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemaResp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	typ := schemaResp.Provider.ValueType()
+
+	tests := []struct {
+		name        string
+		values      map[string]tftypes.Value
+		wantError   string
+		wantWarning string
+	}{
+		{name: "defaults"},
+		{
+			name:      "update_cache",
+			values:    map[string]tftypes.Value{"update_cache": str("sometimes")},
+			wantError: "value must be one of",
+		},
+		{
+			name:      "lock_timeout",
+			values:    map[string]tftypes.Value{"lock_timeout": str("forever")},
+			wantError: "positive duration",
+		},
+		{
+			name:      "redact_patterns",
+			values:    map[string]tftypes.Value{"redact_patterns": stringList("token=(")},
+			wantError: "valid regular expression",
+		},
+		{
+			name:      "otlp_endpoint",
+			values:    map[string]tftypes.Value{"otlp_endpoint": str("collector:4318")},
+			wantError: "http or https URL",
+		},
+		{
+			name:        "cache_valid_time without on_change",
+			values:      map[string]tftypes.Value{"update_cache": str("always"), "cache_valid_time": str("1h")},
+			wantWarning: "cache_valid_time only applies",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tfprotov6.NewDynamicValue(typ, objectValue(typ, tt.values))
+			require.NoError(t, err)
+			resp, err := server.ValidateProviderConfig(context.Background(), &tfprotov6.ValidateProviderConfigRequest{
+				Config: &config,
+			})
+			require.NoError(t, err)
+			errs, warnings := diagnosticSummaries(resp.Diagnostics)
+			assertDiagnostic(t, errs, tt.wantError)
+			assertDiagnostic(t, warnings, tt.wantWarning)
+		})
+	}
+}
+
+// assertDiagnostic checks that exactly one diagnostic containing want was
+// reported, or none at all if want is empty.
+func assertDiagnostic(t *testing.T, diags []string, want string) {
+	t.Helper()
+	if want == "" {
+		assert.Empty(t, diags)
+		return
+	}
+	require.Len(t, diags, 1, "diagnostics: %v", diags)
+	assert.Contains(t, diags[0], want)
+}
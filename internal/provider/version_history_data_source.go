@@ -24,17 +24,17 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"runtime"
-	"strings"
-	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -52,10 +52,19 @@ type VersionHistoryDataSource struct {
 
 // VersionHistoryDataSourceModel describes the data source data model.
 type VersionHistoryDataSourceModel struct {
-	ID       types.String `tfsdk:"id"`
-	Name     types.String `tfsdk:"name"`
-	Manager  types.String `tfsdk:"manager"`
-	Versions types.List   `tfsdk:"versions"`
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Manager     types.String `tfsdk:"manager"`
+	SnapshotURL types.String `tfsdk:"snapshot_url"`
+	Versions    types.List   `tfsdk:"versions"`
+	Releases    types.List   `tfsdk:"releases"`
+}
+
+// VersionReleaseInfo represents a version of a package and where it is published.
+type VersionReleaseInfo struct {
+	Version types.String `tfsdk:"version"`
+	Origin  types.String `tfsdk:"origin"`
+	Suite   types.String `tfsdk:"suite"`
 }
 
 // Metadata returns the data source type name.
@@ -83,14 +92,50 @@ func (d *VersionHistoryDataSource) Schema(
 			},
 			"manager": schema.StringAttribute{
 				MarkdownDescription: "Package manager to query. " +
-					"Valid values: 'auto', 'brew'. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
 					"Defaults to 'auto'.",
 				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
+			},
+			"snapshot_url": schema.StringAttribute{
+				MarkdownDescription: "Base URL of a snapshot.debian.org-style archive, such as 'https://snapshot.debian.org' " +
+					"or a local mirror of it, whose machine-readable index is queried for historical versions " +
+					"that the configured sources no longer publish. Only supported by APT.",
+				Optional: true,
+				Validators: []validator.String{
+					httpURL(),
+				},
 			},
 			"versions": schema.ListAttribute{
-				ElementType:         types.StringType,
-				MarkdownDescription: "List of available versions for the package.",
-				Computed:            true,
+				ElementType: types.StringType,
+				MarkdownDescription: "List of available versions for the package, from the configured sources " +
+					"followed by versions only found in `snapshot_url`.",
+				Computed: true,
+			},
+			"releases": schema.ListNestedAttribute{
+				MarkdownDescription: "Available versions with the source publishing them. " +
+					"A version published by several sources is listed once per source.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"version": schema.StringAttribute{
+							MarkdownDescription: "Package version.",
+							Computed:            true,
+						},
+						"origin": schema.StringAttribute{
+							MarkdownDescription: "Repository, tap or snapshot archive publishing the version " +
+								"(e.g., 'http://archive.ubuntu.com/ubuntu' or 'homebrew/core').",
+							Computed: true,
+						},
+						"suite": schema.StringAttribute{
+							MarkdownDescription: "APT suite and component publishing the version " +
+								"(e.g., 'jammy-updates/main'). Empty for other managers and snapshot archives.",
+							Computed: true,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -129,151 +174,107 @@ func (d *VersionHistoryDataSource) Read(
 	}
 
 	// Determine package manager
-	managerName := "auto"
+	managerName := managerAuto
 	if !data.Manager.IsNull() {
 		managerName = data.Manager.ValueString()
 	}
 
-	// Auto-detect manager based on OS (Phase 2: only macOS supported)
-	if managerName == "auto" {
-		if runtime.GOOS != "darwin" {
-			resp.Diagnostics.AddError(
-				"Unsupported Operating System",
-				fmt.Sprintf("Only macOS (darwin) is supported in Phase 2, got: %s", runtime.GOOS),
-			)
-			return
-		}
-		managerName = "brew"
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
+		return
 	}
 
-	// Only support brew in Phase 2
-	if managerName != "brew" {
+	// Get version history
+	packageName := data.Name.ValueString()
+	releases, err := d.getVersionHistory(ctx, managerName, packageName, data.SnapshotURL.ValueString())
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unsupported Package Manager",
-			fmt.Sprintf("Only 'brew' manager is supported in Phase 2, got: %s", managerName),
+			"Failed to Get Version History",
+			fmt.Sprintf("Failed to get version history for package %s: %v", packageName, err),
 		)
 		return
 	}
 
-	// Get version history
-	packageName := data.Name.ValueString()
-	versions := d.getBrewVersionHistory(ctx, packageName)
-
 	// Set computed values
 	data.ID = types.StringValue(fmt.Sprintf("%s:versions:%s", managerName, packageName))
 	data.Manager = types.StringValue(managerName)
 
-	// Convert versions to list
-	versionsList, diags := types.ListValueFrom(ctx, types.StringType, versions)
+	// Convert versions to lists
+	var diags diag.Diagnostics
+	data.Versions, diags = types.ListValueFrom(ctx, types.StringType, releaseVersions(releases))
+	resp.Diagnostics.Append(diags...)
+
+	data.Releases, diags = types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"version": types.StringType,
+			"origin":  types.StringType,
+			"suite":   types.StringType,
+		},
+	}, versionReleaseInfos(releases))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	data.Versions = versionsList
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (d *VersionHistoryDataSource) getBrewVersionHistory(ctx context.Context, packageName string) []string {
-	brewPath := d.providerData.Config.BrewPath.ValueString()
-	if brewPath == "" {
-		brewPath = "brew"
+func (d *VersionHistoryDataSource) getVersionHistory(
+	ctx context.Context, managerName, packageName, snapshotURL string) ([]adapters.VersionRelease, error) {
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
 	}
 
-	var versions []string
-
-	// First, check for versioned formulas (e.g., python@3.9, python@3.10)
-	// Use proper timeout duration and handle context cancellation
-	result, err := d.providerData.Executor.Run(ctx, brewPath, []string{"search", packageName + "@"}, executor.ExecOpts{
-		Timeout: 30 * time.Second,
-	})
-
-	// Check for context cancellation first
-	if ctx.Err() != nil {
-		return []string{}
+	historian, ok := manager.(adapters.VersionHistorian)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot list version history", manager.GetManagerName())
 	}
 
-	if err == nil && result.ExitCode == 0 {
-		lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" || strings.Contains(line, "==>") {
-				continue
-			}
-
-			// Parse versioned package names
-			names := strings.Fields(line)
-			for _, name := range names {
-				if strings.HasPrefix(name, packageName+"@") {
-					version := strings.TrimPrefix(name, packageName+"@")
-					versions = append(versions, version)
-				}
-			}
-		}
+	releases, err := historian.VersionHistory(ctx, packageName)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotURL == "" {
+		return releases, nil
 	}
 
-	// Check for context cancellation before second command
-	if ctx.Err() != nil {
-		return versions
+	archive, ok := manager.(adapters.SnapshotHistorian)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot query snapshot archives", manager.GetManagerName())
 	}
 
-	// Also get the current stable version using JSON output for consistency
-	result, err = d.providerData.Executor.Run(ctx, brewPath, []string{"info", "--json", packageName}, executor.ExecOpts{
-		Timeout: 30 * time.Second,
-	})
+	snapshot, err := archive.SnapshotHistory(ctx, snapshotURL, packageName)
+	if err != nil {
+		return nil, err
+	}
+	return append(releases, snapshot...), nil
+}
 
-	if err == nil && result.ExitCode == 0 {
-		// Try to parse JSON first for more reliable parsing
-		var infos []map[string]interface{}
-		if jsonErr := json.Unmarshal([]byte(result.Stdout), &infos); jsonErr == nil && len(infos) > 0 {
-			info := infos[0]
-			if versionsMap, ok := info["versions"].(map[string]interface{}); ok {
-				if stable, ok := versionsMap["stable"].(string); ok && stable != "" {
-					// Add stable version if not already in list
-					found := false
-					for _, v := range versions {
-						if v == stable {
-							found = true
-							break
-						}
-					}
-					if !found {
-						versions = append([]string{stable}, versions...)
-					}
-				}
-			}
-		} else {
-			// Fallback to text parsing if JSON fails
-			lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
-			for _, line := range lines {
-				if strings.Contains(line, ": stable ") {
-					// Parse "package: stable X.Y.Z" format
-					parts := strings.Split(line, ": stable ")
-					if len(parts) == 2 {
-						stableVersion := strings.Fields(parts[1])[0]
-						// Add stable version if not already in list
-						found := false
-						for _, v := range versions {
-							if v == stableVersion {
-								found = true
-								break
-							}
-						}
-						if !found {
-							versions = append([]string{stableVersion}, versions...)
-						}
-					}
-					break
-				}
-			}
+// releaseVersions returns each version in releases once, in order.
+func releaseVersions(releases []adapters.VersionRelease) []string {
+	versions := []string{}
+	seen := map[string]bool{}
+	for _, release := range releases {
+		if !seen[release.Version] {
+			seen[release.Version] = true
+			versions = append(versions, release.Version)
 		}
 	}
+	return versions
+}
 
-	// If no versions found, return empty list
-	if len(versions) == 0 {
-		return []string{}
+// versionReleaseInfos converts releases to data source entries.
+func versionReleaseInfos(releases []adapters.VersionRelease) []VersionReleaseInfo {
+	infos := make([]VersionReleaseInfo, 0, len(releases))
+	for _, release := range releases {
+		infos = append(infos, VersionReleaseInfo{
+			Version: types.StringValue(release.Version),
+			Origin:  types.StringValue(release.Origin),
+			Suite:   types.StringValue(release.Suite),
+		})
 	}
-
-	return versions
+	return infos
 }
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestReleaseVersions(t *testing.T) {
+	releases := []adapters.VersionRelease{
+		{Version: "7.81.0-1ubuntu1.16", Origin: "http://archive.ubuntu.com/ubuntu", Suite: "jammy-updates/main"},
+		{Version: "7.81.0-1ubuntu1.16", Origin: "http://security.ubuntu.com/ubuntu", Suite: "jammy-security/main"},
+		{Version: "7.81.0-1ubuntu1", Origin: "http://archive.ubuntu.com/ubuntu", Suite: "jammy/main"},
+		{Version: "7.74.0-1.3", Origin: "https://snapshot.debian.org"},
+	}
+
+	assert.Equal(t, []string{"7.81.0-1ubuntu1.16", "7.81.0-1ubuntu1", "7.74.0-1.3"}, releaseVersions(releases))
+	assert.Equal(t, []string{}, releaseVersions(nil))
+
+	infos := versionReleaseInfos(releases)
+	assert.Len(t, infos, 4)
+	assert.Equal(t, VersionReleaseInfo{
+		Version: types.StringValue("7.74.0-1.3"),
+		Origin:  types.StringValue("https://snapshot.debian.org"),
+		Suite:   types.StringValue(""),
+	}, infos[3])
+}
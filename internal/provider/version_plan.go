@@ -0,0 +1,299 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+const (
+	statePresent = "present"
+	stateAbsent  = "absent"
+	stateLatest  = "latest"
+)
+
+var _ resource.ResourceWithModifyPlan = &PackageResource{}
+
+// ModifyPlan resolves the version the next apply will leave installed. It sets
+// version_target to that version and, when it differs from the installed one,
+// marks version_actual unknown so that the resource is planned for update. It
+// then applies the drift remediation and previews collateral package changes.
+func (r *PackageResource) ModifyPlan(
+	ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to resolve when destroying, or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.providerData == nil {
+		return
+	}
+
+	var plan PackageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Name.IsUnknown() || plan.State.IsUnknown() || plan.Version.IsUnknown() ||
+		plan.Managers.IsUnknown() || plan.Aliases.IsUnknown() {
+		return
+	}
+
+	var prior *PackageResourceModel
+	if !req.State.Raw.IsNull() {
+		prior = &PackageResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, prior)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	manager, packageName, err := r.resolvePackageManager(ctx, plan)
+	if err != nil {
+		// Reported by Create or Update, which resolve the manager again
+		tflog.Debug(ctx, "Skipping version resolution during plan", map[string]interface{}{
+			"package_name": plan.Name.ValueString(),
+			"error":        err.Error(),
+		})
+		if plan.State.ValueString() != stateAbsent {
+			return
+		}
+		manager, packageName = nil, plan.Name.ValueString()
+	}
+
+	var readTimeout types.String
+	if plan.Timeouts != nil {
+		readTimeout = plan.Timeouts.Read
+	}
+	readCtx, cancel := context.WithTimeout(ctx, r.getTimeout(readTimeout, "2m"))
+	defer cancel()
+
+	resp.Diagnostics.Append(r.planVersion(readCtx, manager, packageName, &plan, prior)...)
+	resp.Diagnostics.Append(planDrift(packageName, &plan, prior)...)
+	planTrackedState(&plan)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("version_target"), plan.VersionTarget)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("version_actual"), plan.VersionActual)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("drift_findings"), plan.DriftFindings)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("metadata"), plan.Metadata)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("dependency_tree"), plan.DependencyTree)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("last_access"), plan.LastAccess)...)
+
+	resp.Diagnostics.Append(r.planCollateral(readCtx, manager, packageName, &plan, prior)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("collateral_installs"), plan.CollateralInstalls)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, tfpath.Root("collateral_removals"), plan.CollateralRemovals)...)
+}
+
+// planVersion sets plan.VersionTarget and plan.VersionActual from the installed
+// version (taken from prior state, refreshed by Read, or queried for new
+// resources) and the versions the manager can install. manager may be nil
+// when the package is to be absent.
+func (r *PackageResource) planVersion(ctx context.Context, manager adapters.PackageManager, packageName string,
+	plan, prior *PackageResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	installed := ""
+	if prior != nil {
+		installed = prior.VersionActual.ValueString()
+	} else if manager != nil {
+		if info, err := manager.DetectInstalled(ctx, packageName); err == nil && info.Installed {
+			installed = info.Version
+		}
+	}
+
+	target, known := "", true
+	switch plan.State.ValueString() {
+	case stateAbsent:
+		// Removal leaves nothing installed
+	case stateLatest:
+		candidates, err := packageCandidates(ctx, manager, packageName)
+		switch {
+		case err == nil:
+			target = candidates.Candidate
+		case installed != "":
+			// Keep the installed version rather than planning an update on every run
+			tflog.Warn(ctx, "Could not resolve candidate version, assuming the installed version is current",
+				map[string]interface{}{"package_name": packageName, "error": err.Error()})
+			target = installed
+		default:
+			known = false
+		}
+	default:
+		desired := plan.Version.ValueString()
+		switch {
+		case installed != "" && versionSatisfies(installed, desired):
+			target = installed
+		case installed != "" && prior != nil && !remediatesVersionDrift(plan) &&
+			prior.Version.Equal(plan.Version):
+			// The version drifted outside Terraform and is not to be remediated
+			target = installed
+		default:
+			target, known = r.resolveDesiredVersion(ctx, manager, packageName, desired, &diags)
+		}
+	}
+
+	if !known {
+		plan.VersionTarget = types.StringUnknown()
+		plan.VersionActual = types.StringUnknown()
+		return diags
+	}
+
+	plan.VersionTarget = types.StringValue(target)
+	if prior != nil && target == installed {
+		plan.VersionActual = prior.VersionActual
+	} else {
+		plan.VersionActual = types.StringUnknown()
+	}
+	return diags
+}
+
+// resolveDesiredVersion picks the version an install of the configured version
+// constraint would select. It returns false if that cannot be determined
+// before apply.
+func (r *PackageResource) resolveDesiredVersion(ctx context.Context, manager adapters.PackageManager,
+	packageName, desired string, diags *diag.Diagnostics) (string, bool) {
+	candidates, err := packageCandidates(ctx, manager, packageName)
+	if err != nil {
+		if errors.Is(err, adapters.ErrNotFound) {
+			diags.AddAttributeWarning(
+				tfpath.Root("name"),
+				"Package Not Found",
+				fmt.Sprintf("No configured repository provides %s yet; the apply will fail unless it becomes available "+
+					"(for example through a pkg_repo resource in the same run): %v", packageName, err),
+			)
+		}
+		if desired != "" && !isVersionPattern(desired) {
+			return desired, true
+		}
+		return "", false
+	}
+
+	if desired == "" {
+		return candidates.Candidate, true
+	}
+	for _, version := range candidates.Available {
+		if versionSatisfies(version, desired) {
+			return version, true
+		}
+	}
+
+	diags.AddAttributeWarning(
+		tfpath.Root("version"),
+		"Version Not Available",
+		fmt.Sprintf("None of the versions of %s known to the package manager match %q (available: %s). "+
+			"The apply will fail unless the package cache is refreshed with a matching version.",
+			packageName, desired, strings.Join(candidates.Available, ", ")),
+	)
+	return "", false
+}
+
+// packageCandidates queries the manager's installable versions of a package.
+func packageCandidates(ctx context.Context, manager adapters.PackageManager,
+	packageName string) (*adapters.VersionCandidates, error) {
+	resolver, ok := manager.(adapters.CandidateResolver)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot report candidate versions", manager.GetManagerName())
+	}
+	return resolver.Candidates(ctx, packageName)
+}
+
+// versionSatisfies reports whether version meets the configured version
+// constraint: an exact version or a glob pattern such as '1.7*'. An empty
+// constraint accepts any version.
+func versionSatisfies(version, constraint string) bool {
+	if constraint == "" || constraint == version {
+		return true
+	}
+	if !isVersionPattern(constraint) {
+		return false
+	}
+	matched, err := path.Match(constraint, version)
+	return err == nil && matched
+}
+
+// isVersionPattern reports whether a version constraint contains glob characters.
+func isVersionPattern(constraint string) bool {
+	return strings.ContainsAny(constraint, "*?[")
+}
+
+// installDesiredVersion installs the package at its configured version. For
+// state = "latest" an installed package is upgraded to its candidate version.
+func (r *PackageResource) installDesiredVersion(ctx context.Context, manager adapters.PackageManager,
+	packageName string, data *PackageResourceModel) error {
+	packageType := r.getPackageType(data.PackageType)
+	if data.State.ValueString() != stateLatest {
+		version, err := r.installVersion(ctx, manager, packageName, data)
+		if err != nil {
+			return err
+		}
+		return r.installPackage(ctx, manager, packageName, version, packageType)
+	}
+
+	upgrader, ok := manager.(adapters.Upgrader)
+	if !ok {
+		return r.installPackage(ctx, manager, packageName, "", packageType)
+	}
+	if info, err := manager.DetectInstalled(ctx, packageName); err != nil || !info.Installed {
+		return r.installPackage(ctx, manager, packageName, "", packageType)
+	}
+	return r.rollbackScoped(ctx, manager, func() error {
+		return upgrader.Upgrade(ctx, packageName, packageType)
+	})
+}
+
+// installVersion returns the version to hand to the package manager. Package
+// managers cannot install a glob pattern, so a pattern is replaced by the
+// planned version_target it matched, or resolved again if the plan could not.
+func (r *PackageResource) installVersion(ctx context.Context, manager adapters.PackageManager,
+	packageName string, data *PackageResourceModel) (string, error) {
+	desired := data.Version.ValueString()
+	if !isVersionPattern(desired) {
+		return desired, nil
+	}
+
+	target := data.VersionTarget
+	if !target.IsUnknown() && !target.IsNull() && versionSatisfies(target.ValueString(), desired) {
+		return target.ValueString(), nil
+	}
+
+	var diags diag.Diagnostics
+	if version, ok := r.resolveDesiredVersion(ctx, manager, packageName, desired, &diags); ok {
+		return version, nil
+	}
+	return "", fmt.Errorf("no available version of %s matches %q", packageName, desired)
+}
+
+// settleVersionTarget records the installed version as the target when it
+// could not be resolved during plan.
+func settleVersionTarget(data *PackageResourceModel) {
+	if data.VersionTarget.IsUnknown() || data.VersionTarget.IsNull() {
+		data.VersionTarget = data.VersionActual
+	}
+}
@@ -0,0 +1,228 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func TestVersionSatisfies(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.7.1", "", true},
+		{"1.7.1", "1.7.1", true},
+		{"1.7.1", "1.7.0", false},
+		{"1.7.1", "1.7*", true},
+		{"7.81.0-1ubuntu1.16", "7.81.0-1ubuntu1.*", true},
+		{"1.6", "1.7*", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, versionSatisfies(tt.version, tt.constraint), "%s ~ %s", tt.version, tt.constraint)
+	}
+}
+
+func packagePlan(state, version string) *PackageResourceModel {
+	plan := &PackageResourceModel{
+		Name:             types.StringValue("curl"),
+		State:            types.StringValue(state),
+		Version:          types.StringNull(),
+		ReinstallOnDrift: types.BoolValue(true),
+		VersionActual:    types.StringUnknown(),
+		VersionTarget:    types.StringUnknown(),
+	}
+	if version != "" {
+		plan.Version = types.StringValue(version)
+	}
+	return plan
+}
+
+func priorState(plan *PackageResourceModel, installed string) *PackageResourceModel {
+	prior := *plan
+	prior.VersionActual = types.StringValue(installed)
+	prior.VersionTarget = types.StringValue(installed)
+	return &prior
+}
+
+func TestPackageResource_PlanVersion(t *testing.T) {
+	curlCandidates := &adapters.VersionCandidates{
+		Candidate: "7.81.0-1ubuntu1.16",
+		Available: []string{"7.81.0-1ubuntu1.16", "7.81.0-1ubuntu1.15", "7.81.0-1"},
+	}
+
+	tests := []struct {
+		name         string
+		plan         *PackageResourceModel
+		installed    string
+		hasPrior     bool
+		wantTarget   string
+		wantUnknown  bool
+		wantUpdate   bool
+		wantWarnings int
+	}{
+		{
+			name:       "new install resolves candidate",
+			plan:       packagePlan(statePresent, ""),
+			wantTarget: "7.81.0-1ubuntu1.16",
+			wantUpdate: true,
+		},
+		{
+			name:       "installed without constraint is unchanged",
+			plan:       packagePlan(statePresent, ""),
+			installed:  "7.81.0-1ubuntu1.15",
+			hasPrior:   true,
+			wantTarget: "7.81.0-1ubuntu1.15",
+		},
+		{
+			name:       "latest plans upgrade to new candidate",
+			plan:       packagePlan(stateLatest, ""),
+			installed:  "7.81.0-1ubuntu1.15",
+			hasPrior:   true,
+			wantTarget: "7.81.0-1ubuntu1.16",
+			wantUpdate: true,
+		},
+		{
+			name:       "latest is unchanged when current",
+			plan:       packagePlan(stateLatest, ""),
+			installed:  "7.81.0-1ubuntu1.16",
+			hasPrior:   true,
+			wantTarget: "7.81.0-1ubuntu1.16",
+		},
+		{
+			name:       "drift from desired version",
+			plan:       packagePlan(statePresent, "7.81.0-1"),
+			installed:  "7.81.0-1ubuntu1.16",
+			hasPrior:   true,
+			wantTarget: "7.81.0-1",
+			wantUpdate: true,
+		},
+		{
+			name:       "pattern resolves to newest match",
+			plan:       packagePlan(statePresent, "7.81.0-1ubuntu1.1*"),
+			installed:  "7.81.0-1",
+			hasPrior:   true,
+			wantTarget: "7.81.0-1ubuntu1.16",
+			wantUpdate: true,
+		},
+		{
+			name:         "unavailable version",
+			plan:         packagePlan(statePresent, "8.*"),
+			installed:    "7.81.0-1",
+			hasPrior:     true,
+			wantUnknown:  true,
+			wantUpdate:   true,
+			wantWarnings: 1,
+		},
+		{
+			name:       "absent",
+			plan:       packagePlan(stateAbsent, ""),
+			installed:  "7.81.0-1",
+			hasPrior:   true,
+			wantTarget: "",
+			wantUpdate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newFakePackageManager(nil)
+			manager.candidates = map[string]*adapters.VersionCandidates{"curl": curlCandidates}
+			var prior *PackageResourceModel
+			if tt.hasPrior {
+				prior = priorState(tt.plan, tt.installed)
+			}
+
+			r := &PackageResource{}
+			diags := r.planVersion(context.Background(), manager, "curl", tt.plan, prior)
+
+			assert.Len(t, diags.Warnings(), tt.wantWarnings)
+			if tt.wantUnknown {
+				assert.True(t, tt.plan.VersionTarget.IsUnknown())
+			} else {
+				assert.Equal(t, types.StringValue(tt.wantTarget), tt.plan.VersionTarget)
+			}
+			assert.Equal(t, tt.wantUpdate, tt.plan.VersionActual.IsUnknown(), "version_actual unknown")
+		})
+	}
+}
+
+func TestPackageResource_PlanVersion_DriftWithoutReinstall(t *testing.T) {
+	plan := packagePlan(statePresent, "1.6")
+	plan.ReinstallOnDrift = types.BoolValue(false)
+	prior := priorState(plan, "1.7")
+
+	diags := (&PackageResource{}).planVersion(context.Background(), newFakePackageManager(nil), "jq", plan, prior)
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, types.StringValue("1.7"), plan.VersionTarget)
+	assert.Equal(t, types.StringValue("1.7"), plan.VersionActual)
+}
+
+func TestPackageResource_PlanVersion_UnknownPackage(t *testing.T) {
+	plan := packagePlan(statePresent, "")
+
+	diags := (&PackageResource{}).planVersion(context.Background(), newFakePackageManager(nil), "nosuchpkg", plan, nil)
+
+	require.Len(t, diags.Warnings(), 1)
+	assert.Equal(t, "Package Not Found", diags.Warnings()[0].Summary())
+	assert.True(t, plan.VersionTarget.IsUnknown())
+}
+
+func TestPackageResource_InstallDesiredVersion_LatestUpgrades(t *testing.T) {
+	manager := newFakePackageManager(map[string]string{"curl": "7.81.0-1"})
+	manager.candidates = map[string]*adapters.VersionCandidates{"curl": {Candidate: "7.81.0-1ubuntu1.16"}}
+	r := &PackageResource{providerData: &ProviderData{Batcher: NewInstallBatcher(0, false)}}
+
+	err := r.installDesiredVersion(context.Background(), manager, "curl", packagePlan(stateLatest, ""))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"curl"}, manager.upgraded)
+	assert.Empty(t, manager.singleRuns)
+	assert.Equal(t, "7.81.0-1ubuntu1.16", manager.installed["curl"])
+}
+
+func TestPackageResource_InstallDesiredVersion_LatestInstallsMissing(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	r := &PackageResource{providerData: &ProviderData{Batcher: NewInstallBatcher(0, false)}}
+
+	err := r.installDesiredVersion(context.Background(), manager, "curl", packagePlan(stateLatest, ""))
+
+	require.NoError(t, err)
+	assert.Empty(t, manager.upgraded)
+	assert.Equal(t, []string{"curl"}, manager.singleRuns)
+}
+
+func TestPackageResource_InstallDesiredVersion_GlobInstallsPlannedTarget(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	r := &PackageResource{providerData: &ProviderData{Batcher: NewInstallBatcher(0, false)}}
+	plan := packagePlan(statePresent, "1.7*")
+	plan.VersionTarget = types.StringValue("1.7.1+dfsg-1")
+
+	err := r.installDesiredVersion(context.Background(), manager, "curl", plan)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.7.1+dfsg-1", manager.installed["curl"])
+}
+
+func TestPackageResource_InstallDesiredVersion_GlobResolvesUnknownTarget(t *testing.T) {
+	manager := newFakePackageManager(nil)
+	manager.candidates = map[string]*adapters.VersionCandidates{
+		"curl": {Candidate: "1.8.0-1", Available: []string{"1.8.0-1", "1.7.2-1", "1.7.1-1"}},
+	}
+	r := &PackageResource{providerData: &ProviderData{Batcher: NewInstallBatcher(0, false)}}
+
+	err := r.installDesiredVersion(context.Background(), manager, "curl", packagePlan(statePresent, "1.7*"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.7.2-1", manager.installed["curl"])
+
+	err = r.installDesiredVersion(context.Background(), manager, "curl", packagePlan(statePresent, "2.*"))
+	assert.ErrorContains(t, err, `no available version of curl matches "2.*"`)
+}
@@ -0,0 +1,153 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/osv"
+)
+
+// packageVulnerability is an advisory affecting an installed package.
+type packageVulnerability struct {
+	Package          string
+	InstalledVersion string
+	// SourcePackage is the package name the advisory was matched against,
+	// which differs from Package for Debian and Ubuntu binary packages
+	SourcePackage string
+	Finding       osv.Finding
+}
+
+// vulnerabilityScan is the result of matching installed packages against an
+// OSV database.
+type vulnerabilityScan struct {
+	Ecosystem       string
+	PackagesScanned int
+	Vulnerabilities []packageVulnerability
+}
+
+// loadVulnerabilityDatabase loads the OSV database at path, falling back to
+// the provider's vulnerability_database setting when path is empty.
+func loadVulnerabilityDatabase(providerData *ProviderData, path string) (*osv.Database, error) {
+	if providerData == nil {
+		return nil, fmt.Errorf("provider data is not configured")
+	}
+	if path == "" {
+		path = providerData.Config.VulnerabilityDB.ValueString()
+	}
+	if path == "" {
+		return nil, fmt.Errorf("no vulnerability database configured; set the provider's vulnerability_database " +
+			"or the data source's database to a directory or zip of OSV advisories")
+	}
+	if providerData.Vulnerabilities == nil {
+		return osv.Load(path)
+	}
+	return providerData.Vulnerabilities.Load(path)
+}
+
+// isBinaryOf reports whether a binary package version was built from the
+// source version: they are equal, or the binary version adds a binNMU
+// suffix such as "+b1". Other versions, like a version being checked before
+// it is installed, are matched as they are.
+func isBinaryOf(binaryVersion, sourceVersion string) bool {
+	if sourceVersion == "" {
+		return false
+	}
+	suffix, ok := strings.CutPrefix(binaryVersion, sourceVersion)
+	return ok && (suffix == "" || strings.HasPrefix(suffix, "+b"))
+}
+
+// scanVulnerabilities matches packages against db in the ecosystem of manager.
+// Packages built from a source package are matched by the source package's
+// name and version, which is how Debian and Ubuntu publish advisories.
+func scanVulnerabilities(ctx context.Context, manager adapters.PackageManager, db *osv.Database,
+	packages []adapters.PackageInfo) (*vulnerabilityScan, error) {
+	reporter, ok := manager.(adapters.EcosystemReporter)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot match vulnerability advisories", manager.GetManagerName())
+	}
+	ecosystem, err := reporter.Ecosystem(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := map[string]adapters.SourcePackage{}
+	if lister, ok := manager.(adapters.SourceLister); ok {
+		if sources, err = lister.ListSources(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	scan := &vulnerabilityScan{Ecosystem: ecosystem, Vulnerabilities: []packageVulnerability{}}
+	matches := map[string][]osv.Finding{}
+	for _, pkg := range packages {
+		if pkg.Version == "" {
+			continue
+		}
+		scan.PackagesScanned++
+
+		name, version := pkg.Name, pkg.Version
+		if source, ok := sources[pkg.Name]; ok && source.Name != "" {
+			name = source.Name
+			if isBinaryOf(version, source.Version) {
+				version = source.Version
+			}
+		}
+
+		key := name + "\x00" + version
+		findings, ok := matches[key]
+		if !ok {
+			findings = db.Match(ecosystem, name, version)
+			matches[key] = findings
+		}
+		for _, finding := range findings {
+			scan.Vulnerabilities = append(scan.Vulnerabilities, packageVulnerability{
+				Package:          pkg.Name,
+				InstalledVersion: pkg.Version,
+				SourcePackage:    name,
+				Finding:          finding,
+			})
+		}
+	}
+
+	sort.SliceStable(scan.Vulnerabilities, func(i, j int) bool {
+		a, b := scan.Vulnerabilities[i], scan.Vulnerabilities[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Finding.Vulnerability.ID < b.Finding.Vulnerability.ID
+	})
+
+	tflog.Debug(ctx, "Matched installed packages against vulnerability database", map[string]interface{}{
+		"ecosystem":       ecosystem,
+		"packages":        scan.PackagesScanned,
+		"vulnerabilities": len(scan.Vulnerabilities),
+	})
+	return scan, nil
+}
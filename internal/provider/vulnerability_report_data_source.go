@@ -0,0 +1,348 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/osv"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &VulnerabilityReportDataSource{}
+
+// NewVulnerabilityReportDataSource creates a new vulnerability report data source.
+func NewVulnerabilityReportDataSource() datasource.DataSource {
+	return &VulnerabilityReportDataSource{}
+}
+
+// VulnerabilityReportDataSource defines the data source implementation.
+type VulnerabilityReportDataSource struct {
+	providerData *ProviderData
+}
+
+// VulnerabilityReportDataSourceModel describes the data source data model.
+type VulnerabilityReportDataSourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	Manager            types.String `tfsdk:"manager"`
+	Database           types.String `tfsdk:"database"`
+	Ecosystem          types.String `tfsdk:"ecosystem"`
+	PackagesScanned    types.Int64  `tfsdk:"packages_scanned"`
+	VulnerablePackages types.Int64  `tfsdk:"vulnerable_packages"`
+	SeverityCounts     types.Map    `tfsdk:"severity_counts"`
+	Findings           types.List   `tfsdk:"findings"`
+}
+
+// VulnerabilityFinding represents an advisory affecting an installed package.
+type VulnerabilityFinding struct {
+	Package          types.String `tfsdk:"package"`
+	InstalledVersion types.String `tfsdk:"installed_version"`
+	SourcePackage    types.String `tfsdk:"source_package"`
+	ID               types.String `tfsdk:"id"`
+	CVEs             types.List   `tfsdk:"cves"`
+	Severity         types.String `tfsdk:"severity"`
+	FixedVersion     types.String `tfsdk:"fixed_version"`
+	Summary          types.String `tfsdk:"summary"`
+	URL              types.String `tfsdk:"url"`
+}
+
+// Metadata returns the data source type name.
+// Metadata returns the data source type name.
+func (d *VulnerabilityReportDataSource) Metadata(
+	_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vulnerability_report"
+}
+
+// Schema defines the data source schema.
+// Schema defines the data source schema.
+func (d *VulnerabilityReportDataSource) Schema(
+	_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Matches every installed package against the OSV vulnerability database configured with " +
+			"the provider's `vulnerability_database` and reports the advisories affecting them.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Data source identifier.",
+			},
+			"manager": schema.StringAttribute{
+				MarkdownDescription: "Package manager to query. " +
+					"Valid values: 'auto', 'brew', 'apt'. " +
+					"Defaults to 'auto'.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(managerAuto, managerBrew, managerApt),
+				},
+			},
+			"database": schema.StringAttribute{
+				MarkdownDescription: "Path of the OSV vulnerability database to use instead of the provider's " +
+					"`vulnerability_database`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"ecosystem": schema.StringAttribute{
+				MarkdownDescription: "OSV ecosystem the packages were matched in (e.g., 'Debian:12', 'Ubuntu:22.04' or 'Homebrew').",
+				Computed:            true,
+			},
+			"packages_scanned": schema.Int64Attribute{
+				MarkdownDescription: "Number of installed packages checked.",
+				Computed:            true,
+			},
+			"vulnerable_packages": schema.Int64Attribute{
+				MarkdownDescription: "Number of installed packages with at least one advisory.",
+				Computed:            true,
+			},
+			"severity_counts": schema.MapAttribute{
+				MarkdownDescription: "Number of findings per severity level " +
+					"('critical', 'high', 'medium', 'low' and 'unknown').",
+				ElementType: types.Int64Type,
+				Computed:    true,
+			},
+			"findings": schema.ListNestedAttribute{
+				MarkdownDescription: "Advisories affecting installed packages, sorted by package and advisory. " +
+					"An advisory for a source package is reported once for each installed package built from it.",
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"package": schema.StringAttribute{
+							MarkdownDescription: "Installed package name.",
+							Computed:            true,
+						},
+						"installed_version": schema.StringAttribute{
+							MarkdownDescription: "Installed package version.",
+							Computed:            true,
+						},
+						"source_package": schema.StringAttribute{
+							MarkdownDescription: "Package name the advisory was published for; the source package " +
+								"for APT, otherwise the same as `package`.",
+							Computed: true,
+						},
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Advisory identifier (e.g., 'DSA-5750-1' or 'GHSA-xxxx-xxxx-xxxx').",
+							Computed:            true,
+						},
+						"cves": schema.ListAttribute{
+							MarkdownDescription: "CVE identifiers the advisory covers.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"severity": schema.StringAttribute{
+							MarkdownDescription: "Severity level (low, medium, high, critical), or 'unknown' " +
+								"when the advisory has neither a distribution severity nor a CVSS v3 score.",
+							Computed: true,
+						},
+						"fixed_version": schema.StringAttribute{
+							MarkdownDescription: "First version fixing the advisory, or empty if no fix is available.",
+							Computed:            true,
+						},
+						"summary": schema.StringAttribute{
+							MarkdownDescription: "Advisory title.",
+							Computed:            true,
+						},
+						"url": schema.StringAttribute{
+							MarkdownDescription: "URL to advisory details.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure configures the data source with provider data.
+// Configure configures the data source with provider data.
+func (d *VulnerabilityReportDataSource) Configure(
+	_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.",
+				req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *VulnerabilityReportDataSource) Read(
+	ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VulnerabilityReportDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Determine package manager
+	managerName := managerAuto
+	if !data.Manager.IsNull() {
+		managerName = data.Manager.ValueString()
+	}
+
+	managerName, err := resolveManagerName(managerName)
+	if err != nil {
+		resp.Diagnostics.AddError("Unsupported Operating System", err.Error())
+		return
+	}
+
+	scan, err := d.scanInstalled(ctx, managerName, data.Database.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Scan Installed Packages",
+			fmt.Sprintf("Failed to match installed packages against the vulnerability database: %v", err),
+		)
+		return
+	}
+
+	// Set computed values
+	data.ID = types.StringValue(fmt.Sprintf("%s:vulnerabilities", managerName))
+	data.Manager = types.StringValue(managerName)
+	data.Ecosystem = types.StringValue(scan.Ecosystem)
+	data.PackagesScanned = types.Int64Value(int64(scan.PackagesScanned))
+	data.VulnerablePackages = types.Int64Value(int64(vulnerablePackageCount(scan.Vulnerabilities)))
+
+	var diags diag.Diagnostics
+	data.SeverityCounts, diags = types.MapValueFrom(ctx, types.Int64Type, severityCounts(scan.Vulnerabilities))
+	resp.Diagnostics.Append(diags...)
+
+	findings, diags := vulnerabilityFindings(ctx, scan.Vulnerabilities)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Findings, diags = types.ListValueFrom(ctx, types.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"package":           types.StringType,
+			"installed_version": types.StringType,
+			"source_package":    types.StringType,
+			"id":                types.StringType,
+			"cves":              types.ListType{ElemType: types.StringType},
+			"severity":          types.StringType,
+			"fixed_version":     types.StringType,
+			"summary":           types.StringType,
+			"url":               types.StringType,
+		},
+	}, findings)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *VulnerabilityReportDataSource) scanInstalled(
+	ctx context.Context, managerName, database string) (*vulnerabilityScan, error) {
+	db, err := loadVulnerabilityDatabase(d.providerData, database)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, err := newPackageManager(ctx, d.providerData, managerName)
+	if err != nil {
+		return nil, err
+	}
+
+	lister, ok := manager.(adapters.InstalledLister)
+	if !ok {
+		return nil, fmt.Errorf("package manager %s cannot list installed packages", manager.GetManagerName())
+	}
+
+	installed, err := lister.ListInstalled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanVulnerabilities(ctx, manager, db, installed)
+}
+
+// vulnerablePackageCount returns the number of distinct packages with findings.
+func vulnerablePackageCount(vulnerabilities []packageVulnerability) int {
+	packages := map[string]bool{}
+	for _, match := range vulnerabilities {
+		packages[match.Package] = true
+	}
+	return len(packages)
+}
+
+// severityCounts counts findings per severity level, including levels with no findings.
+func severityCounts(vulnerabilities []packageVulnerability) map[string]int64 {
+	counts := map[string]int64{
+		osv.SeverityCritical: 0,
+		osv.SeverityHigh:     0,
+		osv.SeverityMedium:   0,
+		osv.SeverityLow:      0,
+		osv.SeverityUnknown:  0,
+	}
+	for _, match := range vulnerabilities {
+		counts[match.Finding.SeverityLevel()]++
+	}
+	return counts
+}
+
+// vulnerabilityFindings converts matched advisories to data source entries.
+func vulnerabilityFindings(ctx context.Context, vulnerabilities []packageVulnerability) ([]VulnerabilityFinding, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	findings := make([]VulnerabilityFinding, 0, len(vulnerabilities))
+	for _, match := range vulnerabilities {
+		vuln := match.Finding.Vulnerability
+		cves, d := types.ListValueFrom(ctx, types.StringType, vuln.CVEs())
+		diags.Append(d...)
+		findings = append(findings, VulnerabilityFinding{
+			Package:          types.StringValue(match.Package),
+			InstalledVersion: types.StringValue(match.InstalledVersion),
+			SourcePackage:    types.StringValue(match.SourcePackage),
+			ID:               types.StringValue(vuln.ID),
+			CVEs:             cves,
+			Severity:         types.StringValue(match.Finding.SeverityLevel()),
+			FixedVersion:     types.StringValue(match.Finding.Fixed),
+			Summary:          types.StringValue(vuln.Summary),
+			URL:              types.StringValue(vuln.URL()),
+		})
+	}
+	return findings, diags
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/osv"
+)
+
+// ecosystemPackageManager is a fake manager publishing advisories by source package.
+type ecosystemPackageManager struct {
+	*fakePackageManager
+	ecosystem string
+	sources   map[string]adapters.SourcePackage
+}
+
+func (m *ecosystemPackageManager) Ecosystem(_ context.Context) (string, error) {
+	return m.ecosystem, nil
+}
+
+func (m *ecosystemPackageManager) ListSources(_ context.Context) (map[string]adapters.SourcePackage, error) {
+	return m.sources, nil
+}
+
+const opensslAdvisory = `{
+  "id": "DSA-5532-1",
+  "summary": "openssl security update",
+  "upstream": ["CVE-2023-5363"],
+  "affected": [{
+    "package": {"ecosystem": "Debian:12", "name": "openssl"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "3.0.11-1~deb12u2"}]}],
+    "database_specific": {"severity": "important"}
+  }]
+}`
+
+func writeVulnerabilityDatabase(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "DSA-5532-1.json"), []byte(opensslAdvisory), 0o600))
+	return dir
+}
+
+func TestScanVulnerabilities(t *testing.T) {
+	db, err := osv.Load(writeVulnerabilityDatabase(t))
+	require.NoError(t, err)
+
+	manager := &ecosystemPackageManager{
+		fakePackageManager: newFakePackageManager(nil),
+		ecosystem:          "Debian:12",
+		sources: map[string]adapters.SourcePackage{
+			"libssl3":  {Name: "openssl", Version: "3.0.11-1~deb12u1"},
+			"openssl":  {Name: "openssl", Version: "3.0.11-1~deb12u1"},
+			"libcurl4": {Name: "curl", Version: "7.88.1-10+deb12u5"},
+		},
+	}
+	installed := []adapters.PackageInfo{
+		{Name: "openssl", Version: "3.0.11-1~deb12u1+b1"},
+		{Name: "libssl3", Version: "3.0.11-1~deb12u1"},
+		{Name: "libcurl4", Version: "7.88.1-10+deb12u5"},
+		{Name: "config-only"},
+	}
+
+	scan, err := scanVulnerabilities(context.Background(), manager, db, installed)
+	require.NoError(t, err)
+	assert.Equal(t, "Debian:12", scan.Ecosystem)
+	assert.Equal(t, 3, scan.PackagesScanned)
+	require.Len(t, scan.Vulnerabilities, 2)
+	assert.Equal(t, "libssl3", scan.Vulnerabilities[0].Package)
+	assert.Equal(t, "openssl", scan.Vulnerabilities[0].SourcePackage)
+	assert.Equal(t, "openssl", scan.Vulnerabilities[1].Package)
+	assert.Equal(t, "3.0.11-1~deb12u1+b1", scan.Vulnerabilities[1].InstalledVersion)
+	assert.Equal(t, "3.0.11-1~deb12u2", scan.Vulnerabilities[1].Finding.Fixed)
+
+	assert.Equal(t, 1, vulnerablePackageCount(scan.Vulnerabilities[:1]))
+	assert.Equal(t, 2, vulnerablePackageCount(scan.Vulnerabilities))
+	assert.Equal(t, map[string]int64{"critical": 0, "high": 2, "medium": 0, "low": 0, "unknown": 0},
+		severityCounts(scan.Vulnerabilities))
+
+	findings, diags := vulnerabilityFindings(context.Background(), scan.Vulnerabilities)
+	require.False(t, diags.HasError())
+	assert.Equal(t, types.StringValue("DSA-5532-1"), findings[0].ID)
+	assert.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("CVE-2023-5363")}), findings[0].CVEs)
+	assert.Equal(t, types.StringValue("https://osv.dev/vulnerability/DSA-5532-1"), findings[0].URL)
+
+	advisories, diags := securityAdvisories(context.Background(), scan.Vulnerabilities[:1])
+	require.False(t, diags.HasError())
+	assert.Equal(t, types.StringValue("CVE-2023-5363"), advisories[0].CVE)
+	assert.Equal(t, types.StringValue("high"), advisories[0].Severity)
+}
+
+func TestScanVulnerabilities_CheckedVersion(t *testing.T) {
+	db, err := osv.Load(writeVulnerabilityDatabase(t))
+	require.NoError(t, err)
+
+	manager := &ecosystemPackageManager{
+		fakePackageManager: newFakePackageManager(nil),
+		ecosystem:          "Debian:12",
+		sources:            map[string]adapters.SourcePackage{"libssl3": {Name: "openssl", Version: "3.0.11-1~deb12u1"}},
+	}
+
+	// A version other than the installed one is matched as given
+	scan, err := scanVulnerabilities(context.Background(), manager, db,
+		[]adapters.PackageInfo{{Name: "libssl3", Version: "3.0.11-1~deb12u2"}})
+	require.NoError(t, err)
+	assert.Empty(t, scan.Vulnerabilities)
+}
+
+func TestScanVulnerabilities_UnsupportedManager(t *testing.T) {
+	db, err := osv.Load(writeVulnerabilityDatabase(t))
+	require.NoError(t, err)
+
+	_, err = scanVulnerabilities(context.Background(), newFakePackageManager(nil), db, nil)
+	assert.ErrorContains(t, err, "cannot match vulnerability advisories")
+}
+
+func TestLoadVulnerabilityDatabase(t *testing.T) {
+	providerData := &ProviderData{Config: &PackageProviderModel{}, Vulnerabilities: osv.NewCache()}
+
+	_, err := loadVulnerabilityDatabase(providerData, "")
+	assert.ErrorContains(t, err, "no vulnerability database configured")
+
+	providerData.Config.VulnerabilityDB = types.StringValue(writeVulnerabilityDatabase(t))
+	db, err := loadVulnerabilityDatabase(providerData, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.Count)
+
+	_, err = loadVulnerabilityDatabase(providerData, filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	_, err = loadVulnerabilityDatabase(nil, "")
+	assert.Error(t, err)
+}
+
+func TestIsBinaryOf(t *testing.T) {
+	assert.True(t, isBinaryOf("1.2-3", "1.2-3"))
+	assert.True(t, isBinaryOf("1.2-3+b1", "1.2-3"))
+	assert.False(t, isBinaryOf("1.2-4", "1.2-3"))
+	assert.False(t, isBinaryOf("1.2-3", ""))
+}
@@ -0,0 +1,165 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// propertyPrefix namespaces the CycloneDX properties the provider adds.
+const propertyPrefix = toolName + ":"
+
+type cdxBOM struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `json:"timestamp"`
+	Tools     cdxTools      `json:"tools"`
+	Component *cdxComponent `json:"component,omitempty"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	BOMRef             string             `json:"bom-ref,omitempty"`
+	Type               string             `json:"type"`
+	Supplier           *cdxOrganization   `json:"supplier,omitempty"`
+	Name               string             `json:"name"`
+	Version            string             `json:"version,omitempty"`
+	Description        string             `json:"description,omitempty"`
+	Licenses           []cdxLicenseChoice `json:"licenses,omitempty"`
+	Purl               string             `json:"purl,omitempty"`
+	ExternalReferences []cdxReference     `json:"externalReferences,omitempty"`
+	Properties         []cdxProperty      `json:"properties,omitempty"`
+}
+
+type cdxOrganization struct {
+	Name    string       `json:"name"`
+	Contact []cdxContact `json:"contact,omitempty"`
+}
+
+type cdxContact struct {
+	Email string `json:"email"`
+}
+
+type cdxLicenseChoice struct {
+	License    *cdxLicense `json:"license,omitempty"`
+	Expression string      `json:"expression,omitempty"`
+}
+
+type cdxLicense struct {
+	Name string `json:"name"`
+}
+
+type cdxReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CycloneDX renders the document as CycloneDX 1.5 JSON. Components are
+// identified by their package URL; the manager, architecture and source
+// repository are recorded as properties.
+func CycloneDX(doc Document) (string, error) {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + doc.uuid(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: doc.timestamp(),
+			Tools: cdxTools{Components: []cdxComponent{
+				{Type: "application", Name: toolName, Version: doc.ToolVersion},
+			}},
+		},
+		Components: make([]cdxComponent, 0, len(doc.Components)),
+	}
+	if doc.Name != "" {
+		bom.Metadata.Component = &cdxComponent{Type: "operating-system", Name: doc.Name}
+	}
+
+	refs := map[string]int{}
+	for _, c := range doc.Components {
+		component := cdxComponent{
+			BOMRef:      c.Purl,
+			Type:        "library",
+			Name:        c.Name,
+			Version:     c.Version,
+			Description: c.Description,
+			Purl:        c.Purl,
+		}
+		if component.BOMRef == "" {
+			component.BOMRef = fmt.Sprintf("%s:%s@%s", c.Manager, c.Name, c.Version)
+		}
+		if refs[component.BOMRef]++; refs[component.BOMRef] > 1 {
+			component.BOMRef = fmt.Sprintf("%s#%d", component.BOMRef, refs[component.BOMRef])
+		}
+
+		if c.Supplier != "" {
+			name, email := splitSupplier(c.Supplier)
+			component.Supplier = &cdxOrganization{Name: name}
+			if email != "" {
+				component.Supplier.Contact = []cdxContact{{Email: email}}
+			}
+		}
+		if c.License != "" {
+			if expression, ok := spdxExpression(c.License); ok && len(expression.References) == 0 {
+				component.Licenses = []cdxLicenseChoice{{Expression: expression.Expression}}
+			} else {
+				component.Licenses = []cdxLicenseChoice{{License: &cdxLicense{Name: c.License}}}
+			}
+		}
+		if c.Homepage != "" {
+			component.ExternalReferences = []cdxReference{{Type: "website", URL: c.Homepage}}
+		}
+		for _, property := range []cdxProperty{
+			{Name: propertyPrefix + "manager", Value: c.Manager},
+			{Name: propertyPrefix + "architecture", Value: c.Architecture},
+			{Name: propertyPrefix + "source_repository", Value: c.SourceRepository},
+		} {
+			if property.Value != "" {
+				component.Properties = append(component.Properties, property)
+			}
+		}
+		bom.Components = append(bom.Components, component)
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render CycloneDX document: %w", err)
+	}
+	return string(data), nil
+}
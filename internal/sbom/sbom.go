@@ -0,0 +1,266 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package sbom renders inventories of installed packages as software bills
+// of materials in the CycloneDX and SPDX JSON formats.
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+// toolName identifies the provider as the author of generated documents.
+const toolName = "terraform-provider-pkg"
+
+// Component is an installed package in a bill of materials.
+type Component struct {
+	adapters.Component
+	// Manager is the package manager that installed the package, e.g. "apt"
+	Manager string
+}
+
+// Document is the inventory of a host rendered by CycloneDX and SPDX.
+type Document struct {
+	// Name names the inventoried system, e.g. its host name
+	Name    string
+	Created time.Time
+	// ToolVersion is the provider version recorded as the document's creator
+	ToolVersion string
+	Components  []Component
+}
+
+// uuid derives a stable UUID from the document's contents, so the same
+// inventory always renders the same serial number and namespace.
+func (d Document) uuid() string {
+	data, _ := json.Marshal(d)
+	sum := sha256.Sum256(data)
+	// Format as a version 5 (name-based) UUID with the RFC 4122 variant
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// timestamp formats the creation time as SPDX and CycloneDX require.
+func (d Document) timestamp() string {
+	return d.Created.UTC().Format(time.RFC3339)
+}
+
+// supplierPattern splits "Name <email>" maintainer fields.
+var supplierPattern = regexp.MustCompile(`^(.*?)\s*<([^>]*)>\s*$`)
+
+// splitSupplier splits a supplier into its name and email address, if any.
+func splitSupplier(supplier string) (string, string) {
+	if match := supplierPattern.FindStringSubmatch(supplier); match != nil {
+		return match[1], match[2]
+	}
+	return supplier, ""
+}
+
+// licenseIdentifier matches an SPDX license or exception identifier.
+var licenseIdentifier = regexp.MustCompile(`^(LicenseRef-)?[A-Za-z0-9][A-Za-z0-9.\-]*\+?$`)
+
+// gnuLicense matches GNU license names in Debian (DEP-5) or SPDX form, such as
+// "GPL-2", "LGPL-2.1" or "GPL-3.0-only"; a trailing "+" is removed beforehand.
+var gnuLicense = regexp.MustCompile(`^(gpl|lgpl|agpl|gfdl)-(\d+)(?:\.(\d+))?(-only|-or-later)?$`)
+
+// knownLicenses maps lower-cased license names used by Debian copyright files
+// (DEP-5) and package metadata to SPDX license identifiers. GNU licenses are
+// handled by gnuLicense.
+var knownLicenses = map[string]string{
+	"0bsd":         "0BSD",
+	"apache-1.0":   "Apache-1.0",
+	"apache-1.1":   "Apache-1.1",
+	"apache-2.0":   "Apache-2.0",
+	"artistic-1.0": "Artistic-1.0",
+	"artistic-2.0": "Artistic-2.0",
+	"bsd-2-clause": "BSD-2-Clause",
+	"bsd-3-clause": "BSD-3-Clause",
+	"bsd-4-clause": "BSD-4-Clause",
+	"bsl-1.0":      "BSL-1.0",
+	"cc-by-3.0":    "CC-BY-3.0",
+	"cc-by-4.0":    "CC-BY-4.0",
+	"cc-by-sa-3.0": "CC-BY-SA-3.0",
+	"cc-by-sa-4.0": "CC-BY-SA-4.0",
+	"cc0-1.0":      "CC0-1.0",
+	"cddl-1.0":     "CDDL-1.0",
+	"cddl-1.1":     "CDDL-1.1",
+	"curl":         "curl",
+	"epl-1.0":      "EPL-1.0",
+	"epl-2.0":      "EPL-2.0",
+	"expat":        "MIT",
+	"isc":          "ISC",
+	"lppl-1.3c":    "LPPL-1.3c",
+	"mit":          "MIT",
+	"mpl-1.1":      "MPL-1.1",
+	"mpl-2.0":      "MPL-2.0",
+	"ofl-1.1":      "OFL-1.1",
+	"openssl":      "OpenSSL",
+	"psf-2.0":      "PSF-2.0",
+	"python-2.0":   "Python-2.0",
+	"qpl-1.0":      "QPL-1.0",
+	"sil-ofl-1.1":  "OFL-1.1",
+	"sleepycat":    "Sleepycat",
+	"unlicense":    "Unlicense",
+	"w3c":          "W3C",
+	"x11":          "X11",
+	"zlib":         "Zlib",
+	"zope-2.1":     "ZPL-2.1",
+	"zpl-2.1":      "ZPL-2.1",
+}
+
+// knownExceptions maps lower-cased license exception names to SPDX exception identifiers.
+var knownExceptions = map[string]string{
+	"autoconf-exception-3.0":  "Autoconf-exception-3.0",
+	"bison-exception-2.2":     "Bison-exception-2.2",
+	"classpath-exception-2.0": "Classpath-exception-2.0",
+	"font-exception-2.0":      "Font-exception-2.0",
+	"gcc-exception-3.1":       "GCC-exception-3.1",
+	"llvm-exception":          "LLVM-exception",
+}
+
+// licenseRefInvalid matches the characters not allowed in LicenseRef identifiers.
+var licenseRefInvalid = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// licenseExpression is a declared license converted to SPDX.
+type licenseExpression struct {
+	// Expression is the SPDX license expression
+	Expression string
+	// References maps the LicenseRef identifiers in Expression to the license
+	// names they stand for
+	References map[string]string
+}
+
+// spdxLicenseID converts a license name to an SPDX identifier. Debian names
+// such as "GPL-2+" and "Expat" are mapped to their SPDX equivalents; other
+// names become LicenseRef identifiers, which are recorded in references.
+func spdxLicenseID(name string, references map[string]string) string {
+	if strings.HasPrefix(name, "LicenseRef-") {
+		references[name] = name
+		return name
+	}
+
+	base, orLater := strings.CutSuffix(name, "+")
+	key := strings.ToLower(base)
+	if match := gnuLicense.FindStringSubmatch(key); match != nil {
+		minor := match[3]
+		if minor == "" {
+			minor = "0"
+		}
+		suffix := "-only"
+		if orLater || match[4] == "-or-later" {
+			suffix = "-or-later"
+		}
+		return fmt.Sprintf("%s-%s.%s%s", strings.ToUpper(match[1]), match[2], minor, suffix)
+	}
+	if id, ok := knownLicenses[key]; ok {
+		if orLater {
+			id += "+"
+		}
+		return id
+	}
+
+	id := "LicenseRef-" + strings.Trim(licenseRefInvalid.ReplaceAllString(name, "-"), "-")
+	references[id] = name
+	return id
+}
+
+// spdxExpression converts a declared license to an SPDX license expression.
+// Licenses listed with commas, as Debian copyright files are summarized, all
+// apply, so they are joined with AND. Operators may be lower case, as in
+// Debian copyright files. A single free-text license name becomes a
+// LicenseRef; otherwise spdxExpression reports false for licenses that are not
+// valid expressions.
+func spdxExpression(license string) (licenseExpression, bool) {
+	result := licenseExpression{References: map[string]string{}}
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return licenseExpression{}, false
+	}
+	if !strings.ContainsAny(license, ",()") && !containsOperator(strings.Fields(license)) {
+		result.Expression = spdxLicenseID(license, result.References)
+		return result, true
+	}
+
+	parts := strings.Split(license, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+		if strings.Contains(parts[i], " ") && len(parts) > 1 {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+
+	var expression []string
+	depth := 0
+	expectOperand := true
+	afterWith := false
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(strings.Join(parts, " AND ")))
+	for _, token := range tokens {
+		operator := strings.ToUpper(token)
+		switch {
+		case token == "(" && expectOperand && !afterWith:
+			depth++
+		case token == ")" && !expectOperand && depth > 0:
+			depth--
+		case (operator == "AND" || operator == "OR" || operator == "WITH") && !expectOperand:
+			token = operator
+			expectOperand = true
+			afterWith = operator == "WITH"
+		case expectOperand && afterWith:
+			exception, ok := knownExceptions[strings.ToLower(token)]
+			if !ok {
+				return licenseExpression{}, false
+			}
+			token = exception
+			expectOperand, afterWith = false, false
+		case expectOperand && licenseIdentifier.MatchString(token):
+			token = spdxLicenseID(token, result.References)
+			expectOperand = false
+		default:
+			return licenseExpression{}, false
+		}
+		expression = append(expression, token)
+	}
+	if expectOperand || depth != 0 {
+		return licenseExpression{}, false
+	}
+
+	result.Expression = strings.NewReplacer("( ", "(", " )", ")").Replace(strings.Join(expression, " "))
+	return result, true
+}
+
+// containsOperator reports whether tokens include a license expression operator.
+func containsOperator(tokens []string) bool {
+	for _, token := range tokens {
+		switch strings.ToUpper(token) {
+		case "AND", "OR", "WITH":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+)
+
+func testDocument() Document {
+	return Document{
+		Name:        "web-01",
+		Created:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		ToolVersion: "1.2.3",
+		Components: []Component{
+			{Manager: "apt", Component: adapters.Component{
+				Name:             "curl",
+				Version:          "7.88.1-10+deb12u5",
+				Architecture:     "amd64",
+				Purl:             "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12",
+				License:          "curl, ISC",
+				Supplier:         "Alessandro Ghedini <ghedo@debian.org>",
+				SourceRepository: "http://deb.debian.org/debian bookworm/main",
+				Homepage:         "https://curl.se/",
+				Description:      "command line tool for transferring data with URL syntax",
+			}},
+			{Manager: "apt", Component: adapters.Component{
+				Name:    "tzdata",
+				Version: "2024a-0+deb12u1",
+				License: "public-domain or BSD-3-clause",
+			}},
+		},
+	}
+}
+
+func TestSPDXExpression(t *testing.T) {
+	tests := []struct {
+		license    string
+		expected   string
+		references map[string]string
+		ok         bool
+	}{
+		{"MIT", "MIT", nil, true},
+		{"Apache-2.0 OR MIT", "Apache-2.0 OR MIT", nil, true},
+		{"GPL-2.0-or-later WITH Classpath-exception-2.0", "GPL-2.0-or-later WITH Classpath-exception-2.0", nil, true},
+		{"(MIT OR Apache-2.0) AND BSD-3-Clause", "(MIT OR Apache-2.0) AND BSD-3-Clause", nil, true},
+		{"GPL-2+, BSD-3-clause", "GPL-2.0-or-later AND BSD-3-Clause", nil, true},
+		{"LGPL-2.1, GPL-3", "LGPL-2.1-only AND GPL-3.0-only", nil, true},
+		{"Expat", "MIT", nil, true},
+		{"MIT, Apache-2.0 OR BSD-2-Clause", "MIT AND (Apache-2.0 OR BSD-2-Clause)", nil, true},
+		{"public-domain or BSD-3-clause", "LicenseRef-public-domain OR BSD-3-Clause", map[string]string{"LicenseRef-public-domain": "public-domain"}, true},
+		{"Artistic License", "LicenseRef-Artistic-License", map[string]string{"LicenseRef-Artistic-License": "Artistic License"}, true},
+		{"GPL-2+ with OpenSSL exception", "", nil, false},
+		{"MIT AND", "", nil, false},
+		{"(MIT", "", nil, false},
+		{"", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.license, func(t *testing.T) {
+			expression, ok := spdxExpression(tt.license)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, expression.Expression)
+			if tt.references == nil {
+				assert.Empty(t, expression.References)
+			} else {
+				assert.Equal(t, tt.references, expression.References)
+			}
+		})
+	}
+}
+
+func TestCycloneDX(t *testing.T) {
+	rendered, err := CycloneDX(testDocument())
+	require.NoError(t, err)
+
+	var bom map[string]any
+	require.NoError(t, json.Unmarshal([]byte(rendered), &bom))
+	assert.Equal(t, "CycloneDX", bom["bomFormat"])
+	assert.Equal(t, "1.5", bom["specVersion"])
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, bom["serialNumber"])
+
+	metadata := bom["metadata"].(map[string]any)
+	assert.Equal(t, "2024-05-01T12:00:00Z", metadata["timestamp"])
+	assert.Equal(t, map[string]any{"type": "operating-system", "name": "web-01"}, metadata["component"])
+
+	components := bom["components"].([]any)
+	require.Len(t, components, 2)
+	assert.Equal(t, map[string]any{
+		"bom-ref":     "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12",
+		"type":        "library",
+		"supplier":    map[string]any{"name": "Alessandro Ghedini", "contact": []any{map[string]any{"email": "ghedo@debian.org"}}},
+		"name":        "curl",
+		"version":     "7.88.1-10+deb12u5",
+		"description": "command line tool for transferring data with URL syntax",
+		"licenses":    []any{map[string]any{"expression": "curl AND ISC"}},
+		"purl":        "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12",
+		"externalReferences": []any{
+			map[string]any{"type": "website", "url": "https://curl.se/"},
+		},
+		"properties": []any{
+			map[string]any{"name": "terraform-provider-pkg:manager", "value": "apt"},
+			map[string]any{"name": "terraform-provider-pkg:architecture", "value": "amd64"},
+			map[string]any{"name": "terraform-provider-pkg:source_repository", "value": "http://deb.debian.org/debian bookworm/main"},
+		},
+	}, components[0])
+
+	tzdata := components[1].(map[string]any)
+	assert.Equal(t, "apt:tzdata@2024a-0+deb12u1", tzdata["bom-ref"])
+	assert.Equal(t, []any{map[string]any{"license": map[string]any{"name": "public-domain or BSD-3-clause"}}}, tzdata["licenses"])
+
+	again, err := CycloneDX(testDocument())
+	require.NoError(t, err)
+	assert.Equal(t, rendered, again, "rendering is deterministic")
+}
+
+func TestSPDX(t *testing.T) {
+	rendered, err := SPDX(testDocument())
+	require.NoError(t, err)
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal([]byte(rendered), &document))
+	assert.Equal(t, "SPDX-2.3", document["spdxVersion"])
+	assert.Equal(t, "CC0-1.0", document["dataLicense"])
+	assert.Equal(t, "web-01", document["name"])
+	assert.Regexp(t, `^https://spdx.org/spdxdocs/web-01-[0-9a-f-]{36}$`, document["documentNamespace"])
+	assert.Equal(t, map[string]any{
+		"created":  "2024-05-01T12:00:00Z",
+		"creators": []any{"Tool: terraform-provider-pkg-1.2.3"},
+	}, document["creationInfo"])
+
+	packages := document["packages"].([]any)
+	require.Len(t, packages, 2)
+	assert.Equal(t, map[string]any{
+		"SPDXID":           "SPDXRef-Package-apt-curl-1",
+		"name":             "curl",
+		"versionInfo":      "7.88.1-10+deb12u5",
+		"supplier":         "Organization: Alessandro Ghedini (ghedo@debian.org)",
+		"downloadLocation": "NOASSERTION",
+		"filesAnalyzed":    false,
+		"homepage":         "https://curl.se/",
+		"sourceInfo":       "installed with apt from http://deb.debian.org/debian bookworm/main",
+		"licenseConcluded": "NOASSERTION",
+		"licenseDeclared":  "curl AND ISC",
+		"copyrightText":    "NOASSERTION",
+		"summary":          "command line tool for transferring data with URL syntax",
+		"externalRefs": []any{map[string]any{
+			"referenceCategory": "PACKAGE-MANAGER",
+			"referenceType":     "purl",
+			"referenceLocator":  "pkg:deb/debian/curl@7.88.1-10%2Bdeb12u5?arch=amd64&distro=debian-12",
+		}},
+	}, packages[0])
+
+	tzdata := packages[1].(map[string]any)
+	assert.Equal(t, "NOASSERTION", tzdata["supplier"])
+	assert.Equal(t, "LicenseRef-public-domain OR BSD-3-Clause", tzdata["licenseDeclared"])
+	assert.NotContains(t, tzdata, "licenseComments")
+	assert.Equal(t, []any{map[string]any{
+		"licenseId":     "LicenseRef-public-domain",
+		"extractedText": "Declared license: public-domain",
+		"name":          "public-domain",
+	}}, document["hasExtractedLicensingInfos"])
+
+	assert.Equal(t, []any{
+		map[string]any{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Package-apt-curl-1"},
+		map[string]any{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Package-apt-tzdata-2"},
+	}, document["relationships"])
+}
+
+func TestDocument_UUIDChangesWithContents(t *testing.T) {
+	doc := testDocument()
+	other := testDocument()
+	other.Components = other.Components[:1]
+	assert.NotEqual(t, doc.uuid(), other.uuid())
+	assert.Equal(t, doc.uuid(), testDocument().uuid())
+}
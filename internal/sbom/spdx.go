@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// noAssertion marks SPDX fields whose value is not known.
+const noAssertion = "NOASSERTION"
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+
+	HasExtractedLicensingInfos []spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type spdxExtractedLicense struct {
+	LicenseID     string `json:"licenseId"`
+	ExtractedText string `json:"extractedText"`
+	Name          string `json:"name"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Supplier         string            `json:"supplier"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Homepage         string            `json:"homepage,omitempty"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	LicenseComments  string            `json:"licenseComments,omitempty"`
+	CopyrightText    string            `json:"copyrightText"`
+	Summary          string            `json:"summary,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxIDInvalid matches the characters not allowed in SPDX identifiers.
+var spdxIDInvalid = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+
+// SPDX renders the document as SPDX 2.3 JSON. The document describes every
+// package; Debian license names are mapped to SPDX identifiers, other license
+// names are declared as LicenseRef identifiers with extracted licensing info,
+// and licenses that are not valid expressions are recorded as NOASSERTION with
+// the declared license in the license comments.
+func SPDX(doc Document) (string, error) {
+	name := doc.Name
+	if name == "" {
+		name = "installed-packages"
+	}
+	document := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", spdxIDInvalid.ReplaceAllString(name, "-"), doc.uuid()),
+		CreationInfo: spdxCreationInfo{
+			Created:  doc.timestamp(),
+			Creators: []string{fmt.Sprintf("Tool: %s-%s", toolName, doc.ToolVersion)},
+		},
+		Packages:      make([]spdxPackage, 0, len(doc.Components)),
+		Relationships: make([]spdxRelationship, 0, len(doc.Components)),
+	}
+
+	references := map[string]string{}
+	for i, c := range doc.Components {
+		pkg := spdxPackage{
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%s-%s-%d", c.Manager, spdxIDInvalid.ReplaceAllString(c.Name, "-"), i+1),
+			Name:             c.Name,
+			VersionInfo:      c.Version,
+			Supplier:         noAssertion,
+			DownloadLocation: noAssertion,
+			Homepage:         c.Homepage,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+			Summary:          c.Description,
+		}
+		if c.Supplier != "" {
+			supplierName, email := splitSupplier(c.Supplier)
+			pkg.Supplier = "Organization: " + supplierName
+			if email != "" {
+				pkg.Supplier += " (" + email + ")"
+			}
+		}
+		if c.SourceRepository != "" {
+			pkg.SourceInfo = fmt.Sprintf("installed with %s from %s", c.Manager, c.SourceRepository)
+		}
+		if c.License != "" {
+			if expression, ok := spdxExpression(c.License); ok {
+				pkg.LicenseDeclared = expression.Expression
+				for id, name := range expression.References {
+					references[id] = name
+				}
+			} else {
+				pkg.LicenseComments = "Declared license: " + c.License
+			}
+		}
+		if c.Purl != "" {
+			pkg.ExternalRefs = []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  c.Purl,
+			}}
+		}
+
+		document.Packages = append(document.Packages, pkg)
+		document.Relationships = append(document.Relationships, spdxRelationship{
+			SPDXElementID:      document.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: pkg.SPDXID,
+		})
+	}
+
+	for id, name := range references {
+		document.HasExtractedLicensingInfos = append(document.HasExtractedLicensingInfos, spdxExtractedLicense{
+			LicenseID:     id,
+			ExtractedText: "Declared license: " + name,
+			Name:          name,
+		})
+	}
+	sort.Slice(document.HasExtractedLicensingInfos, func(i, j int) bool {
+		return document.HasExtractedLicensingInfos[i].LicenseID < document.HasExtractedLicensingInfos[j].LicenseID
+	})
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render SPDX document: %w", err)
+	}
+	return string(data), nil
+}
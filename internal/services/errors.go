@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package services
+
+import (
+	"github.com/jamesainslie/terraform-provider-package/internal/adapters"
+	"github.com/jamesainslie/terraform-provider-package/internal/executor"
+)
+
+// serviceErrorPatterns recognize systemctl, launchctl and Windows service
+// control failure messages.
+var serviceErrorPatterns = []adapters.OutputPattern{
+	{Kind: adapters.ErrPermissionDenied, Substrings: []string{
+		"Access denied",
+		"Access is denied",
+		"Interactive authentication required",
+		"Operation not permitted",
+		"Permission denied",
+	}},
+	{Kind: adapters.ErrNotFound, Substrings: []string{
+		"not found",
+		"not loaded",
+		"Could not find service",
+		"Cannot find any service",
+		"No such process",
+		"does not exist",
+	}},
+	{Kind: adapters.ErrDependencyConflict, Substrings: []string{
+		"A dependency job",
+		"dependency failed",
+		"conflicts with",
+	}},
+}
+
+// classifyServiceOutput maps service manager output to an adapter error kind.
+func classifyServiceOutput(stdout, stderr string) error {
+	return adapters.MatchOutput(serviceErrorPatterns, stderr+"\n"+stdout)
+}
+
+// serviceCommandError builds a typed error for a failed service command.
+func serviceCommandError(manager, action, serviceName string, result executor.ExecResult, err error) error {
+	return adapters.NewOperationError(manager, action+" service", serviceName, result, err, classifyServiceOutput)
+}
@@ -219,11 +219,8 @@ func (l *LinuxServiceDetector) checkProcessName(ctx context.Context, serviceName
 // StartService starts a service using systemctl
 func (l *LinuxServiceDetector) StartService(ctx context.Context, serviceName string) error {
 	result, err := l.executor.Run(ctx, "systemctl", []string{"start", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to start service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to start service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("systemctl", "start", serviceName, result, err)
 	}
 	return nil
 }
@@ -231,11 +228,8 @@ func (l *LinuxServiceDetector) StartService(ctx context.Context, serviceName str
 // StopService stops a service using systemctl
 func (l *LinuxServiceDetector) StopService(ctx context.Context, serviceName string) error {
 	result, err := l.executor.Run(ctx, "systemctl", []string{"stop", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to stop service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to stop service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("systemctl", "stop", serviceName, result, err)
 	}
 	return nil
 }
@@ -243,11 +237,8 @@ func (l *LinuxServiceDetector) StopService(ctx context.Context, serviceName stri
 // RestartService restarts a service using systemctl
 func (l *LinuxServiceDetector) RestartService(ctx context.Context, serviceName string) error {
 	result, err := l.executor.Run(ctx, "systemctl", []string{"restart", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to restart service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to restart service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("systemctl", "restart", serviceName, result, err)
 	}
 	return nil
 }
@@ -255,11 +246,8 @@ func (l *LinuxServiceDetector) RestartService(ctx context.Context, serviceName s
 // DisableService disables a service from starting automatically on system startup
 func (l *LinuxServiceDetector) DisableService(ctx context.Context, serviceName string) error {
 	result, err := l.executor.Run(ctx, "systemctl", []string{"disable", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to disable service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to disable service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("systemctl", "disable", serviceName, result, err)
 	}
 	return nil
 }
@@ -280,11 +268,8 @@ func (l *LinuxServiceDetector) IsServiceEnabled(ctx context.Context, serviceName
 func (l *LinuxServiceDetector) SetServiceStartup(ctx context.Context, serviceName string, enabled bool) error {
 	if enabled {
 		result, err := l.executor.Run(ctx, "systemctl", []string{"enable", serviceName}, executor.ExecOpts{})
-		if err != nil {
-			return fmt.Errorf("failed to enable service %s: %w", serviceName, err)
-		}
-		if result.ExitCode != 0 {
-			return fmt.Errorf("failed to enable service %s: %s", serviceName, result.Stderr)
+		if err != nil || result.ExitCode != 0 {
+			return serviceCommandError("systemctl", "enable", serviceName, result, err)
 		}
 		return nil
 	}
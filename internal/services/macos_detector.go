@@ -336,11 +336,8 @@ func (m *MacOSServiceDetector) StartService(ctx context.Context, serviceName str
 
 	// Fallback to launchctl
 	result, err = m.executor.Run(ctx, "launchctl", []string{"start", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to start service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to start service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("launchctl", "start", serviceName, result, err)
 	}
 	return nil
 }
@@ -355,11 +352,8 @@ func (m *MacOSServiceDetector) StopService(ctx context.Context, serviceName stri
 
 	// Fallback to launchctl
 	result, err = m.executor.Run(ctx, "launchctl", []string{"stop", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to stop service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to stop service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("launchctl", "stop", serviceName, result, err)
 	}
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+
 	"github.com/jamesainslie/terraform-provider-package/internal/executor"
 )
 
@@ -21,33 +22,24 @@ func (b *BrewServicesStrategy) GetStrategyName() ServiceManagementStrategy {
 
 func (b *BrewServicesStrategy) StartService(ctx context.Context, serviceName string) error {
 	result, err := b.executor.Run(ctx, "brew", []string{"services", "start", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to start service %s with brew services: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("brew services start failed for %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("brew services", "start", serviceName, result, err)
 	}
 	return nil
 }
 
 func (b *BrewServicesStrategy) StopService(ctx context.Context, serviceName string) error {
 	result, err := b.executor.Run(ctx, "brew", []string{"services", "stop", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to stop service %s with brew services: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("brew services stop failed for %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("brew services", "stop", serviceName, result, err)
 	}
 	return nil
 }
 
 func (b *BrewServicesStrategy) RestartService(ctx context.Context, serviceName string) error {
 	result, err := b.executor.Run(ctx, "brew", []string{"services", "restart", serviceName}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to restart service %s with brew services: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("brew services restart failed for %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("brew services", "restart", serviceName, result, err)
 	}
 	return nil
 }
@@ -144,7 +136,7 @@ func (d *DirectCommandStrategy) StartService(ctx context.Context, serviceName st
 			"service_name": serviceName,
 			"error":        err.Error(),
 		})
-		return fmt.Errorf("failed to start service %s with direct command: %w", serviceName, err)
+		return serviceCommandError("command", "start", serviceName, result, err)
 	}
 
 	if result.ExitCode != 0 {
@@ -170,8 +162,7 @@ func (d *DirectCommandStrategy) StartService(ctx context.Context, serviceName st
 			"stderr":       result.Stderr,
 		})
 
-		return fmt.Errorf("direct command start failed for %s: exit code %d, stderr: %s",
-			serviceName, result.ExitCode, result.Stderr)
+		return serviceCommandError("command", "start", serviceName, result, err)
 	}
 
 	tflog.Debug(ctx, "DirectCommandStrategy.StartService completed successfully", map[string]interface{}{
@@ -195,7 +186,7 @@ func (d *DirectCommandStrategy) StopService(ctx context.Context, serviceName str
 
 	result, err := d.executor.Run(ctx, d.commands.Stop[0], d.commands.Stop[1:], executor.ExecOpts{})
 	if err != nil {
-		return fmt.Errorf("failed to stop service %s with direct command: %w", serviceName, err)
+		return serviceCommandError("command", "stop", serviceName, result, err)
 	}
 	if result.ExitCode != 0 {
 		// Handle service-specific "already stopped" cases
@@ -203,8 +194,7 @@ func (d *DirectCommandStrategy) StopService(ctx context.Context, serviceName str
 			// Service reports it's already stopped - this is actually success
 			return nil
 		}
-		return fmt.Errorf("direct command stop failed for %s: exit code %d, stderr: %s",
-			serviceName, result.ExitCode, result.Stderr)
+		return serviceCommandError("command", "stop", serviceName, result, err)
 	}
 	return nil
 }
@@ -221,10 +211,10 @@ func (d *DirectCommandStrategy) RestartService(ctx context.Context, serviceName
 
 	result, err := d.executor.Run(ctx, d.commands.Restart[0], d.commands.Restart[1:], executor.ExecOpts{})
 	if err != nil {
-		return fmt.Errorf("failed to restart service %s with direct command: %w", serviceName, err)
+		return serviceCommandError("command", "restart", serviceName, result, err)
 	}
 	if result.ExitCode != 0 {
-		return fmt.Errorf("direct command restart failed for %s: %s", serviceName, result.Stderr)
+		return serviceCommandError("command", "restart", serviceName, result, err)
 	}
 	return nil
 }
@@ -318,11 +308,11 @@ func (l *LaunchdStrategy) StartService(ctx context.Context, serviceName string)
 		// Try user domain
 		result, err = l.executor.Run(ctx, "launchctl", []string{"load", "-w", fmt.Sprintf("~/Library/LaunchAgents/%s.plist", serviceName)}, executor.ExecOpts{})
 		if err != nil {
-			return fmt.Errorf("failed to start service %s with launchd: %w", serviceName, err)
+			return serviceCommandError("launchctl", "start", serviceName, result, err)
 		}
 	}
 	if result.ExitCode != 0 {
-		return fmt.Errorf("launchd start failed for %s: %s", serviceName, result.Stderr)
+		return serviceCommandError("launchctl", "start", serviceName, result, err)
 	}
 	return nil
 }
@@ -334,11 +324,11 @@ func (l *LaunchdStrategy) StopService(ctx context.Context, serviceName string) e
 		// Try user domain
 		result, err = l.executor.Run(ctx, "launchctl", []string{"unload", "-w", fmt.Sprintf("~/Library/LaunchAgents/%s.plist", serviceName)}, executor.ExecOpts{})
 		if err != nil {
-			return fmt.Errorf("failed to stop service %s with launchd: %w", serviceName, err)
+			return serviceCommandError("launchctl", "stop", serviceName, result, err)
 		}
 	}
 	if result.ExitCode != 0 {
-		return fmt.Errorf("launchd stop failed for %s: %s", serviceName, result.Stderr)
+		return serviceCommandError("launchctl", "stop", serviceName, result, err)
 	}
 	return nil
 }
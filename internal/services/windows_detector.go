@@ -217,11 +217,8 @@ func (w *WindowsServiceDetector) checkProcessName(ctx context.Context, serviceNa
 func (w *WindowsServiceDetector) StartService(ctx context.Context, serviceName string) error {
 	cmd := fmt.Sprintf("Start-Service -Name '%s'", serviceName)
 	result, err := w.executor.Run(ctx, "powershell", []string{"-Command", cmd}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to start service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to start service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("powershell", "start", serviceName, result, err)
 	}
 	return nil
 }
@@ -230,11 +227,8 @@ func (w *WindowsServiceDetector) StartService(ctx context.Context, serviceName s
 func (w *WindowsServiceDetector) StopService(ctx context.Context, serviceName string) error {
 	cmd := fmt.Sprintf("Stop-Service -Name '%s'", serviceName)
 	result, err := w.executor.Run(ctx, "powershell", []string{"-Command", cmd}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to stop service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to stop service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("powershell", "stop", serviceName, result, err)
 	}
 	return nil
 }
@@ -243,11 +237,8 @@ func (w *WindowsServiceDetector) StopService(ctx context.Context, serviceName st
 func (w *WindowsServiceDetector) RestartService(ctx context.Context, serviceName string) error {
 	cmd := fmt.Sprintf("Restart-Service -Name '%s'", serviceName)
 	result, err := w.executor.Run(ctx, "powershell", []string{"-Command", cmd}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to restart service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to restart service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("powershell", "restart", serviceName, result, err)
 	}
 	return nil
 }
@@ -256,11 +247,8 @@ func (w *WindowsServiceDetector) RestartService(ctx context.Context, serviceName
 func (w *WindowsServiceDetector) DisableService(ctx context.Context, serviceName string) error {
 	cmd := fmt.Sprintf("Set-Service -Name '%s' -StartupType Disabled", serviceName)
 	result, err := w.executor.Run(ctx, "powershell", []string{"-Command", cmd}, executor.ExecOpts{})
-	if err != nil {
-		return fmt.Errorf("failed to disable service %s: %w", serviceName, err)
-	}
-	if result.ExitCode != 0 {
-		return fmt.Errorf("failed to disable service %s: %s", serviceName, result.Stderr)
+	if err != nil || result.ExitCode != 0 {
+		return serviceCommandError("powershell", "disable", serviceName, result, err)
 	}
 	return nil
 }
@@ -283,11 +271,8 @@ func (w *WindowsServiceDetector) SetServiceStartup(ctx context.Context, serviceN
 	if enabled {
 		cmd := fmt.Sprintf("Set-Service -Name '%s' -StartupType Automatic", serviceName)
 		result, err := w.executor.Run(ctx, "powershell", []string{"-Command", cmd}, executor.ExecOpts{})
-		if err != nil {
-			return fmt.Errorf("failed to enable service %s: %w", serviceName, err)
-		}
-		if result.ExitCode != 0 {
-			return fmt.Errorf("failed to enable service %s: %s", serviceName, result.Stderr)
+		if err != nil || result.ExitCode != 0 {
+			return serviceCommandError("powershell", "enable", serviceName, result, err)
 		}
 		return nil
 	}
@@ -0,0 +1,180 @@
+// MIT License
+//
+// Copyright (c) 2025 Terraform Package Provider Contributors
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package telemetry provides OpenTelemetry tracing for provider operations.
+// Tracing is disabled unless an OTLP endpoint is configured, in which case
+// spans are exported over OTLP/HTTP.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName identifies this provider's spans.
+const instrumentationName = "github.com/jamesainslie/terraform-provider-package"
+
+// serviceName is the default service.name resource attribute. OTEL_SERVICE_NAME overrides it.
+const serviceName = "terraform-provider-package"
+
+// batchTimeout bounds how long finished spans wait before export. Provider
+// processes are short-lived, so spans are sent promptly rather than batched up.
+const batchTimeout = time.Second
+
+// Span attribute keys shared by resources, adapters and the executor.
+const (
+	AttrManager  = attribute.Key("pkg.manager")
+	AttrPackage  = attribute.Key("pkg.package")
+	AttrService  = attribute.Key("pkg.service")
+	AttrResource = attribute.Key("pkg.resource")
+	AttrAction   = attribute.Key("pkg.terraform_operation")
+	AttrCommand  = attribute.Key("pkg.command")
+	AttrExitCode = attribute.Key("pkg.exit_code")
+	AttrSudo     = attribute.Key("pkg.sudo")
+	// AttrRetries counts how often an operation was attempted again after failing
+	AttrRetries = attribute.Key("pkg.retries")
+	// AttrHealthChecks counts the health checks run while waiting for a service
+	AttrHealthChecks = attribute.Key("pkg.health_checks")
+)
+
+// otlpEndpointEnvVars are the standard variables that enable trace export.
+var otlpEndpointEnvVars = []string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT"}
+
+var (
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+)
+
+// Config configures trace export.
+type Config struct {
+	// Endpoint is the OTLP/HTTP endpoint URL, such as http://collector:4318.
+	// When empty, the standard OTEL_EXPORTER_OTLP_* variables are used.
+	Endpoint string
+	// Version is recorded as the service.version resource attribute
+	Version string
+}
+
+// Enabled reports whether cfg, or the environment, configures an OTLP endpoint.
+func (cfg Config) Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	if cfg.Endpoint != "" {
+		return true
+	}
+	for _, name := range otlpEndpointEnvVars {
+		if os.Getenv(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Setup installs the global tracer provider and exporter. It does nothing if
+// tracing is not enabled or has already been set up, since a provider process
+// may be configured more than once (e.g., for provider aliases).
+func Setup(ctx context.Context, cfg Config) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if provider != nil {
+		return nil
+	}
+
+	var opts []otlptracehttp.Option
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	return install(ctx, sdktrace.NewBatchSpanProcessor(exporter, sdktrace.WithBatchTimeout(batchTimeout)), cfg.Version)
+}
+
+// install registers a tracer provider that sends spans to processor.
+func install(ctx context.Context, processor sdktrace.SpanProcessor, version string) error {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName), semconv.ServiceVersion(version)),
+		resource.WithHost(),
+		resource.WithFromEnv(),
+	)
+	if err != nil && res == nil {
+		return fmt.Errorf("failed to build telemetry resource: %w", err)
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return nil
+}
+
+// Shutdown flushes pending spans and stops the exporter. It is safe to call
+// when tracing was never set up.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if provider == nil {
+		return nil
+	}
+	err := provider.Shutdown(ctx)
+	provider = nil
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	return err
+}
+
+// Start starts a span as a child of any span in ctx. When tracing is disabled
+// the global no-op tracer makes this essentially free.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
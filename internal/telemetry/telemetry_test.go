@@ -0,0 +1,136 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeCollector stands in for an OpenTelemetry collector's OTLP/HTTP receiver.
+type fakeCollector struct {
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func (c *fakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	for _, resourceSpans := range req.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			c.spans = append(c.spans, scopeSpans.Spans...)
+		}
+	}
+	c.mu.Unlock()
+
+	out, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(out)
+}
+
+func (c *fakeCollector) span(name string) *tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, span := range c.spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func spanAttributes(span *tracepb.Span) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	for _, kv := range span.Attributes {
+		switch v := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.Key] = v.StringValue
+		case *commonpb.AnyValue_IntValue:
+			attrs[kv.Key] = v.IntValue
+		case *commonpb.AnyValue_BoolValue:
+			attrs[kv.Key] = v.BoolValue
+		}
+	}
+	return attrs
+}
+
+func TestSetup_ExportsSpansToCollector(t *testing.T) {
+	collector := &fakeCollector{}
+	server := httptest.NewServer(collector)
+	defer server.Close()
+
+	ctx := context.Background()
+	require.NoError(t, Setup(ctx, Config{Endpoint: server.URL, Version: "test"}))
+
+	parentCtx, parent := Start(ctx, "pkg_package.create", AttrResource.String("pkg_package"))
+	_, child := Start(parentCtx, "apt.install", AttrManager.String("apt"), AttrPackage.String("curl"))
+	child.SetAttributes(AttrExitCode.Int(100), AttrRetries.Int(1))
+	End(child, errors.New("exit status 100"))
+	End(parent, nil)
+
+	require.NoError(t, Shutdown(ctx))
+
+	install := collector.span("apt.install")
+	require.NotNil(t, install, "collector did not receive the adapter span")
+	create := collector.span("pkg_package.create")
+	require.NotNil(t, create, "collector did not receive the resource span")
+
+	assert.Equal(t, create.SpanId, install.ParentSpanId)
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, install.Status.Code)
+
+	attrs := spanAttributes(install)
+	assert.Equal(t, "apt", attrs["pkg.manager"])
+	assert.Equal(t, "curl", attrs["pkg.package"])
+	assert.Equal(t, int64(100), attrs["pkg.exit_code"])
+	assert.Equal(t, int64(1), attrs["pkg.retries"])
+}
+
+func TestConfig_Enabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	t.Setenv("OTEL_SDK_DISABLED", "")
+
+	assert.False(t, Config{}.Enabled())
+	assert.True(t, Config{Endpoint: "http://localhost:4318"}.Enabled())
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	assert.True(t, Config{}.Enabled())
+
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	assert.False(t, Config{Endpoint: "http://localhost:4318"}.Enabled())
+}
+
+func TestSetup_DisabledIsNoop(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	require.NoError(t, Setup(context.Background(), Config{}))
+	_, span := Start(context.Background(), "noop")
+	assert.False(t, span.SpanContext().IsValid())
+	End(span, nil)
+	require.NoError(t, Shutdown(context.Background()))
+}
@@ -27,11 +27,17 @@ import (
 	"context"
 	"flag"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+
 	"github.com/jamesainslie/terraform-provider-package/internal/provider"
+	"github.com/jamesainslie/terraform-provider-package/internal/telemetry"
 )
 
+// telemetryShutdownTimeout bounds how long exiting waits to export remaining spans.
+const telemetryShutdownTimeout = 5 * time.Second
+
 var (
 	// These will be set by the goreleaser configuration
 	// to appropriate values for the compiled binary.
@@ -54,6 +60,13 @@ func main() {
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 
+	// Flush any spans still queued for export once Terraform is done with the
+	// provider. Terraform no longer reads provider logs at this point, so a
+	// failed export cannot be reported and only loses the remaining spans.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+	defer cancel()
+	_ = telemetry.Shutdown(shutdownCtx)
+
 	if err != nil {
 		log.Fatal(err.Error())
 	}